@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"time"
+
+	"carApi/config"
+	"carApi/infrastructure/datastore"
+	"carApi/infrastructure/events"
+	"carApi/infrastructure/exchange"
+	"carApi/infrastructure/lifecycle"
+	"carApi/infrastructure/metrics"
+	"carApi/infrastructure/storage"
+	"carApi/infrastructure/stream"
+	"carApi/infrastructure/tracing"
+	"carApi/infrastructure/webhook"
+	memoryRepository "carApi/repository/memory"
+	pgsqlRepository "carApi/repository/pgsql"
+	redisRepository "carApi/repository/redis"
+	sqliteRepository "carApi/repository/sqlite"
+	"carApi/repository/transaction"
+	"carApi/usecase"
+	"carApi/utils/logger"
+	"github.com/go-redis/redis"
+)
+
+// application is the wiring shared by the commands, each of them gets the same
+// configuration, datastores, repositories and usecases
+type application struct {
+	config    *config.Config
+	logger    logger.Logger
+	lifecycle *lifecycle.Lifecycle
+	metrics   *metrics.Metrics
+
+	// db and cache are nil when the in-memory repositories are used
+	db      *sql.DB
+	cache   *redis.Client
+	storage storage.Storage
+
+	carRepo pgsqlRepository.CarRepository
+	// relay publishes the events of the outbox and dispatcher posts the webhook deliveries,
+	// only serve runs them
+	relay      *events.Relay
+	dispatcher *webhook.Dispatcher
+	// hub fans the events out to the open streams, listener feeds it from the other instances
+	// and is nil when there is no cache to share them through
+	hub      *stream.Hub
+	listener *stream.RedisListener
+	// presence tells the sockets which cars had their viewers change, presenceListener feeds it
+	// from the other instances and is nil without a cache
+	presence         *stream.Presence
+	presenceListener *stream.RedisPresenceListener
+
+	carUC      usecase.CarUsecase
+	carImageUC usecase.CarImageUsecase
+	featureUC  usecase.FeatureUsecase
+	catalogUC  usecase.CatalogUsecase
+	webhookUC  usecase.WebhookUsecase
+
+	carStreamUC   usecase.CarStreamUsecase
+	presenceUC    usecase.PresenceUsecase
+	reservationUC usecase.ReservationUsecase
+	// carRelationUC loads the relations of many cars at once for the graphql queries
+	carRelationUC usecase.CarRelationUsecase
+}
+
+// loadConfig will load the configuration from the flags in args and set up the logger,
+// the command stops when the configuration is invalid
+func loadConfig(args []string) (*config.Config, logger.Logger) {
+	configApp, err := config.Load(args)
+	exitIfInvalid(err)
+
+	appLogger := logger.NewApiLogger(configApp)
+	appLogger.InitLogger()
+	return configApp, appLogger
+}
+
+// newApplication will connect the datastores and build the usecases, every resource is
+// registered on the lifecycle so close releases them
+func newApplication(args []string) *application {
+	configApp, appLogger := loadConfig(args)
+	app := &application{
+		config:    configApp,
+		logger:    appLogger,
+		lifecycle: lifecycle.NewLifecycle(appLogger),
+		metrics:   metrics.NewMetrics(),
+	}
+	exitIfFailed(app.connect())
+	return app
+}
+
+func (app *application) connect() (err error) {
+	// Setup tracing
+	tracerProvider, err := tracing.NewTracerProvider(app.config)
+	if err != nil {
+		return
+	}
+	tracing.Setup(tracerProvider)
+
+	// Setup infra, resources are closed in reverse order so background
+	// workers registered later stop before the database and the cache
+	app.lifecycle.OnShutdown("tracing", tracerProvider.Shutdown)
+
+	// A memory:// URL keeps the data in the process instead, db or cache is then left nil
+	if !datastore.IsMemory(app.config.Database.URL) {
+		if app.db, err = datastore.NewDatabase(app.config.Database, app.logger); err != nil {
+			return
+		}
+		app.lifecycle.OnShutdown("database", func(ctx context.Context) error {
+			return app.db.Close()
+		})
+	}
+
+	if !datastore.IsMemory(app.config.Cache.URL) {
+		if app.cache, err = datastore.NewCache(app.config.Cache, app.logger); err != nil {
+			return
+		}
+		app.lifecycle.OnShutdown("cache", func(ctx context.Context) error {
+			return app.cache.Close()
+		})
+	}
+
+	if app.storage, err = storage.NewStorage(app.config); err != nil {
+		return
+	}
+
+	exchangeRates, err := exchange.LoadRates(app.config.Exchange.RatesFile)
+	if err != nil {
+		return
+	}
+
+	// Setup repository
+	var redisRepo redisRepository.RedisRepository
+	var presenceRepo redisRepository.PresenceRepository
+	if app.cache != nil {
+		redisRepo = redisRepository.NewRedisRepository(app.cache, app.metrics)
+		presenceRepo = redisRepository.NewPresenceRepository(app.cache)
+	} else {
+		redisRepo = memoryRepository.NewMemoryRedisRepository(app.metrics)
+		presenceRepo = memoryRepository.NewMemoryPresenceRepository()
+	}
+
+	var txManager transaction.TxManager
+	var carImageRepo pgsqlRepository.CarImageRepository
+	var featureRepo pgsqlRepository.FeatureRepository
+	var priceRepo pgsqlRepository.PriceHistoryRepository
+	var catalogRepo pgsqlRepository.CatalogRepository
+	var outboxRepo pgsqlRepository.OutboxRepository
+	var webhookRepo pgsqlRepository.WebhookRepository
+	var deliveryRepo pgsqlRepository.WebhookDeliveryRepository
+	var reservationRepo pgsqlRepository.ReservationRepository
+	switch datastore.Scheme(app.config.Database.URL) {
+	case datastore.MemoryScheme:
+		store := memoryRepository.NewStore()
+		app.carRepo = memoryRepository.NewMemoryCarRepository(store)
+		carImageRepo = memoryRepository.NewMemoryCarImageRepository(store)
+		featureRepo = memoryRepository.NewMemoryFeatureRepository(store)
+		priceRepo = memoryRepository.NewMemoryPriceHistoryRepository(store)
+		catalogRepo = memoryRepository.NewMemoryCatalogRepository(store)
+		outboxRepo = memoryRepository.NewMemoryOutboxRepository(store)
+		webhookRepo = memoryRepository.NewMemoryWebhookRepository(store)
+		deliveryRepo = memoryRepository.NewMemoryWebhookDeliveryRepository(store)
+		reservationRepo = memoryRepository.NewMemoryReservationRepository(store)
+		txManager = memoryRepository.NewMemoryTxManager(store)
+	case datastore.SQLiteScheme:
+		app.carRepo = sqliteRepository.NewSqliteCarRepository(app.db)
+		carImageRepo = sqliteRepository.NewSqliteCarImageRepository(app.db)
+		featureRepo = sqliteRepository.NewSqliteFeatureRepository(app.db)
+		priceRepo = sqliteRepository.NewSqlitePriceHistoryRepository(app.db)
+		catalogRepo = sqliteRepository.NewSqliteCatalogRepository(app.db)
+		outboxRepo = sqliteRepository.NewSqliteOutboxRepository(app.db)
+		webhookRepo = sqliteRepository.NewSqliteWebhookRepository(app.db)
+		deliveryRepo = sqliteRepository.NewSqliteWebhookDeliveryRepository(app.db)
+		reservationRepo = sqliteRepository.NewSqliteReservationRepository(app.db)
+		txManager = transaction.NewSQLTxManager(app.db)
+	default:
+		app.carRepo = pgsqlRepository.NewPgsqlCarRepository(app.db)
+		carImageRepo = pgsqlRepository.NewPgsqlCarImageRepository(app.db)
+		featureRepo = pgsqlRepository.NewPgsqlFeatureRepository(app.db)
+		priceRepo = pgsqlRepository.NewPgsqlPriceHistoryRepository(app.db)
+		catalogRepo = pgsqlRepository.NewPgsqlCatalogRepository(app.db)
+		outboxRepo = pgsqlRepository.NewPgsqlOutboxRepository(app.db)
+		webhookRepo = pgsqlRepository.NewPgsqlWebhookRepository(app.db)
+		deliveryRepo = pgsqlRepository.NewPgsqlWebhookDeliveryRepository(app.db)
+		reservationRepo = pgsqlRepository.NewPgsqlReservationRepository(app.db)
+		txManager = transaction.NewSQLTxManager(app.db)
+	}
+
+	publisher, err := events.NewPublisher(app.config, app.cache)
+	if err != nil {
+		return
+	}
+	// The streams of every instance get the events through redis pub/sub, a single instance
+	// without a cache hands them to its hub directly
+	app.hub = stream.NewHub(app.config.Stream.ReplaySize, app.config.Stream.ClientBuffer)
+	streamPublisher := events.Publisher(app.hub)
+	if app.cache != nil {
+		streamPublisher = stream.NewRedisPublisher(app.cache, app.config.Stream.Channel)
+		app.listener = stream.NewRedisListener(app.cache, app.config.Stream.Channel, app.hub, app.logger)
+	}
+
+	app.presence = stream.NewPresence(app.config.Stream.ClientBuffer)
+	announcer := stream.Announcer(app.presence)
+	if app.cache != nil {
+		announcer = stream.NewRedisAnnouncer(app.cache, app.config.Socket.PresenceChannel)
+		app.presenceListener = stream.NewRedisPresenceListener(app.cache, app.config.Socket.PresenceChannel, app.presence, app.logger)
+	}
+
+	// The relay also stores the webhook deliveries of the events, in its transaction
+	publisher = events.NewMultiPublisher(publisher, webhook.NewFanout(webhookRepo, deliveryRepo), streamPublisher)
+	app.relay = events.NewRelay(outboxRepo, txManager, publisher, app.metrics, app.logger, time.Duration(app.config.Events.RelayInterval)*time.Millisecond, app.config.Events.RelayBatchSize)
+	app.dispatcher = webhook.NewDispatcher(webhookRepo, deliveryRepo, txManager, app.metrics, app.logger, app.config.Webhooks)
+
+	// Setup usecase
+	ctxTimeout := time.Duration(app.config.Server.ContextTimeout) * time.Second
+	cacheTTL := time.Duration(app.config.Cache.CarsTTL) * time.Second
+	app.carUC = usecase.NewCarUsecase(app.carRepo, carImageRepo, featureRepo, priceRepo, catalogRepo, outboxRepo, redisRepo, txManager, app.storage, exchangeRates, cacheTTL, ctxTimeout)
+	app.carImageUC = usecase.NewCarImageUsecase(app.carRepo, carImageRepo, txManager, app.storage, app.config.Media.MaxUploadSize, app.config.Media.MaxPixels, ctxTimeout)
+	app.featureUC = usecase.NewFeatureUsecase(app.carRepo, featureRepo, redisRepo, ctxTimeout)
+	app.catalogUC = usecase.NewCatalogUsecase(catalogRepo, ctxTimeout)
+	app.carRelationUC = usecase.NewCarRelationUsecase(carImageRepo, featureRepo, priceRepo, app.storage, ctxTimeout)
+	app.webhookUC = usecase.NewWebhookUsecase(webhookRepo, deliveryRepo, net.DefaultResolver, ctxTimeout)
+	app.carStreamUC = usecase.NewCarStreamUsecase(app.hub, featureRepo, exchangeRates, ctxTimeout)
+	app.presenceUC = usecase.NewPresenceUsecase(app.carRepo, presenceRepo, app.presence, announcer, time.Duration(app.config.Socket.PresenceTTL)*time.Second, ctxTimeout)
+	app.reservationUC = usecase.NewReservationUsecase(app.carRepo, reservationRepo, outboxRepo, txManager, time.Duration(app.config.Socket.ReservationTTL)*time.Second, ctxTimeout)
+
+	return nil
+}
+
+// close releases the resources within the shutdown timeout
+func (app *application) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(app.config.Shutdown.Timeout)*time.Second)
+	defer cancel()
+
+	if err := app.lifecycle.Shutdown(ctx); err != nil {
+		app.logger.Error(err)
+	}
+}
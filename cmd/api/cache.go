@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// runCache handles `cache flush`, which moves the car listings to a new cache version so the
+// cached ones are not read again
+func runCache(args []string) {
+	if len(args) == 0 || args[0] != "flush" {
+		exitIfInvalid(errors.New("usage: cache flush [flags]"))
+	}
+
+	app := newApplication(args[1:])
+	version, err := app.carUC.FlushCache(context.Background())
+	if err == nil {
+		app.logger.Infof("flushed the cached car listings, now at version %d", version)
+	}
+	app.close()
+	exitIfFailed(err)
+}
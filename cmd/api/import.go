@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"carApi/transport/request"
+)
+
+// runImport handles `import FILE`, the file holds a JSON array of POST /api/v1/cars bodies.
+// Every car is validated and created on its own, the invalid ones are reported and skipped.
+func runImport(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		exitIfInvalid(errors.New("usage: import FILE [flags]"))
+	}
+	path, args := args[0], args[1:]
+
+	data, err := os.ReadFile(path)
+	exitIfInvalid(err)
+
+	var cars []request.CreateCarReq
+	if err := json.Unmarshal(data, &cars); err != nil {
+		exitIfInvalid(fmt.Errorf("decoding %s: %w", path, err))
+	}
+
+	app := newApplication(args)
+	err = importCars(app, path, cars)
+	app.close()
+	exitIfFailed(err)
+}
+
+func importCars(app *application, path string, cars []request.CreateCarReq) error {
+	failed := 0
+	for i := range cars {
+		err := cars[i].Validate()
+		if err == nil {
+			err = app.carUC.Create(context.Background(), &cars[i])
+		}
+		if err != nil {
+			failed++
+			app.logger.Errorf("car %d (%s): %v", i+1, cars[i].Identification, err)
+		}
+	}
+
+	app.logger.Infof("imported %d of %d cars from %s", len(cars)-failed, len(cars), path)
+	if failed > 0 {
+		return fmt.Errorf("%d cars could not be imported", failed)
+	}
+	return nil
+}
@@ -1,65 +1,89 @@
 package main
 
 import (
-	"net/http"
-	"time"
-
-	_ "carApi/docs"
-	"carApi/utils"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
 
 	"carApi/config"
-	httpDelivery "carApi/delivery/http"
-	appMiddleware "carApi/delivery/middleware"
-	"carApi/infrastructure/datastore"
-	pgsqlRepository "carApi/repository/pgsql"
-	redisRepository "carApi/repository/redis"
-	"carApi/usecase"
-	"carApi/utils/logger"
-	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
-	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
-func main() {
-	// Load config
-	configApp := config.LoadConfig()
+const usage = `usage: carapi [command] [arguments] [flags]
 
-	// Setup logger
-	appLogger := logger.NewApiLogger(configApp)
-	appLogger.InitLogger()
+commands:
+  serve                 run the HTTP API, the default
+  migrate               up | down [N] | status | goto VERSION | force VERSION
+  seed [N]              load the reference catalog and N fake cars, 100 by default
+  import FILE           create the cars of a JSON file, an array of POST /api/v1/cars bodies
+  cache flush           drop the cached car listings
+  reindex-search        rebuild the cached car listings
+  purge-deleted [DAYS]  remove the cars deleted more than DAYS ago, database.deleted_retention by default
+  config print          show the effective configuration, secrets redacted
+  help                  show this message
 
-	// Setup infra
-	dbInstance, err := datastore.NewDatabase(configApp.DatabaseURL)
-	utils.PanicIfNeeded(err)
+flags are the configuration flags, run any command with -h to list them`
 
-	cacheInstance, err := datastore.NewCache(configApp.CacheURL)
-	utils.PanicIfNeeded(err)
+// commands are the subcommands of the binary, they all share the configuration and the wiring of app.go
+var commands = map[string]func(args []string){
+	"serve":          runServe,
+	"migrate":        runMigrate,
+	"seed":           runSeed,
+	"import":         runImport,
+	"cache":          runCache,
+	"reindex-search": runReindexSearch,
+	"purge-deleted":  runPurgeDeleted,
+	"config":         runConfig,
+	"help":           runHelp,
+}
 
-	// Setup repository
-	redisRepo := redisRepository.NewRedisRepository(cacheInstance)
-	carRepo := pgsqlRepository.NewPgsqlCarRepository(dbInstance)
+func main() {
+	args := os.Args[1:]
 
-	// Setup usecase
-	ctxTimeout := time.Duration(configApp.ContextTimeout) * time.Second
-	carUC := usecase.NewCarUsecase(carRepo, redisRepo, ctxTimeout)
+	// without a command the API is served, flags alone keep working as before
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
 
-	// Setup app middleware
-	appMiddleware := appMiddleware.NewMiddleware(appLogger)
+	command, ok := commands[name]
+	if !ok {
+		exitIfInvalid(fmt.Errorf("unknown command %q\n\n%s", name, usage))
+	}
+	command(args)
+}
 
-	// Setup route engine & middleware
-	e := echo.New()
-	e.Use(middleware.CORS())
-	e.Use(appMiddleware.RequestID())
-	e.Use(appMiddleware.Logger())
-	e.Use(middleware.Recover())
+func runHelp(args []string) {
+	fmt.Println(usage)
+}
+
+// runConfig handles `config print`
+func runConfig(args []string) {
+	if len(args) == 0 || args[0] != "print" {
+		exitIfInvalid(errors.New("usage: config print [flags]"))
+	}
 
-	// Setup handler
-	e.GET("/swagger/*", echoSwagger.WrapHandler)
-	e.GET("/", func(c echo.Context) error {
-		return c.String(http.StatusOK, "i am alive")
-	})
+	configApp, err := config.Load(args[1:])
+	exitIfInvalid(err)
+	exitIfFailed(config.Print(os.Stdout, configApp))
+}
 
-	httpDelivery.NewCarHandler(e, appMiddleware, carUC)
+// exitIfInvalid stops before anything starts when the command line or the configuration is invalid
+func exitIfInvalid(err error) {
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
 
-	e.Logger.Fatal(e.Start(":" + configApp.ServerPORT))
+// exitIfFailed stops with the error once the configuration is valid
+func exitIfFailed(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
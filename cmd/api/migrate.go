@@ -0,0 +1,117 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"carApi/config"
+	"carApi/infrastructure/datastore"
+	"carApi/infrastructure/migrator"
+	"carApi/migration"
+	sqliteMigration "carApi/migration/sqlite"
+	"carApi/utils/logger"
+)
+
+const migrateUsage = "usage: migrate up | down [N] | status | goto VERSION | force VERSION [flags]"
+
+// runMigrate handles `migrate up|down [N]|status|goto VERSION|force VERSION`, the arguments
+// after the command are the configuration flags
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		exitIfInvalid(errors.New(migrateUsage))
+	}
+	command, args := args[0], args[1:]
+
+	// down takes an optional number before the flags, goto and force a version
+	var number string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		number, args = args[0], args[1:]
+	}
+
+	var run func(m *migrator.Migrator) error
+	switch {
+	case command == "up" && number == "":
+		run = (*migrator.Migrator).Up
+	case command == "down":
+		n := 1
+		if number != "" {
+			var err error
+			if n, err = strconv.Atoi(number); err != nil {
+				exitIfInvalid(fmt.Errorf("invalid number of migrations %q", number))
+			}
+		}
+		run = func(m *migrator.Migrator) error { return m.Down(n) }
+	case command == "goto" && number != "":
+		version := parseVersion(number)
+		run = func(m *migrator.Migrator) error { return m.Goto(version) }
+	case command == "force" && number != "":
+		version := parseVersion(number)
+		run = func(m *migrator.Migrator) error { return m.Force(version) }
+	case command == "status" && number == "":
+		run = printMigrationStatus
+	default:
+		exitIfInvalid(errors.New(migrateUsage))
+	}
+
+	configApp, appLogger := loadConfig(args)
+	if datastore.IsMemory(configApp.Database.URL) {
+		exitIfInvalid(errors.New("the memory database has no schema to migrate"))
+	}
+
+	dbInstance, err := datastore.NewDatabase(configApp.Database, appLogger)
+	exitIfFailed(err)
+	defer dbInstance.Close()
+
+	m, err := newMigrator(dbInstance, configApp, appLogger)
+	exitIfFailed(err)
+	defer m.Close()
+
+	exitIfFailed(run(m))
+}
+
+func parseVersion(raw string) uint {
+	version, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		exitIfInvalid(fmt.Errorf("invalid version %q", raw))
+	}
+	return uint(version)
+}
+
+// newMigrator will create a migrator with the migrations of the configured database
+func newMigrator(db *sql.DB, configApp *config.Config, appLogger logger.Logger) (*migrator.Migrator, error) {
+	files := fs.FS(migration.Files)
+	if datastore.Scheme(configApp.Database.URL) == datastore.SQLiteScheme {
+		files = sqliteMigration.Files
+	}
+	return migrator.NewMigrator(db, files, time.Duration(configApp.Migrate.LockTimeout)*time.Second, appLogger)
+}
+
+func printMigrationStatus(m *migrator.Migrator) error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("version %d", status.Version)
+	if status.Dirty {
+		fmt.Print(" (dirty, the last migration failed halfway: repair the schema then force the version it is at)")
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, migration := range status.Migrations {
+		state := "pending"
+		if migration.Applied {
+			state = "applied"
+		}
+		fmt.Fprintf(w, "%06d\t%s\t%s\n", migration.Version, migration.Name, state)
+	}
+	return w.Flush()
+}
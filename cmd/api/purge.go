@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runPurgeDeleted handles `purge-deleted [DAYS]`, it removes for good the cars deleted more than
+// DAYS ago, database.deleted_retention by default
+func runPurgeDeleted(args []string) {
+	days := -1
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		var err error
+		if days, err = strconv.Atoi(args[0]); err != nil || days < 0 {
+			exitIfInvalid(fmt.Errorf("invalid number of days %q", args[0]))
+		}
+		args = args[1:]
+	}
+
+	app := newApplication(args)
+	if days < 0 {
+		days = app.config.Database.DeletedRetention
+	}
+
+	before := time.Now().AddDate(0, 0, -days)
+	purged, err := app.carUC.PurgeDeleted(context.Background(), before)
+	if err == nil {
+		app.logger.Infof("purged %d cars deleted before %s", purged, before.UTC().Format(time.RFC3339))
+	}
+	app.close()
+	exitIfFailed(err)
+}
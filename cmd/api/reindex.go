@@ -0,0 +1,15 @@
+package main
+
+import "context"
+
+// runReindexSearch handles `reindex-search`. The listings are searched through the cache, so
+// rebuilding the index moves the listings to a new cache version and caches the unfiltered one again.
+func runReindexSearch(args []string) {
+	app := newApplication(args)
+	listed, err := app.carUC.RebuildCache(context.Background())
+	if err == nil {
+		app.logger.Infof("rebuilt the cached listings, %d cars listed", listed)
+	}
+	app.close()
+	exitIfFailed(err)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"carApi/infrastructure/seed"
+)
+
+// runSeed handles `seed [N]`, it loads the reference catalog then creates N fake cars
+// through the car usecase so they go through the same normalization as the API
+func runSeed(args []string) {
+	n := 100
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 0 {
+			exitIfInvalid(fmt.Errorf("invalid number of cars %q", args[0]))
+		}
+		args = args[1:]
+	}
+
+	app := newApplication(args)
+	err := seedCars(app, n)
+	app.close()
+	exitIfFailed(err)
+}
+
+func seedCars(app *application, n int) error {
+	catalogSeed, err := seed.Catalog()
+	if err != nil {
+		return err
+	}
+	if err := app.catalogUC.Seed(context.Background(), catalogSeed); err != nil {
+		return fmt.Errorf("seeding the catalog: %w", err)
+	}
+
+	cars := seed.Cars(catalogSeed, n, rand.New(rand.NewSource(time.Now().UnixNano())))
+	for i := range cars {
+		if err := app.carUC.Create(context.Background(), &cars[i]); err != nil {
+			return fmt.Errorf("seeding car %d: %w", i+1, err)
+		}
+	}
+
+	app.logger.Infof("seeded the catalog and %d cars", len(cars))
+	return nil
+}
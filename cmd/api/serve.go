@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	_ "carApi/docs"
+	"carApi/utils"
+
+	graphqlDelivery "carApi/delivery/graphql"
+	grpcDelivery "carApi/delivery/grpc"
+	httpDelivery "carApi/delivery/http"
+	appMiddleware "carApi/delivery/middleware"
+	"carApi/infrastructure/health"
+	"carApi/infrastructure/seed"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	echoSwagger "github.com/swaggo/echo-swagger"
+	"google.golang.org/grpc"
+	grpcHealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// runServe starts the HTTP API and drains it on SIGINT or SIGTERM
+func runServe(args []string) {
+	app := newApplication(args)
+	configApp := app.config
+
+	if configApp.Migrate.Auto && app.db != nil {
+		m, err := newMigrator(app.db, configApp, app.logger)
+		utils.PanicIfNeeded(err)
+		utils.PanicIfNeeded(m.Up())
+		utils.PanicIfNeeded(m.Close())
+	}
+
+	// Seed the reference catalog, entries that already exist are left untouched
+	if configApp.Catalog.Seed {
+		catalogSeed, err := seed.Catalog()
+		utils.PanicIfNeeded(err)
+		utils.PanicIfNeeded(app.catalogUC.Seed(context.Background(), catalogSeed))
+	}
+
+	// Setup metrics
+	if app.db != nil {
+		app.metrics.RegisterDatabase(app.db)
+	}
+	app.metrics.RegisterCarsByCategory(app.carRepo.CountByCategory, time.Duration(configApp.Health.DatabaseTimeout)*time.Millisecond)
+
+	// Publish the outbox and post the webhooks, both stop before the database is closed
+	app.runWorker("events relay", app.relay.Run)
+	app.runWorker("webhook dispatcher", app.dispatcher.Run)
+	if app.listener != nil {
+		app.runWorker("stream listener", app.listener.Run)
+	}
+	if app.presenceListener != nil {
+		app.runWorker("presence listener", app.presenceListener.Run)
+	}
+
+	// Setup app middleware
+	appMiddleware := appMiddleware.NewMiddleware(app.logger)
+
+	// Setup route engine & middleware
+	e := echo.New()
+	e.Binder = httpDelivery.NewBinder()
+	httpDelivery.NewErrorHandler(e, app.logger)
+	// Open streams and sockets never finish on their own, they are ended so draining does not wait for them
+	e.Server.RegisterOnShutdown(app.hub.Close)
+	e.Server.RegisterOnShutdown(app.presence.Close)
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{AllowOrigins: configApp.Server.CORSOrigins}))
+	e.Use(appMiddleware.Tracing())
+	e.Use(appMiddleware.RequestID())
+	e.Use(appMiddleware.Metrics(app.metrics))
+	e.Use(appMiddleware.Logger())
+	e.Use(appMiddleware.Recover())
+
+	// Setup handler
+	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	if configApp.Media.Storage == "local" {
+		e.Static("/media", configApp.Media.LocalDir)
+	}
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "i am alive")
+	})
+	var healthChecks []health.Check
+	if app.db != nil {
+		healthChecks = append(healthChecks, health.DatabaseCheck(app.db, time.Duration(configApp.Health.DatabaseTimeout)*time.Millisecond))
+	}
+	if app.cache != nil {
+		healthChecks = append(healthChecks, health.CacheCheck(app.cache, time.Duration(configApp.Health.CacheTimeout)*time.Millisecond))
+	}
+	healthChecker := health.NewChecker(healthChecks...)
+	httpDelivery.NewHealthHandler(e, app.lifecycle, healthChecker)
+	e.GET("/metrics", echo.WrapHandler(app.metrics.Handler()))
+
+	httpDelivery.NewCarHandler(e, appMiddleware, app.carUC)
+	httpDelivery.NewCarStreamHandler(e, appMiddleware, app.carStreamUC, time.Duration(configApp.Stream.Heartbeat)*time.Second, app.logger)
+	httpDelivery.NewCarSocketHandler(e, appMiddleware, app.carStreamUC, app.presenceUC, app.reservationUC, configApp.Socket, configApp.Auth, configApp.Server.CORSOrigins, app.logger)
+	httpDelivery.NewCarImageHandler(e, appMiddleware, app.carImageUC, configApp.Media.MaxUploadSize)
+	httpDelivery.NewFeatureHandler(e, appMiddleware, app.featureUC)
+	httpDelivery.NewCatalogHandler(e, appMiddleware, app.catalogUC)
+	httpDelivery.NewWebhookHandler(e, appMiddleware, app.webhookUC, configApp.Auth.APIKeys)
+
+	executor, err := graphqlDelivery.NewExecutor(app.carUC, app.carRelationUC, graphqlDelivery.Limits{
+		MaxDepth:      configApp.GraphQL.MaxDepth,
+		MaxComplexity: configApp.GraphQL.MaxComplexity,
+	}, app.logger)
+	exitIfFailed(err)
+	httpDelivery.NewGraphQLHandler(e, appMiddleware, executor)
+
+	// Setup gRPC server, its interceptors do what the middleware does for Echo
+	interceptor := grpcDelivery.NewInterceptor(app.logger)
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		interceptor.Tracing(),
+		interceptor.RequestID(),
+		interceptor.Logger(),
+		interceptor.Recovery(),
+		interceptor.APIKey(configApp.Auth.APIKeys),
+	))
+	grpcDelivery.NewCarServer(grpcServer, app.carUC, app.logger)
+	grpcHealthServer := grpcHealth.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, grpcHealthServer)
+	if configApp.GRPC.Reflection {
+		reflection.Register(grpcServer)
+	}
+
+	// Start server, termination signals are caught from here on
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		if err := e.Start(":" + configApp.Server.Port); err != nil && err != http.ErrServerClosed {
+			app.logger.Fatalf("starting server: %v", err)
+		}
+	}()
+	grpcListener, err := net.Listen("tcp", ":"+configApp.GRPC.Port)
+	if err != nil {
+		app.logger.Fatalf("starting grpc server: %v", err)
+	}
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			app.logger.Fatalf("starting grpc server: %v", err)
+		}
+	}()
+	app.lifecycle.SetReady(true)
+
+	// Wait for a termination signal then drain
+	sig := <-quit
+	app.logger.Infof("received %s, draining", sig)
+
+	// Fail readiness first and give the load balancer time to stop routing to this instance
+	app.lifecycle.SetReady(false)
+	grpcHealthServer.Shutdown()
+	time.Sleep(time.Duration(configApp.Shutdown.Delay) * time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(configApp.Shutdown.Timeout)*time.Second)
+	defer cancel()
+
+	if err := e.Shutdown(ctx); err != nil {
+		app.logger.Errorf("draining server: %v", err)
+	}
+	if err := gracefulStop(ctx, grpcServer); err != nil {
+		app.logger.Errorf("draining grpc server: %v", err)
+	}
+	if err := app.lifecycle.Shutdown(ctx); err != nil {
+		app.logger.Error(err)
+	}
+}
+
+// gracefulStop waits for the pending calls of the gRPC server, those still running when ctx is
+// done are cancelled
+func gracefulStop(ctx context.Context, s *grpc.Server) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.Stop()
+		return ctx.Err()
+	}
+}
+
+// runWorker starts run in the background, it is cancelled on shutdown and awaited until the
+// shutdown timeout
+func (app *application) runWorker(name string, run func(ctx context.Context)) {
+	ctx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		run(ctx)
+		close(done)
+	}()
+	app.lifecycle.OnShutdown(name, func(ctx context.Context) error {
+		stop()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
@@ -1,37 +1,384 @@
 package config
 
 import (
-	"os"
-	"strconv"
+	"fmt"
+	"regexp"
+	"strings"
 
-	"github.com/joho/godotenv"
+	validation "github.com/go-ozzo/ozzo-validation"
 )
 
+// Config is the effective configuration of the API. Every setting starts from Default and can be
+// overridden, in order, by a YAML or TOML file, the environment and command line flags.
+// The env tag names the environment variable, the flag is derived from the file key,
+// e.g. database.max_open_conns becomes -database.max-open-conns.
 type Config struct {
-	ServerPORT     string
-	DatabaseURL    string
-	CacheURL       string
-	LoggerLevel    string
-	ContextTimeout int
+	Server   ServerConfig   `yaml:"server" toml:"server"`
+	Shutdown ShutdownConfig `yaml:"shutdown" toml:"shutdown"`
+	Database DatabaseConfig `yaml:"database" toml:"database"`
+	Cache    CacheConfig    `yaml:"cache" toml:"cache"`
+	Migrate  MigrateConfig  `yaml:"migrate" toml:"migrate"`
+	Health   HealthConfig   `yaml:"health" toml:"health"`
+	Logger   LoggerConfig   `yaml:"logger" toml:"logger"`
+	Tracing  TracingConfig  `yaml:"tracing" toml:"tracing"`
+	Media    MediaConfig    `yaml:"media" toml:"media"`
+	S3       S3Config       `yaml:"s3" toml:"s3"`
+	Catalog  CatalogConfig  `yaml:"catalog" toml:"catalog"`
+	Exchange ExchangeConfig `yaml:"exchange" toml:"exchange"`
+	Auth     AuthConfig     `yaml:"auth" toml:"auth"`
+	Events   EventsConfig   `yaml:"events" toml:"events"`
+	Webhooks WebhooksConfig `yaml:"webhooks" toml:"webhooks"`
+	Stream   StreamConfig   `yaml:"stream" toml:"stream"`
+	Socket   SocketConfig   `yaml:"socket" toml:"socket"`
+	GraphQL  GraphQLConfig  `yaml:"graphql" toml:"graphql"`
+	GRPC     GRPCConfig     `yaml:"grpc" toml:"grpc"`
 }
 
-// LoadConfig will load config from environment variable
-func LoadConfig() (config *Config) {
-	if err := godotenv.Load(); err != nil {
-		panic(err)
+type ServerConfig struct {
+	Port string `yaml:"port" toml:"port" env:"SERVER_PORT"`
+	// ContextTimeout bounds every usecase call, in seconds
+	ContextTimeout int      `yaml:"context_timeout" toml:"context_timeout" env:"CONTEXT_TIMEOUT"`
+	CORSOrigins    []string `yaml:"cors_origins" toml:"cors_origins" env:"CORS_ORIGINS"`
+}
+
+type ShutdownConfig struct {
+	// Timeout and Delay are in seconds
+	Timeout int `yaml:"timeout" toml:"timeout" env:"SHUTDOWN_TIMEOUT"`
+	Delay   int `yaml:"delay" toml:"delay" env:"SHUTDOWN_DELAY"`
+}
+
+type DatabaseConfig struct {
+	URL          string `yaml:"url" toml:"url" env:"DATABASE_URL" secret:"url"`
+	MaxOpenConns int    `yaml:"max_open_conns" toml:"max_open_conns" env:"DATABASE_MAX_OPEN_CONNS"`
+	MaxIdleConns int    `yaml:"max_idle_conns" toml:"max_idle_conns" env:"DATABASE_MAX_IDLE_CONNS"`
+	// ConnMaxLifetime is in seconds
+	ConnMaxLifetime int `yaml:"conn_max_lifetime" toml:"conn_max_lifetime" env:"DATABASE_CONN_MAX_LIFETIME"`
+	// ConnectTimeout is how long startup keeps retrying to reach the database, in seconds
+	ConnectTimeout int `yaml:"connect_timeout" toml:"connect_timeout" env:"DATABASE_CONNECT_TIMEOUT"`
+	// DeletedRetention is how long deleted cars are kept before purge-deleted removes them, in days
+	DeletedRetention int `yaml:"deleted_retention" toml:"deleted_retention" env:"DATABASE_DELETED_RETENTION"`
+}
+
+type CacheConfig struct {
+	URL      string `yaml:"url" toml:"url" env:"CACHE_URL" secret:"url"`
+	PoolSize int    `yaml:"pool_size" toml:"pool_size" env:"CACHE_POOL_SIZE"`
+	// CarsTTL is how long a car listing stays cached, in seconds
+	CarsTTL int `yaml:"cars_ttl" toml:"cars_ttl" env:"CACHE_CARS_TTL"`
+	// ConnectTimeout is how long startup keeps retrying to reach the cache, in seconds
+	ConnectTimeout int `yaml:"connect_timeout" toml:"connect_timeout" env:"CACHE_CONNECT_TIMEOUT"`
+}
+
+type MigrateConfig struct {
+	// Auto applies the pending migrations when the API starts
+	Auto bool `yaml:"auto" toml:"auto" env:"MIGRATE_AUTO"`
+	// LockTimeout is how long to wait for another replica to finish migrating, in seconds
+	LockTimeout int `yaml:"lock_timeout" toml:"lock_timeout" env:"MIGRATE_LOCK_TIMEOUT"`
+}
+
+type HealthConfig struct {
+	// DatabaseTimeout and CacheTimeout are in milliseconds
+	DatabaseTimeout int `yaml:"database_timeout" toml:"database_timeout" env:"HEALTH_DATABASE_TIMEOUT"`
+	CacheTimeout    int `yaml:"cache_timeout" toml:"cache_timeout" env:"HEALTH_CACHE_TIMEOUT"`
+}
+
+type LoggerConfig struct {
+	Level string `yaml:"level" toml:"level" env:"LOGGER_LEVEL"`
+}
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER"`
+	Endpoint    string  `yaml:"endpoint" toml:"endpoint" env:"TRACING_ENDPOINT"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+type MediaConfig struct {
+	Storage  string `yaml:"storage" toml:"storage" env:"MEDIA_STORAGE"`
+	LocalDir string `yaml:"local_dir" toml:"local_dir" env:"MEDIA_LOCAL_DIR"`
+	BaseURL  string `yaml:"base_url" toml:"base_url" env:"MEDIA_BASE_URL"`
+	// MaxUploadSize is in bytes
+	MaxUploadSize int64 `yaml:"max_upload_size" toml:"max_upload_size" env:"MEDIA_MAX_UPLOAD_SIZE"`
+	// MaxPixels caps the width times the height of an upload, checked before it is decoded
+	MaxPixels int64 `yaml:"max_pixels" toml:"max_pixels" env:"MEDIA_MAX_PIXELS"`
+}
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint" toml:"endpoint" env:"S3_ENDPOINT"`
+	Region    string `yaml:"region" toml:"region" env:"S3_REGION"`
+	Bucket    string `yaml:"bucket" toml:"bucket" env:"S3_BUCKET"`
+	AccessKey string `yaml:"access_key" toml:"access_key" env:"S3_ACCESS_KEY" secret:"true"`
+	SecretKey string `yaml:"secret_key" toml:"secret_key" env:"S3_SECRET_KEY" secret:"true"`
+	UseSSL    bool   `yaml:"use_ssl" toml:"use_ssl" env:"S3_USE_SSL"`
+}
+
+type CatalogConfig struct {
+	Seed bool `yaml:"seed" toml:"seed" env:"CATALOG_SEED"`
+}
+
+type ExchangeConfig struct {
+	RatesFile string `yaml:"rates_file" toml:"rates_file" env:"EXCHANGE_RATES_FILE"`
+}
+
+type AuthConfig struct {
+	// APIKeys are accepted in the X-Api-Key header of socket handshakes and webhook requests and
+	// the x-api-key metadata of gRPC writes, none disables the check
+	APIKeys []string `yaml:"api_keys" toml:"api_keys" env:"AUTH_API_KEYS" secret:"true"`
+	// RepKeys are the keys of the sales reps as NAME:KEY, a socket handshake carrying KEY views
+	// and reserves cars as NAME
+	RepKeys []string `yaml:"rep_keys" toml:"rep_keys" env:"AUTH_REP_KEYS" secret:"true"`
+}
+
+// Reps gives the names of the sales reps by their key
+func (c AuthConfig) Reps() map[string]string {
+	reps := map[string]string{}
+	for _, entry := range c.RepKeys {
+		if name, key, ok := strings.Cut(entry, ":"); ok {
+			reps[key] = name
+		}
 	}
+	return reps
+}
 
-	serverPORT := os.Getenv("SERVER_PORT")
-	databaseURL := os.Getenv("DATABASE_URL")
-	cacheURL := os.Getenv("CACHE_URL")
-	loggerLevel := os.Getenv("LOGGER_LEVEL")
-	contextTimeout, _ := strconv.Atoi(os.Getenv("CONTEXT_TIMEOUT"))
+type EventsConfig struct {
+	// Publisher is redis or memory, left empty it is redis unless cache.url is memory://
+	Publisher string `yaml:"publisher" toml:"publisher" env:"EVENTS_PUBLISHER"`
+	Stream    string `yaml:"stream" toml:"stream" env:"EVENTS_STREAM"`
+	// StreamMaxLen trims the stream to about that many entries, 0 keeps them all
+	StreamMaxLen int64 `yaml:"stream_max_len" toml:"stream_max_len" env:"EVENTS_STREAM_MAX_LEN"`
+	// RelayInterval is how often the outbox is polled, in milliseconds
+	RelayInterval  int `yaml:"relay_interval" toml:"relay_interval" env:"EVENTS_RELAY_INTERVAL"`
+	RelayBatchSize int `yaml:"relay_batch_size" toml:"relay_batch_size" env:"EVENTS_RELAY_BATCH_SIZE"`
+}
 
+type WebhooksConfig struct {
+	// Timeout bounds a delivery attempt, in seconds
+	Timeout int `yaml:"timeout" toml:"timeout" env:"WEBHOOKS_TIMEOUT"`
+	// MaxAttempts is the number of attempts before a delivery is dead
+	MaxAttempts int `yaml:"max_attempts" toml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS"`
+	// BackoffInitial is the wait after the first failed attempt, doubled after every other one up
+	// to BackoffMax, in seconds
+	BackoffInitial int `yaml:"backoff_initial" toml:"backoff_initial" env:"WEBHOOKS_BACKOFF_INITIAL"`
+	BackoffMax     int `yaml:"backoff_max" toml:"backoff_max" env:"WEBHOOKS_BACKOFF_MAX"`
+	// PollInterval is how often due deliveries are looked up, in milliseconds
+	PollInterval int `yaml:"poll_interval" toml:"poll_interval" env:"WEBHOOKS_POLL_INTERVAL"`
+	BatchSize    int `yaml:"batch_size" toml:"batch_size" env:"WEBHOOKS_BATCH_SIZE"`
+}
+
+type StreamConfig struct {
+	// Channel is the redis pub/sub channel sharing the inventory changes between instances
+	Channel string `yaml:"channel" toml:"channel" env:"STREAM_CHANNEL"`
+	// ReplaySize is the number of latest events kept for clients resuming with Last-Event-ID
+	ReplaySize int `yaml:"replay_size" toml:"replay_size" env:"STREAM_REPLAY_SIZE"`
+	// ClientBuffer is the number of events a slow client may lag behind before it is disconnected
+	ClientBuffer int `yaml:"client_buffer" toml:"client_buffer" env:"STREAM_CLIENT_BUFFER"`
+	// Heartbeat is how often an idle stream gets a comment so proxies keep it open, in seconds
+	Heartbeat int `yaml:"heartbeat" toml:"heartbeat" env:"STREAM_HEARTBEAT"`
+}
+
+type SocketConfig struct {
+	// Heartbeat is how often a socket is pinged, one missing its pong for two heartbeats is closed, in seconds
+	Heartbeat int `yaml:"heartbeat" toml:"heartbeat" env:"SOCKET_HEARTBEAT"`
+	// WriteTimeout bounds every write, a client not reading is closed after it, in seconds
+	WriteTimeout int `yaml:"write_timeout" toml:"write_timeout" env:"SOCKET_WRITE_TIMEOUT"`
+	// MaxMessageSize is the largest message accepted from a client, in bytes
+	MaxMessageSize int64 `yaml:"max_message_size" toml:"max_message_size" env:"SOCKET_MAX_MESSAGE_SIZE"`
+	// MaxSubscriptions is the number of cars a socket may subscribe to
+	MaxSubscriptions int `yaml:"max_subscriptions" toml:"max_subscriptions" env:"SOCKET_MAX_SUBSCRIPTIONS"`
+	// PresenceTTL is how long a presence lasts unless the socket refreshes it on its heartbeat, in seconds
+	PresenceTTL int `yaml:"presence_ttl" toml:"presence_ttl" env:"SOCKET_PRESENCE_TTL"`
+	// PresenceChannel is the redis pub/sub channel telling the instances which cars had their viewers change
+	PresenceChannel string `yaml:"presence_channel" toml:"presence_channel" env:"SOCKET_PRESENCE_CHANNEL"`
+	// ReservationTTL is how long a reservation holds a car unless its rep reserves it again, in seconds
+	ReservationTTL int `yaml:"reservation_ttl" toml:"reservation_ttl" env:"SOCKET_RESERVATION_TTL"`
+}
+
+type GraphQLConfig struct {
+	// MaxDepth is the deepest nesting of fields a query may select
+	MaxDepth int `yaml:"max_depth" toml:"max_depth" env:"GRAPHQL_MAX_DEPTH"`
+	// MaxComplexity bounds the fields a query may resolve, a page counts its fields once per car it may hold
+	MaxComplexity int `yaml:"max_complexity" toml:"max_complexity" env:"GRAPHQL_MAX_COMPLEXITY"`
+}
+
+type GRPCConfig struct {
+	// Port is where the gRPC server listens next to the HTTP one
+	Port string `yaml:"port" toml:"port" env:"GRPC_PORT"`
+	// Reflection lets tools such as grpcurl list the services without the proto files
+	Reflection bool `yaml:"reflection" toml:"reflection" env:"GRPC_REFLECTION"`
+}
+
+// Default gives the configuration used when no layer sets a value
+func Default() *Config {
 	return &Config{
-		ServerPORT:     serverPORT,
-		DatabaseURL:    databaseURL,
-		CacheURL:       cacheURL,
-		LoggerLevel:    loggerLevel,
-		ContextTimeout: contextTimeout,
+		Server: ServerConfig{
+			Port:           "8080",
+			ContextTimeout: 60,
+			CORSOrigins:    []string{"*"},
+		},
+		Shutdown: ShutdownConfig{
+			Timeout: 30,
+			Delay:   5,
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:     10,
+			MaxIdleConns:     5,
+			ConnMaxLifetime:  300,
+			ConnectTimeout:   30,
+			DeletedRetention: 30,
+		},
+		Cache: CacheConfig{
+			PoolSize:       10,
+			CarsTTL:        30,
+			ConnectTimeout: 30,
+		},
+		Migrate: MigrateConfig{
+			LockTimeout: 60,
+		},
+		Health: HealthConfig{
+			DatabaseTimeout: 1000,
+			CacheTimeout:    500,
+		},
+		Logger: LoggerConfig{
+			Level: "info",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			SampleRatio: 1,
+		},
+		Media: MediaConfig{
+			Storage:       "local",
+			LocalDir:      "./media",
+			BaseURL:       "http://localhost:8080/media",
+			MaxUploadSize: 10 << 20,
+			MaxPixels:     40_000_000,
+		},
+		S3: S3Config{
+			Region: "us-east-1",
+		},
+		Exchange: ExchangeConfig{
+			RatesFile: "./rates.json",
+		},
+		Events: EventsConfig{
+			Stream:         "carapi:events",
+			StreamMaxLen:   100000,
+			RelayInterval:  1000,
+			RelayBatchSize: 100,
+		},
+		Webhooks: WebhooksConfig{
+			Timeout:        10,
+			MaxAttempts:    8,
+			BackoffInitial: 30,
+			BackoffMax:     3600,
+			PollInterval:   1000,
+			BatchSize:      20,
+		},
+		Stream: StreamConfig{
+			Channel:      "carapi:cars",
+			ReplaySize:   1000,
+			ClientBuffer: 64,
+			Heartbeat:    15,
+		},
+		Socket: SocketConfig{
+			Heartbeat:        15,
+			WriteTimeout:     10,
+			MaxMessageSize:   4096,
+			MaxSubscriptions: 100,
+			PresenceTTL:      45,
+			PresenceChannel:  "carapi:presence",
+			ReservationTTL:   3600,
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:      8,
+			MaxComplexity: 1000,
+		},
+		GRPC: GRPCConfig{
+			Port: "9090",
+		},
+	}
+}
+
+// repKeyPattern is a rep key entry, the name goes up to the first colon
+var repKeyPattern = regexp.MustCompile(`^[^:]{1,64}:.+$`)
+
+// Validate checks the required settings and the ranges, errors are keyed by the file key
+func (c *Config) Validate() error {
+	errs := validation.Errors{
+		"server.port":            validation.Validate(c.Server.Port, validation.Required),
+		"server.context_timeout": validation.Validate(c.Server.ContextTimeout, validation.Required, validation.Min(1)),
+		"shutdown.timeout":       validation.Validate(c.Shutdown.Timeout, validation.Required, validation.Min(1)),
+		"shutdown.delay":         validation.Validate(c.Shutdown.Delay, validation.Min(0)),
+
+		"database.url":               validation.Validate(c.Database.URL, validation.Required),
+		"database.max_open_conns":    validation.Validate(c.Database.MaxOpenConns, validation.Required, validation.Min(1)),
+		"database.max_idle_conns":    validation.Validate(c.Database.MaxIdleConns, validation.Min(0), validation.Max(c.Database.MaxOpenConns)),
+		"database.conn_max_lifetime": validation.Validate(c.Database.ConnMaxLifetime, validation.Min(0)),
+		"database.connect_timeout":   validation.Validate(c.Database.ConnectTimeout, validation.Required, validation.Min(1)),
+		"database.deleted_retention": validation.Validate(c.Database.DeletedRetention, validation.Min(0)),
+
+		"cache.url":             validation.Validate(c.Cache.URL, validation.Required),
+		"cache.pool_size":       validation.Validate(c.Cache.PoolSize, validation.Required, validation.Min(1)),
+		"cache.cars_ttl":        validation.Validate(c.Cache.CarsTTL, validation.Required, validation.Min(1)),
+		"cache.connect_timeout": validation.Validate(c.Cache.ConnectTimeout, validation.Required, validation.Min(1)),
+
+		"migrate.lock_timeout": validation.Validate(c.Migrate.LockTimeout, validation.Required, validation.Min(1)),
+
+		"health.database_timeout": validation.Validate(c.Health.DatabaseTimeout, validation.Required, validation.Min(1)),
+		"health.cache_timeout":    validation.Validate(c.Health.CacheTimeout, validation.Required, validation.Min(1)),
+
+		"logger.level": validation.Validate(c.Logger.Level, validation.Required, validation.In("debug", "info", "warn", "error", "dpanic", "panic", "fatal")),
+
+		"tracing.exporter":     validation.Validate(c.Tracing.Exporter, validation.Required, validation.In("none", "stdout", "otlp")),
+		"tracing.sample_ratio": validation.Validate(c.Tracing.SampleRatio, validation.Min(0.0), validation.Max(1.0)),
+
+		"media.storage":         validation.Validate(c.Media.Storage, validation.Required, validation.In("local", "s3")),
+		"media.max_upload_size": validation.Validate(c.Media.MaxUploadSize, validation.Required, validation.Min(int64(1))),
+		"media.max_pixels":      validation.Validate(c.Media.MaxPixels, validation.Required, validation.Min(int64(1))),
+
+		"exchange.rates_file": validation.Validate(c.Exchange.RatesFile, validation.Required),
+
+		"auth.rep_keys": validation.Validate(c.Auth.RepKeys, validation.Each(validation.Match(repKeyPattern).Error("must be NAME:KEY, the name at most 64 characters"))),
+
+		"events.publisher":        validation.Validate(c.Events.Publisher, validation.In("redis", "memory")),
+		"events.stream":           validation.Validate(c.Events.Stream, validation.Required),
+		"events.stream_max_len":   validation.Validate(c.Events.StreamMaxLen, validation.Min(int64(0))),
+		"events.relay_interval":   validation.Validate(c.Events.RelayInterval, validation.Required, validation.Min(1)),
+		"events.relay_batch_size": validation.Validate(c.Events.RelayBatchSize, validation.Required, validation.Min(1)),
+
+		"webhooks.timeout":         validation.Validate(c.Webhooks.Timeout, validation.Required, validation.Min(1)),
+		"webhooks.max_attempts":    validation.Validate(c.Webhooks.MaxAttempts, validation.Required, validation.Min(1)),
+		"webhooks.backoff_initial": validation.Validate(c.Webhooks.BackoffInitial, validation.Required, validation.Min(1)),
+		"webhooks.backoff_max":     validation.Validate(c.Webhooks.BackoffMax, validation.Required, validation.Min(c.Webhooks.BackoffInitial)),
+		"webhooks.poll_interval":   validation.Validate(c.Webhooks.PollInterval, validation.Required, validation.Min(1)),
+		"webhooks.batch_size":      validation.Validate(c.Webhooks.BatchSize, validation.Required, validation.Min(1)),
+
+		"stream.channel":       validation.Validate(c.Stream.Channel, validation.Required),
+		"stream.replay_size":   validation.Validate(c.Stream.ReplaySize, validation.Min(0)),
+		"stream.client_buffer": validation.Validate(c.Stream.ClientBuffer, validation.Required, validation.Min(1)),
+		"stream.heartbeat":     validation.Validate(c.Stream.Heartbeat, validation.Required, validation.Min(1)),
+
+		"socket.heartbeat":         validation.Validate(c.Socket.Heartbeat, validation.Required, validation.Min(1)),
+		"socket.write_timeout":     validation.Validate(c.Socket.WriteTimeout, validation.Required, validation.Min(1)),
+		"socket.max_message_size":  validation.Validate(c.Socket.MaxMessageSize, validation.Required, validation.Min(int64(1))),
+		"socket.max_subscriptions": validation.Validate(c.Socket.MaxSubscriptions, validation.Required, validation.Min(1)),
+		// a presence outlives the heartbeat refreshing it
+		"socket.presence_ttl":     validation.Validate(c.Socket.PresenceTTL, validation.Required, validation.Min(c.Socket.Heartbeat+1)),
+		"socket.presence_channel": validation.Validate(c.Socket.PresenceChannel, validation.Required),
+		"socket.reservation_ttl":  validation.Validate(c.Socket.ReservationTTL, validation.Required, validation.Min(1)),
+
+		"graphql.max_depth":      validation.Validate(c.GraphQL.MaxDepth, validation.Required, validation.Min(1)),
+		"graphql.max_complexity": validation.Validate(c.GraphQL.MaxComplexity, validation.Required, validation.Min(1)),
+
+		"grpc.port": validation.Validate(c.GRPC.Port, validation.Required, validation.NotIn(c.Server.Port).Error("must differ from server.port")),
+	}
+
+	switch c.Media.Storage {
+	case "local":
+		errs["media.local_dir"] = validation.Validate(c.Media.LocalDir, validation.Required)
+	case "s3":
+		errs["s3.endpoint"] = validation.Validate(c.S3.Endpoint, validation.Required)
+		errs["s3.bucket"] = validation.Validate(c.S3.Bucket, validation.Required)
+	}
+
+	if err := errs.Filter(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
 	}
+	return nil
 }
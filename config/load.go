@@ -0,0 +1,178 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the configuration file when the -config flag is not given
+const FileEnv = "CONFIG_FILE"
+
+// Load will build the configuration from the defaults, the file, the environment, a .env file
+// included, and the flags in args, then validate it. flag.ErrHelp is returned when -h is asked.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("carapi", flag.ContinueOnError)
+	file := fs.String("config", "", "YAML or TOML configuration file, same as $"+FileEnv)
+	all := settings(cfg)
+	byFlag := map[string]setting{}
+	flags := map[string]*string{}
+	for _, f := range all {
+		byFlag[f.flag] = f
+		flags[f.flag] = fs.String(f.flag, "", "same as $"+f.env)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("loading .env: %w", err)
+	}
+
+	if *file == "" {
+		*file = os.Getenv(FileEnv)
+	}
+	if *file != "" {
+		if err := decodeFile(*file, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range all {
+		if value, ok := os.LookupEnv(f.env); ok {
+			if err := f.set(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.env, err)
+			}
+		}
+	}
+
+	var err error
+	fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == "config" || err != nil {
+			return
+		}
+		if setErr := byFlag[fl.Name].set(*flags[fl.Name]); setErr != nil {
+			err = fmt.Errorf("-%s: %w", fl.Name, setErr)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decodeFile reads the file in the format given by its extension, unknown keys are rejected
+// so a typo does not silently keep the default
+func decodeFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("decoding %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return fmt.Errorf("decoding %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("decoding %s: unknown key %q", path, undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+
+	return nil
+}
+
+// setting is a single field of a section with the names it can be set by
+type setting struct {
+	key    string
+	env    string
+	flag   string
+	secret string
+	value  reflect.Value
+}
+
+// settings lists every field of every section in declaration order
+func settings(cfg *Config) []setting {
+	var list []setting
+	root := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Field(i)
+		sectionKey := root.Type().Field(i).Tag.Get("yaml")
+		for j := 0; j < section.NumField(); j++ {
+			field := section.Type().Field(j)
+			key := sectionKey + "." + field.Tag.Get("yaml")
+			list = append(list, setting{
+				key:    key,
+				env:    field.Tag.Get("env"),
+				flag:   strings.ReplaceAll(key, "_", "-"),
+				secret: field.Tag.Get("secret"),
+				value:  section.Field(j),
+			})
+		}
+	}
+	return list
+}
+
+// set parses the raw value of an environment variable or a flag into the field,
+// lists are comma separated
+func (s setting) set(raw string) error {
+	switch s.value.Kind() {
+	case reflect.String:
+		s.value.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		s.value.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		s.value.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		s.value.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		s.value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", s.value.Type())
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"io"
+	"net/url"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[redacted]"
+
+// Redacted gives a copy of the configuration that is safe to show, secrets are masked
+// and only the password of a connection URL is
+func (c *Config) Redacted() *Config {
+	cfg := *c
+	for _, s := range settings(&cfg) {
+		switch {
+		case s.secret == "":
+		case s.value.Kind() == reflect.Slice:
+			masked := make([]string, s.value.Len())
+			for i := range masked {
+				masked[i] = redacted
+			}
+			s.value.Set(reflect.ValueOf(masked))
+		case s.value.String() == "":
+		case s.secret == "url":
+			s.value.SetString(redactURL(s.value.String()))
+		default:
+			s.value.SetString(redacted)
+		}
+	}
+	return &cfg
+}
+
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	return u.Redacted()
+}
+
+// Print writes the effective configuration as YAML with the secrets redacted,
+// the output has the layout of a configuration file
+func Print(w io.Writer, cfg *Config) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(cfg.Redacted()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
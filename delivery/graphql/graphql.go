@@ -0,0 +1,125 @@
+// Package graphql serves the cars over GraphQL, the resolvers go through the same usecases and
+// request validation as the REST API.
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	"carApi/utils/logger"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Executor runs the GraphQL requests against the car schema
+type Executor struct {
+	schema        graphql.Schema
+	carRelationUC usecase.CarRelationUsecase
+	limits        Limits
+	logger        logger.Logger
+}
+
+// NewExecutor builds the schema, queries going over the limits are refused and the errors of the
+// usecases carrying no status are logged
+func NewExecutor(carUC usecase.CarUsecase, carRelationUC usecase.CarRelationUsecase, limits Limits, logger logger.Logger) (*Executor, error) {
+	schema, err := newSchema(carUC, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Executor{
+		schema:        schema,
+		carRelationUC: carRelationUC,
+		limits:        limits,
+		logger:        logger,
+	}, nil
+}
+
+// Execute parses, validates and measures the query before running it. A result without data
+// means the query did not run, its errors say why.
+func (ex *Executor) Execute(ctx context.Context, req request.GraphQLReq) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(req.Query),
+			Name: "GraphQL request",
+		}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: formatErrors(gqlerrors.FormatErrors(err))}
+	}
+
+	validated := graphql.ValidateDocument(&ex.schema, doc, nil)
+	if !validated.IsValid {
+		return &graphql.Result{Errors: formatErrors(validated.Errors)}
+	}
+
+	if err := ex.limits.check(ex.schema, doc, req.OperationName, req.Variables); err != nil {
+		return &graphql.Result{Errors: formatErrors(gqlerrors.FormatErrors(err))}
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        ex.schema,
+		Root:          root{acceptLanguage: req.AcceptLanguage},
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(ctx, ex.carRelationUC, ex.logger),
+	})
+	result.Errors = formatErrors(result.Errors)
+	return result
+}
+
+// formatErrors gives the errors carrying a status the shape of the REST errors in their
+// extensions along with the code of the catalog, their message is the details when those are a sentence
+func formatErrors(errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	for i, formatted := range errs {
+		httpErr, ok := unwrapHttpErr(formatted.OriginalError())
+		if !ok {
+			continue
+		}
+
+		errs[i].Message = httpErr.Error()
+		errs[i].Extensions = map[string]interface{}{
+			"status":  httpErr.Status(),
+			"details": httpErr.Details(),
+		}
+		if code := httpErr.Code(); code != "" {
+			errs[i].Extensions["code"] = code
+		}
+		var err utils.HttpError
+		if errors.As(httpErr, &err) {
+			errs[i].Message = err.ErrError
+			errs[i].Extensions["error"] = err.ErrError
+		}
+		if details, ok := httpErr.Details().(string); ok {
+			errs[i].Message = details
+		}
+	}
+	return errs
+}
+
+// unwrapHttpErr finds the error a resolver gave back, the executor wraps it once more when it
+// comes from a thunk
+func unwrapHttpErr(err error) (utils.HttpErr, bool) {
+	for err != nil {
+		var httpErr utils.HttpErr
+		if errors.As(err, &httpErr) {
+			return httpErr, true
+		}
+
+		switch e := err.(type) {
+		case gqlerrors.FormattedError:
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			err = e.OriginalError
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,304 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	graphqlDelivery "carApi/delivery/graphql"
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/transport/request"
+	"carApi/utils"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newExecutor(t *testing.T, carUC *mocks.CarUsecase, carRelationUC *mocks.CarRelationUsecase, limits graphqlDelivery.Limits) *graphqlDelivery.Executor {
+	executor, err := graphqlDelivery.NewExecutor(carUC, carRelationUC, limits, new(mocks.Logger))
+	require.NoError(t, err)
+	return executor
+}
+
+func resultJSON(t *testing.T, result *graphql.Result) string {
+	body, err := json.Marshal(result)
+	require.NoError(t, err)
+	return string(body)
+}
+
+var defaultLimits = graphqlDelivery.Limits{MaxDepth: 8, MaxComplexity: 1000}
+
+func TestExecutor_Query(t *testing.T) {
+	changedAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	cars := []entity.Car{
+		{ID: 1, Make: "Toyota", Price: entity.Money{Amount: 1500000, Currency: "USD"}},
+		{ID: 2, Make: "Honda", Price: entity.Money{Amount: 9000000000, Currency: "USD"}},
+		{ID: 3, Make: "Mazda"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockCarRelationUC := new(mocks.CarRelationUsecase)
+		mileageMin := 16093
+		mockCarUC.On("FetchPage", mock.Anything,
+			entity.CarFilter{Features: []string{"sunroof"}, FeatureMatch: entity.FeatureMatchAny, MileageMin: &mileageMin},
+			entity.Page{Limit: 2},
+			entity.CarDisplay{Currency: "EUR", MileageUnit: entity.MileageUnitMiles}).
+			Return(cars[:2], int64(len(cars)), nil).Once()
+		// the relations of every car on the page are fetched in one call each
+		mockCarRelationUC.On("Images", mock.Anything, []int64{1, 2}).Return(map[int64][]entity.CarImage{
+			1: {{ID: 7, CarID: 1, URL: "/media/7.jpg", Thumbnails: map[string]string{"small": "/media/7-s.jpg", "large": "/media/7-l.jpg"}}},
+			2: {},
+		}, nil).Once()
+		mockCarRelationUC.On("Features", mock.Anything, []int64{1, 2}).Return(map[int64][]entity.Feature{
+			1: {{ID: 3, Code: "sunroof", Name: "Sunroof"}},
+			2: {{ID: 3, Code: "sunroof", Name: "Sunroof"}},
+		}, nil).Once()
+		mockCarRelationUC.On("PriceHistory", mock.Anything, []int64{1, 2}).Return(map[int64][]entity.PricePoint{
+			1: {{ID: 1, CarID: 1, Price: entity.Money{Amount: 1500000, Currency: "USD"}, ChangedAt: changedAt}},
+			2: {},
+		}, nil).Once()
+
+		result := newExecutor(t, mockCarUC, mockCarRelationUC, defaultLimits).Execute(context.TODO(), request.GraphQLReq{
+			Query: `query ($limit: Int) {
+				cars(features: ["sunroof"], featuresMatch: "any", mileageMin: 10000, currency: "eur", limit: $limit) {
+					total limit offset
+					items {
+						id make price { amount currency }
+						images { id thumbnails { size url } }
+						features { code }
+						priceHistory { price { amount } changedAt }
+					}
+				}
+			}`,
+			Variables:      map[string]interface{}{"limit": float64(2)},
+			AcceptLanguage: "en-US",
+		})
+
+		assert.Empty(t, result.Errors)
+		assert.JSONEq(t, `{"data":{"cars":{"total":3,"limit":2,"offset":0,"items":[
+			{"id":"1","make":"Toyota","price":{"amount":1500000,"currency":"USD"},
+				"images":[{"id":"7","thumbnails":[{"size":"large","url":"/media/7-l.jpg"},{"size":"small","url":"/media/7-s.jpg"}]}],
+				"features":[{"code":"sunroof"}],
+				"priceHistory":[{"price":{"amount":1500000},"changedAt":"2022-01-02T03:04:05Z"}]},
+			{"id":"2","make":"Honda","price":{"amount":9000000000,"currency":"USD"},
+				"images":[],"features":[{"code":"sunroof"}],"priceHistory":[]}
+		]}}}`, resultJSON(t, result))
+		mockCarUC.AssertExpectations(t)
+		mockCarRelationUC.AssertExpectations(t)
+	})
+
+	t.Run("error-relation", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockCarRelationUC := new(mocks.CarRelationUsecase)
+		mockCarUC.On("GetByID", mock.Anything, int64(1), entity.CarDisplay{}).
+			Return(cars[0], nil).Once()
+		mockCarRelationUC.On("Features", mock.Anything, []int64{1}).Return(nil, errors.New("unexpected error")).Once()
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Errorw", "INTERNAL ERROR", "request_id", "", "error", "unexpected error").Once()
+
+		executor, err := graphqlDelivery.NewExecutor(mockCarUC, mockCarRelationUC, defaultLimits, mockLogger)
+		require.NoError(t, err)
+		result := executor.Execute(context.TODO(), request.GraphQLReq{
+			Query: `{ car(id: "1") { make features { code } } }`,
+		})
+
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "internal server error", result.Errors[0].Message)
+		assert.Equal(t, []interface{}{"car", "features"}, result.Errors[0].Path)
+		assert.Equal(t, 500, result.Errors[0].Extensions["status"])
+		mockCarRelationUC.AssertExpectations(t)
+		mockLogger.AssertExpectations(t)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockCarRelationUC := new(mocks.CarRelationUsecase)
+
+		result := newExecutor(t, mockCarUC, mockCarRelationUC, defaultLimits).Execute(context.TODO(), request.GraphQLReq{
+			Query: `{ cars(limit: 101, mileageUnit: "ly") { total } }`,
+		})
+
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, 400, result.Errors[0].Extensions["status"])
+		assert.Contains(t, resultJSON(t, result), `{"field":"mileage_unit","error":"must be a valid value"}`)
+		mockCarUC.AssertNotCalled(t, "FetchPage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-limit-zero", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockCarRelationUC := new(mocks.CarRelationUsecase)
+
+		// a limit of 0 would be no limit at all to the repositories
+		result := newExecutor(t, mockCarUC, mockCarRelationUC, defaultLimits).Execute(context.TODO(), request.GraphQLReq{
+			Query: `{ cars(limit: 0) { total } }`,
+		})
+
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, 400, result.Errors[0].Extensions["status"])
+		assert.Contains(t, resultJSON(t, result), `{"field":"limit","error":"cannot be blank"}`)
+		mockCarUC.AssertNotCalled(t, "FetchPage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-not-found", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockCarRelationUC := new(mocks.CarRelationUsecase)
+
+		result := newExecutor(t, mockCarUC, mockCarRelationUC, defaultLimits).Execute(context.TODO(), request.GraphQLReq{
+			Query: `{ car(id: "one") { make } }`,
+		})
+
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "car not found", result.Errors[0].Message)
+		assert.Equal(t, 404, result.Errors[0].Extensions["status"])
+		assert.Equal(t, utils.CodeCarNotFound, result.Errors[0].Extensions["code"])
+		mockCarUC.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestExecutor_Limits(t *testing.T) {
+	tests := map[string]struct {
+		query     string
+		variables map[string]interface{}
+		err       string
+	}{
+		"depth": {
+			query: `{ cars { items { priceHistory { price { amount } } } } }`,
+			err:   "query depth 5 exceeds the limit of 4",
+		},
+		"depth-fragment": {
+			query: `{ car(id: "1") { ...history } } fragment history on Car { priceHistory { price { amount } } }`,
+			err:   "query depth 4 exceeds the limit of 3",
+		},
+		"complexity-default-limit": {
+			// 1 for cars and its selection of 3 fields once per car of the default page of 20
+			query: `{ cars { items { id make } } }`,
+			err:   "query complexity 61 exceeds the limit of 30",
+		},
+		"complexity-variable": {
+			query:     `query ($limit: Int) { cars(limit: $limit) { items { id } } }`,
+			variables: map[string]interface{}{"limit": float64(40)},
+			err:       "query complexity 81 exceeds the limit of 30",
+		},
+		"complexity-zero-limit": {
+			// a limit of 0 counts as the largest page of 100
+			query: `{ cars(limit: 0) { items { id } } }`,
+			err:   "query complexity 201 exceeds the limit of 30",
+		},
+		"within": {
+			query: `{ cars(limit: 5) { items { id images { url } } } __schema { types { name } } }`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockCarUC := new(mocks.CarUsecase)
+			mockCarRelationUC := new(mocks.CarRelationUsecase)
+			limits := graphqlDelivery.Limits{MaxDepth: 4, MaxComplexity: 30}
+			if name == "depth-fragment" {
+				limits.MaxDepth = 3
+			}
+			if tt.err == "" {
+				mockCarUC.On("FetchPage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]entity.Car{}, int64(0), nil).Once()
+			}
+
+			result := newExecutor(t, mockCarUC, mockCarRelationUC, limits).Execute(context.TODO(), request.GraphQLReq{
+				Query:     tt.query,
+				Variables: tt.variables,
+			})
+
+			if tt.err == "" {
+				assert.Empty(t, result.Errors)
+				mockCarUC.AssertExpectations(t)
+				return
+			}
+			require.Len(t, result.Errors, 1)
+			assert.Nil(t, result.Data)
+			assert.Equal(t, tt.err, result.Errors[0].Message)
+			assert.Equal(t, 400, result.Errors[0].Extensions["status"])
+			mockCarUC.AssertNotCalled(t, "FetchPage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockCarUC.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestExecutor_Mutation(t *testing.T) {
+	input := map[string]interface{}{
+		"make": "Toyota", "model": "Corolla", "package": "SE", "color": "Red", "year": float64(2020),
+		"category": "Sedan", "mileage": float64(100), "mileageUnit": "mi",
+		"price":          map[string]interface{}{"amount": float64(1500000), "currency": "USD"},
+		"identification": "JTD123",
+	}
+	createCarReq := request.CreateCarReq{
+		Make: "Toyota", Model: "Corolla", Package: "SE", Color: "Red", Year: 2020,
+		Category: "Sedan", Mileage: 100, MileageUnit: "mi",
+		Price:          request.MoneyReq{Amount: 1500000, Currency: "USD"},
+		Identification: "JTD123",
+	}
+
+	t.Run("success-create", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockCarUC.On("Create", mock.Anything, &createCarReq).Return(nil).Once()
+
+		result := newExecutor(t, mockCarUC, new(mocks.CarRelationUsecase), defaultLimits).Execute(context.TODO(), request.GraphQLReq{
+			Query:     `mutation ($input: CarInput!) { createCar(input: $input) { message } }`,
+			Variables: map[string]interface{}{"input": input},
+		})
+
+		assert.Empty(t, result.Errors)
+		assert.JSONEq(t, `{"data":{"createCar":{"message":"car created"}}}`, resultJSON(t, result))
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("success-update", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		updateCarReq := request.UpdateCarReq(createCarReq)
+		mockCarUC.On("Update", mock.Anything, int64(1), &updateCarReq).Return(nil).Once()
+		mockCarUC.On("GetByID", mock.Anything, int64(1), entity.CarDisplay{}).Return(entity.Car{ID: 1, Make: "Toyota"}, nil).Once()
+
+		result := newExecutor(t, mockCarUC, new(mocks.CarRelationUsecase), defaultLimits).Execute(context.TODO(), request.GraphQLReq{
+			Query:     `mutation ($input: CarInput!) { updateCar(id: "1", input: $input) { id make } }`,
+			Variables: map[string]interface{}{"input": input},
+		})
+
+		assert.Empty(t, result.Errors)
+		assert.JSONEq(t, `{"data":{"updateCar":{"id":"1","make":"Toyota"}}}`, resultJSON(t, result))
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+
+		result := newExecutor(t, mockCarUC, new(mocks.CarRelationUsecase), defaultLimits).Execute(context.TODO(), request.GraphQLReq{
+			Query: `mutation { createCar(input: {make: "Toyota"}) { message } }`,
+		})
+
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, 400, result.Errors[0].Extensions["status"])
+		assert.Contains(t, resultJSON(t, result), `{"field":"model","error":"cannot be blank"}`)
+		mockCarUC.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-usecase", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockCarUC.On("Delete", mock.Anything, int64(9)).Return(errors.New("unexpected error")).Once()
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Errorw", "INTERNAL ERROR", "request_id", "", "error", "unexpected error").Once()
+
+		executor, err := graphqlDelivery.NewExecutor(mockCarUC, new(mocks.CarRelationUsecase), defaultLimits, mockLogger)
+		require.NoError(t, err)
+		result := executor.Execute(context.TODO(), request.GraphQLReq{
+			Query: `mutation { deleteCar(id: "9") { message } }`,
+		})
+
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "internal server error", result.Errors[0].Message)
+		assert.Equal(t, 500, result.Errors[0].Extensions["status"])
+		mockCarUC.AssertExpectations(t)
+		mockLogger.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,150 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"carApi/utils"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bound the cost of a query, one going over them is refused before anything is resolved
+type Limits struct {
+	// MaxDepth is the deepest nesting of fields
+	MaxDepth int
+	// MaxComplexity is the number of fields, those below a field taking a limit argument count
+	// once per item the limit lets through
+	MaxComplexity int
+}
+
+// check measures the operation of the document that is about to run, the introspection
+// fields are left out as the schema they describe is bounded
+func (l Limits) check(schema graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) error {
+	m := measure{
+		schema:    schema,
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+	}
+
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			m.fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	// the executor reports an operation that cannot be found
+	if operation == nil {
+		return nil
+	}
+
+	root := schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	depth, complexity := m.selections(root, operation.SelectionSet)
+	if depth > l.MaxDepth {
+		return utils.NewError(utils.CodeQueryTooDeep, fmt.Sprintf("query depth %d exceeds the limit of %d", depth, l.MaxDepth))
+	}
+	if complexity > l.MaxComplexity {
+		return utils.NewError(utils.CodeQueryTooComplex, fmt.Sprintf("query complexity %d exceeds the limit of %d", complexity, l.MaxComplexity))
+	}
+	return nil
+}
+
+type measure struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selections gives the depth and the complexity of a selection set on the parent type, the
+// document is validated so fragments do not cycle
+func (m measure) selections(parent graphql.Type, set *ast.SelectionSet) (depth int, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+
+			var child graphql.Type
+			items := 1
+			if field := fieldDefinition(parent, selection.Name.Value); field != nil {
+				child = graphql.GetNamed(field.Type).(graphql.Type)
+				items = m.limit(field, selection)
+			}
+
+			d, c = m.selections(child, selection.SelectionSet)
+			d, c = d+1, 1+items*c
+		case *ast.InlineFragment:
+			d, c = m.selections(m.condition(parent, selection.TypeCondition), selection.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := m.fragments[selection.Name.Value]; ok {
+				d, c = m.selections(m.condition(parent, fragment.TypeCondition), fragment.SelectionSet)
+			}
+		}
+
+		depth = max(depth, d)
+		complexity += c
+	}
+	return depth, complexity
+}
+
+// limit gives the number of items the limit argument of the field lets through, 1 for a field
+// taking none. A limit left at 0 counts as the largest page, whatever the resolver makes of it.
+func (m measure) limit(field *graphql.FieldDefinition, selection *ast.Field) int {
+	for _, argument := range field.Args {
+		if argument.Name() != "limit" {
+			continue
+		}
+
+		limit, _ := argument.DefaultValue.(int)
+		for _, given := range selection.Arguments {
+			if given.Name.Value != "limit" {
+				continue
+			}
+
+			switch value := given.Value.(type) {
+			case *ast.IntValue:
+				limit, _ = strconv.Atoi(value.Value)
+			case *ast.Variable:
+				if variable, ok := m.variables[value.Name.Value].(float64); ok {
+					limit = int(variable)
+				}
+			}
+		}
+		if limit <= 0 {
+			return maxPageLimit
+		}
+		return limit
+	}
+	return 1
+}
+
+func (m measure) condition(parent graphql.Type, condition *ast.Named) graphql.Type {
+	if condition == nil {
+		return parent
+	}
+	return m.schema.Type(condition.Name.Value)
+}
+
+func fieldDefinition(parent graphql.Type, name string) *graphql.FieldDefinition {
+	object, ok := parent.(*graphql.Object)
+	if !ok {
+		return nil
+	}
+	return object.Fields()[name]
+}
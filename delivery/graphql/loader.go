@@ -0,0 +1,96 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"carApi/entity"
+	"carApi/usecase"
+	"carApi/utils/logger"
+)
+
+// loader batches the relation of the cars resolved at the same level of a query. Load only
+// queues the car and gives a thunk, the executor resolves a level before calling its thunks so
+// the first one fetches every queued car at once and the others read the result.
+type loader[T any] struct {
+	fetch  func(ctx context.Context, carIDs []int64) (map[int64][]T, error)
+	logger logger.Logger
+
+	mu      sync.Mutex
+	queued  []int64
+	loaded  map[int64][]T
+	failed  map[int64]error
+	pending map[int64]bool
+}
+
+func newLoader[T any](fetch func(ctx context.Context, carIDs []int64) (map[int64][]T, error), logger logger.Logger) *loader[T] {
+	return &loader[T]{
+		fetch:   fetch,
+		logger:  logger,
+		loaded:  map[int64][]T{},
+		failed:  map[int64]error{},
+		pending: map[int64]bool{},
+	}
+}
+
+// Load gives a thunk resolving to the relation of the car, a car loaded before is not fetched again
+func (l *loader[T]) Load(ctx context.Context, carID int64) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, ok := l.loaded[carID]; !ok && !l.pending[carID] {
+		l.pending[carID] = true
+		l.queued = append(l.queued, carID)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.queued) > 0 {
+			l.flush(ctx)
+		}
+		if err := l.failed[carID]; err != nil {
+			return nil, err
+		}
+		return l.loaded[carID], nil
+	}
+}
+
+// flush fetches the queued cars, a failure is given to every car of the batch
+func (l *loader[T]) flush(ctx context.Context) {
+	carIDs := l.queued
+	l.queued = nil
+
+	values, err := l.fetch(ctx, carIDs)
+	for _, carID := range carIDs {
+		delete(l.pending, carID)
+		if err != nil {
+			l.failed[carID] = resolverError(ctx, l.logger, err)
+			continue
+		}
+		l.loaded[carID] = values[carID]
+	}
+}
+
+// loaders are the loaders of one request, they are not shared so a request never reads what
+// another one loaded
+type loaders struct {
+	images       *loader[entity.CarImage]
+	features     *loader[entity.Feature]
+	priceHistory *loader[entity.PricePoint]
+}
+
+type loadersKey struct{}
+
+// withLoaders gives a context carrying new loaders over the relations
+func withLoaders(ctx context.Context, carRelationUC usecase.CarRelationUsecase, logger logger.Logger) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		images:       newLoader(carRelationUC.Images, logger),
+		features:     newLoader(carRelationUC.Features, logger),
+		priceHistory: newLoader(carRelationUC.PriceHistory, logger),
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,481 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"carApi/entity"
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	"carApi/utils/logger"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// defaultPageLimit is the number of cars in a page when the query sets no limit
+const defaultPageLimit = 20
+
+// maxPageLimit is the largest limit a page takes
+const maxPageLimit = 100
+
+// root is the value the operations resolve from, it carries what the request says about the client
+type root struct {
+	acceptLanguage string
+}
+
+// carPage is a page of the car listing, Total counts the cars of every page
+type carPage struct {
+	Items  []entity.Car
+	Total  int
+	Limit  int
+	Offset int
+}
+
+// message is the outcome of a mutation giving no car back, it mirrors the REST response
+type message struct {
+	Message string
+}
+
+type thumbnail struct {
+	Size string
+	URL  string
+}
+
+var longType = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Long",
+	Description: "A signed 64-bit integer, money amounts are minor units which may not fit an Int.",
+	Serialize: func(value interface{}) interface{} {
+		switch value := value.(type) {
+		case int64:
+			return value
+		case int:
+			return int64(value)
+		}
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		switch value := value.(type) {
+		case int64:
+			return value
+		case int:
+			return int64(value)
+		case float64:
+			if value == math.Trunc(value) && math.Abs(value) <= 1<<53 {
+				return int64(value)
+			}
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		if value, ok := valueAST.(*ast.IntValue); ok {
+			if n, err := strconv.ParseInt(value.Value, 10, 64); err == nil {
+				return n
+			}
+		}
+		return nil
+	},
+})
+
+var moneyType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Money",
+	Description: "An amount in the minor units of its currency, e.g. cents for USD.",
+	Fields: graphql.Fields{
+		"amount":   &graphql.Field{Type: graphql.NewNonNull(longType)},
+		"currency": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var distanceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Distance",
+	Description: "A mileage expressed in a given unit.",
+	Fields: graphql.Fields{
+		"value": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"unit":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var thumbnailType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Thumbnail",
+	Fields: graphql.Fields{
+		"size": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"url":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var carImageType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CarImage",
+	Fields: graphql.Fields{
+		"id":  &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"url": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"thumbnails": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(thumbnailType))),
+			Description: "The thumbnails ordered by size name.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				image := p.Source.(entity.CarImage)
+				thumbnails := []thumbnail{}
+				for size, url := range image.Thumbnails {
+					thumbnails = append(thumbnails, thumbnail{Size: size, URL: url})
+				}
+				sort.Slice(thumbnails, func(i, j int) bool { return thumbnails[i].Size < thumbnails[j].Size })
+				return thumbnails, nil
+			},
+		},
+		"contentType": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"size":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "In bytes."},
+		"width":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"height":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"position":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"isPrimary":   &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"createdAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		"updatedAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+var featureType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Feature",
+	Fields: graphql.Fields{
+		"id":   &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"code": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var pricePointType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PricePoint",
+	Description: "The price a car is listed at from changedAt until the next point.",
+	Fields: graphql.Fields{
+		"price":     &graphql.Field{Type: graphql.NewNonNull(moneyType)},
+		"changedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+var carType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Car",
+	Fields: graphql.Fields{
+		"id":             &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"make":           &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"model":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"package":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"color":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"year":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"category":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"mileage":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "In kilometers."},
+		"displayMileage": &graphql.Field{Type: distanceType, Description: "The mileage in the requested unit."},
+		"price":          &graphql.Field{Type: graphql.NewNonNull(moneyType)},
+		"displayPrice":   &graphql.Field{Type: moneyType, Description: "The price converted to the requested currency."},
+		"identification": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"createdAt":      &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		"updatedAt":      &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		"images": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(carImageType))),
+			Description: "The images in display order.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return loadersFrom(p.Context).images.Load(p.Context, p.Source.(entity.Car).ID), nil
+			},
+		},
+		"features": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(featureType))),
+			Description: "The features ordered by code.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return loadersFrom(p.Context).features.Load(p.Context, p.Source.(entity.Car).ID), nil
+			},
+		},
+		"priceHistory": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(pricePointType))),
+			Description: "The prices the car was listed at, from the oldest.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return loadersFrom(p.Context).priceHistory.Load(p.Context, p.Source.(entity.Car).ID), nil
+			},
+		},
+	},
+})
+
+var carPageType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CarPage",
+	Fields: graphql.Fields{
+		"items":  &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(carType)))},
+		"total":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "The number of cars on every page."},
+		"limit":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"offset": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+var messageType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Message",
+	Fields: graphql.Fields{
+		"message": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var moneyInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "MoneyInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"amount":   &graphql.InputObjectFieldConfig{Type: longType},
+		"currency": &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+// carInputType leaves every field nullable, the car request validation reports the missing ones
+// as the REST API does
+var carInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CarInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"make":           &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"model":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"package":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"color":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"year":           &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"category":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"mileage":        &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"mileageUnit":    &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "km or mi, kilometers when not given."},
+		"price":          &graphql.InputObjectFieldConfig{Type: moneyInputType},
+		"identification": &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+// displayArgs are the arguments choosing how the cars are presented
+var displayArgs = graphql.FieldConfigArgument{
+	"currency":    &graphql.ArgumentConfig{Type: graphql.String, Description: "Converts the price into displayPrice."},
+	"mileageUnit": &graphql.ArgumentConfig{Type: graphql.String, Description: "km or mi, falls back to the Accept-Language header."},
+}
+
+// newSchema builds the schema over the car usecase, the relations of the cars are loaded in
+// batches by the loaders of the request
+func newSchema(carUC usecase.CarUsecase, logger logger.Logger) (graphql.Schema, error) {
+	r := resolver{carUC: carUC, logger: logger}
+
+	carsArgs := graphql.FieldConfigArgument{
+		"features":      &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"featuresMatch": &graphql.ArgumentConfig{Type: graphql.String, Description: "all or any, all when not given."},
+		"mileageMin":    &graphql.ArgumentConfig{Type: graphql.Int},
+		"mileageMax":    &graphql.ArgumentConfig{Type: graphql.Int},
+		"limit":         &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultPageLimit, Description: "At most 100."},
+		"offset":        &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+	}
+	for name, arg := range displayArgs {
+		carsArgs[name] = arg
+	}
+
+	carArgs := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+	for name, arg := range displayArgs {
+		carArgs[name] = arg
+	}
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"car":  &graphql.Field{Type: carType, Args: carArgs, Resolve: r.car},
+				"cars": &graphql.Field{Type: carPageType, Args: carsArgs, Resolve: r.cars},
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"createCar": &graphql.Field{
+					Type:    messageType,
+					Args:    graphql.FieldConfigArgument{"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(carInputType)}},
+					Resolve: r.createCar,
+				},
+				"updateCar": &graphql.Field{
+					Type: carType,
+					Args: graphql.FieldConfigArgument{
+						"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(carInputType)},
+					},
+					Resolve: r.updateCar,
+				},
+				"deleteCar": &graphql.Field{
+					Type:    messageType,
+					Args:    graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}},
+					Resolve: r.deleteCar,
+				},
+			},
+		}),
+	})
+}
+
+// resolver resolves the operations with the usecases, the arguments go through the same
+// request validation as the REST API
+type resolver struct {
+	carUC  usecase.CarUsecase
+	logger logger.Logger
+}
+
+func (r resolver) car(p graphql.ResolveParams) (interface{}, error) {
+	id, err := carID(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	req := request.GetCarReq{
+		Currency:       stringArg(p.Args, "currency"),
+		MileageUnit:    stringArg(p.Args, "mileageUnit"),
+		AcceptLanguage: p.Info.RootValue.(root).acceptLanguage,
+	}
+	if err := req.Validate(); err != nil {
+		return nil, utils.NewInvalidInputError(err.(validation.Errors))
+	}
+
+	car, err := r.carUC.GetByID(p.Context, id, req.Display())
+	if err != nil {
+		return nil, resolverError(p.Context, r.logger, err)
+	}
+	return car, nil
+}
+
+func (r resolver) cars(p graphql.ResolveParams) (interface{}, error) {
+	var features []string
+	if list, ok := p.Args["features"].([]interface{}); ok {
+		for _, feature := range list {
+			features = append(features, feature.(string))
+		}
+	}
+
+	req := request.FetchCarPageReq{
+		FetchCarReq: request.FetchCarReq{
+			Features:       strings.Join(features, ","),
+			FeaturesMatch:  stringArg(p.Args, "featuresMatch"),
+			Currency:       stringArg(p.Args, "currency"),
+			MileageMin:     intPtrArg(p.Args, "mileageMin"),
+			MileageMax:     intPtrArg(p.Args, "mileageMax"),
+			MileageUnit:    stringArg(p.Args, "mileageUnit"),
+			AcceptLanguage: p.Info.RootValue.(root).acceptLanguage,
+		},
+		Limit:  defaultPageLimit,
+		Offset: intArg(p.Args, "offset"),
+	}
+	if limit := intPtrArg(p.Args, "limit"); limit != nil {
+		req.Limit = *limit
+	}
+	if err := req.Validate(); err != nil {
+		return nil, utils.NewInvalidInputError(err.(validation.Errors))
+	}
+
+	cars, total, err := r.carUC.FetchPage(p.Context, req.Filter(), req.Page(), req.Display())
+	if err != nil {
+		return nil, resolverError(p.Context, r.logger, err)
+	}
+
+	return carPage{
+		Items:  cars,
+		Total:  int(total),
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}, nil
+}
+
+func (r resolver) createCar(p graphql.ResolveParams) (interface{}, error) {
+	req := carInput(p.Args)
+	if err := req.Validate(); err != nil {
+		return nil, utils.NewInvalidInputError(err.(validation.Errors))
+	}
+
+	if err := r.carUC.Create(p.Context, &req); err != nil {
+		return nil, resolverError(p.Context, r.logger, err)
+	}
+	return message{Message: "car created"}, nil
+}
+
+// updateCar gives the car back as stored, the display arguments of a query do not apply
+func (r resolver) updateCar(p graphql.ResolveParams) (interface{}, error) {
+	id, err := carID(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	req := request.UpdateCarReq(carInput(p.Args))
+	if err := req.Validate(); err != nil {
+		return nil, utils.NewInvalidInputError(err.(validation.Errors))
+	}
+
+	if err := r.carUC.Update(p.Context, id, &req); err != nil {
+		return nil, resolverError(p.Context, r.logger, err)
+	}
+
+	car, err := r.carUC.GetByID(p.Context, id, entity.CarDisplay{})
+	if err != nil {
+		return nil, resolverError(p.Context, r.logger, err)
+	}
+	return car, nil
+}
+
+func (r resolver) deleteCar(p graphql.ResolveParams) (interface{}, error) {
+	id, err := carID(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.carUC.Delete(p.Context, id); err != nil {
+		return nil, resolverError(p.Context, r.logger, err)
+	}
+	return message{Message: "car deleted"}, nil
+}
+
+// carID parses the id argument, an id that is not a number names no car
+func carID(args map[string]interface{}) (int64, error) {
+	id, err := strconv.ParseInt(stringArg(args, "id"), 10, 64)
+	if err != nil {
+		return 0, utils.NewError(utils.CodeCarNotFound, "car not found")
+	}
+	return id, nil
+}
+
+// carInput converts the input argument into a create car request
+func carInput(args map[string]interface{}) request.CreateCarReq {
+	input, _ := args["input"].(map[string]interface{})
+	req := request.CreateCarReq{
+		Make:           stringArg(input, "make"),
+		Model:          stringArg(input, "model"),
+		Package:        stringArg(input, "package"),
+		Color:          stringArg(input, "color"),
+		Year:           intArg(input, "year"),
+		Category:       stringArg(input, "category"),
+		Mileage:        intArg(input, "mileage"),
+		MileageUnit:    stringArg(input, "mileageUnit"),
+		Identification: stringArg(input, "identification"),
+	}
+	if price, ok := input["price"].(map[string]interface{}); ok {
+		req.Price.Amount, _ = price["amount"].(int64)
+		req.Price.Currency = stringArg(price, "currency")
+	}
+	return req
+}
+
+func stringArg(args map[string]interface{}, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+func intArg(args map[string]interface{}, name string) int {
+	value, _ := args[name].(int)
+	return value
+}
+
+func intPtrArg(args map[string]interface{}, name string) *int {
+	value, ok := args[name].(int)
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+// resolverError keeps the usecase errors carrying a status, any other error is logged with the
+// request id and hidden behind an internal server error
+func resolverError(ctx context.Context, logger logger.Logger, err error) error {
+	var httpErr utils.HttpErr
+	if errors.As(err, &httpErr) {
+		return err
+	}
+
+	logger.Errorw("INTERNAL ERROR", "request_id", utils.GetReqID(ctx), "error", err.Error())
+	return utils.NewInternalServerError(err)
+}
@@ -0,0 +1,223 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"carApi/entity"
+	"carApi/infrastructure/tracing"
+	carv1 "carApi/proto/car/v1"
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	"carApi/utils/logger"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultPageLimit is the number of cars listed when the request sets no limit
+const defaultPageLimit = 20
+
+type CarServer struct {
+	carv1.UnimplementedCarServiceServer
+	CarUC  usecase.CarUsecase
+	Logger logger.Logger
+}
+
+// NewCarServer will register the car service, the requests go through the same validation as
+// the REST API
+func NewCarServer(s *grpc.Server, carUC usecase.CarUsecase, logger logger.Logger) {
+	carv1.RegisterCarServiceServer(s, &CarServer{
+		CarUC:  carUC,
+		Logger: logger,
+	})
+}
+
+func (s *CarServer) CreateCar(c context.Context, in *carv1.CreateCarRequest) (*carv1.CreateCarResponse, error) {
+	ctx, span := tracing.Tracer().Start(c, "CarServer.CreateCar")
+	defer span.End()
+
+	req := carInput(in.GetCar())
+	if err := req.Validate(); err != nil {
+		return nil, statusError(ctx, s.Logger, utils.NewInvalidInputError(err.(validation.Errors)))
+	}
+
+	if err := s.CarUC.Create(ctx, &req); err != nil {
+		return nil, statusError(ctx, s.Logger, err)
+	}
+	return &carv1.CreateCarResponse{}, nil
+}
+
+func (s *CarServer) GetCar(c context.Context, in *carv1.GetCarRequest) (*carv1.Car, error) {
+	ctx, span := tracing.Tracer().Start(c, "CarServer.GetCar")
+	defer span.End()
+
+	req := request.GetCarReq{
+		Currency:       in.GetCurrency(),
+		MileageUnit:    in.GetMileageUnit(),
+		AcceptLanguage: acceptLanguage(ctx),
+	}
+	if err := req.Validate(); err != nil {
+		return nil, statusError(ctx, s.Logger, utils.NewInvalidInputError(err.(validation.Errors)))
+	}
+
+	car, err := s.CarUC.GetByID(ctx, in.GetId(), req.Display())
+	if err != nil {
+		return nil, statusError(ctx, s.Logger, err)
+	}
+	return carMessage(car), nil
+}
+
+func (s *CarServer) ListCars(c context.Context, in *carv1.ListCarsRequest) (*carv1.ListCarsResponse, error) {
+	ctx, span := tracing.Tracer().Start(c, "CarServer.ListCars")
+	defer span.End()
+
+	req := request.FetchCarPageReq{
+		FetchCarReq: request.FetchCarReq{
+			Features:       strings.Join(in.GetFeatures(), ","),
+			FeaturesMatch:  in.GetFeaturesMatch(),
+			Currency:       in.GetCurrency(),
+			MileageMin:     intPtr(in.MileageMin),
+			MileageMax:     intPtr(in.MileageMax),
+			MileageUnit:    in.GetMileageUnit(),
+			AcceptLanguage: acceptLanguage(ctx),
+		},
+		Limit:  int(in.GetLimit()),
+		Offset: int(in.GetOffset()),
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultPageLimit
+	}
+	if err := req.Validate(); err != nil {
+		return nil, statusError(ctx, s.Logger, utils.NewInvalidInputError(err.(validation.Errors)))
+	}
+
+	cars, total, err := s.CarUC.FetchPage(ctx, req.Filter(), req.Page(), req.Display())
+	if err != nil {
+		return nil, statusError(ctx, s.Logger, err)
+	}
+
+	res := &carv1.ListCarsResponse{Total: int32(total)}
+	for _, car := range cars {
+		res.Cars = append(res.Cars, carMessage(car))
+	}
+	return res, nil
+}
+
+// UpdateCar gives the car back as stored, without display options
+func (s *CarServer) UpdateCar(c context.Context, in *carv1.UpdateCarRequest) (*carv1.Car, error) {
+	ctx, span := tracing.Tracer().Start(c, "CarServer.UpdateCar")
+	defer span.End()
+
+	req := request.UpdateCarReq(carInput(in.GetCar()))
+	if err := req.Validate(); err != nil {
+		return nil, statusError(ctx, s.Logger, utils.NewInvalidInputError(err.(validation.Errors)))
+	}
+
+	if err := s.CarUC.Update(ctx, in.GetId(), &req); err != nil {
+		return nil, statusError(ctx, s.Logger, err)
+	}
+
+	car, err := s.CarUC.GetByID(ctx, in.GetId(), entity.CarDisplay{})
+	if err != nil {
+		return nil, statusError(ctx, s.Logger, err)
+	}
+	return carMessage(car), nil
+}
+
+func (s *CarServer) DeleteCar(c context.Context, in *carv1.DeleteCarRequest) (*carv1.DeleteCarResponse, error) {
+	ctx, span := tracing.Tracer().Start(c, "CarServer.DeleteCar")
+	defer span.End()
+
+	if err := s.CarUC.Delete(ctx, in.GetId()); err != nil {
+		return nil, statusError(ctx, s.Logger, err)
+	}
+	return &carv1.DeleteCarResponse{}, nil
+}
+
+// acceptLanguage reads the accept-language metadata, it decides the mileage unit as the header
+// does on the REST API
+func acceptLanguage(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("accept-language"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func intPtr(value *int64) *int {
+	if value == nil {
+		return nil
+	}
+	n := int(*value)
+	return &n
+}
+
+// carInput converts the car of a request into a create car request, a car left out gives an
+// empty request the validation reports
+func carInput(in *carv1.CarInput) request.CreateCarReq {
+	return request.CreateCarReq{
+		Make:        in.GetMake(),
+		Model:       in.GetModel(),
+		Package:     in.GetPackage(),
+		Color:       in.GetColor(),
+		Year:        int(in.GetYear()),
+		Category:    in.GetCategory(),
+		Mileage:     int(in.GetMileage()),
+		MileageUnit: in.GetMileageUnit(),
+		Price: request.MoneyReq{
+			Amount:   in.GetPrice().GetAmount(),
+			Currency: in.GetPrice().GetCurrency(),
+		},
+		Identification: in.GetIdentification(),
+	}
+}
+
+func carMessage(car entity.Car) *carv1.Car {
+	message := &carv1.Car{
+		Id:             car.ID,
+		Make:           car.Make,
+		Model:          car.Model,
+		Package:        car.Package,
+		Color:          car.Color,
+		Year:           int32(car.Year),
+		Category:       car.Category,
+		Mileage:        int64(car.Mileage),
+		Price:          moneyMessage(car.Price),
+		Identification: car.Identification,
+		CreatedAt:      timestamppb.New(car.CreatedAt),
+		UpdatedAt:      timestamppb.New(car.UpdatedAt),
+	}
+	if car.DisplayMileage != nil {
+		message.DisplayMileage = &carv1.Distance{Value: int64(car.DisplayMileage.Value), Unit: car.DisplayMileage.Unit}
+	}
+	if car.DisplayPrice != nil {
+		message.DisplayPrice = moneyMessage(*car.DisplayPrice)
+	}
+
+	for _, image := range car.Images {
+		message.Images = append(message.Images, &carv1.CarImage{
+			Id:          image.ID,
+			Url:         image.URL,
+			Thumbnails:  image.Thumbnails,
+			ContentType: image.ContentType,
+			Size:        image.Size,
+			Width:       int32(image.Width),
+			Height:      int32(image.Height),
+			Position:    int32(image.Position),
+			IsPrimary:   image.IsPrimary,
+			CreatedAt:   timestamppb.New(image.CreatedAt),
+			UpdatedAt:   timestamppb.New(image.UpdatedAt),
+		})
+	}
+	for _, feature := range car.Features {
+		message.Features = append(message.Features, &carv1.Feature{Id: feature.ID, Code: feature.Code, Name: feature.Name})
+	}
+	return message
+}
+
+func moneyMessage(money entity.Money) *carv1.Money {
+	return &carv1.Money{Amount: money.Amount, Currency: money.Currency}
+}
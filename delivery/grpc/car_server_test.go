@@ -0,0 +1,309 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	grpcDelivery "carApi/delivery/grpc"
+	"carApi/entity"
+	"carApi/mocks"
+	carv1 "carApi/proto/car/v1"
+	"carApi/transport/request"
+	"carApi/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const apiKey = "key-1"
+
+// inboundLog matches the call logging a request, its message and key value pairs
+func inboundLog() []interface{} {
+	args := []interface{}{"INBOUND LOG"}
+	for i := 0; i < 16; i++ {
+		args = append(args, mock.Anything)
+	}
+	return args
+}
+
+// serveCars serves the car service with the interceptors of serve over an in-memory connection
+func serveCars(t *testing.T, carUC *mocks.CarUsecase, mockLogger *mocks.Logger) carv1.CarServiceClient {
+	interceptor := grpcDelivery.NewInterceptor(mockLogger)
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		interceptor.Tracing(),
+		interceptor.RequestID(),
+		interceptor.Logger(),
+		interceptor.Recovery(),
+		interceptor.APIKey([]string{apiKey}),
+	))
+	grpcDelivery.NewCarServer(s, carUC, mockLogger)
+
+	listener := bufconn.Listen(1 << 20)
+	go s.Serve(listener)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return carv1.NewCarServiceClient(conn)
+}
+
+func withAPIKey(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+}
+
+func carInput() *carv1.CarInput {
+	return &carv1.CarInput{
+		Make:           "Make",
+		Model:          "Model",
+		Package:        "Package",
+		Color:          "Color",
+		Year:           2020,
+		Category:       "Category",
+		Mileage:        15000,
+		Price:          &carv1.Money{Amount: 1500000, Currency: "USD"},
+		Identification: "Identification",
+	}
+}
+
+func TestCarServer_CreateCar(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Infow", inboundLog()...).Once()
+		mockCarUC.On("Create", mock.Anything, &request.CreateCarReq{
+			Make:           "Make",
+			Model:          "Model",
+			Package:        "Package",
+			Color:          "Color",
+			Year:           2020,
+			Category:       "Category",
+			Mileage:        15000,
+			Price:          request.MoneyReq{Amount: 1500000, Currency: "USD"},
+			Identification: "Identification",
+		}).Return(nil).Once()
+
+		_, err := serveCars(t, mockCarUC, mockLogger).CreateCar(withAPIKey(context.TODO()), &carv1.CreateCarRequest{Car: carInput()})
+
+		require.NoError(t, err)
+		mockCarUC.AssertExpectations(t)
+		mockLogger.AssertExpectations(t)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Infow", inboundLog()...).Once()
+		input := carInput()
+		input.Make = ""
+		input.MileageUnit = "ly"
+
+		_, err := serveCars(t, mockCarUC, mockLogger).CreateCar(withAPIKey(context.TODO()), &carv1.CreateCarRequest{Car: input})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		require.Len(t, st.Details(), 1)
+		badRequest := st.Details()[0].(*errdetails.BadRequest)
+		require.Len(t, badRequest.FieldViolations, 2)
+		assert.Equal(t, "make", badRequest.FieldViolations[0].Field)
+		assert.Equal(t, "cannot be blank", badRequest.FieldViolations[0].Description)
+		assert.Equal(t, "mileage_unit", badRequest.FieldViolations[1].Field)
+		mockCarUC.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-api-key", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Infow", inboundLog()...).Once()
+
+		_, err := serveCars(t, mockCarUC, mockLogger).CreateCar(context.TODO(), &carv1.CreateCarRequest{Car: carInput()})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockCarUC.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCarServer_GetCar(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockLogger := new(mocks.Logger)
+		var requestID interface{}
+		mockLogger.On("Infow", inboundLog()...).Run(func(args mock.Arguments) { requestID = args.Get(2) }).Once()
+		createdAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+		mockCarUC.On("GetByID", mock.Anything, int64(1), entity.CarDisplay{Currency: "EUR", MileageUnit: entity.MileageUnitMiles}).Return(entity.Car{
+			ID:             1,
+			Make:           "Toyota",
+			Mileage:        16093,
+			DisplayMileage: &entity.Distance{Value: 10000, Unit: entity.MileageUnitMiles},
+			Price:          entity.Money{Amount: 1500000, Currency: "USD"},
+			DisplayPrice:   &entity.Money{Amount: 1380000, Currency: "EUR"},
+			Images:         []entity.CarImage{{ID: 7, URL: "/media/7.jpg", Thumbnails: map[string]string{"small": "/media/7-s.jpg"}}},
+			Features:       []entity.Feature{{ID: 3, Code: "sunroof", Name: "Sunroof"}},
+			CreatedAt:      createdAt,
+		}, nil).Once()
+
+		// reads need no API key, the request id comes back in the header
+		ctx := metadata.AppendToOutgoingContext(context.TODO(), "accept-language", "en-US", "x-request-id", "req-1")
+		var header metadata.MD
+		car, err := serveCars(t, mockCarUC, mockLogger).GetCar(ctx, &carv1.GetCarRequest{Id: 1, Currency: "eur"}, grpc.Header(&header))
+
+		require.NoError(t, err)
+		assert.Equal(t, "Toyota", car.Make)
+		assert.Equal(t, int64(10000), car.DisplayMileage.Value)
+		assert.Equal(t, "EUR", car.DisplayPrice.Currency)
+		assert.Equal(t, "/media/7-s.jpg", car.Images[0].Thumbnails["small"])
+		assert.Equal(t, "sunroof", car.Features[0].Code)
+		assert.Equal(t, createdAt, car.CreatedAt.AsTime())
+		assert.Equal(t, []string{"req-1"}, header.Get("x-request-id"))
+		assert.Equal(t, "req-1", requestID)
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("error-not-found", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Infow", inboundLog()...).Once()
+		mockCarUC.On("GetByID", mock.Anything, int64(9), entity.CarDisplay{}).Return(entity.Car{}, utils.NewNotFoundError("car not found")).Once()
+
+		_, err := serveCars(t, mockCarUC, mockLogger).GetCar(context.TODO(), &carv1.GetCarRequest{Id: 9})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Equal(t, "car not found", st.Message())
+	})
+
+	t.Run("error-panic", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Infow", inboundLog()...).Once()
+		mockLogger.On("Errorw", "PANIC RECOVERED", "request_id", mock.Anything, "method", carv1.CarService_GetCar_FullMethodName, "error", "boom", "stack", mock.Anything).Once()
+		mockCarUC.On("GetByID", mock.Anything, int64(1), entity.CarDisplay{}).Run(func(mock.Arguments) { panic("boom") }).Once()
+
+		_, err := serveCars(t, mockCarUC, mockLogger).GetCar(context.TODO(), &carv1.GetCarRequest{Id: 1})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+		mockLogger.AssertExpectations(t)
+	})
+}
+
+func TestCarServer_ListCars(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Infow", inboundLog()...).Once()
+		mileageMax := 30000
+		mockCarUC.On("FetchPage", mock.Anything,
+			entity.CarFilter{Features: []string{"gps", "sunroof"}, FeatureMatch: entity.FeatureMatchAll, MileageMax: &mileageMax},
+			entity.Page{Limit: 1, Offset: 1},
+			entity.CarDisplay{}).
+			Return([]entity.Car{{ID: 2}}, int64(3), nil).Once()
+
+		mileage := int64(30000)
+		res, err := serveCars(t, mockCarUC, mockLogger).ListCars(context.TODO(), &carv1.ListCarsRequest{
+			Features:   []string{"sunroof", "gps"},
+			MileageMax: &mileage,
+			Limit:      1,
+			Offset:     1,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), res.Total)
+		require.Len(t, res.Cars, 1)
+		assert.Equal(t, int64(2), res.Cars[0].Id)
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Infow", inboundLog()...).Once()
+
+		_, err := serveCars(t, mockCarUC, mockLogger).ListCars(context.TODO(), &carv1.ListCarsRequest{Limit: 101})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockCarUC.AssertNotCalled(t, "FetchPage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCarServer_UpdateCar(t *testing.T) {
+	mockCarUC := new(mocks.CarUsecase)
+	mockLogger := new(mocks.Logger)
+	mockLogger.On("Infow", inboundLog()...).Once()
+	mockCarUC.On("Update", mock.Anything, int64(1), mock.AnythingOfType("*request.UpdateCarReq")).Return(nil).Once()
+	mockCarUC.On("GetByID", mock.Anything, int64(1), entity.CarDisplay{}).Return(entity.Car{ID: 1, Make: "Make"}, nil).Once()
+
+	car, err := serveCars(t, mockCarUC, mockLogger).UpdateCar(withAPIKey(context.TODO()), &carv1.UpdateCarRequest{Id: 1, Car: carInput()})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Make", car.Make)
+	mockCarUC.AssertExpectations(t)
+}
+
+func TestCarServer_DeleteCar(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Infow", inboundLog()...).Once()
+		mockCarUC.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+
+		_, err := serveCars(t, mockCarUC, mockLogger).DeleteCar(withAPIKey(context.TODO()), &carv1.DeleteCarRequest{Id: 1})
+
+		require.NoError(t, err)
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("error-internal", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Infow", inboundLog()...).Once()
+		mockLogger.On("Errorw", "INTERNAL ERROR", "request_id", mock.AnythingOfType("string"), "error", "unexpected error").Once()
+		mockCarUC.On("Delete", mock.Anything, int64(1)).Return(errors.New("unexpected error")).Once()
+
+		_, err := serveCars(t, mockCarUC, mockLogger).DeleteCar(withAPIKey(context.TODO()), &carv1.DeleteCarRequest{Id: 1})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.Internal, st.Code())
+		assert.Equal(t, "internal server error", st.Message())
+		mockLogger.AssertExpectations(t)
+	})
+}
+
+func TestCarServer_ErrorCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"duplicate create", utils.NewError(utils.CodeCatalogEntryAlreadyExists, "make Toyota already exists"), codes.AlreadyExists},
+		{"pending state", utils.NewError(utils.CodeWebhookDeliveryPending, "delivery is still pending"), codes.FailedPrecondition},
+		{"reserved", utils.NewError(utils.CodeCarReserved, "car is reserved by another rep"), codes.FailedPrecondition},
+		{"other conflict", utils.NewConflictError("conflict"), codes.FailedPrecondition},
+		{"status only", utils.NewNotFoundError("car not found"), codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCarUC := new(mocks.CarUsecase)
+			mockLogger := new(mocks.Logger)
+			mockLogger.On("Infow", inboundLog()...).Once()
+			mockCarUC.On("Delete", mock.Anything, int64(1)).Return(tt.err).Once()
+
+			_, err := serveCars(t, mockCarUC, mockLogger).DeleteCar(withAPIKey(context.TODO()), &carv1.DeleteCarRequest{Id: 1})
+
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"carApi/utils"
+	"carApi/utils/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// codeByErrorCode maps the codes of the catalog whose status says too little to the gRPC codes: a
+// 409 is AlreadyExists only for a duplicate create and a state the call cannot proceed from is
+// FailedPrecondition, Aborted is left to a write racing another one which no usecase reports
+var codeByErrorCode = map[utils.ErrorCode]codes.Code{
+	utils.CodeFeatureAlreadyExists:      codes.AlreadyExists,
+	utils.CodeCatalogEntryAlreadyExists: codes.AlreadyExists,
+	utils.CodeWebhookDeliveryPending:    codes.FailedPrecondition,
+	utils.CodeCarReserved:               codes.FailedPrecondition,
+}
+
+// codeByStatus maps the statuses of the usecase errors the catalog codes leave out to the gRPC
+// codes, any other status is Unknown
+var codeByStatus = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusUnsupportedMediaType:  codes.InvalidArgument,
+	http.StatusUnprocessableEntity:   codes.InvalidArgument,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	http.StatusInternalServerError:   codes.Internal,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// statusError converts an error of the usecases into a gRPC status, from its catalog code or else
+// its status. The message is the details when those are a sentence, field errors are attached as
+// a BadRequest. An error carrying no status is logged and hidden behind Internal.
+func statusError(ctx context.Context, logger logger.Logger, err error) error {
+	var httpErr utils.HttpErr
+	if !errors.As(err, &httpErr) {
+		logger.Errorw("INTERNAL ERROR", "request_id", utils.GetReqID(ctx), "error", err.Error())
+		httpErr = utils.NewInternalServerError(err)
+	}
+
+	code, ok := codeByErrorCode[httpErr.Code()]
+	if !ok {
+		code, ok = codeByStatus[httpErr.Status()]
+	}
+	if !ok {
+		code = codes.Unknown
+	}
+
+	message := http.StatusText(httpErr.Status())
+	var e utils.HttpError
+	if errors.As(httpErr, &e) {
+		message = e.ErrError
+	}
+	if details, ok := httpErr.Details().(string); ok {
+		message = details
+	}
+	st := status.New(code, message)
+
+	if violations := fieldViolations(httpErr.Details()); len(violations) > 0 {
+		if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
+
+// fieldViolations reads the field errors of a validation error, the details of other errors
+// give none
+func fieldViolations(details interface{}) []*errdetails.BadRequest_FieldViolation {
+	if _, ok := details.(string); ok || details == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(details)
+	if err != nil {
+		return nil
+	}
+	var fields []struct {
+		Field string `json:"field"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fields))
+	for _, field := range fields {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field.Field, Description: field.Error})
+	}
+	return violations
+}
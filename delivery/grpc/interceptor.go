@@ -0,0 +1,216 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/tracing"
+	carv1 "carApi/proto/car/v1"
+	"carApi/utils"
+	"carApi/utils/logger"
+	uuid "github.com/satori/go.uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// readMethods are the unary methods any client may call, the others write and need an API key.
+// The streaming methods of the health and reflection services only read.
+var readMethods = map[string]bool{
+	carv1.CarService_GetCar_FullMethodName:   true,
+	carv1.CarService_ListCars_FullMethodName: true,
+	healthpb.Health_Check_FullMethodName:     true,
+	healthpb.Health_List_FullMethodName:      true,
+}
+
+// Interceptor holds the unary interceptors of the gRPC server, each of them does what the
+// middleware of the same name does for Echo
+type Interceptor struct {
+	logger logger.Logger
+}
+
+// NewInterceptor will create new an Interceptor object
+func NewInterceptor(logger logger.Logger) *Interceptor {
+	return &Interceptor{
+		logger: logger,
+	}
+}
+
+// Tracing will continue the trace of an incoming W3C traceparent metadata, or start a new one,
+// with a server span named after the method
+func (i *Interceptor) Tracing() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+		ctx, span := tracing.Tracer().Start(ctx, info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.method", info.FullMethod),
+			),
+		)
+		defer span.End()
+
+		res, err := handler(ctx, req)
+
+		code := status.Code(err)
+		span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+		switch code {
+		case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+			span.SetStatus(otelcodes.Error, code.String())
+		}
+		return res, err
+	}
+}
+
+// RequestID will search for a correlation metadata and set a request-level correlation id into
+// the context, it falls back to the trace id then to a new UUID and is sent back in the header
+func (i *Interceptor) RequestID() grpc.UnaryServerInterceptor {
+	key := strings.ToLower(entity.RequestIDHeader)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		span := trace.SpanFromContext(ctx)
+
+		requestID := ""
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(key); len(values) > 0 {
+			requestID = values[0]
+		}
+		if requestID == "" && span.SpanContext().HasTraceID() {
+			requestID = span.SpanContext().TraceID().String()
+		}
+		if requestID == "" {
+			requestID = uuid.NewV4().String()
+		}
+		span.SetAttributes(attribute.String("request.id", requestID))
+
+		// only a call served by a grpc.Server has a header to set
+		_ = grpc.SetHeader(ctx, metadata.Pairs(key, requestID))
+
+		return handler(context.WithValue(ctx, entity.RequestIDKey, requestID), req)
+	}
+}
+
+func (i *Interceptor) Logger() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		res, err := handler(ctx, req)
+
+		remoteIP := ""
+		if p, ok := peer.FromContext(ctx); ok {
+			remoteIP = p.Addr.String()
+		}
+		userAgent := ""
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("user-agent"); len(values) > 0 {
+			userAgent = values[0]
+		}
+		body := ""
+		if message, ok := req.(proto.Message); ok {
+			if b, err := protojson.Marshal(message); err == nil {
+				body = utils.CompactJSON(b)
+			}
+		}
+
+		i.logger.Infow("INBOUND LOG",
+			"request_id", utils.GetReqID(ctx),
+			"trace_id", trace.SpanContextFromContext(ctx).TraceID().String(),
+			"remote_ip", remoteIP,
+			"method", info.FullMethod,
+			"user_agent", userAgent,
+			"body", body,
+			"code", status.Code(err).String(),
+			"latency", float64(time.Since(start).Nanoseconds()/1e4)/100.0,
+		)
+
+		return res, err
+	}
+}
+
+// Recovery will turn a panic of a handler into an Internal error, the panic is logged with its
+// stack and the server keeps serving
+func (i *Interceptor) Recovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				i.logger.Errorw("PANIC RECOVERED",
+					"request_id", utils.GetReqID(ctx),
+					"method", info.FullMethod,
+					"error", fmt.Sprint(r),
+					"stack", string(debug.Stack()),
+				)
+				res, err = nil, status.Error(codes.Internal, "internal server error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// APIKey will reject write calls that do not carry one of the keys in the x-api-key metadata,
+// reads stay public and no keys disables the check
+func (i *Interceptor) APIKey(keys []string) grpc.UnaryServerInterceptor {
+	key := strings.ToLower(entity.APIKeyHeader)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if len(keys) == 0 || readMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, value := range md.Get(key) {
+			if validAPIKey(keys, value) {
+				return handler(ctx, req)
+			}
+		}
+
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid "+key+" metadata")
+	}
+}
+
+func validAPIKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// metadataCarrier lets the propagator read the trace context from the incoming metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Binder binds MessagePack bodies, read with the json tags, on top of what the echo binder binds
+type Binder struct {
+	echo.DefaultBinder
+}
+
+// NewBinder will create the binder echo uses for every handler
+func NewBinder() *Binder {
+	return &Binder{}
+}
+
+func (b *Binder) Bind(i interface{}, c echo.Context) error {
+	req := c.Request()
+	if !strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationMsgpack) {
+		return b.DefaultBinder.Bind(i, c)
+	}
+
+	if err := b.BindPathParams(c, i); err != nil {
+		return err
+	}
+	if req.Method == http.MethodGet || req.Method == http.MethodDelete || req.Method == http.MethodHead {
+		if err := b.BindQueryParams(c, i); err != nil {
+			return err
+		}
+	}
+	if req.ContentLength == 0 {
+		return nil
+	}
+
+	dec := msgpack.NewDecoder(req.Body)
+	dec.SetCustomStructTag("json")
+	if err := dec.Decode(i); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return nil
+}
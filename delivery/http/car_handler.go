@@ -1,10 +1,15 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"carApi/delivery/middleware"
+	"carApi/entity"
+	"carApi/infrastructure/tracing"
 	"carApi/transport/request"
 	"carApi/usecase"
 	"carApi/utils"
@@ -12,6 +17,15 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// requestFormats are the bodies the binder reads
+var requestFormats = []string{echo.MIMEApplicationJSON, echo.MIMEApplicationXML, echo.MIMEApplicationMsgpack}
+
+// responseFormats are the formats every car response can be written in, JSON first as the default
+var responseFormats = []string{echo.MIMEApplicationJSON, echo.MIMEApplicationXML, echo.MIMEApplicationMsgpack}
+
+// listFormats adds CSV for the listing, a row per car
+var listFormats = append(responseFormats[:len(responseFormats):len(responseFormats)], middleware.MIMETextCSV)
+
 type CarHandler struct {
 	CarUC usecase.CarUsecase
 }
@@ -22,101 +36,175 @@ func NewCarHandler(e *echo.Echo, middleware *middleware.Middleware, carUC usecas
 		CarUC: carUC,
 	}
 
+	negotiate := middleware.Negotiate(responseFormats...)
 	apiV1 := e.Group("/api/v1")
-	apiV1.POST("/cars", handler.Create)
-	apiV1.GET("/cars/:id", handler.GetByID)
-	apiV1.GET("/cars", handler.Fetch)
-	apiV1.PUT("/cars/:id", handler.Update)
-	apiV1.DELETE("/cars/:id", handler.Delete)
+	apiV1.POST("/cars", handler.Create, negotiate)
+	apiV1.GET("/cars/:id", handler.GetByID, negotiate)
+	apiV1.GET("/cars", handler.Fetch, middleware.Negotiate(listFormats...))
+	apiV1.PUT("/cars/:id", handler.Update, negotiate)
+	apiV1.DELETE("/cars/:id", handler.Delete, negotiate)
 }
 
 func (h *CarHandler) Create(c echo.Context) error {
-	ctx := c.Request().Context()
+	ctx, span := tracing.Tracer().Start(c.Request().Context(), "CarHandler.Create")
+	defer span.End()
 	var req request.CreateCarReq
 
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewUnprocessableEntityError(err.Error()))
+		return bindError(err)
 	}
 
 	if err := req.Validate(); err != nil {
 		errVal := err.(validation.Errors)
-		return c.JSON(http.StatusBadRequest, utils.NewInvalidInputError(errVal))
+		return utils.NewInvalidInputError(errVal)
 	}
 
 	if err := h.CarUC.Create(ctx, &req); err != nil {
-		return c.JSON(utils.ParseHttpError(err))
+		return err
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
+	return respond(ctx, c, http.StatusOK, map[string]interface{}{
 		"message": "car created",
 	})
 
 }
 
 func (h *CarHandler) GetByID(c echo.Context) error {
-	ctx := c.Request().Context()
+	ctx, span := tracing.Tracer().Start(c.Request().Context(), "CarHandler.GetByID")
+	defer span.End()
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusNotFound, utils.NewNotFoundError("car not found"))
+		return utils.NewError(utils.CodeCarNotFound, "car not found")
+	}
+
+	var req request.GetCarReq
+	if err := c.Bind(&req); err != nil {
+		return bindError(err)
+	}
+
+	if err := (&echo.DefaultBinder{}).BindHeaders(c, &req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
 	}
 
-	car, err := h.CarUC.GetByID(ctx, int64(id))
+	if err := req.Validate(); err != nil {
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	car, err := h.CarUC.GetByID(ctx, int64(id), req.Display())
 	if err != nil {
-		return c.JSON(utils.ParseHttpError(err))
+		return err
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{"data": car})
+	return respond(ctx, c, http.StatusOK, map[string]interface{}{"data": car})
 }
 
 func (h *CarHandler) Fetch(c echo.Context) error {
-	ctx := c.Request().Context()
+	ctx, span := tracing.Tracer().Start(c.Request().Context(), "CarHandler.Fetch")
+	defer span.End()
+
+	var req request.FetchCarReq
+	if err := c.Bind(&req); err != nil {
+		return bindError(err)
+	}
 
-	cars, err := h.CarUC.Fetch(ctx)
+	if err := (&echo.DefaultBinder{}).BindHeaders(c, &req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := req.Validate(); err != nil {
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	cars, err := h.CarUC.Fetch(ctx, req.Filter(), req.Display())
 	if err != nil {
-		return c.JSON(utils.ParseHttpError(err))
+		return err
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{"data": cars})
+	return respond(ctx, c, http.StatusOK, carList{Data: cars})
 }
 
 func (h *CarHandler) Update(c echo.Context) error {
-	ctx := c.Request().Context()
+	ctx, span := tracing.Tracer().Start(c.Request().Context(), "CarHandler.Update")
+	defer span.End()
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusNotFound, utils.NewNotFoundError("car not found"))
+		return utils.NewError(utils.CodeCarNotFound, "car not found")
 	}
 
 	var req request.UpdateCarReq
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, utils.NewUnprocessableEntityError(err.Error()))
+		return bindError(err)
 	}
 
 	if err := req.Validate(); err != nil {
 		errVal := err.(validation.Errors)
-		return c.JSON(http.StatusBadRequest, utils.NewInvalidInputError(errVal))
+		return utils.NewInvalidInputError(errVal)
 	}
 
 	if err := h.CarUC.Update(ctx, int64(id), &req); err != nil {
-		return c.JSON(utils.ParseHttpError(err))
+		return err
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
+	return respond(ctx, c, http.StatusOK, map[string]interface{}{
 		"message": "car updated",
 	})
 }
 
 func (h *CarHandler) Delete(c echo.Context) error {
-	ctx := c.Request().Context()
+	ctx, span := tracing.Tracer().Start(c.Request().Context(), "CarHandler.Delete")
+	defer span.End()
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusNotFound, utils.NewNotFoundError("car not found"))
+		return utils.NewError(utils.CodeCarNotFound, "car not found")
 	}
 
 	if err := h.CarUC.Delete(ctx, int64(id)); err != nil {
-		return c.JSON(utils.ParseHttpError(err))
+		return err
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
+	return respond(ctx, c, http.StatusOK, map[string]interface{}{
 		"message": "car deleted",
 	})
 }
+
+// bindError tells a body in a format the binder cannot read from a malformed one
+func bindError(err error) utils.HttpErr {
+	if errors.Is(err, echo.ErrUnsupportedMediaType) {
+		return utils.NewUnsupportedMediaTypeError("content type must be " + strings.Join(requestFormats, ", "))
+	}
+	return utils.NewUnprocessableEntityError(err.Error())
+}
+
+// carList is the body of the car listing, written as CSV it gives a row per car
+type carList struct {
+	Data []entity.Car `json:"data"`
+}
+
+func (l carList) CSVRecords() [][]string {
+	records := [][]string{{
+		"id", "make", "model", "package", "color", "year", "category", "mileage", "display_mileage", "display_mileage_unit",
+		"price_amount", "price_currency", "display_price_amount", "display_price_currency", "identification", "created_at", "updated_at",
+	}}
+
+	for _, car := range l.Data {
+		var displayMileage, displayMileageUnit, displayPriceAmount, displayPriceCurrency string
+		if car.DisplayMileage != nil {
+			displayMileage = strconv.Itoa(car.DisplayMileage.Value)
+			displayMileageUnit = car.DisplayMileage.Unit
+		}
+		if car.DisplayPrice != nil {
+			displayPriceAmount = strconv.FormatInt(car.DisplayPrice.Amount, 10)
+			displayPriceCurrency = car.DisplayPrice.Currency
+		}
+
+		records = append(records, []string{
+			strconv.FormatInt(car.ID, 10), car.Make, car.Model, car.Package, car.Color, strconv.Itoa(car.Year), car.Category,
+			strconv.Itoa(car.Mileage), displayMileage, displayMileageUnit,
+			strconv.FormatInt(car.Price.Amount, 10), car.Price.Currency, displayPriceAmount, displayPriceCurrency,
+			car.Identification, car.CreatedAt.Format(time.RFC3339Nano), car.UpdatedAt.Format(time.RFC3339Nano),
+		})
+	}
+	return records
+}
@@ -1,6 +1,8 @@
 package http_test
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -11,6 +13,7 @@ import (
 	"time"
 
 	httpDelivery "carApi/delivery/http"
+	"carApi/delivery/middleware"
 	"carApi/entity"
 	"carApi/mocks"
 	"carApi/transport/request"
@@ -19,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
 func TestCarHandler_Create(t *testing.T) {
@@ -28,10 +32,10 @@ func TestCarHandler_Create(t *testing.T) {
 		Model:          "Model",
 		Package:        "Package",
 		Color:          "Color",
-		Year:           0,
+		Year:           2020,
 		Category:       "Category",
-		Mileage:        0,
-		Price:          0,
+		Mileage:        15000,
+		Price:          request.MoneyReq{Amount: 1500000, Currency: "USD"},
 		Identification: "Identification",
 	}
 
@@ -61,6 +65,92 @@ func TestCarHandler_Create(t *testing.T) {
 		mockCarUC.AssertExpectations(t)
 	})
 
+	t.Run("success-xml-body", func(t *testing.T) {
+		mockCarUC.On("Create", mock.Anything, &createCarReq).Return(nil).Once()
+
+		xmlReq := `<car><make>Make</make><model>Model</model><package>Package</package><color>Color</color><year>2020</year>` +
+			`<category>Category</category><mileage>15000</mileage><price><amount>1500000</amount><currency>USD</currency></price>` +
+			`<identification>Identification</identification></car>`
+
+		e := echo.New()
+		e.Binder = httpDelivery.NewBinder()
+		req, err := http.NewRequest(echo.POST, "/api/v1/cars", strings.NewReader(xmlReq))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationXML)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars")
+		c.Set(middleware.FormatKey, echo.MIMEApplicationXML)
+
+		handler := httpDelivery.CarHandler{
+			CarUC: mockCarUC,
+		}
+		err = handler.Create(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, echo.MIMEApplicationXMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Body.String(), "<response><message>car created</message></response>")
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("success-msgpack-body", func(t *testing.T) {
+		mockCarUC.On("Create", mock.Anything, &createCarReq).Return(nil).Once()
+
+		var body bytes.Buffer
+		enc := msgpack.NewEncoder(&body)
+		enc.SetCustomStructTag("json")
+		require.NoError(t, enc.Encode(createCarReq))
+
+		e := echo.New()
+		e.Binder = httpDelivery.NewBinder()
+		req, err := http.NewRequest(echo.POST, "/api/v1/cars", &body)
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationMsgpack)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars")
+		c.Set(middleware.FormatKey, echo.MIMEApplicationMsgpack)
+
+		handler := httpDelivery.CarHandler{
+			CarUC: mockCarUC,
+		}
+		err = handler.Create(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, echo.MIMEApplicationMsgpack, rec.Header().Get(echo.HeaderContentType))
+
+		var resp map[string]interface{}
+		require.NoError(t, msgpack.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "car created", resp["message"])
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("error-unsupported-media-type", func(t *testing.T) {
+		e := echo.New()
+		e.Binder = httpDelivery.NewBinder()
+		req, err := http.NewRequest(echo.POST, "/api/v1/cars", strings.NewReader("make=Make"))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars")
+
+		handler := httpDelivery.CarHandler{
+			CarUC: mockCarUC,
+		}
+		err = handler.Create(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusUnsupportedMediaType, code)
+		mockCarUC.AssertExpectations(t)
+	})
+
 	t.Run("error-validation", func(t *testing.T) {
 		invalidCreateCarReq := request.CreateCarReq{
 			Make:           "Make",
@@ -70,7 +160,7 @@ func TestCarHandler_Create(t *testing.T) {
 			Year:           0,
 			Category:       "Category",
 			Mileage:        0,
-			Price:          0,
+			Price:          request.MoneyReq{},
 			Identification: "Identification",
 		}
 		jsonReq, err := json.Marshal(invalidCreateCarReq)
@@ -90,8 +180,9 @@ func TestCarHandler_Create(t *testing.T) {
 		}
 		err = handler.Create(c)
 
-		require.NoError(t, err)
-		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
 		mockCarUC.AssertExpectations(t)
 	})
 
@@ -116,8 +207,9 @@ func TestCarHandler_Create(t *testing.T) {
 		}
 		err = handler.Create(c)
 
-		require.NoError(t, err)
-		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusInternalServerError, code)
 		mockCarUC.AssertExpectations(t)
 	})
 
@@ -134,14 +226,14 @@ func TestCarHandler_GetByID(t *testing.T) {
 		Year:           0,
 		Category:       "Category",
 		Mileage:        0,
-		Price:          0,
+		Price:          entity.Money{Amount: 1500000, Currency: "USD"},
 		Identification: "Identification",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockCarUC.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).
+		mockCarUC.On("GetByID", mock.Anything, mock.AnythingOfType("int64"), entity.CarDisplay{}).
 			Return(mockCar, nil).Once()
 
 		e := echo.New()
@@ -166,8 +258,8 @@ func TestCarHandler_GetByID(t *testing.T) {
 	})
 
 	t.Run("data-not-exist", func(t *testing.T) {
-		mockCarUC.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).
-			Return(entity.Car{}, utils.NewNotFoundError("car not found")).Once()
+		mockCarUC.On("GetByID", mock.Anything, mock.AnythingOfType("int64"), entity.CarDisplay{}).
+			Return(entity.Car{}, utils.NewError(utils.CodeCarNotFound, "car not found")).Once()
 
 		e := echo.New()
 		req, err := http.NewRequest(echo.GET, "/api/v1/cars/"+strconv.Itoa(int(mockCar.ID)), strings.NewReader(""))
@@ -185,13 +277,16 @@ func TestCarHandler_GetByID(t *testing.T) {
 		}
 		err = handler.GetByID(c)
 
-		require.NoError(t, err)
-		assert.Equal(t, http.StatusNotFound, rec.Code)
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.True(t, errors.Is(err, utils.ErrNotFound))
+		assert.Equal(t, utils.CodeCarNotFound, err.(utils.HttpErr).Code())
 		mockCarUC.AssertExpectations(t)
 	})
 
 	t.Run("error-usecase", func(t *testing.T) {
-		mockCarUC.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).
+		mockCarUC.On("GetByID", mock.Anything, mock.AnythingOfType("int64"), entity.CarDisplay{}).
 			Return(entity.Car{}, errors.New("Unexpected Error")).Once()
 
 		e := echo.New()
@@ -210,8 +305,9 @@ func TestCarHandler_GetByID(t *testing.T) {
 		}
 		err = handler.GetByID(c)
 
-		require.NoError(t, err)
-		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusInternalServerError, code)
 		mockCarUC.AssertExpectations(t)
 	})
 }
@@ -227,7 +323,7 @@ func TestCarHandler_Fetch(t *testing.T) {
 		Year:           0,
 		Category:       "Category",
 		Mileage:        0,
-		Price:          0,
+		Price:          entity.Money{Amount: 1500000, Currency: "USD"},
 		Identification: "Identification",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
@@ -237,7 +333,7 @@ func TestCarHandler_Fetch(t *testing.T) {
 	mockListCar = append(mockListCar, mockCar)
 
 	t.Run("success", func(t *testing.T) {
-		mockCarUC.On("Fetch", mock.Anything).Return(mockListCar, nil).Once()
+		mockCarUC.On("Fetch", mock.Anything, mock.AnythingOfType("entity.CarFilter"), entity.CarDisplay{}).Return(mockListCar, nil).Once()
 
 		e := echo.New()
 		req, err := http.NewRequest(echo.GET, "/api/v1/cars/", strings.NewReader(""))
@@ -258,8 +354,214 @@ func TestCarHandler_Fetch(t *testing.T) {
 		mockCarUC.AssertExpectations(t)
 	})
 
+	t.Run("success-csv", func(t *testing.T) {
+		mockCarUC.On("Fetch", mock.Anything, mock.AnythingOfType("entity.CarFilter"), entity.CarDisplay{}).Return(mockListCar, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars/", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/")
+		c.Set(middleware.FormatKey, middleware.MIMETextCSV)
+
+		handler := httpDelivery.CarHandler{
+			CarUC: mockCarUC,
+		}
+		err = handler.Fetch(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=UTF-8", rec.Header().Get(echo.HeaderContentType))
+
+		records, err := csv.NewReader(rec.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, []string{"id", "make", "model"}, records[0][:3])
+		assert.Equal(t, []string{"1", "Make", "Model"}, records[1][:3])
+		assert.Equal(t, []string{"1500000", "USD", "", "", "Identification"}, records[1][10:15])
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("success-xml", func(t *testing.T) {
+		mockCarUC.On("Fetch", mock.Anything, mock.AnythingOfType("entity.CarFilter"), entity.CarDisplay{}).Return(mockListCar, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars/", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/")
+		c.Set(middleware.FormatKey, echo.MIMEApplicationXML)
+
+		handler := httpDelivery.CarHandler{
+			CarUC: mockCarUC,
+		}
+		err = handler.Fetch(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "<response><data><item><id>1</id><make>Make</make>")
+		assert.Contains(t, rec.Body.String(), "<price><amount>1500000</amount><currency>USD</currency></price>")
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("success-filtered-by-features", func(t *testing.T) {
+		filter := entity.CarFilter{Features: []string{"heated_seats", "sunroof"}, FeatureMatch: entity.FeatureMatchAny}
+		mockCarUC.On("Fetch", mock.Anything, filter, entity.CarDisplay{}).Return(mockListCar, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars?features=sunroof,Heated_Seats,sunroof&features_match=any", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars")
+
+		handler := httpDelivery.CarHandler{
+			CarUC: mockCarUC,
+		}
+		err = handler.Fetch(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("success-display-currency", func(t *testing.T) {
+		mockCarUC.On("Fetch", mock.Anything, mock.AnythingOfType("entity.CarFilter"), entity.CarDisplay{Currency: "EUR"}).Return(mockListCar, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars?currency=eur", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars")
+
+		handler := httpDelivery.CarHandler{
+			CarUC: mockCarUC,
+		}
+		err = handler.Fetch(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("success-mileage-from-accept-language", func(t *testing.T) {
+		mileageMin, mileageMax := 1609, 16094
+		filter := entity.CarFilter{FeatureMatch: entity.FeatureMatchAll, MileageMin: &mileageMin, MileageMax: &mileageMax}
+		mockCarUC.On("Fetch", mock.Anything, filter, entity.CarDisplay{MileageUnit: entity.MileageUnitMiles}).Return(mockListCar, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars?mileage_min=1000&mileage_max=10000", strings.NewReader(""))
+		assert.NoError(t, err)
+		req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars")
+
+		handler := httpDelivery.CarHandler{
+			CarUC: mockCarUC,
+		}
+		err = handler.Fetch(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("success-mileage-unit-overrides-language", func(t *testing.T) {
+		mileageMin := 1000
+		filter := entity.CarFilter{FeatureMatch: entity.FeatureMatchAll, MileageMin: &mileageMin}
+		mockCarUC.On("Fetch", mock.Anything, filter, entity.CarDisplay{MileageUnit: entity.MileageUnitKilometers}).Return(mockListCar, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars?mileage_min=1000&mileage_unit=km", strings.NewReader(""))
+		assert.NoError(t, err)
+		req.Header.Set("Accept-Language", "en-US")
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars")
+
+		handler := httpDelivery.CarHandler{
+			CarUC: mockCarUC,
+		}
+		err = handler.Fetch(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("error-validation-mileage-range", func(t *testing.T) {
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars?mileage_min=5000&mileage_max=1000", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars")
+
+		handler := httpDelivery.CarHandler{
+			CarUC: mockCarUC,
+		}
+		err = handler.Fetch(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, err.Error(), "mileage_max")
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("error-validation-currency", func(t *testing.T) {
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars?currency=xyz", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars")
+
+		handler := httpDelivery.CarHandler{
+			CarUC: mockCarUC,
+		}
+		err = handler.Fetch(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars?features=sunroof&features_match=some", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars")
+
+		handler := httpDelivery.CarHandler{
+			CarUC: mockCarUC,
+		}
+		err = handler.Fetch(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		mockCarUC.AssertExpectations(t)
+	})
+
 	t.Run("error-usecase", func(t *testing.T) {
-		mockCarUC.On("Fetch", mock.Anything).Return([]entity.Car{}, errors.New("Unexpected Error")).Once()
+		mockCarUC.On("Fetch", mock.Anything, mock.AnythingOfType("entity.CarFilter"), entity.CarDisplay{}).Return([]entity.Car{}, errors.New("Unexpected Error")).Once()
 
 		e := echo.New()
 		req, err := http.NewRequest(echo.GET, "/api/v1/cars/", strings.NewReader(""))
@@ -275,8 +577,9 @@ func TestCarHandler_Fetch(t *testing.T) {
 		}
 		err = handler.Fetch(c)
 
-		require.NoError(t, err)
-		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusInternalServerError, code)
 		mockCarUC.AssertExpectations(t)
 	})
 }
@@ -292,7 +595,7 @@ func TestCarHandler_Update(t *testing.T) {
 		Year:           0,
 		Category:       "Category",
 		Mileage:        0,
-		Price:          0,
+		Price:          entity.Money{Amount: 1500000, Currency: "USD"},
 		Identification: "Identification",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
@@ -302,10 +605,10 @@ func TestCarHandler_Update(t *testing.T) {
 		Model:          "Model2",
 		Package:        "Package2",
 		Color:          "Color2",
-		Year:           0,
+		Year:           2020,
 		Category:       "Category2",
-		Mileage:        0,
-		Price:          0,
+		Mileage:        15000,
+		Price:          request.MoneyReq{Amount: 1500000, Currency: "USD"},
 		Identification: "Identification2",
 	}
 
@@ -360,8 +663,9 @@ func TestCarHandler_Update(t *testing.T) {
 		}
 		err = handler.Update(c)
 
-		require.NoError(t, err)
-		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
 		mockCarUC.AssertExpectations(t)
 	})
 
@@ -388,8 +692,9 @@ func TestCarHandler_Update(t *testing.T) {
 		}
 		err = handler.Update(c)
 
-		require.NoError(t, err)
-		assert.Equal(t, http.StatusNotFound, rec.Code)
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusNotFound, code)
 		mockCarUC.AssertExpectations(t)
 	})
 
@@ -416,8 +721,9 @@ func TestCarHandler_Update(t *testing.T) {
 		}
 		err = handler.Update(c)
 
-		require.NoError(t, err)
-		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusInternalServerError, code)
 		mockCarUC.AssertExpectations(t)
 	})
 
@@ -434,7 +740,7 @@ func TestCarHandler_Delete(t *testing.T) {
 		Year:           0,
 		Category:       "Category",
 		Mileage:        0,
-		Price:          0,
+		Price:          entity.Money{Amount: 1500000, Currency: "USD"},
 		Identification: "Identification",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
@@ -483,8 +789,9 @@ func TestCarHandler_Delete(t *testing.T) {
 		}
 		err = handler.Delete(c)
 
-		require.NoError(t, err)
-		assert.Equal(t, http.StatusNotFound, rec.Code)
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusNotFound, code)
 		mockCarUC.AssertExpectations(t)
 	})
 
@@ -507,8 +814,9 @@ func TestCarHandler_Delete(t *testing.T) {
 		}
 		err = handler.Delete(c)
 
-		require.NoError(t, err)
-		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusInternalServerError, code)
 		mockCarUC.AssertExpectations(t)
 	})
 }
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"carApi/delivery/middleware"
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+)
+
+// uploadFormOverhead is what the upload body may carry besides the image: the multipart
+// boundaries and headers and the other fields
+const uploadFormOverhead = 64 << 10
+
+type CarImageHandler struct {
+	CarImageUC    usecase.CarImageUsecase
+	MaxUploadSize int64
+}
+
+// NewCarImageHandler will initialize the cars/:id/images resources endpoint, an upload body is not
+// read past maxUploadSize and the form around the image
+func NewCarImageHandler(e *echo.Echo, middleware *middleware.Middleware, carImageUC usecase.CarImageUsecase, maxUploadSize int64) {
+	handler := &CarImageHandler{
+		CarImageUC:    carImageUC,
+		MaxUploadSize: maxUploadSize,
+	}
+
+	apiV1 := e.Group("/api/v1")
+	apiV1.POST("/cars/:id/images", handler.Upload)
+	apiV1.GET("/cars/:id/images", handler.Fetch)
+	apiV1.PUT("/cars/:id/images/:imageId", handler.Update)
+	apiV1.DELETE("/cars/:id/images/:imageId", handler.Delete)
+}
+
+func (h *CarImageHandler) Upload(c echo.Context) error {
+	ctx := c.Request().Context()
+	carID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeCarNotFound, "car not found")
+	}
+
+	// the form is parsed from the whole body, spooled to disk past memory, so it is cut short first
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, h.MaxUploadSize+uploadFormOverhead)
+	fileHeader, err := c.FormFile("image")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return utils.NewError(utils.CodeImageTooLarge, fmt.Sprintf("image must not exceed %d bytes", h.MaxUploadSize))
+	}
+	if err != nil {
+		return utils.NewError(utils.CodeImageRequired, "image file is required")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+	defer file.Close()
+
+	req := request.UploadCarImageReq{File: file}
+	errVal := validation.Errors{}
+	if position := c.FormValue("position"); position != "" {
+		value, err := strconv.Atoi(position)
+		if err != nil {
+			errVal["position"] = errors.New("must be an integer")
+		}
+		req.Position = &value
+	}
+	if primary := c.FormValue("is_primary"); primary != "" {
+		if req.IsPrimary, err = strconv.ParseBool(primary); err != nil {
+			errVal["is_primary"] = errors.New("must be a boolean")
+		}
+	}
+	if len(errVal) > 0 {
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	if err := req.Validate(); err != nil {
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	carImage, err := h.CarImageUC.Upload(ctx, int64(carID), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": carImage})
+}
+
+func (h *CarImageHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+	carID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeCarNotFound, "car not found")
+	}
+
+	images, err := h.CarImageUC.Fetch(ctx, int64(carID))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": images})
+}
+
+func (h *CarImageHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	carID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeCarNotFound, "car not found")
+	}
+
+	imageID, err := strconv.Atoi(c.Param("imageId"))
+	if err != nil {
+		return utils.NewError(utils.CodeCarImageNotFound, "car image not found")
+	}
+
+	var req request.UpdateCarImageReq
+	if err := c.Bind(&req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := req.Validate(); err != nil {
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	if err := h.CarImageUC.Update(ctx, int64(carID), int64(imageID), &req); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "car image updated",
+	})
+}
+
+func (h *CarImageHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	carID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeCarNotFound, "car not found")
+	}
+
+	imageID, err := strconv.Atoi(c.Param("imageId"))
+	if err != nil {
+		return utils.NewError(utils.CodeCarImageNotFound, "car image not found")
+	}
+
+	if err := h.CarImageUC.Delete(ctx, int64(carID), int64(imageID)); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "car image deleted",
+	})
+}
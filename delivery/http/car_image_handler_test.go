@@ -0,0 +1,253 @@
+package http_test
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpDelivery "carApi/delivery/http"
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newMultipartRequest(t *testing.T, fields map[string]string, file []byte) *http.Request {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	if file != nil {
+		part, err := writer.CreateFormFile("image", "car.png")
+		require.NoError(t, err)
+		_, err = part.Write(file)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req, err := http.NewRequest(echo.POST, "/api/v1/cars/1/images", body)
+	require.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	return req
+}
+
+func TestCarImageHandler_Upload(t *testing.T) {
+	mockCarImageUC := new(mocks.CarImageUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockCarImageUC.On("Upload", mock.Anything, int64(1), mock.AnythingOfType("*request.UploadCarImageReq")).
+			Return(entity.CarImage{ID: 1, CarID: 1}, nil).Once()
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newMultipartRequest(t, map[string]string{"position": "2", "is_primary": "true"}, []byte("image")), rec)
+		c.SetPath("/api/v1/cars/:id/images")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		handler := httpDelivery.CarImageHandler{
+			CarImageUC: mockCarImageUC,
+		}
+		err := handler.Upload(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockCarImageUC.AssertExpectations(t)
+	})
+
+	t.Run("error-missing-file", func(t *testing.T) {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newMultipartRequest(t, nil, nil), rec)
+		c.SetPath("/api/v1/cars/:id/images")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		handler := httpDelivery.CarImageHandler{
+			CarImageUC: mockCarImageUC,
+		}
+		err := handler.Upload(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		mockCarImageUC.AssertExpectations(t)
+	})
+
+	t.Run("error-too-large", func(t *testing.T) {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newMultipartRequest(t, nil, bytes.Repeat([]byte("x"), 128<<10)), rec)
+		c.SetPath("/api/v1/cars/:id/images")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		handler := httpDelivery.CarImageHandler{
+			CarImageUC:    mockCarImageUC,
+			MaxUploadSize: 1 << 10,
+		}
+		err := handler.Upload(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+		mockCarImageUC.AssertExpectations(t)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newMultipartRequest(t, map[string]string{"position": "-1"}, []byte("image")), rec)
+		c.SetPath("/api/v1/cars/:id/images")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		handler := httpDelivery.CarImageHandler{
+			CarImageUC: mockCarImageUC,
+		}
+		err := handler.Upload(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		mockCarImageUC.AssertExpectations(t)
+	})
+
+	t.Run("error-usecase", func(t *testing.T) {
+		mockCarImageUC.On("Upload", mock.Anything, int64(1), mock.AnythingOfType("*request.UploadCarImageReq")).
+			Return(entity.CarImage{}, utils.NewUnsupportedMediaTypeError("text/plain is not a supported image type")).Once()
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newMultipartRequest(t, nil, []byte("image")), rec)
+		c.SetPath("/api/v1/cars/:id/images")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		handler := httpDelivery.CarImageHandler{
+			CarImageUC: mockCarImageUC,
+		}
+		err := handler.Upload(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusUnsupportedMediaType, code)
+		mockCarImageUC.AssertExpectations(t)
+	})
+}
+
+func TestCarImageHandler_Fetch(t *testing.T) {
+	mockCarImageUC := new(mocks.CarImageUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockCarImageUC.On("Fetch", mock.Anything, int64(1)).
+			Return([]entity.CarImage{{ID: 1, CarID: 1}}, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars/1/images", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/:id/images")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		handler := httpDelivery.CarImageHandler{
+			CarImageUC: mockCarImageUC,
+		}
+		err = handler.Fetch(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockCarImageUC.AssertExpectations(t)
+	})
+}
+
+func TestCarImageHandler_Update(t *testing.T) {
+	mockCarImageUC := new(mocks.CarImageUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockCarImageUC.On("Update", mock.Anything, int64(1), int64(2), mock.AnythingOfType("*request.UpdateCarImageReq")).
+			Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.PUT, "/api/v1/cars/1/images/2", strings.NewReader(`{"position":0,"is_primary":true}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/:id/images/:imageId")
+		c.SetParamNames("id", "imageId")
+		c.SetParamValues("1", "2")
+
+		handler := httpDelivery.CarImageHandler{
+			CarImageUC: mockCarImageUC,
+		}
+		err = handler.Update(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockCarImageUC.AssertExpectations(t)
+	})
+}
+
+func TestCarImageHandler_Delete(t *testing.T) {
+	mockCarImageUC := new(mocks.CarImageUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockCarImageUC.On("Delete", mock.Anything, int64(1), int64(2)).Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.DELETE, "/api/v1/cars/1/images/2", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/:id/images/:imageId")
+		c.SetParamNames("id", "imageId")
+		c.SetParamValues("1", "2")
+
+		handler := httpDelivery.CarImageHandler{
+			CarImageUC: mockCarImageUC,
+		}
+		err = handler.Delete(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockCarImageUC.AssertExpectations(t)
+	})
+
+	t.Run("error-usecase", func(t *testing.T) {
+		mockCarImageUC.On("Delete", mock.Anything, int64(1), int64(2)).Return(errors.New("Unexpected Error")).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.DELETE, "/api/v1/cars/1/images/2", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/:id/images/:imageId")
+		c.SetParamNames("id", "imageId")
+		c.SetParamValues("1", "2")
+
+		handler := httpDelivery.CarImageHandler{
+			CarImageUC: mockCarImageUC,
+		}
+		err = handler.Delete(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusInternalServerError, code)
+		mockCarImageUC.AssertExpectations(t)
+	})
+}
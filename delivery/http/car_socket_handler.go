@@ -0,0 +1,467 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"carApi/config"
+	"carApi/delivery/middleware"
+	"carApi/entity"
+	"carApi/infrastructure/tracing"
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	"carApi/utils/logger"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+type CarSocketHandler struct {
+	CarStreamUC      usecase.CarStreamUsecase
+	PresenceUC       usecase.PresenceUsecase
+	ReservationUC    usecase.ReservationUsecase
+	Upgrader         websocket.Upgrader
+	Heartbeat        time.Duration
+	WriteTimeout     time.Duration
+	MaxMessageSize   int64
+	MaxSubscriptions int
+	Logger           logger.Logger
+}
+
+// NewCarSocketHandler will initialize the cars socket endpoint, the handshake needs one of the
+// api keys or rep keys, which browsers offer among its subprotocols, and browsers may only open it
+// from the CORS origins. Only a socket opened with a rep key views and reserves cars, as the rep it
+// is named after.
+func NewCarSocketHandler(e *echo.Echo, middleware *middleware.Middleware, carStreamUC usecase.CarStreamUsecase, presenceUC usecase.PresenceUsecase, reservationUC usecase.ReservationUsecase, cfg config.SocketConfig, auth config.AuthConfig, origins []string, logger logger.Logger) {
+	handler := &CarSocketHandler{
+		CarStreamUC:      carStreamUC,
+		PresenceUC:       presenceUC,
+		ReservationUC:    reservationUC,
+		Upgrader:         websocket.Upgrader{CheckOrigin: allowOrigins(origins), Subprotocols: []string{entity.SocketProtocol}},
+		Heartbeat:        time.Duration(cfg.Heartbeat) * time.Second,
+		WriteTimeout:     time.Duration(cfg.WriteTimeout) * time.Second,
+		MaxMessageSize:   cfg.MaxMessageSize,
+		MaxSubscriptions: cfg.MaxSubscriptions,
+		Logger:           logger,
+	}
+
+	// the rep keys open the socket along the api keys, no api keys leaves it open to anyone
+	reps := auth.Reps()
+	keys := append([]string{}, auth.APIKeys...)
+	if len(keys) > 0 {
+		for key := range reps {
+			keys = append(keys, key)
+		}
+	}
+
+	apiV1 := e.Group("/api/v1")
+	apiV1.GET("/cars/ws", handler.Connect, middleware.RequireAPIKey(keys), middleware.Rep(reps))
+}
+
+// carSocketFrame is a message sent to the client, ID is the event of a car change
+type carSocketFrame struct {
+	Type  entity.CarSocketMessageType `json:"type"`
+	ID    int64                       `json:"id,omitempty"`
+	Data  interface{}                 `json:"data,omitempty"`
+	Error interface{}                 `json:"error,omitempty"`
+}
+
+// Connect upgrades the request to a socket pushing the changes, presences and reservations of the
+// cars the client subscribes to, until either side closes it
+func (h *CarSocketHandler) Connect(c echo.Context) error {
+	ctx, span := tracing.Tracer().Start(c.Request().Context(), "CarSocketHandler.Connect")
+
+	var req request.ConnectCarSocketReq
+	if err := c.Bind(&req); err != nil {
+		span.End()
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := (&echo.DefaultBinder{}).BindHeaders(c, &req); err != nil {
+		span.End()
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := req.Validate(); err != nil {
+		span.End()
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	// the rep is the one its key authenticates, a rep parameter naming anyone else is refused
+	rep, _ := c.Get(middleware.RepKey).(string)
+	if req.Rep != "" && req.Rep != rep {
+		span.End()
+		return utils.NewError(utils.CodeForbidden, "rep does not match the rep key of the handshake")
+	}
+
+	// a failed upgrade has already answered the client
+	conn, err := h.Upgrader.Upgrade(c.Response(), c.Request(), nil)
+	span.End()
+	if err != nil {
+		return nil
+	}
+
+	socket := &carSocket{
+		handler:    h,
+		conn:       conn,
+		rep:        rep,
+		display:    req.Display(),
+		subscribed: map[int64]bool{},
+		viewing:    map[int64]bool{},
+		replies:    make(chan carSocketReply, 16),
+	}
+	socket.run(ctx)
+	return nil
+}
+
+// carSocket is an open socket, its messages are read by their own goroutine while run is the
+// only one writing
+type carSocket struct {
+	handler *CarSocketHandler
+	conn    *websocket.Conn
+	// rep is the sales rep the socket authenticated as, empty for a socket that only listens
+	rep     string
+	display entity.CarDisplay
+
+	mu         sync.Mutex
+	subscribed map[int64]bool
+	viewing    map[int64]bool
+
+	replies chan carSocketReply
+}
+
+// carSocketReply is a frame answering the client or a car whose viewers or reservation are sent
+// once the writer gets to it, so they are never older than those pushed on a change before
+type carSocketReply struct {
+	frame         carSocketFrame
+	presenceOf    int64
+	reservationOf int64
+}
+
+func (s *carSocket) run(c context.Context) {
+	ctx, cancel := context.WithCancel(c)
+	subscription := s.handler.CarStreamUC.Subscribe(ctx, 0)
+	watch := s.handler.PresenceUC.Watch(ctx)
+
+	read := make(chan struct{})
+	go func() {
+		s.read(ctx)
+		close(read)
+	}()
+
+	// the reader is awaited so the presences it joins are all left
+	defer func() {
+		cancel()
+		subscription.Close()
+		watch.Close()
+		s.conn.Close()
+		<-read
+		s.leaveAll()
+	}()
+
+	heartbeat := time.NewTicker(s.handler.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		var frame carSocketFrame
+		select {
+		case <-read:
+			return
+		case reply := <-s.replies:
+			frame = reply.frame
+			if reply.presenceOf != 0 {
+				frame = s.presence(ctx, reply.presenceOf)
+			}
+			if reply.reservationOf != 0 {
+				frame = s.reservation(ctx, reply.reservationOf)
+			}
+		case event, ok := <-subscription.Events:
+			if !ok {
+				// lagging behind or the server is shutting down, the client reconnects
+				s.close(websocket.CloseTryAgainLater, "reconnect")
+				return
+			}
+			if !s.isSubscribed(event.CarID) {
+				continue
+			}
+
+			reservation, ok, err := s.handler.ReservationUC.Change(ctx, event)
+			if err != nil {
+				frame = s.errorFrame(ctx, err, map[string]interface{}{"car_id": event.CarID})
+				break
+			}
+			if ok {
+				frame = carSocketFrame{Type: entity.CarSocketReservation, ID: event.ID, Data: reservation}
+				break
+			}
+
+			change, ok, err := s.handler.CarStreamUC.Change(ctx, event, entity.CarFilter{}, s.display)
+			if err != nil {
+				frame = s.errorFrame(ctx, err, map[string]interface{}{"car_id": event.CarID})
+			} else if !ok {
+				continue
+			} else {
+				frame = carSocketFrame{Type: entity.CarSocketMessageType(change.Type), ID: change.EventID, Data: change}
+			}
+		case carID, ok := <-watch.Cars:
+			if !ok {
+				s.close(websocket.CloseTryAgainLater, "reconnect")
+				return
+			}
+			if !s.isSubscribed(carID) {
+				continue
+			}
+			frame = s.presence(ctx, carID)
+		case <-heartbeat.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(s.handler.WriteTimeout)); err != nil {
+				return
+			}
+			s.refresh(ctx)
+			continue
+		}
+
+		if err := s.write(frame); err != nil {
+			return
+		}
+	}
+}
+
+// read handles the messages of the client until the socket is closed, a client missing the
+// pongs of two heartbeats is considered gone
+func (s *carSocket) read(ctx context.Context) {
+	s.conn.SetReadLimit(s.handler.MaxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(2 * s.handler.Heartbeat))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(2 * s.handler.Heartbeat))
+	})
+
+	for {
+		_, message, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		for _, reply := range s.handle(ctx, message) {
+			select {
+			case s.replies <- reply:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// handle gives the replies to the message
+func (s *carSocket) handle(ctx context.Context, message []byte) []carSocketReply {
+	var req request.CarSocketMessageReq
+	if err := json.Unmarshal(message, &req); err != nil {
+		return s.errorReply(ctx, utils.NewUnprocessableEntityError(err.Error()), nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return s.errorReply(ctx, utils.NewInvalidInputError(err.(validation.Errors)), nil)
+	}
+
+	switch req.Type {
+	case entity.CarSocketSubscribe:
+		added, err := s.subscribe(req.CarIDs)
+		if err != nil {
+			return s.errorReply(ctx, err, nil)
+		}
+
+		// the current viewers and reservations of the cars follow, their changes come as they happen
+		replies := []carSocketReply{{frame: s.subscriptions()}}
+		for _, carID := range added {
+			replies = append(replies, carSocketReply{presenceOf: carID}, carSocketReply{reservationOf: carID})
+		}
+		return replies
+	case entity.CarSocketUnsubscribe:
+		s.mu.Lock()
+		for _, carID := range req.CarIDs {
+			delete(s.subscribed, carID)
+		}
+		s.mu.Unlock()
+		return []carSocketReply{{frame: s.subscriptions()}}
+	}
+
+	// viewing and reserving take a rep, the event of a reservation tells its outcome
+	if s.rep == "" {
+		return s.errorReply(ctx, utils.NewError(utils.CodeForbidden, "a rep key is needed to view and reserve cars"), map[string]interface{}{"car_id": req.CarID})
+	}
+
+	switch req.Type {
+	case entity.CarSocketView:
+		s.mu.Lock()
+		full := !s.viewing[req.CarID] && len(s.viewing) >= s.handler.MaxSubscriptions
+		s.mu.Unlock()
+		if full {
+			return s.errorReply(ctx, utils.NewError(utils.CodeSubscriptionLimitExceeded, fmt.Sprintf("cannot view more than %d cars", s.handler.MaxSubscriptions)), nil)
+		}
+
+		if err := s.handler.PresenceUC.View(ctx, req.CarID, s.rep); err != nil {
+			return s.errorReply(ctx, err, map[string]interface{}{"car_id": req.CarID})
+		}
+		s.mu.Lock()
+		s.viewing[req.CarID] = true
+		s.mu.Unlock()
+	case entity.CarSocketLeave:
+		s.mu.Lock()
+		delete(s.viewing, req.CarID)
+		s.mu.Unlock()
+
+		if err := s.handler.PresenceUC.Leave(ctx, req.CarID, s.rep); err != nil {
+			return s.errorReply(ctx, err, map[string]interface{}{"car_id": req.CarID})
+		}
+	case entity.CarSocketReserve:
+		if _, err := s.handler.ReservationUC.Reserve(ctx, req.CarID, s.rep); err != nil {
+			return s.errorReply(ctx, err, map[string]interface{}{"car_id": req.CarID})
+		}
+	case entity.CarSocketRelease:
+		if err := s.handler.ReservationUC.Release(ctx, req.CarID, s.rep); err != nil {
+			return s.errorReply(ctx, err, map[string]interface{}{"car_id": req.CarID})
+		}
+	}
+	return nil
+}
+
+// subscribe adds the cars to the subscriptions and gives the ones not subscribed to already,
+// none are added when that would go past the limit
+func (s *carSocket) subscribe(carIDs []int64) (added []int64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, carID := range carIDs {
+		if !s.subscribed[carID] && !containsID(added, carID) {
+			added = append(added, carID)
+		}
+	}
+	if len(s.subscribed)+len(added) > s.handler.MaxSubscriptions {
+		return nil, utils.NewError(utils.CodeSubscriptionLimitExceeded, fmt.Sprintf("cannot subscribe to more than %d cars", s.handler.MaxSubscriptions))
+	}
+
+	for _, carID := range added {
+		s.subscribed[carID] = true
+	}
+	return added, nil
+}
+
+func (s *carSocket) isSubscribed(carID int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.subscribed[carID]
+}
+
+// subscriptions gives the frame listing the cars subscribed to
+func (s *carSocket) subscriptions() carSocketFrame {
+	s.mu.Lock()
+	carIDs := []int64{}
+	for carID := range s.subscribed {
+		carIDs = append(carIDs, carID)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(carIDs, func(i, j int) bool { return carIDs[i] < carIDs[j] })
+	return carSocketFrame{Type: entity.CarSocketSubscribed, Data: map[string]interface{}{"car_ids": carIDs}}
+}
+
+// presence gives the frame of the viewers of the car
+func (s *carSocket) presence(ctx context.Context, carID int64) carSocketFrame {
+	presence, err := s.handler.PresenceUC.Viewers(ctx, carID)
+	if err != nil {
+		return s.errorFrame(ctx, err, map[string]interface{}{"car_id": carID})
+	}
+	return carSocketFrame{Type: entity.CarSocketPresence, Data: presence}
+}
+
+// reservation gives the frame of the reservation of the car
+func (s *carSocket) reservation(ctx context.Context, carID int64) carSocketFrame {
+	reservation, err := s.handler.ReservationUC.Get(ctx, carID)
+	if err != nil {
+		return s.errorFrame(ctx, err, map[string]interface{}{"car_id": carID})
+	}
+	return carSocketFrame{Type: entity.CarSocketReservation, Data: reservation}
+}
+
+// refresh keeps the presences of the socket from lapsing, a failure is told to the client
+// which may view the car again
+func (s *carSocket) refresh(ctx context.Context) {
+	for _, carID := range s.viewed() {
+		if err := s.handler.PresenceUC.Refresh(ctx, carID, s.rep); err != nil {
+			s.write(s.errorFrame(ctx, err, map[string]interface{}{"car_id": carID}))
+		}
+	}
+}
+
+// leaveAll ends the presences of the socket once it is closed, those failing lapse on their own
+func (s *carSocket) leaveAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.handler.WriteTimeout)
+	defer cancel()
+
+	for _, carID := range s.viewed() {
+		s.handler.PresenceUC.Leave(ctx, carID, s.rep)
+	}
+}
+
+func (s *carSocket) viewed() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	carIDs := make([]int64, 0, len(s.viewing))
+	for carID := range s.viewing {
+		carIDs = append(carIDs, carID)
+	}
+	return carIDs
+}
+
+// write sends the frame, a client not reading it within the write timeout is gone
+func (s *carSocket) write(frame carSocketFrame) error {
+	s.conn.SetWriteDeadline(time.Now().Add(s.handler.WriteTimeout))
+	return s.conn.WriteJSON(frame)
+}
+
+func (s *carSocket) close(code int, text string) {
+	s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(s.handler.WriteTimeout))
+}
+
+// errorFrame gives the frame of the error as the API would answer it, data tells what it is about
+func (s *carSocket) errorFrame(ctx context.Context, err error, data interface{}) carSocketFrame {
+	return carSocketFrame{Type: entity.CarSocketError, Data: data, Error: statusError(ctx, s.handler.Logger, err)}
+}
+
+func (s *carSocket) errorReply(ctx context.Context, err error, data interface{}) []carSocketReply {
+	return []carSocketReply{{frame: s.errorFrame(ctx, err, data)}}
+}
+
+// allowOrigins accepts handshakes from the origins, "*" accepts any. Clients that are not
+// browsers send no origin and are accepted.
+func allowOrigins(origins []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get(echo.HeaderOrigin)
+		if origin == "" {
+			return true
+		}
+		for _, allowed := range origins {
+			if allowed == "*" || allowed == origin {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,297 @@
+package http_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpDelivery "carApi/delivery/http"
+	appMiddleware "carApi/delivery/middleware"
+	"carApi/entity"
+	"carApi/infrastructure/stream"
+	"carApi/mocks"
+	"carApi/utils"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type carSocketFrame struct {
+	Type  string          `json:"type"`
+	ID    int64           `json:"id"`
+	Data  json.RawMessage `json:"data"`
+	Error json.RawMessage `json:"error"`
+}
+
+// serveCarSocket serves the handler and dials it with the key, "key-a" is the rep key of rep-a.
+// The hub and presence feed the socket.
+func serveCarSocket(t *testing.T, handler *httpDelivery.CarSocketHandler, key string, query string) (*websocket.Conn, *http.Response, error) {
+	header := http.Header{}
+	if key != "" {
+		header.Set(entity.APIKeyHeader, key)
+	}
+	return dialCarSocket(t, handler, websocket.DefaultDialer, header, query)
+}
+
+// dialCarSocket serves the handler and dials it with the dialer and header
+func dialCarSocket(t *testing.T, handler *httpDelivery.CarSocketHandler, dialer *websocket.Dialer, header http.Header, query string) (*websocket.Conn, *http.Response, error) {
+	e := echo.New()
+	httpDelivery.NewErrorHandler(e, handler.Logger)
+	e.GET("/api/v1/cars/ws", handler.Connect, appMiddleware.NewMiddleware(handler.Logger).Rep(map[string]string{"key-a": "rep-a"}))
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+
+	conn, res, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/api/v1/cars/ws"+query, header)
+	if err == nil {
+		t.Cleanup(func() { conn.Close() })
+	}
+	return conn, res, err
+}
+
+func newCarSocketHandler(carStreamUC *mocks.CarStreamUsecase, presenceUC *mocks.PresenceUsecase, reservationUC *mocks.ReservationUsecase) *httpDelivery.CarSocketHandler {
+	return &httpDelivery.CarSocketHandler{
+		CarStreamUC:      carStreamUC,
+		PresenceUC:       presenceUC,
+		ReservationUC:    reservationUC,
+		Upgrader:         websocket.Upgrader{Subprotocols: []string{entity.SocketProtocol}},
+		Heartbeat:        time.Minute,
+		WriteTimeout:     time.Second,
+		MaxMessageSize:   4096,
+		MaxSubscriptions: 2,
+		Logger:           new(mocks.Logger),
+	}
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) carSocketFrame {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	var frame carSocketFrame
+	require.NoError(t, conn.ReadJSON(&frame))
+	return frame
+}
+
+func TestCarSocketHandler_Connect(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCarStreamUC := new(mocks.CarStreamUsecase)
+		mockPresenceUC := new(mocks.PresenceUsecase)
+		mockReservationUC := new(mocks.ReservationUsecase)
+		hub := stream.NewHub(0, 10)
+		presence := stream.NewPresence(10)
+
+		mockCarStreamUC.On("Subscribe", mock.Anything, int64(0)).Return(func(ctx context.Context, lastEventID int64) *stream.Subscription {
+			return hub.Subscribe(lastEventID)
+		}).Once()
+		mockPresenceUC.On("Watch", mock.Anything).Return(func(ctx context.Context) *stream.PresenceWatch {
+			return presence.Watch()
+		}).Once()
+		mockPresenceUC.On("Viewers", mock.Anything, int64(1)).Return(entity.CarPresence{CarID: 1, Viewers: []string{}}, nil).Once()
+		mockReservationUC.On("Get", mock.Anything, int64(1)).Return(entity.CarReservation{CarID: 1}, nil).Once()
+		mockPresenceUC.On("View", mock.Anything, int64(1), "rep-a").Return(nil).Run(func(mock.Arguments) {
+			presence.Announce(context.TODO(), 1)
+		}).Once()
+		mockPresenceUC.On("Viewers", mock.Anything, int64(1)).Return(entity.CarPresence{CarID: 1, Viewers: []string{"rep-a"}}, nil).Once()
+		mockReservationUC.On("Change", mock.Anything, entity.Event{ID: 5, Type: entity.EventCarUpdated, CarID: 1}).Return(entity.CarReservation{}, false, nil).Once()
+		mockCarStreamUC.On("Change", mock.Anything, entity.Event{ID: 5, Type: entity.EventCarUpdated, CarID: 1}, entity.CarFilter{}, entity.CarDisplay{Currency: "EUR"}).
+			Return(entity.CarChange{EventID: 5, Type: entity.CarChangeUpdated, CarID: 1, Car: &entity.Car{ID: 1}}, true, nil).Once()
+		left := make(chan struct{})
+		mockPresenceUC.On("Leave", mock.Anything, int64(1), "rep-a").Return(nil).Run(func(mock.Arguments) { close(left) }).Once()
+
+		conn, _, err := serveCarSocket(t, newCarSocketHandler(mockCarStreamUC, mockPresenceUC, mockReservationUC), "key-a", "?rep=rep-a&currency=eur")
+		require.NoError(t, err)
+
+		require.NoError(t, conn.WriteJSON(map[string]interface{}{"type": "subscribe", "car_ids": []int64{1}}))
+		frame := readFrame(t, conn)
+		assert.Equal(t, "subscribed", frame.Type)
+		assert.JSONEq(t, `{"car_ids":[1]}`, string(frame.Data))
+		frame = readFrame(t, conn)
+		assert.Equal(t, "presence", frame.Type)
+		assert.JSONEq(t, `{"car_id":1,"viewers":[]}`, string(frame.Data))
+		frame = readFrame(t, conn)
+		assert.Equal(t, "reservation", frame.Type)
+		assert.JSONEq(t, `{"car_id":1,"reservation":null}`, string(frame.Data))
+
+		require.NoError(t, conn.WriteJSON(map[string]interface{}{"type": "view", "car_id": 1}))
+		frame = readFrame(t, conn)
+		assert.Equal(t, "presence", frame.Type)
+		assert.JSONEq(t, `{"car_id":1,"viewers":["rep-a"]}`, string(frame.Data))
+
+		// the changes of cars not subscribed to are left out
+		require.NoError(t, hub.Publish(context.TODO(), entity.Event{ID: 4, Type: entity.EventCarUpdated, CarID: 2}))
+		require.NoError(t, hub.Publish(context.TODO(), entity.Event{ID: 5, Type: entity.EventCarUpdated, CarID: 1}))
+		frame = readFrame(t, conn)
+		assert.Equal(t, "car.updated", frame.Type)
+		assert.Equal(t, int64(5), frame.ID)
+
+		conn.Close()
+		select {
+		case <-left:
+		case <-time.After(time.Second):
+			t.Fatal("the presence was not left on close")
+		}
+		mockCarStreamUC.AssertExpectations(t)
+		mockPresenceUC.AssertExpectations(t)
+		mockReservationUC.AssertExpectations(t)
+	})
+
+	t.Run("success-reservation", func(t *testing.T) {
+		mockCarStreamUC := new(mocks.CarStreamUsecase)
+		mockPresenceUC := new(mocks.PresenceUsecase)
+		mockReservationUC := new(mocks.ReservationUsecase)
+		hub := stream.NewHub(0, 10)
+		reservedAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+		reservation := entity.Reservation{CarID: 1, Rep: "rep-a", ReservedAt: reservedAt, ExpiresAt: reservedAt.Add(time.Hour)}
+		event := entity.Event{ID: 6, Type: entity.EventCarReserved, CarID: 1}
+
+		mockCarStreamUC.On("Subscribe", mock.Anything, int64(0)).Return(func(ctx context.Context, lastEventID int64) *stream.Subscription {
+			return hub.Subscribe(lastEventID)
+		}).Once()
+		mockPresenceUC.On("Watch", mock.Anything).Return(stream.NewPresence(10).Watch()).Once()
+		mockPresenceUC.On("Viewers", mock.Anything, int64(1)).Return(entity.CarPresence{CarID: 1, Viewers: []string{}}, nil).Once()
+		mockReservationUC.On("Get", mock.Anything, int64(1)).Return(entity.CarReservation{CarID: 1}, nil).Once()
+		mockReservationUC.On("Reserve", mock.Anything, int64(1), "rep-a").Return(reservation, nil).Run(func(mock.Arguments) {
+			hub.Publish(context.TODO(), event)
+		}).Once()
+		mockReservationUC.On("Change", mock.Anything, event).Return(entity.CarReservation{CarID: 1, Reservation: &reservation}, true, nil).Once()
+
+		conn, _, err := serveCarSocket(t, newCarSocketHandler(mockCarStreamUC, mockPresenceUC, mockReservationUC), "key-a", "")
+		require.NoError(t, err)
+
+		require.NoError(t, conn.WriteJSON(map[string]interface{}{"type": "subscribe", "car_ids": []int64{1}}))
+		for _, frameType := range []string{"subscribed", "presence", "reservation"} {
+			assert.Equal(t, frameType, readFrame(t, conn).Type)
+		}
+
+		// the reservation is pushed by its event, to the rep reserving as to any other subscriber
+		require.NoError(t, conn.WriteJSON(map[string]interface{}{"type": "reserve", "car_id": 1}))
+		frame := readFrame(t, conn)
+		assert.Equal(t, "reservation", frame.Type)
+		assert.Equal(t, int64(6), frame.ID)
+		assert.JSONEq(t, `{"car_id":1,"reservation":{"car_id":1,"rep":"rep-a","reserved_at":"2026-10-19T10:00:00Z","expires_at":"2026-10-19T11:00:00Z"}}`, string(frame.Data))
+		mockCarStreamUC.AssertNotCalled(t, "Change", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockReservationUC.AssertExpectations(t)
+	})
+
+	t.Run("success-protocol", func(t *testing.T) {
+		mockCarStreamUC := new(mocks.CarStreamUsecase)
+		mockPresenceUC := new(mocks.PresenceUsecase)
+		mockCarStreamUC.On("Subscribe", mock.Anything, int64(0)).Return(stream.NewHub(0, 10).Subscribe(0)).Once()
+		mockPresenceUC.On("Watch", mock.Anything).Return(stream.NewPresence(10).Watch()).Once()
+		viewed := make(chan struct{})
+		mockPresenceUC.On("View", mock.Anything, int64(1), "rep-a").Return(nil).Run(func(mock.Arguments) { close(viewed) }).Once()
+		mockPresenceUC.On("Leave", mock.Anything, int64(1), "rep-a").Return(nil).Maybe()
+
+		// a browser cannot set the header, it offers the key among the subprotocols
+		dialer := &websocket.Dialer{Subprotocols: []string{"carapi", "key." + base64.RawURLEncoding.EncodeToString([]byte("key-a"))}}
+		conn, _, err := dialCarSocket(t, newCarSocketHandler(mockCarStreamUC, mockPresenceUC, new(mocks.ReservationUsecase)), dialer, nil, "")
+		require.NoError(t, err)
+		assert.Equal(t, "carapi", conn.Subprotocol(), "the key is never echoed")
+
+		require.NoError(t, conn.WriteJSON(map[string]interface{}{"type": "view", "car_id": 1}))
+		select {
+		case <-viewed:
+		case <-time.After(time.Second):
+			t.Fatal("the car was not viewed as the rep of the key")
+		}
+	})
+
+	t.Run("error-anonymous", func(t *testing.T) {
+		mockCarStreamUC := new(mocks.CarStreamUsecase)
+		mockPresenceUC := new(mocks.PresenceUsecase)
+		mockReservationUC := new(mocks.ReservationUsecase)
+		mockCarStreamUC.On("Subscribe", mock.Anything, int64(0)).Return(stream.NewHub(0, 10).Subscribe(0)).Once()
+		mockPresenceUC.On("Watch", mock.Anything).Return(stream.NewPresence(10).Watch()).Once()
+
+		// a socket opened with an api key rather than a rep key only listens
+		conn, _, err := serveCarSocket(t, newCarSocketHandler(mockCarStreamUC, mockPresenceUC, mockReservationUC), "api-key", "")
+		require.NoError(t, err)
+
+		for _, message := range []string{"view", "reserve"} {
+			require.NoError(t, conn.WriteJSON(map[string]interface{}{"type": message, "car_id": 1}))
+			frame := readFrame(t, conn)
+			assert.Equal(t, "error", frame.Type)
+			assert.JSONEq(t, `{"car_id":1}`, string(frame.Data))
+			assert.Contains(t, string(frame.Error), `"status":403`)
+		}
+		mockPresenceUC.AssertNotCalled(t, "View", mock.Anything, mock.Anything, mock.Anything)
+		mockReservationUC.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-rep", func(t *testing.T) {
+		mockCarStreamUC := new(mocks.CarStreamUsecase)
+		mockPresenceUC := new(mocks.PresenceUsecase)
+
+		// a rep parameter cannot name another rep than the key does
+		_, res, err := serveCarSocket(t, newCarSocketHandler(mockCarStreamUC, mockPresenceUC, new(mocks.ReservationUsecase)), "key-a", "?rep=rep-b")
+
+		require.Error(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		mockCarStreamUC.AssertNotCalled(t, "Subscribe", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-message", func(t *testing.T) {
+		mockCarStreamUC := new(mocks.CarStreamUsecase)
+		mockPresenceUC := new(mocks.PresenceUsecase)
+		mockReservationUC := new(mocks.ReservationUsecase)
+		mockCarStreamUC.On("Subscribe", mock.Anything, int64(0)).Return(stream.NewHub(0, 10).Subscribe(0)).Once()
+		mockPresenceUC.On("Watch", mock.Anything).Return(stream.NewPresence(10).Watch()).Once()
+		mockPresenceUC.On("View", mock.Anything, int64(9), "rep-a").Return(utils.NewNotFoundError("car not found")).Once()
+
+		conn, _, err := serveCarSocket(t, newCarSocketHandler(mockCarStreamUC, mockPresenceUC, mockReservationUC), "key-a", "")
+		require.NoError(t, err)
+
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"subscribe"`)))
+		frame := readFrame(t, conn)
+		assert.Equal(t, "error", frame.Type)
+		assert.Contains(t, string(frame.Error), `"status":422`)
+
+		require.NoError(t, conn.WriteJSON(map[string]interface{}{"type": "subscribe", "car_ids": []int64{1, 2, 3}}))
+		frame = readFrame(t, conn)
+		assert.Equal(t, "error", frame.Type)
+		assert.Contains(t, string(frame.Error), "cannot subscribe to more than 2 cars")
+
+		require.NoError(t, conn.WriteJSON(map[string]interface{}{"type": "view", "car_id": 9}))
+		frame = readFrame(t, conn)
+		assert.Equal(t, "error", frame.Type)
+		assert.JSONEq(t, `{"car_id":9}`, string(frame.Data))
+		assert.Contains(t, string(frame.Error), `"status":404`)
+		mockPresenceUC.AssertExpectations(t)
+	})
+
+	t.Run("error-lagging", func(t *testing.T) {
+		mockCarStreamUC := new(mocks.CarStreamUsecase)
+		mockPresenceUC := new(mocks.PresenceUsecase)
+		mockReservationUC := new(mocks.ReservationUsecase)
+		hub := stream.NewHub(0, 10)
+		subscription := hub.Subscribe(0)
+		mockCarStreamUC.On("Subscribe", mock.Anything, int64(0)).Return(subscription).Once()
+		mockPresenceUC.On("Watch", mock.Anything).Return(stream.NewPresence(10).Watch()).Once()
+
+		conn, _, err := serveCarSocket(t, newCarSocketHandler(mockCarStreamUC, mockPresenceUC, mockReservationUC), "key-a", "")
+		require.NoError(t, err)
+
+		// the hub drops the subscription of a socket lagging behind, as it does on shutdown
+		hub.Close()
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		_, _, err = conn.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, websocket.CloseTryAgainLater), "got %v", err)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		mockCarStreamUC := new(mocks.CarStreamUsecase)
+		mockPresenceUC := new(mocks.PresenceUsecase)
+		mockReservationUC := new(mocks.ReservationUsecase)
+
+		_, res, err := serveCarSocket(t, newCarSocketHandler(mockCarStreamUC, mockPresenceUC, mockReservationUC), "key-a", "?currency=xx")
+
+		require.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		mockCarStreamUC.AssertNotCalled(t, "Subscribe", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"carApi/delivery/middleware"
+	"carApi/entity"
+	"carApi/infrastructure/tracing"
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	"carApi/utils/logger"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+)
+
+type CarStreamHandler struct {
+	CarStreamUC usecase.CarStreamUsecase
+	Heartbeat   time.Duration
+	Logger      logger.Logger
+}
+
+// NewCarStreamHandler will initialize the cars stream endpoint, heartbeat is the interval of the
+// comments keeping idle connections open through proxies
+func NewCarStreamHandler(e *echo.Echo, middleware *middleware.Middleware, carStreamUC usecase.CarStreamUsecase, heartbeat time.Duration, logger logger.Logger) {
+	handler := &CarStreamHandler{
+		CarStreamUC: carStreamUC,
+		Heartbeat:   heartbeat,
+		Logger:      logger,
+	}
+
+	apiV1 := e.Group("/api/v1")
+	apiV1.GET("/cars/stream", handler.Stream)
+}
+
+// Stream sends the changes of the inventory as server-sent events until the client goes away or
+// the server shuts down. Each event carries the id to resume from with Last-Event-ID.
+func (h *CarStreamHandler) Stream(c echo.Context) error {
+	ctx, span := tracing.Tracer().Start(c.Request().Context(), "CarStreamHandler.Stream")
+	defer span.End()
+
+	var req request.StreamCarReq
+	if err := c.Bind(&req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := (&echo.DefaultBinder{}).BindHeaders(c, &req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := req.Validate(); err != nil {
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	filter, display := req.Filter(), req.Display()
+	subscription := h.CarStreamUC.Subscribe(ctx, req.ResumeFrom())
+	defer subscription.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	if subscription.Reset {
+		if err := writeSSE(res, 0, string(entity.CarChangeReset), struct{}{}); err != nil {
+			return nil
+		}
+	}
+
+	send := func(event entity.Event) error {
+		change, ok, err := h.CarStreamUC.Change(ctx, event, filter, display)
+		if err != nil {
+			return writeSSE(res, event.ID, "error", map[string]interface{}{"car_id": event.CarID, "error": statusError(ctx, h.Logger, err)})
+		}
+		if !ok {
+			return nil
+		}
+		return writeSSE(res, change.EventID, string(change.Type), change)
+	}
+
+	for _, event := range subscription.Replay {
+		if err := send(event); err != nil {
+			return nil
+		}
+	}
+
+	var heartbeat <-chan time.Time
+	if h.Heartbeat > 0 {
+		ticker := time.NewTicker(h.Heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-subscription.Events:
+			if !ok {
+				// dropped for lagging behind or the server is shutting down, the client reconnects
+				return nil
+			}
+			if err := send(event); err != nil {
+				return nil
+			}
+		case <-heartbeat:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// writeSSE writes one event frame and flushes it, a zero id leaves the id of the stream as it is
+func writeSSE(res *echo.Response, id int64, event string, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if id != 0 {
+		if _, err := fmt.Fprintf(res, "id: %d\n", id); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, body); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
@@ -0,0 +1,143 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpDelivery "carApi/delivery/http"
+	"carApi/entity"
+	"carApi/infrastructure/stream"
+	"carApi/mocks"
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCarStreamHandler_Stream(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCarStreamUC := new(mocks.CarStreamUsecase)
+
+		events := make(chan entity.Event, 2)
+		events <- entity.Event{ID: 8, Type: entity.EventCarDeleted, CarID: 2}
+		events <- entity.Event{ID: 9, Type: entity.EventCarUpdated, CarID: 3}
+		close(events)
+		subscription := &stream.Subscription{
+			Events: events,
+			Replay: []entity.Event{{ID: 7, Type: entity.EventCarCreated, CarID: 1}},
+		}
+
+		mockCarStreamUC.On("Subscribe", mock.Anything, int64(6)).Return(subscription).Once()
+		mockCarStreamUC.On("Change", mock.Anything, entity.Event{ID: 7, Type: entity.EventCarCreated, CarID: 1}, mock.AnythingOfType("entity.CarFilter"), mock.AnythingOfType("entity.CarDisplay")).
+			Return(entity.CarChange{EventID: 7, Type: entity.CarChangeCreated, CarID: 1, Car: &entity.Car{ID: 1, Make: "Toyota"}}, true, nil).Once()
+		mockCarStreamUC.On("Change", mock.Anything, entity.Event{ID: 8, Type: entity.EventCarDeleted, CarID: 2}, mock.AnythingOfType("entity.CarFilter"), mock.AnythingOfType("entity.CarDisplay")).
+			Return(entity.CarChange{EventID: 8, Type: entity.CarChangeDeleted, CarID: 2}, true, nil).Once()
+		mockCarStreamUC.On("Change", mock.Anything, entity.Event{ID: 9, Type: entity.EventCarUpdated, CarID: 3}, mock.AnythingOfType("entity.CarFilter"), mock.AnythingOfType("entity.CarDisplay")).
+			Return(entity.CarChange{}, false, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars/stream?features=gps", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Last-Event-ID", "6")
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/stream")
+
+		handler := httpDelivery.CarStreamHandler{
+			CarStreamUC: mockCarStreamUC,
+		}
+		err = handler.Stream(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/event-stream", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Body.String(), "id: 7\nevent: car.created\ndata: {\"car_id\":1,\"car\":{")
+		assert.Contains(t, rec.Body.String(), "id: 8\nevent: car.deleted\ndata: {\"car_id\":2}\n\n")
+		assert.NotContains(t, rec.Body.String(), "id: 9")
+		mockCarStreamUC.AssertExpectations(t)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		mockCarStreamUC := new(mocks.CarStreamUsecase)
+
+		events := make(chan entity.Event)
+		close(events)
+		mockCarStreamUC.On("Subscribe", mock.Anything, int64(3)).Return(&stream.Subscription{Events: events, Reset: true}).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars/stream?last_event_id=3", nil)
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/stream")
+
+		handler := httpDelivery.CarStreamHandler{
+			CarStreamUC: mockCarStreamUC,
+		}
+		err = handler.Stream(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, "event: reset\ndata: {}\n\n", rec.Body.String())
+		mockCarStreamUC.AssertExpectations(t)
+	})
+
+	t.Run("error-change", func(t *testing.T) {
+		mockCarStreamUC := new(mocks.CarStreamUsecase)
+
+		events := make(chan entity.Event, 1)
+		events <- entity.Event{ID: 4, Type: entity.EventCarCreated, CarID: 1}
+		close(events)
+		mockCarStreamUC.On("Subscribe", mock.Anything, int64(0)).Return(&stream.Subscription{Events: events}).Once()
+		mockCarStreamUC.On("Change", mock.Anything, mock.AnythingOfType("entity.Event"), mock.AnythingOfType("entity.CarFilter"), mock.AnythingOfType("entity.CarDisplay")).
+			Return(entity.CarChange{}, false, errors.New("unexpected error")).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars/stream", nil)
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/stream")
+
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Errorw", "INTERNAL ERROR", "request_id", "", "error", "unexpected error").Once()
+
+		handler := httpDelivery.CarStreamHandler{
+			CarStreamUC: mockCarStreamUC,
+			Logger:      mockLogger,
+		}
+		err = handler.Stream(c)
+
+		require.NoError(t, err)
+		assert.Contains(t, rec.Body.String(), "id: 4\nevent: error\ndata: {\"car_id\":1,")
+		mockCarStreamUC.AssertExpectations(t)
+		mockLogger.AssertExpectations(t)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		mockCarStreamUC := new(mocks.CarStreamUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/cars/stream?last_event_id=-1", nil)
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/stream")
+
+		handler := httpDelivery.CarStreamHandler{
+			CarStreamUC: mockCarStreamUC,
+		}
+		err = handler.Stream(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		mockCarStreamUC.AssertNotCalled(t, "Subscribe", mock.Anything, mock.Anything)
+	})
+}
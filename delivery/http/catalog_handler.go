@@ -0,0 +1,127 @@
+package http
+
+import (
+	"net/http"
+
+	"carApi/delivery/middleware"
+	"carApi/entity"
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+)
+
+type CatalogHandler struct {
+	CatalogUC usecase.CatalogUsecase
+}
+
+// NewCatalogHandler will initialize the catalog / resources endpoint,
+// every entry is addressed by its name and named after its kind in the route params
+func NewCatalogHandler(e *echo.Echo, middleware *middleware.Middleware, catalogUC usecase.CatalogUsecase) {
+	handler := &CatalogHandler{
+		CatalogUC: catalogUC,
+	}
+
+	apiV1 := e.Group("/api/v1/catalog")
+	apiV1.GET("/makes", handler.Fetch(entity.CatalogKindMake))
+	apiV1.POST("/makes", handler.Create(entity.CatalogKindMake))
+	apiV1.PUT("/makes/:make", handler.Update(entity.CatalogKindMake))
+	apiV1.DELETE("/makes/:make", handler.Delete(entity.CatalogKindMake))
+
+	apiV1.GET("/makes/:make/models", handler.Fetch(entity.CatalogKindModel))
+	apiV1.POST("/makes/:make/models", handler.Create(entity.CatalogKindModel))
+	apiV1.PUT("/makes/:make/models/:model", handler.Update(entity.CatalogKindModel))
+	apiV1.DELETE("/makes/:make/models/:model", handler.Delete(entity.CatalogKindModel))
+
+	apiV1.GET("/makes/:make/models/:model/trims", handler.Fetch(entity.CatalogKindTrim))
+	apiV1.POST("/makes/:make/models/:model/trims", handler.Create(entity.CatalogKindTrim))
+	apiV1.PUT("/makes/:make/models/:model/trims/:trim", handler.Update(entity.CatalogKindTrim))
+	apiV1.DELETE("/makes/:make/models/:model/trims/:trim", handler.Delete(entity.CatalogKindTrim))
+
+	apiV1.GET("/categories", handler.Fetch(entity.CatalogKindCategory))
+	apiV1.POST("/categories", handler.Create(entity.CatalogKindCategory))
+	apiV1.PUT("/categories/:category", handler.Update(entity.CatalogKindCategory))
+	apiV1.DELETE("/categories/:category", handler.Delete(entity.CatalogKindCategory))
+}
+
+func (h *CatalogHandler) Fetch(kind string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		entries, err := h.CatalogUC.Fetch(ctx, kind, catalogPath(c))
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{"data": entries})
+	}
+}
+
+func (h *CatalogHandler) Create(kind string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req request.CatalogEntryReq
+
+		if err := c.Bind(&req); err != nil {
+			return utils.NewUnprocessableEntityError(err.Error())
+		}
+
+		if err := req.Validate(); err != nil {
+			errVal := err.(validation.Errors)
+			return utils.NewInvalidInputError(errVal)
+		}
+
+		entry, err := h.CatalogUC.Create(ctx, kind, catalogPath(c), &req)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{"data": entry})
+	}
+}
+
+func (h *CatalogHandler) Update(kind string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req request.CatalogEntryReq
+
+		if err := c.Bind(&req); err != nil {
+			return utils.NewUnprocessableEntityError(err.Error())
+		}
+
+		if err := req.Validate(); err != nil {
+			errVal := err.(validation.Errors)
+			return utils.NewInvalidInputError(errVal)
+		}
+
+		if err := h.CatalogUC.Update(ctx, kind, catalogPath(c), c.Param(kind), &req); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"message": kind + " updated",
+		})
+	}
+}
+
+func (h *CatalogHandler) Delete(kind string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		if err := h.CatalogUC.Delete(ctx, kind, catalogPath(c), c.Param(kind)); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"message": kind + " deleted",
+		})
+	}
+}
+
+func catalogPath(c echo.Context) entity.CatalogPath {
+	return entity.CatalogPath{
+		Make:  c.Param(entity.CatalogKindMake),
+		Model: c.Param(entity.CatalogKindModel),
+	}
+}
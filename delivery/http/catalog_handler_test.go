@@ -0,0 +1,175 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpDelivery "carApi/delivery/http"
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogHandler_Fetch(t *testing.T) {
+	mockCatalogUC := new(mocks.CatalogUsecase)
+
+	t.Run("success-models-of-make", func(t *testing.T) {
+		mockCatalogUC.On("Fetch", mock.Anything, entity.CatalogKindModel, entity.CatalogPath{Make: "toyota"}).
+			Return([]entity.CatalogEntry{{ID: 2, Kind: entity.CatalogKindModel, ParentID: 1, Name: "Corolla"}}, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/catalog/makes/toyota/models", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/catalog/makes/:make/models")
+		c.SetParamNames("make")
+		c.SetParamValues("toyota")
+
+		handler := httpDelivery.CatalogHandler{
+			CatalogUC: mockCatalogUC,
+		}
+		err = handler.Fetch(entity.CatalogKindModel)(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Corolla")
+		mockCatalogUC.AssertExpectations(t)
+	})
+
+	t.Run("make-not-exist", func(t *testing.T) {
+		mockCatalogUC.On("Fetch", mock.Anything, entity.CatalogKindModel, entity.CatalogPath{Make: "tesla"}).
+			Return(nil, utils.NewNotFoundError("make not found")).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/catalog/makes/tesla/models", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/catalog/makes/:make/models")
+		c.SetParamNames("make")
+		c.SetParamValues("tesla")
+
+		handler := httpDelivery.CatalogHandler{
+			CatalogUC: mockCatalogUC,
+		}
+		err = handler.Fetch(entity.CatalogKindModel)(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusNotFound, code)
+		mockCatalogUC.AssertExpectations(t)
+	})
+}
+
+func TestCatalogHandler_Create(t *testing.T) {
+	mockCatalogUC := new(mocks.CatalogUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockCatalogUC.On("Create", mock.Anything, entity.CatalogKindCategory, entity.CatalogPath{}, mock.AnythingOfType("*request.CatalogEntryReq")).
+			Return(entity.CatalogEntry{ID: 4, Kind: entity.CatalogKindCategory, Name: "SUV"}, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/api/v1/catalog/categories", strings.NewReader(`{"name":"SUV"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/catalog/categories")
+
+		handler := httpDelivery.CatalogHandler{
+			CatalogUC: mockCatalogUC,
+		}
+		err = handler.Create(entity.CatalogKindCategory)(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockCatalogUC.AssertExpectations(t)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/api/v1/catalog/categories", strings.NewReader(`{"name":""}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/catalog/categories")
+
+		handler := httpDelivery.CatalogHandler{
+			CatalogUC: mockCatalogUC,
+		}
+		err = handler.Create(entity.CatalogKindCategory)(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		mockCatalogUC.AssertExpectations(t)
+	})
+}
+
+func TestCatalogHandler_Update(t *testing.T) {
+	mockCatalogUC := new(mocks.CatalogUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockCatalogUC.On("Update", mock.Anything, entity.CatalogKindTrim, entity.CatalogPath{Make: "toyota", Model: "corolla"}, "le", mock.AnythingOfType("*request.CatalogEntryReq")).
+			Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.PUT, "/api/v1/catalog/makes/toyota/models/corolla/trims/le", strings.NewReader(`{"name":"LE"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/catalog/makes/:make/models/:model/trims/:trim")
+		c.SetParamNames("make", "model", "trim")
+		c.SetParamValues("toyota", "corolla", "le")
+
+		handler := httpDelivery.CatalogHandler{
+			CatalogUC: mockCatalogUC,
+		}
+		err = handler.Update(entity.CatalogKindTrim)(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockCatalogUC.AssertExpectations(t)
+	})
+}
+
+func TestCatalogHandler_Delete(t *testing.T) {
+	mockCatalogUC := new(mocks.CatalogUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockCatalogUC.On("Delete", mock.Anything, entity.CatalogKindMake, entity.CatalogPath{Make: "toyota"}, "toyota").Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.DELETE, "/api/v1/catalog/makes/toyota", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/catalog/makes/:make")
+		c.SetParamNames("make")
+		c.SetParamValues("toyota")
+
+		handler := httpDelivery.CatalogHandler{
+			CatalogUC: mockCatalogUC,
+		}
+		err = handler.Delete(entity.CatalogKindMake)(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockCatalogUC.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"carApi/delivery/middleware"
+	"carApi/utils"
+	"carApi/utils/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// echoErrorCodes are the codes of the statuses echo and its middlewares answer on their own, an
+// unmatched route or a body over the limit for instance
+var echoErrorCodes = map[int]utils.ErrorCode{
+	http.StatusBadRequest:            utils.CodeBadRequest,
+	http.StatusUnauthorized:          utils.CodeUnauthorized,
+	http.StatusForbidden:             utils.CodeForbidden,
+	http.StatusNotFound:              utils.CodeNotFound,
+	http.StatusMethodNotAllowed:      utils.CodeMethodNotAllowed,
+	http.StatusRequestEntityTooLarge: utils.CodePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  utils.CodeUnsupportedMediaType,
+	http.StatusUnprocessableEntity:   utils.CodeUnprocessableEntity,
+}
+
+type ErrorHandler struct {
+	Logger logger.Logger
+}
+
+// NewErrorHandler will make every error returned by the handlers and middlewares of e, panics
+// recovered included, answered in the same envelope
+func NewErrorHandler(e *echo.Echo, logger logger.Logger) {
+	handler := &ErrorHandler{
+		Logger: logger,
+	}
+
+	e.HTTPErrorHandler = handler.Handle
+}
+
+// Handle writes the body middleware.ErrorBody gives for the error, as XML or MessagePack when one
+// was picked and as JSON otherwise
+func (h *ErrorHandler) Handle(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	httpErr := statusError(c.Request().Context(), h.Logger, err)
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(httpErr.Status())
+	} else {
+		err = writeError(c, httpErr)
+	}
+	if err != nil {
+		h.Logger.Errorw("WRITING ERROR FAILED", "request_id", utils.GetReqID(c.Request().Context()), "error", err.Error())
+	}
+}
+
+// statusError finds the error carrying a status in the chain of err, the errors of echo keep
+// theirs. Any other error is logged with the request id and hidden behind an internal error.
+func statusError(ctx context.Context, logger logger.Logger, err error) utils.HttpErr {
+	var httpErr utils.HttpErr
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+
+	var echoErr *echo.HTTPError
+	if errors.As(err, &echoErr) && echoErr.Code < http.StatusInternalServerError {
+		details := fmt.Sprint(echoErr.Message)
+		if code, ok := echoErrorCodes[echoErr.Code]; ok {
+			return utils.NewError(code, details)
+		}
+		return utils.NewHttpError(echoErr.Code, strings.ToLower(http.StatusText(echoErr.Code)), details)
+	}
+
+	logger.Errorw("INTERNAL ERROR", "request_id", utils.GetReqID(ctx), "error", err.Error())
+	return utils.NewInternalServerError(err)
+}
+
+func writeError(c echo.Context, err utils.HttpErr) error {
+	body := middleware.ErrorBody(c, err)
+
+	switch format, _ := c.Get(middleware.FormatKey).(string); format {
+	case echo.MIMEApplicationXML, echo.MIMEApplicationMsgpack:
+		return render(c, err.Status(), format, body)
+	}
+
+	if _, ok := body.(utils.Problem); !ok {
+		return c.JSON(err.Status(), body)
+	}
+	data, marshalErr := json.Marshal(body)
+	if marshalErr != nil {
+		return marshalErr
+	}
+	return c.Blob(err.Status(), middleware.MIMEApplicationProblemJSON, data)
+}
@@ -0,0 +1,123 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpDelivery "carApi/delivery/http"
+	"carApi/delivery/middleware"
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHandler_Handle(t *testing.T) {
+	notFound := utils.NewError(utils.CodeCarNotFound, "car not found")
+
+	t.Run("problem", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(echo.GET, "/api/v1/cars/1", nil)
+		req.Header.Set(entity.RequestIDHeader, "req-1")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := httpDelivery.ErrorHandler{Logger: new(mocks.Logger)}
+		handler.Handle(fmt.Errorf("get car: %w", notFound), c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, middleware.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+		assert.JSONEq(t, `{"type":"urn:carapi:problem:car-not-found","title":"Car not found","status":404,"detail":"car not found","instance":"req-1","code":"CAR_NOT_FOUND"}`, rec.Body.String())
+	})
+
+	t.Run("problem-xml", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(echo.GET, "/api/v1/cars/1", nil)
+		req.Header.Set(entity.RequestIDHeader, "req-1")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set(middleware.FormatKey, echo.MIMEApplicationXML)
+
+		handler := httpDelivery.ErrorHandler{Logger: new(mocks.Logger)}
+		handler.Handle(notFound, c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, middleware.MIMEApplicationProblemXML+"; charset=UTF-8", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Body.String(), "<problem><type>urn:carapi:problem:car-not-found</type><title>Car not found</title><status>404</status>")
+		assert.Contains(t, rec.Body.String(), "<instance>req-1</instance><code>CAR_NOT_FOUND</code></problem>")
+	})
+
+	t.Run("legacy", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(echo.GET, "/api/v1/cars/1", nil)
+		req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := httpDelivery.ErrorHandler{Logger: new(mocks.Logger)}
+		handler.Handle(notFound, c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"status":404,"error":"not found","details":"car not found"}`, rec.Body.String())
+	})
+
+	t.Run("echo-errors", func(t *testing.T) {
+		tests := []struct {
+			err    error
+			status int
+			code   string
+		}{
+			{err: echo.ErrNotFound, status: http.StatusNotFound, code: "NOT_FOUND"},
+			{err: echo.ErrMethodNotAllowed, status: http.StatusMethodNotAllowed, code: "METHOD_NOT_ALLOWED"},
+			{err: echo.ErrStatusRequestEntityTooLarge, status: http.StatusRequestEntityTooLarge, code: "PAYLOAD_TOO_LARGE"},
+		}
+
+		for _, tt := range tests {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(echo.GET, "/", nil), rec)
+
+			handler := httpDelivery.ErrorHandler{Logger: new(mocks.Logger)}
+			handler.Handle(tt.err, c)
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"code":"`+tt.code+`"`)
+		}
+	})
+
+	t.Run("internal", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(echo.GET, "/api/v1/cars/1", nil)
+		req = req.WithContext(context.WithValue(req.Context(), entity.RequestIDKey, "req-1"))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Errorw", "INTERNAL ERROR", "request_id", "req-1", "error", "connection refused").Once()
+
+		handler := httpDelivery.ErrorHandler{Logger: mockLogger}
+		handler.Handle(errors.New("connection refused"), c)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"INTERNAL_ERROR"`)
+		assert.NotContains(t, rec.Body.String(), "connection refused")
+		mockLogger.AssertExpectations(t)
+	})
+
+	t.Run("head", func(t *testing.T) {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(echo.HEAD, "/api/v1/cars/1", nil), rec)
+
+		handler := httpDelivery.ErrorHandler{Logger: new(mocks.Logger)}
+		handler.Handle(notFound, c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+}
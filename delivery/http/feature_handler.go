@@ -0,0 +1,181 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"carApi/delivery/middleware"
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+)
+
+type FeatureHandler struct {
+	FeatureUC usecase.FeatureUsecase
+}
+
+// NewFeatureHandler will initialize the features / resources endpoint and the car features endpoint
+func NewFeatureHandler(e *echo.Echo, middleware *middleware.Middleware, featureUC usecase.FeatureUsecase) {
+	handler := &FeatureHandler{
+		FeatureUC: featureUC,
+	}
+
+	apiV1 := e.Group("/api/v1")
+	apiV1.POST("/features", handler.Create)
+	apiV1.GET("/features/:id", handler.GetByID)
+	apiV1.GET("/features", handler.Fetch)
+	apiV1.PUT("/features/:id", handler.Update)
+	apiV1.DELETE("/features/:id", handler.Delete)
+
+	apiV1.GET("/cars/:id/features", handler.FetchByCar)
+	apiV1.POST("/cars/:id/features", handler.AttachToCar)
+	apiV1.DELETE("/cars/:id/features/:code", handler.DetachFromCar)
+}
+
+func (h *FeatureHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.CreateFeatureReq
+
+	if err := c.Bind(&req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := req.Validate(); err != nil {
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	feature, err := h.FeatureUC.Create(ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": feature})
+}
+
+func (h *FeatureHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeFeatureNotFound, "feature not found")
+	}
+
+	feature, err := h.FeatureUC.GetByID(ctx, int64(id))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": feature})
+}
+
+func (h *FeatureHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	features, err := h.FeatureUC.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": features})
+}
+
+func (h *FeatureHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeFeatureNotFound, "feature not found")
+	}
+
+	var req request.UpdateFeatureReq
+	if err := c.Bind(&req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := req.Validate(); err != nil {
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	if err := h.FeatureUC.Update(ctx, int64(id), &req); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "feature updated",
+	})
+}
+
+func (h *FeatureHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeFeatureNotFound, "feature not found")
+	}
+
+	if err := h.FeatureUC.Delete(ctx, int64(id)); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "feature deleted",
+	})
+}
+
+func (h *FeatureHandler) FetchByCar(c echo.Context) error {
+	ctx := c.Request().Context()
+	carID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeCarNotFound, "car not found")
+	}
+
+	features, err := h.FeatureUC.FetchByCar(ctx, int64(carID))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": features})
+}
+
+func (h *FeatureHandler) AttachToCar(c echo.Context) error {
+	ctx := c.Request().Context()
+	carID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeCarNotFound, "car not found")
+	}
+
+	var req request.AttachCarFeaturesReq
+	if err := c.Bind(&req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := req.Validate(); err != nil {
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	if err := h.FeatureUC.AttachToCar(ctx, int64(carID), &req); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "car features attached",
+	})
+}
+
+func (h *FeatureHandler) DetachFromCar(c echo.Context) error {
+	ctx := c.Request().Context()
+	carID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeCarNotFound, "car not found")
+	}
+
+	if err := h.FeatureUC.DetachFromCar(ctx, int64(carID), c.Param("code")); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "car feature detached",
+	})
+}
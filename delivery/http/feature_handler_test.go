@@ -0,0 +1,192 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpDelivery "carApi/delivery/http"
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureHandler_Create(t *testing.T) {
+	mockFeatureUC := new(mocks.FeatureUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockFeatureUC.On("Create", mock.Anything, mock.AnythingOfType("*request.CreateFeatureReq")).
+			Return(entity.Feature{ID: 1, Code: "sunroof", Name: "Sunroof"}, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/api/v1/features", strings.NewReader(`{"code":"sunroof","name":"Sunroof"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/features")
+
+		handler := httpDelivery.FeatureHandler{
+			FeatureUC: mockFeatureUC,
+		}
+		err = handler.Create(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockFeatureUC.AssertExpectations(t)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/api/v1/features", strings.NewReader(`{"code":"Sun Roof","name":"Sunroof"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/features")
+
+		handler := httpDelivery.FeatureHandler{
+			FeatureUC: mockFeatureUC,
+		}
+		err = handler.Create(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		mockFeatureUC.AssertExpectations(t)
+	})
+
+	t.Run("error-conflict", func(t *testing.T) {
+		mockFeatureUC.On("Create", mock.Anything, mock.AnythingOfType("*request.CreateFeatureReq")).
+			Return(entity.Feature{}, utils.NewConflictError("feature sunroof already exists")).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/api/v1/features", strings.NewReader(`{"code":"sunroof","name":"Sunroof"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/features")
+
+		handler := httpDelivery.FeatureHandler{
+			FeatureUC: mockFeatureUC,
+		}
+		err = handler.Create(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusConflict, code)
+		mockFeatureUC.AssertExpectations(t)
+	})
+}
+
+func TestFeatureHandler_Fetch(t *testing.T) {
+	mockFeatureUC := new(mocks.FeatureUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockFeatureUC.On("Fetch", mock.Anything).Return([]entity.Feature{{ID: 1, Code: "sunroof"}}, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/features", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/features")
+
+		handler := httpDelivery.FeatureHandler{
+			FeatureUC: mockFeatureUC,
+		}
+		err = handler.Fetch(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockFeatureUC.AssertExpectations(t)
+	})
+}
+
+func TestFeatureHandler_AttachToCar(t *testing.T) {
+	mockFeatureUC := new(mocks.FeatureUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockFeatureUC.On("AttachToCar", mock.Anything, int64(1), mock.AnythingOfType("*request.AttachCarFeaturesReq")).Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/api/v1/cars/1/features", strings.NewReader(`{"features":["sunroof","heated_seats"]}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/:id/features")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		handler := httpDelivery.FeatureHandler{
+			FeatureUC: mockFeatureUC,
+		}
+		err = handler.AttachToCar(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockFeatureUC.AssertExpectations(t)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/api/v1/cars/1/features", strings.NewReader(`{"features":[]}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/:id/features")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		handler := httpDelivery.FeatureHandler{
+			FeatureUC: mockFeatureUC,
+		}
+		err = handler.AttachToCar(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		mockFeatureUC.AssertExpectations(t)
+	})
+}
+
+func TestFeatureHandler_DetachFromCar(t *testing.T) {
+	mockFeatureUC := new(mocks.FeatureUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockFeatureUC.On("DetachFromCar", mock.Anything, int64(1), "sunroof").Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.DELETE, "/api/v1/cars/1/features/sunroof", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/cars/:id/features/:code")
+		c.SetParamNames("id", "code")
+		c.SetParamValues("1", "sunroof")
+
+		handler := httpDelivery.FeatureHandler{
+			FeatureUC: mockFeatureUC,
+		}
+		err = handler.DetachFromCar(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockFeatureUC.AssertExpectations(t)
+	})
+}
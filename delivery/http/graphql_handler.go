@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+
+	graphqlDelivery "carApi/delivery/graphql"
+	"carApi/delivery/middleware"
+	"carApi/infrastructure/tracing"
+	"carApi/transport/request"
+	"carApi/utils"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+)
+
+type GraphQLHandler struct {
+	Executor *graphqlDelivery.Executor
+}
+
+// NewGraphQLHandler will initialize the graphql endpoint
+func NewGraphQLHandler(e *echo.Echo, middleware *middleware.Middleware, executor *graphqlDelivery.Executor) {
+	handler := &GraphQLHandler{
+		Executor: executor,
+	}
+
+	apiV1 := e.Group("/api/v1")
+	apiV1.POST("/graphql", handler.Query)
+}
+
+// Query runs a query or a mutation. Errors raised while resolving come back next to the data
+// with a 200, a request that could not run at all gets a 400.
+func (h *GraphQLHandler) Query(c echo.Context) error {
+	ctx, span := tracing.Tracer().Start(c.Request().Context(), "GraphQLHandler.Query")
+	defer span.End()
+
+	var req request.GraphQLReq
+	if err := c.Bind(&req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := (&echo.DefaultBinder{}).BindHeaders(c, &req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := req.Validate(); err != nil {
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	result := h.Executor.Execute(ctx, req)
+	if result.Data == nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,86 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	graphqlDelivery "carApi/delivery/graphql"
+	httpDelivery "carApi/delivery/http"
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLHandler_Query(t *testing.T) {
+	newHandler := func(t *testing.T, mockCarUC *mocks.CarUsecase) httpDelivery.GraphQLHandler {
+		executor, err := graphqlDelivery.NewExecutor(mockCarUC, new(mocks.CarRelationUsecase), graphqlDelivery.Limits{MaxDepth: 3, MaxComplexity: 100}, new(mocks.Logger))
+		require.NoError(t, err)
+		return httpDelivery.GraphQLHandler{Executor: executor}
+	}
+
+	query := func(handler httpDelivery.GraphQLHandler, body string) (*httptest.ResponseRecorder, error) {
+		e := echo.New()
+		req := httptest.NewRequest(echo.POST, "/api/v1/graphql", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Accept-Language", "en-US")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/graphql")
+		return rec, handler.Query(c)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+		mockCarUC.On("GetByID", mock.Anything, int64(1), entity.CarDisplay{MileageUnit: entity.MileageUnitMiles}).
+			Return(entity.Car{ID: 1, Make: "Toyota"}, nil).Once()
+
+		rec, err := query(newHandler(t, mockCarUC), `{"query":"query Car($id: ID!) { car(id: $id) { id make } }","operationName":"Car","variables":{"id":"1"}}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data":{"car":{"id":"1","make":"Toyota"}}}`, rec.Body.String())
+		mockCarUC.AssertExpectations(t)
+	})
+
+	t.Run("error-limits", func(t *testing.T) {
+		mockCarUC := new(mocks.CarUsecase)
+
+		rec, err := query(newHandler(t, mockCarUC), `{"query":"{ car(id: \"1\") { priceHistory { price { amount } } } }"}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "query depth 4 exceeds the limit of 3")
+		mockCarUC.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-syntax", func(t *testing.T) {
+		rec, err := query(newHandler(t, new(mocks.CarUsecase)), `{"query":"{ car(id: "}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"errors"`)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		_, err := query(newHandler(t, new(mocks.CarUsecase)), `{"variables":{}}`)
+
+		require.Error(t, err)
+		code, httpErr := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, []utils.FieldError{{Field: "query", Error: "cannot be blank"}}, httpErr.Details())
+	})
+
+	t.Run("error-bind", func(t *testing.T) {
+		_, err := query(newHandler(t, new(mocks.CarUsecase)), `{"query":1}`)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+}
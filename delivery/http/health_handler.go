@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+
+	"carApi/infrastructure/health"
+	"carApi/infrastructure/lifecycle"
+	"github.com/labstack/echo/v4"
+)
+
+type HealthHandler struct {
+	Lifecycle *lifecycle.Lifecycle
+	Checker   *health.Checker
+}
+
+// NewHealthHandler will initialize the liveness and readiness endpoints
+func NewHealthHandler(e *echo.Echo, lifecycle *lifecycle.Lifecycle, checker *health.Checker) {
+	handler := &HealthHandler{
+		Lifecycle: lifecycle,
+		Checker:   checker,
+	}
+
+	e.GET("/healthz", handler.Healthz)
+	e.GET("/readyz", handler.Readyz)
+}
+
+// Healthz only tells the process is serving, dependencies are left to Readyz
+// so an outage of Postgres or Redis does not get every instance restarted
+func (h *HealthHandler) Healthz(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{"status": "alive"})
+}
+
+// Readyz reports 503 as soon as the instance starts draining or a critical
+// dependency is down, a degraded cache keeps the instance in rotation
+func (h *HealthHandler) Readyz(c echo.Context) error {
+	if !h.Lifecycle.Ready() {
+		return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{"status": "draining"})
+	}
+
+	report := h.Checker.Check(c.Request().Context())
+	if report.Status == health.StatusDown {
+		return c.JSON(http.StatusServiceUnavailable, report)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
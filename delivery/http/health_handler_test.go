@@ -0,0 +1,97 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpDelivery "carApi/delivery/http"
+	"carApi/infrastructure/health"
+	"carApi/infrastructure/lifecycle"
+	"carApi/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler_Healthz(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := httpDelivery.HealthHandler{}
+	err := handler.Healthz(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestHealthHandler_Readyz(t *testing.T) {
+	var dbErr, cacheErr error
+	ping := func(err *error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			return *err
+		}
+	}
+
+	lc := lifecycle.NewLifecycle(new(mocks.Logger))
+	handler := httpDelivery.HealthHandler{
+		Lifecycle: lc,
+		Checker: health.NewChecker(
+			health.Check{Name: "database", Critical: true, Timeout: time.Second, Ping: ping(&dbErr)},
+			health.Check{Name: "cache", Critical: false, Timeout: time.Second, Ping: ping(&cacheErr)},
+		),
+	}
+
+	readyz := func() *httptest.ResponseRecorder {
+		e := echo.New()
+		req := httptest.NewRequest(echo.GET, "/readyz", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		require.NoError(t, handler.Readyz(c))
+		return rec
+	}
+
+	t.Run("ready", func(t *testing.T) {
+		lc.SetReady(true)
+
+		rec := readyz()
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"up"`)
+	})
+
+	t.Run("cache-degraded", func(t *testing.T) {
+		cacheErr = errors.New("connection refused")
+
+		rec := readyz()
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"degraded"`)
+		assert.Contains(t, rec.Body.String(), "connection refused")
+	})
+
+	t.Run("database-down", func(t *testing.T) {
+		dbErr = errors.New("connection refused")
+
+		rec := readyz()
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"down"`)
+	})
+
+	t.Run("draining", func(t *testing.T) {
+		dbErr, cacheErr = nil, nil
+		lc.SetReady(false)
+
+		rec := readyz()
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Contains(t, rec.Body.String(), "draining")
+	})
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+
+	"carApi/delivery/middleware"
+	"carApi/infrastructure/tracing"
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// csvTable is a body that can be written as CSV, the first record names the columns
+type csvTable interface {
+	CSVRecords() [][]string
+}
+
+// respond writes the body in the format the Negotiate middleware picked, JSON when none was
+// picked or when CSV was asked for a body that is not a table. The serialization runs in its own
+// span so a slow one on large listings shows up in the trace.
+func respond(ctx context.Context, c echo.Context, code int, body interface{}) error {
+	format, _ := c.Get(middleware.FormatKey).(string)
+	_, span := tracing.Tracer().Start(ctx, "response.encode", trace.WithAttributes(attribute.String("format", format)))
+	err := render(c, code, format, body)
+	tracing.End(span, err)
+	return err
+}
+
+func render(c echo.Context, code int, format string, body interface{}) error {
+	switch format {
+	case echo.MIMEApplicationXML:
+		if _, ok := body.(utils.Problem); ok {
+			data, err := marshalXML(body, "problem")
+			if err != nil {
+				return err
+			}
+			return c.Blob(code, middleware.MIMEApplicationProblemXML+"; charset=UTF-8", data)
+		}
+		data, err := marshalXML(body, "response")
+		if err != nil {
+			return err
+		}
+		return c.Blob(code, echo.MIMEApplicationXMLCharsetUTF8, data)
+	case echo.MIMEApplicationMsgpack:
+		var buf bytes.Buffer
+		enc := msgpack.NewEncoder(&buf)
+		enc.SetCustomStructTag("json")
+		if err := enc.Encode(body); err != nil {
+			return err
+		}
+		return c.Blob(code, echo.MIMEApplicationMsgpack, buf.Bytes())
+	case middleware.MIMETextCSV:
+		if table, ok := body.(csvTable); ok {
+			var buf bytes.Buffer
+			if err := csv.NewWriter(&buf).WriteAll(table.CSVRecords()); err != nil {
+				return err
+			}
+			return c.Blob(code, middleware.MIMETextCSV+"; charset=UTF-8", buf.Bytes())
+		}
+	}
+	return c.JSON(code, body)
+}
+
+// marshalXML writes the JSON form of the body as XML so both formats name the fields alike. The
+// document is a root element, an object gives an element per key, an array an item element per
+// value and null an empty element.
+func marshalXML(body interface{}, root string) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	if err := encodeXML(enc, dec, root); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeXML(enc *xml.Encoder, dec *json.Decoder, name string) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		for dec.More() {
+			child := "item"
+			if token == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				child = key.(string)
+			}
+			if err := encodeXML(enc, dec, child); err != nil {
+				return err
+			}
+		}
+		// the closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(token))); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
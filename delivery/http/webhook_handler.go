@@ -0,0 +1,169 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"carApi/delivery/middleware"
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookHandler struct {
+	WebhookUC usecase.WebhookUsecase
+}
+
+// NewWebhookHandler will initialize the webhooks / resources endpoint and their delivery logs, all
+// of them need one of apiKeys
+func NewWebhookHandler(e *echo.Echo, middleware *middleware.Middleware, webhookUC usecase.WebhookUsecase, apiKeys []string) {
+	handler := &WebhookHandler{
+		WebhookUC: webhookUC,
+	}
+
+	webhooks := e.Group("/api/v1/webhooks", middleware.RequireAPIKey(apiKeys))
+	webhooks.POST("", handler.Create)
+	webhooks.GET("/:id", handler.GetByID)
+	webhooks.GET("", handler.Fetch)
+	webhooks.PUT("/:id", handler.Update)
+	webhooks.DELETE("/:id", handler.Delete)
+
+	webhooks.GET("/:id/deliveries", handler.FetchDeliveries)
+	webhooks.POST("/:id/deliveries/:deliveryId/redeliver", handler.Redeliver)
+}
+
+func (h *WebhookHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.CreateWebhookReq
+
+	if err := c.Bind(&req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := req.Validate(); err != nil {
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	subscription, err := h.WebhookUC.Create(ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": subscription})
+}
+
+func (h *WebhookHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeWebhookNotFound, "webhook not found")
+	}
+
+	subscription, err := h.WebhookUC.GetByID(ctx, int64(id))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": subscription})
+}
+
+func (h *WebhookHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	subscriptions, err := h.WebhookUC.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": subscriptions})
+}
+
+func (h *WebhookHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeWebhookNotFound, "webhook not found")
+	}
+
+	var req request.UpdateWebhookReq
+	if err := c.Bind(&req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := req.Validate(); err != nil {
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	if err := h.WebhookUC.Update(ctx, int64(id), &req); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "webhook updated",
+	})
+}
+
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeWebhookNotFound, "webhook not found")
+	}
+
+	if err := h.WebhookUC.Delete(ctx, int64(id)); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "webhook deleted",
+	})
+}
+
+func (h *WebhookHandler) FetchDeliveries(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeWebhookNotFound, "webhook not found")
+	}
+
+	var req request.FetchWebhookDeliveriesReq
+	if err := c.Bind(&req); err != nil {
+		return utils.NewUnprocessableEntityError(err.Error())
+	}
+
+	if err := req.Validate(); err != nil {
+		errVal := err.(validation.Errors)
+		return utils.NewInvalidInputError(errVal)
+	}
+
+	deliveries, err := h.WebhookUC.FetchDeliveries(ctx, int64(id), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": deliveries})
+}
+
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.NewError(utils.CodeWebhookNotFound, "webhook not found")
+	}
+
+	deliveryID, err := strconv.Atoi(c.Param("deliveryId"))
+	if err != nil {
+		return utils.NewError(utils.CodeWebhookDeliveryNotFound, "webhook delivery not found")
+	}
+
+	delivery, err := h.WebhookUC.Redeliver(ctx, int64(id), int64(deliveryID))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": delivery})
+}
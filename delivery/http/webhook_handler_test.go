@@ -0,0 +1,226 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpDelivery "carApi/delivery/http"
+	appMiddleware "carApi/delivery/middleware"
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/transport/request"
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookHandler(t *testing.T) {
+	mockWebhookUC := new(mocks.WebhookUsecase)
+	e := echo.New()
+	httpDelivery.NewErrorHandler(e, new(mocks.Logger))
+	httpDelivery.NewWebhookHandler(e, appMiddleware.NewMiddleware(new(mocks.Logger)), mockWebhookUC, []string{"first"})
+
+	t.Run("read-without-key", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(echo.GET, "/api/v1/webhooks", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		mockWebhookUC.AssertNotCalled(t, "Fetch", mock.Anything)
+	})
+
+	t.Run("read-with-key", func(t *testing.T) {
+		mockWebhookUC.On("Fetch", mock.Anything).Return([]entity.WebhookSubscription{}, nil).Once()
+
+		req := httptest.NewRequest(echo.GET, "/api/v1/webhooks", nil)
+		req.Header.Set(entity.APIKeyHeader, "first")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockWebhookUC.AssertExpectations(t)
+	})
+}
+
+func TestWebhookHandler_Create(t *testing.T) {
+	mockWebhookUC := new(mocks.WebhookUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockWebhookUC.On("Create", mock.Anything, mock.AnythingOfType("*request.CreateWebhookReq")).
+			Return(entity.WebhookSubscription{ID: 1, URL: "https://203.0.113.10/hooks", Secret: "0123456789abcdef"}, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/api/v1/webhooks", strings.NewReader(`{"url":"https://203.0.113.10/hooks","event_types":["car.created"],"secret":"0123456789abcdef"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/webhooks")
+
+		handler := httpDelivery.WebhookHandler{
+			WebhookUC: mockWebhookUC,
+		}
+		err = handler.Create(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "0123456789abcdef")
+		mockWebhookUC.AssertExpectations(t)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/api/v1/webhooks", strings.NewReader(`{"url":"ftp://partner.example.com","event_types":["car.sold"],"secret":"short"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/webhooks")
+
+		handler := httpDelivery.WebhookHandler{
+			WebhookUC: mockWebhookUC,
+		}
+		err = handler.Create(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, err.Error(), "url")
+		assert.Contains(t, err.Error(), "event_types")
+		assert.Contains(t, err.Error(), "secret")
+		mockWebhookUC.AssertExpectations(t)
+	})
+
+	t.Run("error-url", func(t *testing.T) {
+		for _, url := range []string{"ftp://203.0.113.10/hooks", "/hooks", "http:///hooks"} {
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/api/v1/webhooks", strings.NewReader(`{"url":"`+url+`","event_types":["car.created"],"secret":"0123456789abcdef"}`))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/webhooks")
+
+			handler := httpDelivery.WebhookHandler{
+				WebhookUC: mockWebhookUC,
+			}
+			err = handler.Create(c)
+
+			require.Error(t, err, url)
+			code, _ := utils.ParseHttpError(err)
+			assert.Equal(t, http.StatusBadRequest, code, url)
+			assert.Contains(t, err.Error(), "absolute http or https URL", url)
+		}
+		mockWebhookUC.AssertExpectations(t)
+	})
+}
+
+func TestWebhookHandler_FetchDeliveries(t *testing.T) {
+	mockWebhookUC := new(mocks.WebhookUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockWebhookUC.On("FetchDeliveries", mock.Anything, int64(1), &request.FetchWebhookDeliveriesReq{Status: "dead", Limit: 10}).
+			Return([]entity.WebhookDelivery{{ID: 3, SubscriptionID: 1, Status: entity.WebhookDeliveryDead}}, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/webhooks/1/deliveries?status=dead&limit=10", nil)
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/webhooks/:id/deliveries")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		handler := httpDelivery.WebhookHandler{
+			WebhookUC: mockWebhookUC,
+		}
+		err = handler.FetchDeliveries(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"dead"`)
+		mockWebhookUC.AssertExpectations(t)
+	})
+
+	t.Run("error-status", func(t *testing.T) {
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/api/v1/webhooks/1/deliveries?status=failed", nil)
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/webhooks/:id/deliveries")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		handler := httpDelivery.WebhookHandler{
+			WebhookUC: mockWebhookUC,
+		}
+		err = handler.FetchDeliveries(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		mockWebhookUC.AssertExpectations(t)
+	})
+}
+
+func TestWebhookHandler_Redeliver(t *testing.T) {
+	mockWebhookUC := new(mocks.WebhookUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		mockWebhookUC.On("Redeliver", mock.Anything, int64(1), int64(3)).
+			Return(entity.WebhookDelivery{ID: 3, SubscriptionID: 1, Status: entity.WebhookDeliveryPending}, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/api/v1/webhooks/1/deliveries/3/redeliver", nil)
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/webhooks/:id/deliveries/:deliveryId/redeliver")
+		c.SetParamNames("id", "deliveryId")
+		c.SetParamValues("1", "3")
+
+		handler := httpDelivery.WebhookHandler{
+			WebhookUC: mockWebhookUC,
+		}
+		err = handler.Redeliver(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockWebhookUC.AssertExpectations(t)
+	})
+
+	t.Run("error-pending", func(t *testing.T) {
+		mockWebhookUC.On("Redeliver", mock.Anything, int64(1), int64(4)).
+			Return(entity.WebhookDelivery{}, utils.NewConflictError("webhook delivery is already pending")).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/api/v1/webhooks/1/deliveries/4/redeliver", nil)
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/webhooks/:id/deliveries/:deliveryId/redeliver")
+		c.SetParamNames("id", "deliveryId")
+		c.SetParamValues("1", "4")
+
+		handler := httpDelivery.WebhookHandler{
+			WebhookUC: mockWebhookUC,
+		}
+		err = handler.Redeliver(c)
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusConflict, code)
+		mockWebhookUC.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"carApi/entity"
+	"carApi/utils"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+// RequireAPIKey will reject the requests that do not carry one of the keys, whatever their method,
+// for the routes where a read is not public either: the socket handshake, as the socket lets the
+// client write, and the webhooks, which give out partner endpoints. The key is read from the
+// X-Api-Key header or, for browsers opening a socket, from the subprotocols of the handshake. A
+// query parameter would end up in the logs of every proxy on the way. No keys disables the check.
+func (m *Middleware) RequireAPIKey(keys []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if len(keys) == 0 {
+				return next(c)
+			}
+
+			if validAPIKey(keys, requestKey(c)) {
+				return next(c)
+			}
+
+			return utils.NewUnauthorizedError("missing or invalid " + entity.APIKeyHeader + " header")
+		}
+	}
+}
+
+// RepKey is the context key holding the name of the sales rep a socket authenticated as
+const RepKey = "rep"
+
+// Rep will name the sales rep behind a socket after the rep key its handshake carries, reps are
+// keyed by their key. The handshake goes on unnamed without one.
+func (m *Middleware) Rep(reps map[string]string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := requestKey(c)
+			for repKey, name := range reps {
+				if subtle.ConstantTimeCompare([]byte(key), []byte(repKey)) == 1 {
+					c.Set(RepKey, name)
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// requestKey gives the key in the header or else the one a socket handshake offers as the
+// base64url of the key after SocketKeyProtocolPrefix
+func requestKey(c echo.Context) string {
+	if key := c.Request().Header.Get(entity.APIKeyHeader); key != "" {
+		return key
+	}
+
+	for _, protocol := range websocket.Subprotocols(c.Request()) {
+		if !strings.HasPrefix(protocol, entity.SocketKeyProtocolPrefix) {
+			continue
+		}
+		key, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(protocol, entity.SocketKeyProtocolPrefix))
+		if err == nil {
+			return string(key)
+		}
+	}
+	return ""
+}
+
+func validAPIKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package middleware_test
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpDelivery "carApi/delivery/http"
+	appMiddleware "carApi/delivery/middleware"
+	"carApi/entity"
+	"carApi/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireAPIKey(t *testing.T) {
+	m := appMiddleware.NewMiddleware(new(mocks.Logger))
+	e := echo.New()
+	httpDelivery.NewErrorHandler(e, new(mocks.Logger))
+	e.GET("/api/v1/cars/ws", func(c echo.Context) error {
+		return c.String(http.StatusOK, "socket")
+	}, m.RequireAPIKey([]string{"first", "second"}))
+
+	tests := []struct {
+		name     string
+		target   string
+		key      string
+		protocol string
+		status   int
+	}{
+		{"without key", "/api/v1/cars/ws", "", "", http.StatusUnauthorized},
+		{"with wrong key", "/api/v1/cars/ws", "third", "", http.StatusUnauthorized},
+		{"with key header", "/api/v1/cars/ws", "second", "", http.StatusOK},
+		{"with key parameter only", "/api/v1/cars/ws?api_key=first", "", "", http.StatusUnauthorized},
+		{"with key protocol", "/api/v1/cars/ws", "", "carapi, key." + base64.RawURLEncoding.EncodeToString([]byte("first")), http.StatusOK},
+		{"with wrong key protocol", "/api/v1/cars/ws", "", "carapi, key." + base64.RawURLEncoding.EncodeToString([]byte("third")), http.StatusUnauthorized},
+		{"with unencoded key protocol", "/api/v1/cars/ws", "", "carapi, key.first", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(echo.GET, tt.target, nil)
+			if tt.key != "" {
+				req.Header.Set(entity.APIKeyHeader, tt.key)
+			}
+			if tt.protocol != "" {
+				req.Header.Set("Sec-WebSocket-Protocol", tt.protocol)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestRep(t *testing.T) {
+	m := appMiddleware.NewMiddleware(new(mocks.Logger))
+	e := echo.New()
+	e.GET("/api/v1/cars/ws", func(c echo.Context) error {
+		rep, _ := c.Get(appMiddleware.RepKey).(string)
+		return c.String(http.StatusOK, rep)
+	}, m.Rep(map[string]string{"key-1": "jane", "key-2": "john"}))
+
+	tests := []struct {
+		name     string
+		target   string
+		key      string
+		protocol string
+		rep      string
+	}{
+		{"without key", "/api/v1/cars/ws", "", "", ""},
+		{"with other key", "/api/v1/cars/ws", "api-key", "", ""},
+		{"with rep key header", "/api/v1/cars/ws", "key-2", "", "john"},
+		{"with rep key parameter only", "/api/v1/cars/ws?api_key=key-1", "", "", ""},
+		{"with rep key protocol", "/api/v1/cars/ws", "", "carapi, key." + base64.RawURLEncoding.EncodeToString([]byte("key-1")), "jane"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(echo.GET, tt.target, nil)
+			if tt.key != "" {
+				req.Header.Set(entity.APIKeyHeader, tt.key)
+			}
+			if tt.protocol != "" {
+				req.Header.Set("Sec-WebSocket-Protocol", tt.protocol)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.rep, rec.Body.String())
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"carApi/utils"
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func (m *Middleware) Logger() echo.MiddlewareFunc {
@@ -30,6 +31,7 @@ func (m *Middleware) Logger() echo.MiddlewareFunc {
 
 			m.logger.Infow("INBOUND LOG",
 				"request_id", utils.GetReqID(req.Context()),
+				"trace_id", trace.SpanContextFromContext(req.Context()).TraceID().String(),
 				"remote_ip", c.RealIP(),
 				"host", req.Host,
 				"uri", req.RequestURI,
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"carApi/infrastructure/metrics"
+	"github.com/labstack/echo/v4"
+)
+
+// Metrics will count and time every request by its route template
+func (m *Middleware) Metrics(appMetrics *metrics.Metrics) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			route := ""
+			if err := next(c); err != nil {
+				// the router reports unknown paths with the raw request path, keep them out of the labels
+				if errors.Is(err, echo.ErrNotFound) || errors.Is(err, echo.ErrMethodNotAllowed) {
+					route = "unmatched"
+				}
+				c.Error(err)
+			}
+
+			if route == "" {
+				route = c.Path()
+			}
+			labels := []string{c.Request().Method, route, strconv.Itoa(c.Response().Status)}
+
+			appMetrics.HTTPRequests.WithLabelValues(labels...).Inc()
+			appMetrics.HTTPDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appMiddleware "carApi/delivery/middleware"
+	"carApi/infrastructure/metrics"
+	"carApi/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	appMetrics := metrics.NewMetrics()
+
+	e := echo.New()
+	e.Use(appMiddleware.NewMiddleware(new(mocks.Logger)).Metrics(appMetrics))
+	e.GET("/api/v1/cars/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, "car")
+	})
+
+	for _, path := range []string{"/api/v1/cars/1", "/api/v1/cars/2", "/unknown"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(echo.GET, path, nil))
+	}
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(appMetrics.HTTPRequests.WithLabelValues("GET", "/api/v1/cars/:id", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(appMetrics.HTTPRequests.WithLabelValues("GET", "unmatched", "404")))
+	assert.Equal(t, 2, testutil.CollectAndCount(appMetrics.HTTPDuration))
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// FormatKey is the context key holding the media type the response is written in
+const FormatKey = "format"
+
+// MIMETextCSV is offered by the lists, a row per item
+const MIMETextCSV = "text/csv"
+
+// mediaAliases are the other names clients give the offered media types, asking for problem
+// details is asking for the format they are written in
+var mediaAliases = map[string]string{
+	echo.MIMETextXML:           echo.MIMEApplicationXML,
+	"application/x-msgpack":    echo.MIMEApplicationMsgpack,
+	MIMEApplicationProblemJSON: echo.MIMEApplicationJSON,
+	MIMEApplicationProblemXML:  echo.MIMEApplicationXML,
+}
+
+// Negotiate will pick the media type of the response among offers from the Accept header and
+// keep it under FormatKey, the first offer goes when the header is absent or takes anything.
+// A request accepting none of the offers is answered 406 before the handler runs.
+func (m *Middleware) Negotiate(offers ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+			format, ok := negotiate(c.Request().Header.Get(echo.HeaderAccept), offers)
+			if !ok {
+				return utils.NewNotAcceptableError("acceptable media types are " + strings.Join(offers, ", "))
+			}
+
+			c.Set(FormatKey, format)
+			return next(c)
+		}
+	}
+}
+
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+// negotiate gives the offer matching the most preferred range of the Accept header, ranges of
+// equal quality keep the order of the header
+func negotiate(accept string, offers []string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	var ranges []mediaRange
+	for _, r := range parseAccept(accept) {
+		if alias, ok := mediaAliases[r.mediaType]; ok {
+			r.mediaType = alias
+		}
+		if r.quality > 0 {
+			ranges = append(ranges, r)
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+
+	for _, r := range ranges {
+		for _, offer := range offers {
+			if matchMediaRange(r.mediaType, offer) {
+				return offer, true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseAccept reads the media ranges of the Accept header with their quality, 1 when not given
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		r := mediaRange{mediaType: strings.ToLower(strings.TrimSpace(params[0])), quality: 1}
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if name == "q" {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					r.quality = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+func matchMediaRange(mediaRange string, offer string) bool {
+	if mediaRange == "*/*" || mediaRange == offer {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(mediaRange, "/*"); ok {
+		return strings.HasPrefix(offer, prefix+"/")
+	}
+	return false
+}
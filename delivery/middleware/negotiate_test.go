@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appMiddleware "carApi/delivery/middleware"
+	"carApi/mocks"
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{echo.MIMEApplicationJSON, echo.MIMEApplicationXML, echo.MIMEApplicationMsgpack, appMiddleware.MIMETextCSV}
+
+	tests := []struct {
+		name   string
+		accept string
+		format string
+	}{
+		{name: "no-accept-header", accept: "", format: echo.MIMEApplicationJSON},
+		{name: "anything", accept: "*/*", format: echo.MIMEApplicationJSON},
+		{name: "exact", accept: "text/csv", format: appMiddleware.MIMETextCSV},
+		{name: "alias", accept: "text/xml", format: echo.MIMEApplicationXML},
+		{name: "quality", accept: "application/json;q=0.5, application/msgpack", format: echo.MIMEApplicationMsgpack},
+		{name: "equal-quality-keeps-order", accept: "application/xml, application/json", format: echo.MIMEApplicationXML},
+		{name: "type-wildcard", accept: "image/png, text/*;q=0.8", format: appMiddleware.MIMETextCSV},
+		{name: "excluded", accept: "application/json;q=0, */*;q=0.1", format: echo.MIMEApplicationJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, "/", nil)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var format string
+			handler := func(c echo.Context) error {
+				format = c.Get(appMiddleware.FormatKey).(string)
+				return c.NoContent(http.StatusOK)
+			}
+
+			err := appMiddleware.NewMiddleware(new(mocks.Logger)).Negotiate(offers...)(handler)(c)
+
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.format, format)
+			assert.Equal(t, echo.HeaderAccept, rec.Header().Get(echo.HeaderVary))
+		})
+	}
+
+	t.Run("not-acceptable", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(echo.GET, "/", nil)
+		req.Header.Set(echo.HeaderAccept, "image/png, text/html;q=0.9")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := func(c echo.Context) error {
+			t.Fatal("the handler should not run")
+			return nil
+		}
+
+		err := appMiddleware.NewMiddleware(new(mocks.Logger)).Negotiate(offers...)(handler)(c)
+
+		require.Error(t, err)
+		code, httpErr := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusNotAcceptable, code)
+		assert.Equal(t, utils.CodeNotAcceptable, httpErr.Code())
+		assert.Contains(t, err.Error(), "acceptable media types are application/json, application/xml, application/msgpack, text/csv")
+	})
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"carApi/entity"
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// The media types of the RFC 7807 problem details
+const (
+	MIMEApplicationProblemJSON = "application/problem+json"
+	MIMEApplicationProblemXML  = "application/problem+xml"
+)
+
+// ErrorBody gives the problem details of the error, their instance is the request id. A client
+// preferring application/json to application/problem+json in its Accept header keeps getting
+// the legacy {status, error, details} body.
+func ErrorBody(c echo.Context, err utils.HttpErr) interface{} {
+	var jsonQuality, problemQuality float64
+	for _, r := range parseAccept(c.Request().Header.Get(echo.HeaderAccept)) {
+		switch r.mediaType {
+		case echo.MIMEApplicationJSON:
+			jsonQuality = r.quality
+		case MIMEApplicationProblemJSON:
+			problemQuality = r.quality
+		}
+	}
+	if jsonQuality > problemQuality {
+		return err
+	}
+
+	return utils.NewProblem(err, c.Request().Header.Get(entity.RequestIDHeader))
+}
@@ -0,0 +1,47 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	appMiddleware "carApi/delivery/middleware"
+	"carApi/entity"
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		legacy bool
+	}{
+		{name: "no-accept-header", accept: ""},
+		{name: "anything", accept: "*/*"},
+		{name: "problem", accept: "application/problem+json"},
+		{name: "problem-preferred", accept: "application/json;q=0.5, application/problem+json"},
+		{name: "xml", accept: "application/xml"},
+		{name: "legacy", accept: "application/json", legacy: true},
+		{name: "legacy-preferred", accept: "application/json, application/problem+json;q=0.5", legacy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, "/", nil)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+			req.Header.Set(entity.RequestIDHeader, "req-1")
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			err := utils.NewError(utils.CodeCarNotFound, "car not found")
+			body := appMiddleware.ErrorBody(c, err)
+
+			if tt.legacy {
+				assert.Equal(t, err, body)
+				return
+			}
+			assert.Equal(t, utils.NewProblem(err, "req-1"), body)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"carApi/utils"
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// Recover will turn a panic of a handler into an internal error answered like any other error,
+// the panic is logged with the request id and its stack and the server keeps serving
+func (m *Middleware) Recover() echo.MiddlewareFunc {
+	return echoMiddleware.RecoverWithConfig(echoMiddleware.RecoverConfig{
+		LogErrorFunc: func(c echo.Context, err error, stack []byte) error {
+			m.logger.Errorw("PANIC RECOVERED",
+				"request_id", utils.GetReqID(c.Request().Context()),
+				"error", err.Error(),
+				"stack", string(stack),
+			)
+			return utils.NewInternalServerError(err)
+		},
+	})
+}
@@ -0,0 +1,40 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpDelivery "carApi/delivery/http"
+	appMiddleware "carApi/delivery/middleware"
+	"carApi/entity"
+	"carApi/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecover(t *testing.T) {
+	e := echo.New()
+	httpDelivery.NewErrorHandler(e, new(mocks.Logger))
+	req := httptest.NewRequest(echo.GET, "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), entity.RequestIDKey, "req-1"))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := func(c echo.Context) error {
+		panic("nil map")
+	}
+
+	mockLogger := new(mocks.Logger)
+	mockLogger.On("Errorw", "PANIC RECOVERED", "request_id", "req-1", "error", "nil map", "stack", mock.AnythingOfType("string")).Once()
+
+	err := appMiddleware.NewMiddleware(mockLogger).Recover()(handler)(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"type":"urn:carapi:problem:internal-error","title":"Internal server error","status":500,"code":"INTERNAL_ERROR"}`, rec.Body.String())
+	mockLogger.AssertExpectations(t)
+}
@@ -6,19 +6,28 @@ import (
 	"carApi/entity"
 	"github.com/labstack/echo/v4"
 	uuid "github.com/satori/go.uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // RequestID will search for a correlation header and set a request-level
-// correlation id into the context. If no header is found, a new UUID will be generated.
+// correlation id into the context. If no header is found, the trace id is used
+// when the request is traced, otherwise a new UUID will be generated.
 func (m *Middleware) RequestID() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			span := trace.SpanFromContext(ctx)
+
 			requestID := c.Request().Header.Get(entity.RequestIDHeader)
+			if requestID == "" && span.SpanContext().HasTraceID() {
+				requestID = span.SpanContext().TraceID().String()
+			}
 			if requestID == "" {
 				requestID = uuid.NewV4().String()
 			}
+			span.SetAttributes(attribute.String("request.id", requestID))
 
-			ctx := c.Request().Context()
 			newReq := c.Request().WithContext(context.WithValue(ctx, entity.RequestIDKey, requestID))
 			c.SetRequest(newReq)
 			c.Request().Header.Set(entity.RequestIDHeader, requestID)
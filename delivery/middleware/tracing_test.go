@@ -0,0 +1,47 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appMiddleware "carApi/delivery/middleware"
+	"carApi/entity"
+	"carApi/infrastructure/tracing"
+	"carApi/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracing(t *testing.T) {
+	var buf bytes.Buffer
+	provider, err := tracing.NewStdoutTracerProvider(&buf)
+	assert.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+	tracing.Setup(provider)
+
+	middleware := appMiddleware.NewMiddleware(new(mocks.Logger))
+
+	var requestID string
+	e := echo.New()
+	e.Use(middleware.Tracing())
+	e.Use(middleware.RequestID())
+	e.GET("/api/v1/cars/:id", func(c echo.Context) error {
+		requestID = c.Request().Header.Get(entity.RequestIDHeader)
+		return c.String(http.StatusOK, "car")
+	})
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(echo.GET, "/api/v1/cars/1", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, traceID, requestID)
+	assert.Contains(t, rec.Header().Get("traceparent"), traceID)
+	assert.Contains(t, buf.String(), `"Name":"GET /api/v1/cars/:id"`)
+	assert.Contains(t, buf.String(), traceID)
+}
@@ -5,16 +5,20 @@ import (
 )
 
 type Car struct {
-	ID             int64     `json:"id"`
-	Make           string    `json:"make"`
-	Model          string    `json:"model"`
-	Package        string    `json:"package"`
-	Color          string    `json:"color"`
-	Year           int       `json:"year"`
-	Category       string    `json:"category"`
-	Mileage        int       `json:"mileage"`
-	Price          int       `json:"price"`
-	Identification string    `json:"identification"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             int64      `json:"id"`
+	Make           string     `json:"make"`
+	Model          string     `json:"model"`
+	Package        string     `json:"package"`
+	Color          string     `json:"color"`
+	Year           int        `json:"year"`
+	Category       string     `json:"category"`
+	Mileage        int        `json:"mileage"`
+	DisplayMileage *Distance  `json:"display_mileage,omitempty"`
+	Price          Money      `json:"price"`
+	DisplayPrice   *Money     `json:"display_price,omitempty"`
+	Identification string     `json:"identification"`
+	Images         []CarImage `json:"images,omitempty"`
+	Features       []Feature  `json:"features,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
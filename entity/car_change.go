@@ -0,0 +1,24 @@
+package entity
+
+// CarChangeType names a change of the inventory as streamed to clients
+type CarChangeType string
+
+const (
+	CarChangeCreated CarChangeType = "car.created"
+	CarChangeUpdated CarChangeType = "car.updated"
+	CarChangeDeleted CarChangeType = "car.deleted"
+	// CarChangeRemoved is an update after which the car no longer passes the filter of the stream
+	CarChangeRemoved CarChangeType = "car.removed"
+	// CarChangeReset tells the changes since the last seen event are no longer known, the client
+	// fetches the listing again
+	CarChangeReset CarChangeType = "reset"
+)
+
+// CarChange is a change of the inventory, EventID is the id of the event it comes from and Car
+// is only set when the car was created or updated
+type CarChange struct {
+	EventID int64         `json:"-"`
+	Type    CarChangeType `json:"-"`
+	CarID   int64         `json:"car_id"`
+	Car     *Car          `json:"car,omitempty"`
+}
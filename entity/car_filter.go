@@ -0,0 +1,67 @@
+package entity
+
+const (
+	// FeatureMatchAll keeps the cars that have every requested feature
+	FeatureMatchAll = "all"
+	// FeatureMatchAny keeps the cars that have at least one requested feature
+	FeatureMatchAny = "any"
+)
+
+// CarFilter holds the criteria used to narrow down a car listing,
+// mileage bounds are inclusive and in kilometers
+type CarFilter struct {
+	Features     []string
+	FeatureMatch string
+	MileageMin   *int
+	MileageMax   *int
+}
+
+// Page is a window of a listing Offset rows in, no Limit gives every row from there on
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// CarDisplay holds how cars should be presented to the client, an empty value keeps them as stored
+type CarDisplay struct {
+	Currency    string
+	MileageUnit string
+}
+
+// Matches tells whether the car passes the filter, its Features must be loaded when the filter
+// has any
+func (f CarFilter) Matches(car Car) bool {
+	if f.MileageMin != nil && car.Mileage < *f.MileageMin {
+		return false
+	}
+
+	if f.MileageMax != nil && car.Mileage > *f.MileageMax {
+		return false
+	}
+
+	if len(f.Features) == 0 {
+		return true
+	}
+
+	codes := map[string]bool{}
+	for _, feature := range car.Features {
+		codes[feature.Code] = true
+	}
+
+	wanted := map[string]bool{}
+	found := 0
+	for _, code := range f.Features {
+		if wanted[code] {
+			continue
+		}
+		wanted[code] = true
+		if codes[code] {
+			found++
+		}
+	}
+
+	if f.FeatureMatch == FeatureMatchAny {
+		return found > 0
+	}
+	return found == len(wanted)
+}
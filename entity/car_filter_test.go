@@ -0,0 +1,37 @@
+package entity_test
+
+import (
+	"testing"
+
+	"carApi/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCarFilter_Matches(t *testing.T) {
+	car := entity.Car{
+		Mileage:  50000,
+		Features: []entity.Feature{{Code: "heated_seats"}, {Code: "sunroof"}},
+	}
+	intPtr := func(v int) *int { return &v }
+
+	tests := map[string]struct {
+		filter  entity.CarFilter
+		matches bool
+	}{
+		"empty":               {entity.CarFilter{}, true},
+		"mileage-inside":      {entity.CarFilter{MileageMin: intPtr(50000), MileageMax: intPtr(50000)}, true},
+		"mileage-below":       {entity.CarFilter{MileageMin: intPtr(50001)}, false},
+		"mileage-above":       {entity.CarFilter{MileageMax: intPtr(49999)}, false},
+		"features-all":        {entity.CarFilter{Features: []string{"heated_seats", "sunroof"}, FeatureMatch: entity.FeatureMatchAll}, true},
+		"features-all-miss":   {entity.CarFilter{Features: []string{"sunroof", "tow_hitch"}, FeatureMatch: entity.FeatureMatchAll}, false},
+		"features-any":        {entity.CarFilter{Features: []string{"sunroof", "tow_hitch"}, FeatureMatch: entity.FeatureMatchAny}, true},
+		"features-any-miss":   {entity.CarFilter{Features: []string{"tow_hitch"}, FeatureMatch: entity.FeatureMatchAny}, false},
+		"features-duplicated": {entity.CarFilter{Features: []string{"sunroof", "sunroof"}, FeatureMatch: entity.FeatureMatchAll}, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.matches, test.filter.Matches(car))
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+)
+
+// Thumbnail sizes generated for every uploaded car image, keyed by name
+// and expressed as the maximum length of the longest side in pixels.
+var ThumbnailSizes = map[string]int{
+	"small":  160,
+	"medium": 480,
+	"large":  1024,
+}
+
+type CarImage struct {
+	ID          int64             `json:"id"`
+	CarID       int64             `json:"car_id"`
+	StorageKey  string            `json:"-"`
+	URL         string            `json:"url"`
+	Thumbnails  map[string]string `json:"thumbnails"`
+	ContentType string            `json:"content_type"`
+	Size        int64             `json:"size"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	Position    int               `json:"position"`
+	IsPrimary   bool              `json:"is_primary"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
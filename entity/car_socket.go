@@ -0,0 +1,30 @@
+package entity
+
+// CarSocketMessageType names a message of the car socket, the server also sends the changes of
+// the subscribed cars typed by their CarChangeType
+type CarSocketMessageType string
+
+const (
+	// CarSocketSubscribe and CarSocketUnsubscribe are sent by the client with the car_ids to push
+	// the changes, presences and reservations of, or to stop
+	CarSocketSubscribe   CarSocketMessageType = "subscribe"
+	CarSocketUnsubscribe CarSocketMessageType = "unsubscribe"
+	// CarSocketView and CarSocketLeave are sent by the client with the car_id its rep starts or
+	// stops viewing
+	CarSocketView  CarSocketMessageType = "view"
+	CarSocketLeave CarSocketMessageType = "leave"
+	// CarSocketReserve and CarSocketRelease are sent by the client with the car_id its rep
+	// reserves or releases
+	CarSocketReserve CarSocketMessageType = "reserve"
+	CarSocketRelease CarSocketMessageType = "release"
+
+	// CarSocketSubscribed acknowledges a subscribe or unsubscribe with the cars now subscribed to
+	CarSocketSubscribed CarSocketMessageType = "subscribed"
+	// CarSocketPresence gives the viewers of a subscribed car, on subscribe and when they change
+	CarSocketPresence CarSocketMessageType = "presence"
+	// CarSocketReservation gives the reservation of a subscribed car, on subscribe and when it is
+	// reserved or released
+	CarSocketReservation CarSocketMessageType = "reservation"
+	// CarSocketError answers a message that could not be handled, the socket stays open
+	CarSocketError CarSocketMessageType = "error"
+)
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	CatalogKindMake     = "make"
+	CatalogKindModel    = "model"
+	CatalogKindTrim     = "trim"
+	CatalogKindCategory = "category"
+)
+
+// CatalogParentKind tells under which kind an entry of a given kind is nested,
+// root kinds are absent
+var CatalogParentKind = map[string]string{
+	CatalogKindModel: CatalogKindMake,
+	CatalogKindTrim:  CatalogKindModel,
+}
+
+// CatalogEntry is a reference value for a car make, model, trim or category,
+// models belong to a make and trims belong to a model through ParentID
+type CatalogEntry struct {
+	ID        int64     `json:"id"`
+	Kind      string    `json:"kind"`
+	ParentID  int64     `json:"parent_id,omitempty"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CatalogPath addresses the parents of a nested catalog entry by name
+type CatalogPath struct {
+	Make  string
+	Model string
+}
+
+// CatalogSeed is the shape of the bundled reference catalog
+type CatalogSeed struct {
+	Makes      []CatalogSeedMake `json:"makes"`
+	Categories []string          `json:"categories"`
+}
+
+type CatalogSeedMake struct {
+	Name   string             `json:"name"`
+	Models []CatalogSeedModel `json:"models"`
+}
+
+type CatalogSeedModel struct {
+	Name  string   `json:"name"`
+	Trims []string `json:"trims"`
+}
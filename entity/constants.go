@@ -5,3 +5,11 @@ type ctxKeyRequestID int
 const RequestIDKey ctxKeyRequestID = 0
 
 var RequestIDHeader = "X-Request-Id"
+
+var APIKeyHeader = "X-Api-Key"
+
+// SocketProtocol is the subprotocol of the car socket, browsers cannot set headers on a handshake
+// so they offer it along SocketKeyProtocolPrefix and the base64url of their key
+var SocketProtocol = "carapi"
+
+var SocketKeyProtocolPrefix = "key."
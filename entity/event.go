@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType names a domain event, consumers dispatch on it so a published type is never renamed
+type EventType string
+
+const (
+	EventCarCreated      EventType = "car.created"
+	EventCarUpdated      EventType = "car.updated"
+	EventCarPriceChanged EventType = "car.price_changed"
+	EventCarDeleted      EventType = "car.deleted"
+	EventCarReserved     EventType = "car.reserved"
+	EventCarReleased     EventType = "car.released"
+)
+
+// Event is a domain event as kept in the outbox until it is published. ID grows with every
+// event so it orders the events of a car and lets consumers drop the ones delivered twice.
+type Event struct {
+	ID         int64           `json:"id"`
+	Type       EventType       `json:"type"`
+	CarID      int64           `json:"car_id"`
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// EventPayload is the content of a domain event
+type EventPayload interface {
+	EventType() EventType
+}
+
+// CarCreated holds the car as it was stored
+type CarCreated struct {
+	Car Car `json:"car"`
+}
+
+// CarUpdated holds the car after the update
+type CarUpdated struct {
+	Car Car `json:"car"`
+}
+
+// CarPriceChanged is emitted along with CarUpdated when the update changes the price
+type CarPriceChanged struct {
+	CarID    int64 `json:"car_id"`
+	OldPrice Money `json:"old_price"`
+	NewPrice Money `json:"new_price"`
+}
+
+// CarDeleted holds the id of the deleted car
+type CarDeleted struct {
+	CarID int64 `json:"car_id"`
+}
+
+// CarReserved holds the reservation taken, or renewed by its rep
+type CarReserved struct {
+	Reservation Reservation `json:"reservation"`
+}
+
+// CarReleased is emitted when the rep holding the car releases it, an expiry emits nothing
+type CarReleased struct {
+	CarID int64  `json:"car_id"`
+	Rep   string `json:"rep"`
+}
+
+func (CarCreated) EventType() EventType      { return EventCarCreated }
+func (CarUpdated) EventType() EventType      { return EventCarUpdated }
+func (CarPriceChanged) EventType() EventType { return EventCarPriceChanged }
+func (CarDeleted) EventType() EventType      { return EventCarDeleted }
+func (CarReserved) EventType() EventType     { return EventCarReserved }
+func (CarReleased) EventType() EventType     { return EventCarReleased }
+
+// NewCarEvent will create the event of the car with the payload encoded as JSON
+func NewCarEvent(carID int64, payload EventPayload, occurredAt time.Time) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Type:       payload.EventType(),
+		CarID:      carID,
+		Payload:    data,
+		OccurredAt: occurredAt,
+	}, nil
+}
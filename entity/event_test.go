@@ -0,0 +1,26 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCarEvent(t *testing.T) {
+	occurredAt := time.Now()
+	event, err := entity.NewCarEvent(1, entity.CarPriceChanged{
+		CarID:    1,
+		OldPrice: entity.Money{Amount: 1500000, Currency: "USD"},
+		NewPrice: entity.Money{Amount: 1400000, Currency: "USD"},
+	}, occurredAt)
+	require.NoError(t, err)
+
+	assert.Zero(t, event.ID, "the outbox gives the id")
+	assert.Equal(t, entity.EventCarPriceChanged, event.Type)
+	assert.Equal(t, int64(1), event.CarID)
+	assert.Equal(t, occurredAt, event.OccurredAt)
+	assert.JSONEq(t, `{"car_id":1,"old_price":{"amount":1500000,"currency":"USD"},"new_price":{"amount":1400000,"currency":"USD"}}`, string(event.Payload))
+}
@@ -0,0 +1,13 @@
+package entity
+
+import (
+	"time"
+)
+
+type Feature struct {
+	ID        int64     `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"math"
+)
+
+const (
+	// MileageUnitKilometers is the canonical unit, cars are stored in kilometers
+	MileageUnitKilometers = "km"
+	// MileageUnitMiles is the statute mile
+	MileageUnitMiles = "mi"
+)
+
+const kilometersPerMile = 1.609344
+
+// Distance is a mileage expressed in a given unit
+type Distance struct {
+	Value int    `json:"value"`
+	Unit  string `json:"unit"`
+}
+
+// ToKilometers converts a mileage in the given unit to the canonical unit
+func ToKilometers(value float64, unit string) float64 {
+	if unit == MileageUnitMiles {
+		return value * kilometersPerMile
+	}
+	return value
+}
+
+// FromKilometers converts a canonical mileage to the given unit, rounded to the nearest integer
+func FromKilometers(kilometers int, unit string) Distance {
+	value := float64(kilometers)
+	if unit == MileageUnitMiles {
+		value = math.Round(value / kilometersPerMile)
+	}
+	return Distance{Value: int(value), Unit: unit}
+}
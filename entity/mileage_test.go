@@ -0,0 +1,16 @@
+package entity_test
+
+import (
+	"testing"
+
+	"carApi/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMileage_Conversion(t *testing.T) {
+	assert.InDelta(t, 16093.44, entity.ToKilometers(10000, entity.MileageUnitMiles), 0.001)
+	assert.Equal(t, 10000.0, entity.ToKilometers(10000, entity.MileageUnitKilometers))
+
+	assert.Equal(t, entity.Distance{Value: 10000, Unit: entity.MileageUnitMiles}, entity.FromKilometers(16093, entity.MileageUnitMiles))
+	assert.Equal(t, entity.Distance{Value: 16093, Unit: entity.MileageUnitKilometers}, entity.FromKilometers(16093, entity.MileageUnitKilometers))
+}
@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoExchangeRate is returned when a conversion involves a currency missing from the rates
+var ErrNoExchangeRate = errors.New("no exchange rate")
+
+// Currencies lists the supported ISO 4217 codes with their number of minor unit digits
+var Currencies = map[string]int{
+	"ARS": 2,
+	"AUD": 2,
+	"BRL": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CLP": 0,
+	"CNY": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"MXN": 2,
+	"PLN": 2,
+	"SEK": 2,
+	"USD": 2,
+}
+
+// Money is an amount in the minor units of its currency, e.g. cents for USD
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// ExchangeRates holds how much of each currency one unit of Base buys
+type ExchangeRates struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+func (r ExchangeRates) rate(currency string) (float64, bool) {
+	if currency == r.Base {
+		return 1, true
+	}
+	rate, ok := r.Rates[currency]
+	return rate, ok && rate > 0
+}
+
+// Convert expresses the money in another currency, rounding to the nearest minor unit
+func (r ExchangeRates) Convert(money Money, to string) (Money, error) {
+	if money.Currency == to {
+		return money, nil
+	}
+
+	fromRate, ok := r.rate(money.Currency)
+	if !ok {
+		return Money{}, ErrNoExchangeRate
+	}
+	toRate, ok := r.rate(to)
+	if !ok {
+		return Money{}, ErrNoExchangeRate
+	}
+
+	major := float64(money.Amount) / math.Pow10(Currencies[money.Currency])
+	converted := major / fromRate * toRate
+	return Money{
+		Amount:   int64(math.Round(converted * math.Pow10(Currencies[to]))),
+		Currency: to,
+	}, nil
+}
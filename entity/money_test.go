@@ -0,0 +1,39 @@
+package entity_test
+
+import (
+	"testing"
+
+	"carApi/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExchangeRates_Convert(t *testing.T) {
+	rates := entity.ExchangeRates{
+		Base:  "USD",
+		Rates: map[string]float64{"EUR": 0.9, "JPY": 150, "BRL": 5},
+	}
+
+	t.Run("from-base", func(t *testing.T) {
+		money, err := rates.Convert(entity.Money{Amount: 1000000, Currency: "USD"}, "EUR")
+		require.NoError(t, err)
+		assert.Equal(t, entity.Money{Amount: 900000, Currency: "EUR"}, money)
+	})
+
+	t.Run("between-non-base-with-different-minor-units", func(t *testing.T) {
+		money, err := rates.Convert(entity.Money{Amount: 500000, Currency: "BRL"}, "JPY")
+		require.NoError(t, err)
+		assert.Equal(t, entity.Money{Amount: 150000, Currency: "JPY"}, money)
+	})
+
+	t.Run("same-currency", func(t *testing.T) {
+		money, err := rates.Convert(entity.Money{Amount: 1234, Currency: "EUR"}, "EUR")
+		require.NoError(t, err)
+		assert.Equal(t, entity.Money{Amount: 1234, Currency: "EUR"}, money)
+	})
+
+	t.Run("missing-rate", func(t *testing.T) {
+		_, err := rates.Convert(entity.Money{Amount: 1234, Currency: "USD"}, "GBP")
+		assert.ErrorIs(t, err, entity.ErrNoExchangeRate)
+	})
+}
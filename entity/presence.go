@@ -0,0 +1,7 @@
+package entity
+
+// CarPresence is the sales reps viewing a car
+type CarPresence struct {
+	CarID   int64    `json:"car_id"`
+	Viewers []string `json:"viewers"`
+}
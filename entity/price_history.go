@@ -0,0 +1,13 @@
+package entity
+
+import (
+	"time"
+)
+
+// PricePoint is the price a car is listed at from ChangedAt until the next point
+type PricePoint struct {
+	ID        int64     `json:"id"`
+	CarID     int64     `json:"car_id"`
+	Price     Money     `json:"price"`
+	ChangedAt time.Time `json:"changed_at"`
+}
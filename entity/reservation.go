@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+)
+
+// Reservation holds a car for a sales rep until it is released or expires, an expired
+// reservation no longer holds the car and any rep may reserve it
+type Reservation struct {
+	CarID      int64     `json:"car_id"`
+	Rep        string    `json:"rep"`
+	ReservedAt time.Time `json:"reserved_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Held reports whether the reservation still holds the car at now
+func (r Reservation) Held(now time.Time) bool {
+	return now.Before(r.ExpiresAt)
+}
+
+// CarReservation is the reservation state of a car as pushed to the sockets, Reservation is nil
+// while the car is available
+type CarReservation struct {
+	CarID       int64        `json:"car_id"`
+	Reservation *Reservation `json:"reservation"`
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventTypes lists the event types a webhook can subscribe to
+var EventTypes = []EventType{EventCarCreated, EventCarUpdated, EventCarPriceChanged, EventCarDeleted, EventCarReserved, EventCarReleased}
+
+// WebhookSubscription is a partner endpoint receiving the events of the given types, the secret
+// signs the deliveries and is never given back
+type WebhookSubscription struct {
+	ID         int64       `json:"id"`
+	URL        string      `json:"url"`
+	EventTypes []EventType `json:"event_types"`
+	Secret     string      `json:"-"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// Subscribes tells whether the events of the type are delivered to the subscription
+func (s WebhookSubscription) Subscribes(eventType EventType) bool {
+	for _, subscribed := range s.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus is where a delivery stands, only a pending one is attempted
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending is waiting for its first attempt or a retry
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliverySucceeded got a 2xx answer
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryDead failed every attempt, only a redelivery sends it again
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is an event sent to a subscription and the outcome of its last attempt. Body is
+// the exact JSON document posted, its signature is computed on every attempt.
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	SubscriptionID int64                 `json:"subscription_id"`
+	EventID        int64                 `json:"event_id"`
+	EventType      EventType             `json:"event_type"`
+	Body           json.RawMessage       `json:"body"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
@@ -1,19 +1,31 @@
 package datastore
 
 import (
+	"context"
+	"time"
+
+	"carApi/config"
+	"carApi/utils/logger"
 	"github.com/go-redis/redis"
 )
 
-// NewCache will create new cache instance
-func NewCache(redisURL string) (client *redis.Client, err error) {
-	opt, err := redis.ParseURL(redisURL)
+// NewCache will create new cache instance, it waits for the cache to accept
+// connections for up to the configured connect timeout
+func NewCache(cfg config.CacheConfig, log logger.Logger) (client *redis.Client, err error) {
+	opt, err := redis.ParseURL(cfg.URL)
 	if err != nil {
 		return
 	}
+	opt.PoolSize = cfg.PoolSize
 
 	client = redis.NewClient(opt)
-	if _, err = client.Ping().Result(); err != nil {
-		return
+	backoff := NewBackoff(time.Duration(cfg.ConnectTimeout) * time.Second)
+	err = backoff.Retry(context.Background(), log, "cache", func(ctx context.Context) error {
+		return client.WithContext(ctx).Ping().Err()
+	})
+	if err != nil {
+		client.Close()
+		return nil, err
 	}
 
 	return
@@ -1,28 +1,73 @@
 package datastore
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"net/url"
 	"time"
 
+	"carApi/config"
+	"carApi/utils/logger"
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
-// NewDatabase will create new database instance
-func NewDatabase(databaseURL string) (db *sql.DB, err error) {
-	parseDBUrl, _ := url.Parse(databaseURL)
-	db, err = sql.Open(parseDBUrl.Scheme, databaseURL)
+// NewDatabase will create new database instance, it waits for the database to accept
+// connections for up to the configured connect timeout
+func NewDatabase(cfg config.DatabaseConfig, log logger.Logger) (db *sql.DB, err error) {
+	parseDBUrl, err := url.Parse(cfg.URL)
 	if err != nil {
-		return
+		return nil, fmt.Errorf("parsing database url: %w", err)
+	}
+	if parseDBUrl.Scheme == "" {
+		return nil, fmt.Errorf("database url %q has no scheme", parseDBUrl.Redacted())
+	}
+
+	driver, dsn := parseDBUrl.Scheme, cfg.URL
+	if parseDBUrl.Scheme == SQLiteScheme {
+		if driver, dsn, err = sqliteDSN(parseDBUrl); err != nil {
+			return nil, err
+		}
 	}
 
-	if err = db.Ping(); err != nil {
+	db, err = sql.Open(driver, dsn)
+	if err != nil {
 		return
 	}
 
-	db.SetConnMaxLifetime(time.Minute * 5)
-	db.SetMaxIdleConns(0)
-	db.SetMaxOpenConns(5)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+
+	backoff := NewBackoff(time.Duration(cfg.ConnectTimeout) * time.Second)
+	if err = backoff.Retry(context.Background(), log, "database", db.PingContext); err != nil {
+		db.Close()
+		return nil, err
+	}
 
 	return
 }
+
+// sqliteDSN translates a sqlite:// URL into the data source name of the sqlite3 driver. Foreign
+// keys are enforced like in Postgres, writers wait for each other instead of failing and a
+// transaction takes the write lock when it begins so what it reads cannot change before it
+// writes. The query of the URL can override these settings.
+func sqliteDSN(dbURL *url.URL) (driver string, dsn string, err error) {
+	path := dbURL.Opaque
+	if path == "" {
+		path = dbURL.Host + dbURL.Path
+	}
+	if path == "" {
+		return "", "", fmt.Errorf("database url %q has no file path", dbURL.Redacted())
+	}
+
+	query := dbURL.Query()
+	for key, value := range map[string]string{"_foreign_keys": "on", "_busy_timeout": "5000", "_journal_mode": "WAL", "_txlock": "immediate"} {
+		if !query.Has(key) {
+			query.Set(key, value)
+		}
+	}
+
+	return "sqlite3", "file:" + path + "?" + query.Encode(), nil
+}
@@ -0,0 +1,59 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"carApi/utils/logger"
+)
+
+// Backoff is the policy used at startup while a datastore is not accepting connections yet,
+// e.g. when docker-compose starts the API before Postgres is ready
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Timeout time.Duration
+}
+
+// NewBackoff will create a policy waiting half a second after the first failure and
+// doubling the wait up to five seconds, until timeout
+func NewBackoff(timeout time.Duration) Backoff {
+	return Backoff{
+		Initial: 500 * time.Millisecond,
+		Max:     5 * time.Second,
+		Timeout: timeout,
+	}
+}
+
+// Retry calls connect until it succeeds or the timeout is reached, every failed attempt is logged
+func (b Backoff) Retry(ctx context.Context, log logger.Logger, name string, connect func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, b.Timeout)
+	defer cancel()
+
+	wait := b.Initial
+	for attempt := 1; ; attempt++ {
+		err := connect(ctx)
+		if err == nil {
+			if attempt > 1 {
+				log.Infof("connected to %s after %d attempts", name, attempt)
+			}
+			return nil
+		}
+
+		log.Warnf("connecting to %s failed (attempt %d), retrying in %s: %v", name, attempt, wait, err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("connecting to %s: giving up after %d attempts: %w", name, attempt, err)
+		case <-timer.C:
+		}
+
+		wait *= 2
+		if wait > b.Max {
+			wait = b.Max
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package datastore_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"carApi/config"
+	"carApi/infrastructure/datastore"
+	"carApi/mocks"
+	"github.com/alicebob/miniredis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBackoff_Retry(t *testing.T) {
+	backoff := datastore.Backoff{Initial: time.Millisecond, Max: 2 * time.Millisecond, Timeout: time.Second}
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Warnf", mock.Anything, "database", mock.Anything, mock.Anything, mock.Anything).Twice()
+		mockLogger.On("Infof", mock.Anything, "database", 3).Once()
+
+		attempts := 0
+		err := backoff.Retry(context.TODO(), mockLogger, "database", func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("connection refused")
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+		mockLogger.AssertExpectations(t)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Warnf", mock.Anything, "cache", mock.Anything, mock.Anything, mock.Anything)
+
+		backoff := backoff
+		backoff.Timeout = 20 * time.Millisecond
+		err := backoff.Retry(context.TODO(), mockLogger, "cache", func(ctx context.Context) error {
+			return errors.New("connection refused")
+		})
+
+		assert.ErrorContains(t, err, "connecting to cache: giving up after")
+		assert.ErrorContains(t, err, "connection refused")
+	})
+}
+
+func TestNewDatabase_InvalidURL(t *testing.T) {
+	_, err := datastore.NewDatabase(config.DatabaseConfig{URL: "localhost/carapi", ConnectTimeout: 1}, new(mocks.Logger))
+	assert.EqualError(t, err, `database url "localhost/carapi" has no scheme`)
+
+	_, err = datastore.NewDatabase(config.DatabaseConfig{URL: "postgres://%zz", ConnectTimeout: 1}, new(mocks.Logger))
+	assert.ErrorContains(t, err, "parsing database url")
+}
+
+func TestNewDatabase_SQLite(t *testing.T) {
+	dir := t.TempDir()
+	db, err := datastore.NewDatabase(config.DatabaseConfig{URL: "sqlite://" + filepath.Join(dir, "carapi.db"), MaxOpenConns: 2, ConnectTimeout: 1}, new(mocks.Logger))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	var foreignKeys int
+	assert.NoError(t, db.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys))
+	assert.Equal(t, 1, foreignKeys, "foreign keys are enforced like in Postgres")
+	assert.FileExists(t, filepath.Join(dir, "carapi.db"))
+
+	_, err = datastore.NewDatabase(config.DatabaseConfig{URL: "sqlite://", ConnectTimeout: 1}, new(mocks.Logger))
+	assert.EqualError(t, err, `database url "sqlite:" has no file path`)
+}
+
+func TestNewCache(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub redis connection", err)
+	}
+	defer mr.Close()
+
+	client, err := datastore.NewCache(config.CacheConfig{URL: "redis://" + mr.Addr(), PoolSize: 3, ConnectTimeout: 1}, new(mocks.Logger))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, client.Options().PoolSize)
+	client.Close()
+}
@@ -0,0 +1,27 @@
+package datastore
+
+import (
+	"net/url"
+)
+
+const (
+	// MemoryScheme selects the in-memory repositories instead of a server when used as the scheme
+	// of the database or the cache URL, e.g. memory://. Nothing is kept across restarts.
+	MemoryScheme = "memory"
+	// SQLiteScheme selects a SQLite database file, e.g. sqlite://./carapi.db or sqlite:///var/lib/carapi.db
+	SQLiteScheme = "sqlite"
+)
+
+// Scheme gives the scheme of the datastore URL, empty when it does not parse
+func Scheme(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Scheme
+}
+
+// IsMemory tells whether the datastore URL selects the in-memory repositories
+func IsMemory(rawURL string) bool {
+	return Scheme(rawURL) == MemoryScheme
+}
@@ -0,0 +1,35 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"carApi/entity"
+)
+
+// MemoryPublisher keeps the published events in the process, for tests and local development
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []entity.Event
+}
+
+// NewMemoryPublisher will create a publisher without events
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, event entity.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, event)
+	return nil
+}
+
+// Events gives the events published so far in publishing order
+func (p *MemoryPublisher) Events() []entity.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]entity.Event(nil), p.events...)
+}
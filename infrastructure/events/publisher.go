@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"carApi/config"
+	"carApi/entity"
+	"github.com/go-redis/redis"
+)
+
+// Publisher represent the event publishing contract, Publish returns once the event is accepted
+// so the relay can mark it as published
+type Publisher interface {
+	Publish(ctx context.Context, event entity.Event) error
+}
+
+// NewPublisher will create the publisher selected by EVENTS_PUBLISHER, client is nil when the
+// cache is kept in memory
+func NewPublisher(cfg *config.Config, client *redis.Client) (Publisher, error) {
+	publisher := cfg.Events.Publisher
+	if publisher == "" {
+		publisher = "redis"
+		if client == nil {
+			publisher = "memory"
+		}
+	}
+
+	switch publisher {
+	case "redis":
+		if client == nil {
+			return nil, fmt.Errorf("the redis events publisher needs a redis cache.url")
+		}
+		return NewRedisStreamPublisher(client, cfg.Events.Stream, cfg.Events.StreamMaxLen), nil
+	case "memory":
+		return NewMemoryPublisher(), nil
+	default:
+		return nil, fmt.Errorf("unknown events publisher %q", publisher)
+	}
+}
+
+type multiPublisher []Publisher
+
+// NewMultiPublisher will create a publisher handing every event to each of publishers in turn, an
+// event is accepted once all of them accepted it. A failure makes the relay publish the event
+// again to all of them, so each one has to tolerate duplicates.
+func NewMultiPublisher(publishers ...Publisher) Publisher {
+	return multiPublisher(publishers)
+}
+
+func (p multiPublisher) Publish(ctx context.Context, event entity.Event) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
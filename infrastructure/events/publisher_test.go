@@ -0,0 +1,70 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+
+	"carApi/config"
+	"carApi/entity"
+	"carApi/infrastructure/events"
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPublisher(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+
+	tests := map[string]struct {
+		publisher string
+		client    *redis.Client
+		memory    bool
+		err       bool
+	}{
+		"default-redis":     {client: client},
+		"default-memory":    {memory: true},
+		"memory":            {publisher: "memory", client: client, memory: true},
+		"redis-needs-cache": {publisher: "redis", err: true},
+		"unknown":           {publisher: "kafka", client: client, err: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Events.Publisher = test.publisher
+
+			publisher, err := events.NewPublisher(cfg, test.client)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			_, memory := publisher.(*events.MemoryPublisher)
+			assert.Equal(t, test.memory, memory)
+		})
+	}
+}
+
+func TestMultiPublisher(t *testing.T) {
+	event := entity.Event{ID: 1, Type: entity.EventCarDeleted, CarID: 1}
+
+	t.Run("success", func(t *testing.T) {
+		first, second := events.NewMemoryPublisher(), events.NewMemoryPublisher()
+
+		err := events.NewMultiPublisher(first, second).Publish(context.TODO(), event)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Event{event}, first.Events())
+		assert.Equal(t, []entity.Event{event}, second.Events())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		first, last := events.NewMemoryPublisher(), events.NewMemoryPublisher()
+		failing := &failingPublisher{Publisher: events.NewMemoryPublisher(), fail: map[int64]bool{1: true}}
+
+		err := events.NewMultiPublisher(first, failing, last).Publish(context.TODO(), event)
+		assert.Error(t, err)
+		assert.Len(t, first.Events(), 1)
+		assert.Empty(t, last.Events(), "the publishers after a failure are not called")
+	})
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/tracing"
+	"github.com/go-redis/redis"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type redisStreamPublisher struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewRedisStreamPublisher will create a publisher appending the events to a redis stream. Every
+// event goes to the same stream so consumers read the events of a car in order, the event id is
+// kept in the entry to drop the ones delivered twice. maxLen of zero never trims the stream.
+func NewRedisStreamPublisher(client *redis.Client, stream string, maxLen int64) Publisher {
+	return &redisStreamPublisher{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+func (p *redisStreamPublisher) Publish(ctx context.Context, event entity.Event) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "redis XADD",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation.name", "XADD"),
+			attribute.String("messaging.destination.name", p.stream),
+			attribute.String("event.type", string(event.Type)),
+		),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	err = p.client.WithContext(ctx).XAdd(&redis.XAddArgs{
+		Stream:       p.stream,
+		MaxLenApprox: p.maxLen,
+		Values: map[string]interface{}{
+			"id":          strconv.FormatInt(event.ID, 10),
+			"type":        string(event.Type),
+			"car_id":      strconv.FormatInt(event.CarID, 10),
+			"payload":     string(event.Payload),
+			"occurred_at": event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		},
+	}).Err()
+	return
+}
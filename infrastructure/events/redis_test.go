@@ -0,0 +1,82 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/events"
+	"github.com/alicebob/miniredis/server"
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamServer answers XADD, miniredis has no streams
+type streamServer struct {
+	*server.Server
+
+	mu      sync.Mutex
+	entries [][]string
+}
+
+func newStreamServer(t *testing.T) *streamServer {
+	srv, err := server.NewServer("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(srv.Close)
+
+	s := &streamServer{Server: srv}
+	require.NoError(t, srv.Register("XADD", func(c *server.Peer, cmd string, args []string) {
+		s.mu.Lock()
+		s.entries = append(s.entries, args)
+		c.WriteBulk("1-0")
+		s.mu.Unlock()
+	}))
+	return s
+}
+
+func TestRedisStreamPublisher(t *testing.T) {
+	srv := newStreamServer(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr().String()})
+	defer client.Close()
+
+	occurredAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	event := entity.Event{ID: 7, Type: entity.EventCarDeleted, CarID: 3, Payload: json.RawMessage(`{"car_id":3}`), OccurredAt: occurredAt}
+
+	publisher := events.NewRedisStreamPublisher(client, "carapi:events", 1000)
+	require.NoError(t, publisher.Publish(context.TODO(), event))
+
+	require.Len(t, srv.entries, 1)
+	args := srv.entries[0]
+	assert.Equal(t, []string{"carapi:events", "maxlen", "~", "1000", "*"}, args[:5])
+
+	fields := map[string]string{}
+	for i := 5; i+1 < len(args); i += 2 {
+		fields[args[i]] = args[i+1]
+	}
+	assert.Equal(t, map[string]string{
+		"id":          "7",
+		"type":        "car.deleted",
+		"car_id":      "3",
+		"payload":     `{"car_id":3}`,
+		"occurred_at": "2024-05-01T12:00:00Z",
+	}, fields)
+}
+
+func TestRedisStreamPublisher_Error(t *testing.T) {
+	srv, err := server.NewServer("127.0.0.1:0")
+	require.NoError(t, err)
+	defer srv.Close()
+	require.NoError(t, srv.Register("XADD", func(c *server.Peer, cmd string, args []string) {
+		c.WriteError("OOM command not allowed when used memory > 'maxmemory'")
+	}))
+
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr().String()})
+	defer client.Close()
+
+	publisher := events.NewRedisStreamPublisher(client, "carapi:events", 0)
+	assert.Error(t, publisher.Publish(context.TODO(), entity.Event{ID: 1, Type: entity.EventCarDeleted}))
+}
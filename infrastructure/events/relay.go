@@ -0,0 +1,110 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/metrics"
+	"carApi/infrastructure/tracing"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+	"carApi/utils/logger"
+)
+
+// Relay publishes the events of the outbox. An event is marked as published only after the
+// publisher accepted it, so a crash in between publishes it again: delivery is at least once.
+// A batch is relayed in one transaction, the pending rows stay locked so two relays do not
+// interleave the events of a car.
+type Relay struct {
+	outboxRepo pgsql.OutboxRepository
+	txManager  transaction.TxManager
+	publisher  Publisher
+	metrics    *metrics.Metrics
+	logger     logger.Logger
+	interval   time.Duration
+	batchSize  int
+}
+
+// NewRelay will create a relay polling the outbox every interval for up to batchSize events
+func NewRelay(outboxRepo pgsql.OutboxRepository, txManager transaction.TxManager, publisher Publisher, metrics *metrics.Metrics, logger logger.Logger, interval time.Duration, batchSize int) *Relay {
+	return &Relay{
+		outboxRepo: outboxRepo,
+		txManager:  txManager,
+		publisher:  publisher,
+		metrics:    metrics,
+		logger:     logger,
+		interval:   interval,
+		batchSize:  batchSize,
+	}
+}
+
+// Run relays the pending events until ctx is done, a full batch is followed by the next one
+// without waiting for the interval
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		published, err := r.RelayPending(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.logger.Errorf("relaying events: %v", err)
+		}
+
+		if err == nil && published == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes one batch of pending events and gives how many were published. When an
+// event fails, the following events of its car are kept for the next batch so they are never
+// published ahead of it, the events of the other cars go on.
+func (r *Relay) RelayPending(c context.Context) (published int, err error) {
+	ctx, span := tracing.Tracer().Start(c, "events.Relay")
+	defer func() { tracing.End(span, err) }()
+
+	err = r.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		published = 0
+		events, err := r.outboxRepo.FetchPending(ctx, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		failed := map[int64]bool{}
+		for _, event := range events {
+			if failed[event.CarID] {
+				continue
+			}
+
+			if err := r.publish(ctx, event); err != nil {
+				r.logger.Warnf("publishing event %d %s of car %d: %v", event.ID, event.Type, event.CarID, err)
+				failed[event.CarID] = true
+				continue
+			}
+
+			if err := r.outboxRepo.MarkPublished(ctx, event.ID, time.Now()); err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	return
+}
+
+func (r *Relay) publish(ctx context.Context, event entity.Event) error {
+	if err := r.publisher.Publish(ctx, event); err != nil {
+		r.metrics.ObserveEvent(string(event.Type), metrics.EventResultError)
+		return err
+	}
+
+	r.metrics.ObserveEvent(string(event.Type), metrics.EventResultOK)
+	return nil
+}
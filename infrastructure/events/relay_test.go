@@ -0,0 +1,183 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/events"
+	"carApi/infrastructure/metrics"
+	"carApi/mocks"
+	"carApi/repository/memory"
+	"carApi/repository/pgsql"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// failingPublisher fails the events of the cars in fail and passes the others on
+type failingPublisher struct {
+	events.Publisher
+
+	mu   sync.Mutex
+	fail map[int64]bool
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, event entity.Event) error {
+	p.mu.Lock()
+	fail := p.fail[event.CarID]
+	p.mu.Unlock()
+
+	if fail {
+		return errors.New("stream unavailable")
+	}
+	return p.Publisher.Publish(ctx, event)
+}
+
+func (p *failingPublisher) recover(carID int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.fail, carID)
+}
+
+func addEvents(t *testing.T, outboxRepo pgsql.OutboxRepository, events ...entity.EventPayload) {
+	for _, payload := range events {
+		var carID int64
+		switch p := payload.(type) {
+		case entity.CarCreated:
+			carID = p.Car.ID
+		case entity.CarDeleted:
+			carID = p.CarID
+		}
+
+		event, err := entity.NewCarEvent(carID, payload, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, outboxRepo.Add(context.TODO(), &event))
+	}
+}
+
+func eventKeys(published []entity.Event) (keys []string) {
+	for _, event := range published {
+		keys = append(keys, fmt.Sprintf("%s:%d", event.Type, event.CarID))
+	}
+	return
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := memory.NewStore()
+		outboxRepo := memory.NewMemoryOutboxRepository(store)
+		publisher := events.NewMemoryPublisher()
+		appMetrics := metrics.NewMetrics()
+		addEvents(t, outboxRepo, entity.CarCreated{Car: entity.Car{ID: 1}}, entity.CarCreated{Car: entity.Car{ID: 2}}, entity.CarDeleted{CarID: 1})
+
+		relay := events.NewRelay(outboxRepo, memory.NewMemoryTxManager(store), publisher, appMetrics, new(mocks.Logger), time.Second, 10)
+		published, err := relay.RelayPending(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, 3, published)
+		assert.Equal(t, []string{"car.created:1", "car.created:2", "car.deleted:1"}, eventKeys(publisher.Events()))
+		assert.Equal(t, 2.0, testutil.ToFloat64(appMetrics.Events.WithLabelValues("car.created", metrics.EventResultOK)))
+
+		published, err = relay.RelayPending(context.TODO())
+		assert.NoError(t, err)
+		assert.Zero(t, published, "published events are not relayed again")
+		assert.Len(t, publisher.Events(), 3)
+	})
+
+	t.Run("batch-size", func(t *testing.T) {
+		store := memory.NewStore()
+		outboxRepo := memory.NewMemoryOutboxRepository(store)
+		publisher := events.NewMemoryPublisher()
+		addEvents(t, outboxRepo, entity.CarDeleted{CarID: 1}, entity.CarDeleted{CarID: 2}, entity.CarDeleted{CarID: 3})
+
+		relay := events.NewRelay(outboxRepo, memory.NewMemoryTxManager(store), publisher, metrics.NewMetrics(), new(mocks.Logger), time.Second, 2)
+		published, err := relay.RelayPending(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, published)
+
+		published, err = relay.RelayPending(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, published)
+		assert.Equal(t, []string{"car.deleted:1", "car.deleted:2", "car.deleted:3"}, eventKeys(publisher.Events()))
+	})
+
+	t.Run("failure-keeps-car-order", func(t *testing.T) {
+		store := memory.NewStore()
+		outboxRepo := memory.NewMemoryOutboxRepository(store)
+		memoryPublisher := events.NewMemoryPublisher()
+		publisher := &failingPublisher{Publisher: memoryPublisher, fail: map[int64]bool{1: true}}
+		appMetrics := metrics.NewMetrics()
+		addEvents(t, outboxRepo, entity.CarCreated{Car: entity.Car{ID: 1}}, entity.CarCreated{Car: entity.Car{ID: 2}}, entity.CarDeleted{CarID: 1})
+
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Warnf", mock.Anything, int64(1), entity.EventCarCreated, int64(1), mock.Anything).Once()
+
+		relay := events.NewRelay(outboxRepo, memory.NewMemoryTxManager(store), publisher, appMetrics, mockLogger, time.Second, 10)
+		published, err := relay.RelayPending(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, published)
+		assert.Equal(t, []string{"car.created:2"}, eventKeys(memoryPublisher.Events()), "the deletion of car 1 waits for its creation")
+		assert.Equal(t, 1.0, testutil.ToFloat64(appMetrics.Events.WithLabelValues("car.created", metrics.EventResultError)))
+		mockLogger.AssertExpectations(t)
+
+		publisher.recover(1)
+		published, err = relay.RelayPending(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, published)
+		assert.Equal(t, []string{"car.created:2", "car.created:1", "car.deleted:1"}, eventKeys(memoryPublisher.Events()))
+	})
+
+	t.Run("error-mark-published", func(t *testing.T) {
+		mockOutboxRepo := new(mocks.OutboxRepository)
+		mockTxManager := new(mocks.TxManager)
+		publisher := events.NewMemoryPublisher()
+		event := entity.Event{ID: 1, Type: entity.EventCarDeleted, CarID: 1}
+
+		mockTxManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).Once()
+		mockOutboxRepo.On("FetchPending", mock.Anything, 10).Return([]entity.Event{event}, nil).Once()
+		mockOutboxRepo.On("MarkPublished", mock.Anything, int64(1), mock.AnythingOfType("time.Time")).Return(errors.New("connection reset")).Once()
+
+		relay := events.NewRelay(mockOutboxRepo, mockTxManager, publisher, metrics.NewMetrics(), new(mocks.Logger), time.Second, 10)
+		_, err := relay.RelayPending(context.TODO())
+		assert.Error(t, err)
+		assert.Len(t, publisher.Events(), 1, "the event is published again with the next batch")
+
+		mockOutboxRepo.AssertExpectations(t)
+		mockTxManager.AssertExpectations(t)
+	})
+}
+
+func TestRelay_Run(t *testing.T) {
+	store := memory.NewStore()
+	outboxRepo := memory.NewMemoryOutboxRepository(store)
+	publisher := events.NewMemoryPublisher()
+	addEvents(t, outboxRepo, entity.CarDeleted{CarID: 1}, entity.CarDeleted{CarID: 2}, entity.CarDeleted{CarID: 3})
+
+	relay := events.NewRelay(outboxRepo, memory.NewMemoryTxManager(store), publisher, metrics.NewMetrics(), new(mocks.Logger), 10*time.Millisecond, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return len(publisher.Events()) == 3 }, time.Second, 5*time.Millisecond)
+
+	addEvents(t, outboxRepo, entity.CarDeleted{CarID: 4})
+	assert.Eventually(t, func() bool { return len(publisher.Events()) == 4 }, time.Second, 5*time.Millisecond, "later events are picked on the next tick")
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the relay did not stop with its context")
+	}
+}
@@ -0,0 +1,41 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"carApi/entity"
+)
+
+// LoadRates will read the exchange rates from a JSON file, an empty path
+// gives rates without any currency so only same-currency display works
+func LoadRates(path string) (rates entity.ExchangeRates, err error) {
+	if path == "" {
+		return
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(body, &rates); err != nil {
+		return rates, fmt.Errorf("exchange rates %s: %w", path, err)
+	}
+
+	if _, ok := entity.Currencies[rates.Base]; !ok {
+		return rates, fmt.Errorf("exchange rates %s: unsupported base currency %q", path, rates.Base)
+	}
+
+	for currency, rate := range rates.Rates {
+		if _, ok := entity.Currencies[currency]; !ok {
+			return rates, fmt.Errorf("exchange rates %s: unsupported currency %q", path, currency)
+		}
+		if rate <= 0 {
+			return rates, fmt.Errorf("exchange rates %s: rate for %s must be positive", path, currency)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,47 @@
+package exchange_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"carApi/infrastructure/exchange"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRates(t *testing.T, body string) string {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o644))
+	return path
+}
+
+func TestLoadRates(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		rates, err := exchange.LoadRates(writeRates(t, `{"base":"USD","rates":{"EUR":0.92,"BRL":5.1}}`))
+		require.NoError(t, err)
+		assert.Equal(t, "USD", rates.Base)
+		assert.Equal(t, 0.92, rates.Rates["EUR"])
+	})
+
+	t.Run("empty-path", func(t *testing.T) {
+		rates, err := exchange.LoadRates("")
+		require.NoError(t, err)
+		assert.Empty(t, rates.Rates)
+	})
+
+	t.Run("error-unsupported-currency", func(t *testing.T) {
+		_, err := exchange.LoadRates(writeRates(t, `{"base":"USD","rates":{"XXX":1}}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("error-non-positive-rate", func(t *testing.T) {
+		_, err := exchange.LoadRates(writeRates(t, `{"base":"USD","rates":{"EUR":0}}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("error-missing-file", func(t *testing.T) {
+		_, err := exchange.LoadRates(filepath.Join(t.TempDir(), "missing.json"))
+		assert.Error(t, err)
+	})
+}
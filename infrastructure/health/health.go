@@ -0,0 +1,157 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded"
+)
+
+// Check is a dependency probe, a non critical dependency being down only degrades the instance
+type Check struct {
+	Name     string
+	Critical bool
+	Timeout  time.Duration
+	Ping     func(ctx context.Context) error
+}
+
+// DependencyStatus is the outcome of the latest probe of a dependency, LastError is
+// kept after the dependency recovers to help diagnosing flapping
+type DependencyStatus struct {
+	Status      string     `json:"status"`
+	Critical    bool       `json:"critical"`
+	LatencyMs   float64    `json:"latency_ms"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// Report aggregates the dependency statuses, Status is down when a critical
+// dependency is down and degraded when only non critical ones are
+type Report struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+type lastError struct {
+	message string
+	at      time.Time
+}
+
+// Checker probes the dependencies of the instance
+type Checker struct {
+	checks []Check
+
+	mu         sync.Mutex
+	lastErrors map[string]lastError
+}
+
+// NewChecker will create a checker for the given dependencies
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{
+		checks:     checks,
+		lastErrors: map[string]lastError{},
+	}
+}
+
+// Check probes every dependency concurrently, each within its own timeout
+func (c *Checker) Check(ctx context.Context) Report {
+	statuses := make([]DependencyStatus, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			statuses[i] = c.probe(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:       StatusUp,
+		Dependencies: make(map[string]DependencyStatus, len(c.checks)),
+	}
+	for i, check := range c.checks {
+		report.Dependencies[check.Name] = statuses[i]
+		if statuses[i].Status == StatusUp {
+			continue
+		}
+		if check.Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	return report
+}
+
+func (c *Checker) probe(ctx context.Context, check Check) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+
+	// the probe gives up at the timeout even if the client ignores the context
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Ping(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	status := DependencyStatus{
+		Status:    StatusUp,
+		Critical:  check.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		status.Status = StatusDown
+		c.lastErrors[check.Name] = lastError{message: err.Error(), at: time.Now()}
+	}
+	if last, ok := c.lastErrors[check.Name]; ok {
+		at := last.at
+		status.LastError = last.message
+		status.LastErrorAt = &at
+	}
+
+	return status
+}
+
+// DatabaseCheck will probe the database connection pool
+func DatabaseCheck(db *sql.DB, timeout time.Duration) Check {
+	return Check{
+		Name:     "database",
+		Critical: true,
+		Timeout:  timeout,
+		Ping:     db.PingContext,
+	}
+}
+
+// CacheCheck will probe the redis client, the API keeps serving from the database without it
+func CacheCheck(client *redis.Client, timeout time.Duration) Check {
+	return Check{
+		Name:     "cache",
+		Critical: false,
+		Timeout:  timeout,
+		Ping: func(ctx context.Context) error {
+			return client.WithContext(ctx).Ping().Err()
+		},
+	}
+}
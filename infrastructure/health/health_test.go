@@ -0,0 +1,103 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"carApi/infrastructure/health"
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func staticCheck(name string, critical bool, err *error) health.Check {
+	return health.Check{
+		Name:     name,
+		Critical: critical,
+		Timeout:  time.Second,
+		Ping: func(ctx context.Context) error {
+			return *err
+		},
+	}
+}
+
+func TestChecker_Check(t *testing.T) {
+	var dbErr, cacheErr error
+	checker := health.NewChecker(staticCheck("database", true, &dbErr), staticCheck("cache", false, &cacheErr))
+
+	t.Run("up", func(t *testing.T) {
+		report := checker.Check(context.TODO())
+		assert.Equal(t, health.StatusUp, report.Status)
+		assert.Equal(t, health.StatusUp, report.Dependencies["database"].Status)
+		assert.Empty(t, report.Dependencies["cache"].LastError)
+	})
+
+	t.Run("cache-down-is-degraded", func(t *testing.T) {
+		cacheErr = errors.New("connection refused")
+		report := checker.Check(context.TODO())
+		assert.Equal(t, health.StatusDegraded, report.Status)
+		assert.Equal(t, health.StatusDown, report.Dependencies["cache"].Status)
+		assert.Equal(t, "connection refused", report.Dependencies["cache"].LastError)
+	})
+
+	t.Run("database-down-is-down", func(t *testing.T) {
+		dbErr = errors.New("connection refused")
+		report := checker.Check(context.TODO())
+		assert.Equal(t, health.StatusDown, report.Status)
+	})
+
+	t.Run("recovered-keeps-last-error", func(t *testing.T) {
+		dbErr, cacheErr = nil, nil
+		report := checker.Check(context.TODO())
+		assert.Equal(t, health.StatusUp, report.Status)
+		assert.Equal(t, "connection refused", report.Dependencies["cache"].LastError)
+		assert.NotNil(t, report.Dependencies["cache"].LastErrorAt)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		slow := health.NewChecker(health.Check{
+			Name:     "slow",
+			Critical: true,
+			Timeout:  10 * time.Millisecond,
+			Ping: func(ctx context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			},
+		})
+		report := slow.Check(context.TODO())
+		assert.Equal(t, health.StatusDown, report.Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Dependencies["slow"].LastError)
+	})
+}
+
+func TestDatabaseCheck(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	checker := health.NewChecker(health.DatabaseCheck(db, time.Second))
+	assert.Equal(t, health.StatusUp, checker.Check(context.TODO()).Status)
+
+	db.Close()
+	report := checker.Check(context.TODO())
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, "sql: database is closed", report.Dependencies["database"].LastError)
+}
+
+func TestCacheCheck(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	checker := health.NewChecker(health.CacheCheck(client, time.Second))
+	assert.Equal(t, health.StatusUp, checker.Check(context.TODO()).Status)
+
+	mr.Close()
+	report := checker.Check(context.TODO())
+	assert.Equal(t, health.StatusDegraded, report.Status)
+	assert.NotEmpty(t, report.Dependencies["cache"].LastError)
+}
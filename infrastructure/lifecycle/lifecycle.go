@@ -0,0 +1,78 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"carApi/utils/logger"
+)
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Lifecycle tracks whether the instance should receive traffic and closes
+// its resources in order once it is stopped
+type Lifecycle struct {
+	logger logger.Logger
+	ready  atomic.Bool
+
+	mu    sync.Mutex
+	hooks []hook
+}
+
+// NewLifecycle will create a lifecycle that is not ready yet
+func NewLifecycle(logger logger.Logger) *Lifecycle {
+	return &Lifecycle{
+		logger: logger,
+	}
+}
+
+// Ready tells whether the instance accepts new traffic
+func (l *Lifecycle) Ready() bool {
+	return l.ready.Load()
+}
+
+// SetReady flips the readiness reported to the load balancer
+func (l *Lifecycle) SetReady(ready bool) {
+	l.ready.Store(ready)
+}
+
+// OnShutdown registers a resource to close, like defer hooks run in reverse
+// registration order so a resource outlives everything registered after it
+func (l *Lifecycle) OnShutdown(name string, fn func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, hook{name: name, fn: fn})
+}
+
+// Shutdown marks the instance as not ready and runs every hook, a failing
+// hook does not prevent the following ones from running
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.SetReady(false)
+
+	l.mu.Lock()
+	hooks := l.hooks
+	l.hooks = nil
+	l.mu.Unlock()
+
+	var failed []string
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		l.logger.Infof("shutting down %s", h.name)
+		if err := h.fn(ctx); err != nil {
+			l.logger.Errorf("shutting down %s: %v", h.name, err)
+			failed = append(failed, fmt.Sprintf("%s: %v", h.name, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("shutdown failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"carApi/infrastructure/lifecycle"
+	"carApi/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLifecycle_Shutdown(t *testing.T) {
+	mockLogger := new(mocks.Logger)
+	mockLogger.On("Infof", mock.Anything, mock.Anything)
+	mockLogger.On("Errorf", mock.Anything, mock.Anything, mock.Anything)
+
+	lc := lifecycle.NewLifecycle(mockLogger)
+	assert.False(t, lc.Ready())
+	lc.SetReady(true)
+	assert.True(t, lc.Ready())
+
+	var order []string
+	lc.OnShutdown("cache", func(ctx context.Context) error {
+		order = append(order, "cache")
+		return nil
+	})
+	lc.OnShutdown("database", func(ctx context.Context) error {
+		order = append(order, "database")
+		return errors.New("already closed")
+	})
+	lc.OnShutdown("workers", func(ctx context.Context) error {
+		order = append(order, "workers")
+		return nil
+	})
+
+	err := lc.Shutdown(context.TODO())
+
+	assert.EqualError(t, err, "shutdown failed: database: already closed")
+	assert.Equal(t, []string{"workers", "database", "cache"}, order)
+	assert.False(t, lc.Ready())
+
+	order = nil
+	assert.NoError(t, lc.Shutdown(context.TODO()))
+	assert.Empty(t, order)
+}
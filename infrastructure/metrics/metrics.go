@@ -0,0 +1,169 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metric names and labels are relied upon by dashboards and alerts, rename them only with a migration plan.
+//
+//	carapi_http_requests_total{method, route, status}             counter of handled requests
+//	carapi_http_request_duration_seconds{method, route, status}   histogram of request latency
+//	carapi_cache_requests_total{operation, result}                counter of redis calls, get results are hit, miss or error and set results are ok or error
+//	carapi_events_published_total{type, result}                   counter of outbox events handed to the publisher, result is ok or error
+//	carapi_webhook_deliveries_total{result}                       counter of webhook delivery attempts, result is succeeded, retry or dead
+//	carapi_cars{category}                                         gauge of stored cars per category, computed at scrape time
+//	go_sql_*{db_name="carapi"}                                    database/sql pool stats: open, in use and idle connections, wait count and duration
+//
+// route is the registered route template, e.g. /api/v1/cars/:id, or "unmatched" so raw paths never become labels.
+const (
+	Namespace = "carapi"
+
+	CacheOperationGet = "get"
+	CacheOperationSet = "set"
+
+	CacheResultHit   = "hit"
+	CacheResultMiss  = "miss"
+	CacheResultOK    = "ok"
+	CacheResultError = "error"
+
+	EventResultOK    = "ok"
+	EventResultError = "error"
+
+	WebhookResultSucceeded = "succeeded"
+	WebhookResultRetry     = "retry"
+	WebhookResultDead      = "dead"
+)
+
+// Metrics holds the collectors exposed on /metrics, they live in their own
+// registry so tests can create as many as they need
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	HTTPRequests  *prometheus.CounterVec
+	HTTPDuration  *prometheus.HistogramVec
+	CacheRequests *prometheus.CounterVec
+	Events        *prometheus.CounterVec
+	Webhooks      *prometheus.CounterVec
+}
+
+// NewMetrics will create the HTTP, cache, events and webhook collectors along with the Go runtime ones
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests handled, by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		HTTPDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests, by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		CacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "cache",
+			Name:      "requests_total",
+			Help:      "Number of redis calls, by operation and result.",
+		}, []string{"operation", "result"}),
+		Events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "events",
+			Name:      "published_total",
+			Help:      "Number of outbox events handed to the publisher, by type and result.",
+		}, []string{"type", "result"}),
+		Webhooks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "webhook",
+			Name:      "deliveries_total",
+			Help:      "Number of webhook delivery attempts, by result.",
+		}, []string{"result"}),
+	}
+
+	m.Registry.MustRegister(
+		m.HTTPRequests,
+		m.HTTPDuration,
+		m.CacheRequests,
+		m.Events,
+		m.Webhooks,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// ObserveCache counts a redis call
+func (m *Metrics) ObserveCache(operation string, result string) {
+	m.CacheRequests.WithLabelValues(operation, result).Inc()
+}
+
+// ObserveEvent counts an attempt to publish an event
+func (m *Metrics) ObserveEvent(eventType string, result string) {
+	m.Events.WithLabelValues(eventType, result).Inc()
+}
+
+// ObserveWebhook counts an attempt to deliver a webhook
+func (m *Metrics) ObserveWebhook(result string) {
+	m.Webhooks.WithLabelValues(result).Inc()
+}
+
+// RegisterDatabase exposes the connection pool stats of db
+func (m *Metrics) RegisterDatabase(db *sql.DB) {
+	m.Registry.MustRegister(collectors.NewDBStatsCollector(db, Namespace))
+}
+
+// RegisterCarsByCategory exposes the carapi_cars gauge, count is called on
+// every scrape and given up to timeout to answer
+func (m *Metrics) RegisterCarsByCategory(count func(ctx context.Context) (map[string]int64, error), timeout time.Duration) {
+	m.Registry.MustRegister(&countCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "", "cars"),
+			"Number of stored cars, by category.",
+			[]string{"category"}, nil,
+		),
+		count:   count,
+		timeout: timeout,
+	})
+}
+
+// Handler serves the registry in the Prometheus exposition format, a failing
+// collector such as an unreachable database does not fail the whole scrape
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
+
+type countCollector struct {
+	desc    *prometheus.Desc
+	count   func(ctx context.Context) (map[string]int64, error)
+	timeout time.Duration
+}
+
+func (c *countCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *countCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	counts, err := c.count(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	for label, value := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(value), label)
+	}
+}
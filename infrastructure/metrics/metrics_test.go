@@ -0,0 +1,61 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"carApi/infrastructure/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestMetrics_RegisterCarsByCategory(t *testing.T) {
+	var countErr error
+	appMetrics := metrics.NewMetrics()
+	appMetrics.RegisterCarsByCategory(func(ctx context.Context) (map[string]int64, error) {
+		return map[string]int64{"Sedan": 3, "SUV": 1}, countErr
+	}, time.Second)
+
+	expected := `
+# HELP carapi_cars Number of stored cars, by category.
+# TYPE carapi_cars gauge
+carapi_cars{category="SUV"} 1
+carapi_cars{category="Sedan"} 3
+`
+	assert.NoError(t, testutil.GatherAndCompare(appMetrics.Registry, strings.NewReader(expected), "carapi_cars"))
+
+	t.Run("count-error-keeps-scrape", func(t *testing.T) {
+		countErr = errors.New("connection refused")
+		appMetrics.ObserveCache(metrics.CacheOperationGet, metrics.CacheResultHit)
+
+		rec := httptest.NewRecorder()
+		appMetrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `carapi_cache_requests_total{operation="get",result="hit"} 1`)
+		assert.NotContains(t, rec.Body.String(), "carapi_cars{")
+	})
+}
+
+func TestMetrics_RegisterDatabase(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	appMetrics := metrics.NewMetrics()
+	appMetrics.RegisterDatabase(db)
+
+	rec := httptest.NewRecorder()
+	appMetrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Contains(t, rec.Body.String(), `go_sql_open_connections{db_name="carapi"}`)
+	assert.Contains(t, rec.Body.String(), `go_sql_in_use_connections{db_name="carapi"}`)
+	assert.Contains(t, rec.Body.String(), `go_sql_wait_count_total{db_name="carapi"}`)
+}
@@ -0,0 +1,200 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"carApi/utils/logger"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	sqliteMigrate "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Migration is a version of the schema as found in the migration files
+type Migration struct {
+	Version uint   `json:"version"`
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+}
+
+// Status is the version of the database and the state of every migration
+type Status struct {
+	Version    uint        `json:"version"`
+	Dirty      bool        `json:"dirty"`
+	Migrations []Migration `json:"migrations"`
+}
+
+// Migrator applies the migrations to the database. The postgres driver holds an advisory
+// lock while migrating so replicas starting together wait for each other instead of racing,
+// a SQLite database belongs to a single instance and is only locked within the process.
+type Migrator struct {
+	files   fs.FS
+	migrate *migrate.Migrate
+}
+
+// NewMigrator will create a migrator over the files for the database behind db, Postgres or SQLite,
+// it keeps one connection of the pool until Close and waits up to lockTimeout for another migrator
+// to release the lock
+func NewMigrator(db *sql.DB, files fs.FS, lockTimeout time.Duration, log logger.Logger) (*Migrator, error) {
+	sourceDriver, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	databaseName, databaseDriver, err := newDatabaseDriver(db)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", sourceDriver, databaseName, databaseDriver)
+	if err != nil {
+		databaseDriver.Close()
+		return nil, err
+	}
+	m.LockTimeout = lockTimeout
+	m.Log = &migrateLogger{log}
+
+	return &Migrator{
+		files:   files,
+		migrate: m,
+	}, nil
+}
+
+func newDatabaseDriver(db *sql.DB) (string, database.Driver, error) {
+	if _, ok := db.Driver().(*sqlite3.SQLiteDriver); ok {
+		driver, err := sqliteMigrate.WithInstance(db, &sqliteMigrate.Config{})
+		if err != nil {
+			return "", nil, err
+		}
+		return "sqlite3", sharedDatabase{driver}, nil
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return "", nil, err
+	}
+
+	driver, err := postgres.WithConnection(context.Background(), conn, &postgres.Config{})
+	if err != nil {
+		conn.Close()
+		return "", nil, err
+	}
+	return "postgres", driver, nil
+}
+
+// sharedDatabase keeps the pool open when the migrator is closed, the sqlite driver
+// only knows how to close the whole pool
+type sharedDatabase struct {
+	database.Driver
+}
+
+func (sharedDatabase) Close() error {
+	return nil
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.migrate.Up())
+}
+
+// Down rolls back the last n applied migrations
+func (m *Migrator) Down(n int) error {
+	if n < 1 {
+		return fmt.Errorf("number of migrations to roll back must be positive, got %d", n)
+	}
+	return ignoreNoChange(m.migrate.Steps(-n))
+}
+
+// Goto migrates up or down to the version
+func (m *Migrator) Goto(version uint) error {
+	return ignoreNoChange(m.migrate.Migrate(version))
+}
+
+// Force records the version as applied and clean without running any migration,
+// it is how a dirty database is recovered once the schema has been repaired by hand
+func (m *Migrator) Force(version uint) error {
+	return m.migrate.Force(int(version))
+}
+
+// Status gives the version of the database and which migrations are applied
+func (m *Migrator) Status() (status Status, err error) {
+	status.Version, status.Dirty, err = m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	status.Migrations, err = Migrations(m.files)
+	if err != nil {
+		return
+	}
+
+	for i := range status.Migrations {
+		status.Migrations[i].Applied = status.Migrations[i].Version <= status.Version && status.Version > 0
+	}
+	return
+}
+
+// Close releases the connection taken from the pool, the pool itself stays open
+func (m *Migrator) Close() error {
+	_, err := m.migrate.Close()
+	return err
+}
+
+// Migrations lists the versions found in the files in ascending order
+func Migrations(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint]Migration{}
+	for _, entry := range entries {
+		parsed, err := source.Parse(entry.Name())
+		if err != nil {
+			continue
+		}
+		byVersion[parsed.Version] = Migration{Version: parsed.Version, Name: parsed.Identifier}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
+
+// migrateLogger reports the applied migrations through the API logger
+type migrateLogger struct {
+	logger logger.Logger
+}
+
+func (l *migrateLogger) Printf(format string, v ...interface{}) {
+	l.logger.Info(strings.TrimSuffix(fmt.Sprintf(format, v...), "\n"))
+}
+
+func (l *migrateLogger) Verbose() bool {
+	return false
+}
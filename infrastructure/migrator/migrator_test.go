@@ -0,0 +1,65 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"carApi/infrastructure/migrator"
+	sqliteMigration "carApi/migration/sqlite"
+	"carApi/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMigrations(t *testing.T) {
+	files := fstest.MapFS{
+		"000002_create_car_images_table.down.sql": {Data: []byte("DROP TABLE car_images;")},
+		"000002_create_car_images_table.up.sql":   {Data: []byte("CREATE TABLE car_images();")},
+		"000001_create_car_table.down.sql":        {Data: []byte("DROP TABLE cars;")},
+		"000001_create_car_table.up.sql":          {Data: []byte("CREATE TABLE cars();")},
+		"migration.go":                            {Data: []byte("package migration")},
+	}
+
+	migrations, err := migrator.Migrations(files)
+	assert.NoError(t, err)
+	assert.Equal(t, []migrator.Migration{
+		{Version: 1, Name: "create_car_table"},
+		{Version: 2, Name: "create_car_images_table"},
+	}, migrations)
+}
+
+func TestMigrator_SQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "carapi.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	mockLogger := new(mocks.Logger)
+	mockLogger.On("Info", mock.Anything).Times(10)
+	m, err := migrator.NewMigrator(db, sqliteMigration.Files, time.Second, mockLogger)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, m.Up())
+	status, err := m.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(9), status.Version)
+	for _, migration := range status.Migrations {
+		assert.True(t, migration.Applied, "%06d_%s", migration.Version, migration.Name)
+	}
+
+	assert.NoError(t, m.Down(1))
+	status, err = m.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(8), status.Version)
+	assert.NoError(t, m.Close())
+
+	_, err = db.Exec("INSERT INTO features (code, name) VALUES ('sunroof', 'Sunroof')")
+	assert.NoError(t, err, "closing the migrator keeps the pool open")
+	mockLogger.AssertExpectations(t)
+}
@@ -0,0 +1,97 @@
+package seed
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"carApi/entity"
+	"carApi/transport/request"
+)
+
+// bodyStyles gives the category of the catalog models, models missing here get a random category
+var bodyStyles = map[string]string{
+	"Corolla":  "Sedan",
+	"Camry":    "Sedan",
+	"RAV4":     "SUV",
+	"Hilux":    "Pickup",
+	"Civic":    "Hatchback",
+	"Accord":   "Sedan",
+	"CR-V":     "SUV",
+	"Focus":    "Hatchback",
+	"Mustang":  "Coupe",
+	"F-150":    "Pickup",
+	"Golf":     "Hatchback",
+	"Polo":     "Hatchback",
+	"Tiguan":   "SUV",
+	"3 Series": "Sedan",
+	"X5":       "SUV",
+}
+
+var colors = []string{"White", "Black", "Silver", "Grey", "Blue", "Red", "Green", "Beige"}
+
+// vinCharacters are the characters allowed in a VIN, I, O and Q are left out
+const vinCharacters = "ABCDEFGHJKLMNPRSTUVWXYZ0123456789"
+
+type catalogModel struct {
+	make  string
+	model entity.CatalogSeedModel
+}
+
+// Cars will generate n plausible cars from the catalog for development and load tests: older
+// cars have more mileage and a lower price. The same rnd seed gives the same cars.
+func Cars(catalog entity.CatalogSeed, n int, rnd *rand.Rand) []request.CreateCarReq {
+	var models []catalogModel
+	for _, carMake := range catalog.Makes {
+		for _, model := range carMake.Models {
+			models = append(models, catalogModel{make: carMake.Name, model: model})
+		}
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	thisYear := time.Now().Year()
+	cars := make([]request.CreateCarReq, 0, n)
+	for i := 0; i < n; i++ {
+		picked := models[rnd.Intn(len(models))]
+		age := rnd.Intn(15)
+
+		trim := "Base"
+		if len(picked.model.Trims) > 0 {
+			trim = picked.model.Trims[rnd.Intn(len(picked.model.Trims))]
+		}
+
+		category, ok := bodyStyles[picked.model.Name]
+		if !ok && len(catalog.Categories) > 0 {
+			category = catalog.Categories[rnd.Intn(len(catalog.Categories))]
+		}
+
+		// a new car between 20,000 and 60,000 USD losing about 8% a year, rounded to 100 USD
+		price := float64(20000+rnd.Intn(40000)) * math.Pow(0.92, float64(age))
+		amount := int64(price/100) * 100 * 100
+
+		cars = append(cars, request.CreateCarReq{
+			Make:           picked.make,
+			Model:          picked.model.Name,
+			Package:        trim,
+			Color:          colors[rnd.Intn(len(colors))],
+			Year:           thisYear - age,
+			Category:       category,
+			Mileage:        1 + age*(8000+rnd.Intn(12000)) + rnd.Intn(5000),
+			MileageUnit:    entity.MileageUnitKilometers,
+			Price:          request.MoneyReq{Amount: amount, Currency: "USD"},
+			Identification: vin(rnd),
+		})
+	}
+	return cars
+}
+
+func vin(rnd *rand.Rand) string {
+	var b strings.Builder
+	for i := 0; i < 17; i++ {
+		b.WriteByte(vinCharacters[rnd.Intn(len(vinCharacters))])
+	}
+	return b.String()
+}
@@ -0,0 +1,17 @@
+package seed
+
+import (
+	_ "embed"
+	"encoding/json"
+
+	"carApi/entity"
+)
+
+//go:embed catalog.json
+var catalogJSON []byte
+
+// Catalog will decode the reference catalog bundled in the binary
+func Catalog() (catalog entity.CatalogSeed, err error) {
+	err = json.Unmarshal(catalogJSON, &catalog)
+	return
+}
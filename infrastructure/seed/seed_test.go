@@ -0,0 +1,37 @@
+package seed_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"carApi/infrastructure/seed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog(t *testing.T) {
+	catalog, err := seed.Catalog()
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, catalog.Makes)
+	assert.NotEmpty(t, catalog.Categories)
+	for _, carMake := range catalog.Makes {
+		assert.NotEmpty(t, carMake.Name)
+		assert.NotEmpty(t, carMake.Models, carMake.Name)
+	}
+}
+
+func TestCars(t *testing.T) {
+	catalog, err := seed.Catalog()
+	require.NoError(t, err)
+
+	cars := seed.Cars(catalog, 50, rand.New(rand.NewSource(1)))
+	assert.Len(t, cars, 50)
+	for _, car := range cars {
+		assert.NoError(t, car.Validate())
+		assert.Len(t, car.Identification, 17)
+		assert.Contains(t, catalog.Categories, car.Category)
+	}
+
+	assert.Equal(t, cars, seed.Cars(catalog, 50, rand.New(rand.NewSource(1))))
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type localStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage will create a Storage that keeps objects under dir and
+// exposes them below baseURL
+func NewLocalStorage(dir, baseURL string) Storage {
+	return &localStorage{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (s *localStorage) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(cleaned)), nil
+}
+
+// Put writes the object atomically so readers never see a partial file
+func (s *localStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (err error) {
+	target, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return
+	}
+
+	if err = tmp.Close(); err != nil {
+		return
+	}
+
+	return os.Rename(tmp.Name(), target)
+}
+
+// Delete removes the object, a missing object is not an error
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *localStorage) URL(key string) string {
+	return s.baseURL + "/" + strings.TrimLeft(key, "/")
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"carApi/config"
+)
+
+// Storage represent the media storage contract
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// NewStorage will create the storage backend selected by MEDIA_STORAGE
+func NewStorage(cfg *config.Config) (Storage, error) {
+	switch cfg.Media.Storage {
+	case "", "local":
+		return NewLocalStorage(cfg.Media.LocalDir, cfg.Media.BaseURL), nil
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			UseSSL:    cfg.S3.UseSSL,
+			BaseURL:   cfg.Media.BaseURL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown media storage %q", cfg.Media.Storage)
+	}
+}
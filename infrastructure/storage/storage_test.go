@@ -0,0 +1,83 @@
+package storage_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"carApi/infrastructure/storage"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewLocalStorage(dir, "http://localhost:8080/media/")
+
+	err := store.Put(context.TODO(), "cars/1/image.jpg", strings.NewReader("jpeg"), 4, "image/jpeg")
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "cars", "1", "image.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", string(data))
+	assert.Equal(t, "http://localhost:8080/media/cars/1/image.jpg", store.URL("cars/1/image.jpg"))
+
+	t.Run("key-cannot-escape-dir", func(t *testing.T) {
+		err := store.Put(context.TODO(), "../../escape.jpg", strings.NewReader("jpeg"), 4, "image/jpeg")
+		require.NoError(t, err)
+		_, err = os.Stat(filepath.Join(dir, "escape.jpg"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, store.Delete(context.TODO(), "cars/1/image.jpg"))
+		_, err := os.Stat(filepath.Join(dir, "cars", "1", "image.jpg"))
+		assert.True(t, os.IsNotExist(err))
+		assert.NoError(t, store.Delete(context.TODO(), "cars/1/image.jpg"))
+	})
+}
+
+func TestS3Storage(t *testing.T) {
+	server := httptest.NewServer(gofakes3.New(s3mem.New()).Server())
+	defer server.Close()
+
+	endpoint, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	store, err := storage.NewS3Storage(storage.S3Config{
+		Endpoint:  endpoint.Host,
+		Region:    "us-east-1",
+		Bucket:    "car-images",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	require.NoError(t, err)
+
+	err = store.Put(context.TODO(), "cars/1/image.jpg", strings.NewReader("jpeg"), 4, "image/jpeg")
+	require.NoError(t, err)
+
+	objectURL := store.URL("cars/1/image.jpg")
+	assert.Equal(t, server.URL+"/car-images/cars/1/image.jpg", objectURL)
+
+	res, err := http.Get(objectURL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", string(data))
+	assert.Equal(t, "image/jpeg", res.Header.Get("Content-Type"))
+
+	require.NoError(t, store.Delete(context.TODO(), "cars/1/image.jpg"))
+	res, err = http.Get(objectURL)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
@@ -0,0 +1,141 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"carApi/entity"
+)
+
+// Hub fans the events out to the streams open on this instance and keeps the latest ones so a
+// client reconnecting with Last-Event-ID gets the ones it missed. It is an events publisher: the
+// relay feeds it directly when there is a single instance, a redis listener does otherwise.
+type Hub struct {
+	mu           sync.Mutex
+	replay       []entity.Event
+	replayed     map[int64]bool
+	replaySize   int
+	clientBuffer int
+	subscribers  map[*Subscription]bool
+	closed       bool
+}
+
+// Subscription is a stream of events, Replay holds the events missed since the Last-Event-ID and
+// Reset tells some of them are no longer known. Events is closed when the subscriber lags
+// behind by more than the client buffer or the hub is closed.
+type Subscription struct {
+	Events <-chan entity.Event
+	Replay []entity.Event
+	Reset  bool
+
+	hub    *Hub
+	events chan entity.Event
+}
+
+// NewHub will create a hub keeping up to replaySize events, clientBuffer bounds the events
+// waiting to be sent to a subscriber
+func NewHub(replaySize int, clientBuffer int) *Hub {
+	return &Hub{
+		replayed:     map[int64]bool{},
+		replaySize:   replaySize,
+		clientBuffer: clientBuffer,
+		subscribers:  map[*Subscription]bool{},
+	}
+}
+
+// Publish hands the event to every subscriber, an event already kept for replay was relayed
+// twice and is dropped. A subscriber whose buffer is full is disconnected rather than slowing
+// down the others, it resumes from its Last-Event-ID.
+func (h *Hub) Publish(ctx context.Context, event entity.Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed || h.replayed[event.ID] {
+		return nil
+	}
+
+	if h.replaySize > 0 {
+		if len(h.replay) == h.replaySize {
+			delete(h.replayed, h.replay[0].ID)
+			h.replay = append(h.replay[:0], h.replay[1:]...)
+		}
+		h.replay = append(h.replay, event)
+		h.replayed[event.ID] = true
+	}
+
+	for subscription := range h.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			h.drop(subscription)
+		}
+	}
+	return nil
+}
+
+// Subscribe opens a subscription from lastEventID, zero only gives the events to come. The events
+// are replayed in the order they were received, so a Last-Event-ID no longer kept resets the
+// subscription instead of guessing which ones came after it.
+func (h *Hub) Subscribe(lastEventID int64) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := make(chan entity.Event, h.clientBuffer)
+	subscription := &Subscription{Events: events, hub: h, events: events}
+	if h.closed {
+		close(events)
+		return subscription
+	}
+
+	if lastEventID != 0 {
+		subscription.Reset = true
+		for i, event := range h.replay {
+			if event.ID == lastEventID {
+				subscription.Reset = false
+				subscription.Replay = append([]entity.Event(nil), h.replay[i+1:]...)
+				break
+			}
+		}
+	}
+
+	h.subscribers[subscription] = true
+	return subscription
+}
+
+// Subscribers gives the number of open subscriptions
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.subscribers)
+}
+
+// Close ends every subscription so the streams return, the server waits for them on shutdown
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for subscription := range h.subscribers {
+		h.drop(subscription)
+	}
+}
+
+func (h *Hub) drop(subscription *Subscription) {
+	delete(h.subscribers, subscription)
+	close(subscription.events)
+}
+
+// Close ends the subscription, it is safe to call after the hub dropped it
+func (s *Subscription) Close() {
+	if s.hub == nil {
+		return
+	}
+
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	if s.hub.subscribers[s] {
+		s.hub.drop(s)
+	}
+}
@@ -0,0 +1,123 @@
+package stream_test
+
+import (
+	"context"
+	"testing"
+
+	"carApi/entity"
+	"carApi/infrastructure/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func publish(t *testing.T, hub *stream.Hub, ids ...int64) {
+	for _, id := range ids {
+		require.NoError(t, hub.Publish(context.TODO(), entity.Event{ID: id, Type: entity.EventCarDeleted, CarID: id}))
+	}
+}
+
+func eventIDs(events []entity.Event) (ids []int64) {
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return
+}
+
+func receive(subscription *stream.Subscription, n int) (ids []int64) {
+	for i := 0; i < n; i++ {
+		ids = append(ids, (<-subscription.Events).ID)
+	}
+	return
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	t.Run("live", func(t *testing.T) {
+		hub := stream.NewHub(10, 10)
+		publish(t, hub, 1)
+
+		subscription := hub.Subscribe(0)
+		defer subscription.Close()
+		assert.Empty(t, subscription.Replay, "without Last-Event-ID only the events to come are given")
+		assert.False(t, subscription.Reset)
+
+		publish(t, hub, 2, 3)
+		assert.Equal(t, []int64{2, 3}, receive(subscription, 2))
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		hub := stream.NewHub(10, 10)
+		publish(t, hub, 1, 2, 3)
+
+		subscription := hub.Subscribe(1)
+		defer subscription.Close()
+		assert.False(t, subscription.Reset)
+		assert.Equal(t, []int64{2, 3}, eventIDs(subscription.Replay))
+
+		publish(t, hub, 4)
+		assert.Equal(t, []int64{4}, receive(subscription, 1))
+	})
+
+	t.Run("resume-received-order", func(t *testing.T) {
+		hub := stream.NewHub(10, 10)
+		// two relays may interleave the events of different cars
+		publish(t, hub, 5, 3, 4)
+
+		subscription := hub.Subscribe(5)
+		defer subscription.Close()
+		assert.Equal(t, []int64{3, 4}, eventIDs(subscription.Replay))
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		hub := stream.NewHub(2, 10)
+		publish(t, hub, 1, 2, 3)
+
+		subscription := hub.Subscribe(1)
+		defer subscription.Close()
+		assert.True(t, subscription.Reset, "the event is no longer kept")
+		assert.Empty(t, subscription.Replay)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		hub := stream.NewHub(10, 10)
+		subscription := hub.Subscribe(0)
+		defer subscription.Close()
+
+		publish(t, hub, 1, 1, 2)
+		assert.Equal(t, []int64{1, 2}, receive(subscription, 2))
+		assert.Empty(t, subscription.Events)
+	})
+}
+
+func TestHub_SlowSubscriber(t *testing.T) {
+	hub := stream.NewHub(10, 2)
+	slow := hub.Subscribe(0)
+	fast := hub.Subscribe(0)
+	defer fast.Close()
+
+	publish(t, hub, 1, 2)
+	assert.Equal(t, []int64{1, 2}, receive(fast, 2))
+
+	publish(t, hub, 3)
+	assert.Equal(t, []int64{3}, receive(fast, 1))
+	assert.Equal(t, []int64{1, 2}, receive(slow, 2))
+	_, open := <-slow.Events
+	assert.False(t, open, "the subscriber lagging behind is disconnected")
+	assert.Equal(t, 1, hub.Subscribers())
+
+	slow.Close()
+}
+
+func TestHub_Close(t *testing.T) {
+	hub := stream.NewHub(10, 10)
+	subscription := hub.Subscribe(0)
+
+	hub.Close()
+	_, open := <-subscription.Events
+	assert.False(t, open)
+	subscription.Close()
+
+	late := hub.Subscribe(0)
+	_, open = <-late.Events
+	assert.False(t, open, "a closed hub ends the subscriptions at once")
+	assert.Zero(t, hub.Subscribers())
+}
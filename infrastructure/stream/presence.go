@@ -0,0 +1,99 @@
+package stream
+
+import (
+	"context"
+	"sync"
+)
+
+// Announcer tells every instance the viewers of a car changed
+type Announcer interface {
+	Announce(ctx context.Context, carID int64) error
+}
+
+// Presence hands the cars whose viewers changed to the sockets of this instance, the viewers
+// themselves are kept by the presence repository. It is an announcer: the presence usecase
+// announces to it directly when there is a single instance, a redis listener does otherwise.
+type Presence struct {
+	mu           sync.Mutex
+	clientBuffer int
+	watchers     map[*PresenceWatch]bool
+	closed       bool
+}
+
+// PresenceWatch is a stream of the cars whose viewers changed. Cars is closed when the watcher
+// lags behind by more than the client buffer or the presence is closed.
+type PresenceWatch struct {
+	Cars <-chan int64
+
+	presence *Presence
+	cars     chan int64
+}
+
+// NewPresence will create a presence, clientBuffer bounds the cars waiting to be handled by a watcher
+func NewPresence(clientBuffer int) *Presence {
+	return &Presence{
+		clientBuffer: clientBuffer,
+		watchers:     map[*PresenceWatch]bool{},
+	}
+}
+
+// Announce hands the car to every watcher, a watcher whose buffer is full is dropped
+func (p *Presence) Announce(ctx context.Context, carID int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for watch := range p.watchers {
+		select {
+		case watch.cars <- carID:
+		default:
+			p.drop(watch)
+		}
+	}
+	return nil
+}
+
+// Watch opens a watch of the cars announced from now on
+func (p *Presence) Watch() *PresenceWatch {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cars := make(chan int64, p.clientBuffer)
+	watch := &PresenceWatch{Cars: cars, presence: p, cars: cars}
+	if p.closed {
+		close(cars)
+		return watch
+	}
+
+	p.watchers[watch] = true
+	return watch
+}
+
+// Close ends every watch
+func (p *Presence) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for watch := range p.watchers {
+		p.drop(watch)
+	}
+}
+
+func (p *Presence) drop(watch *PresenceWatch) {
+	delete(p.watchers, watch)
+	close(watch.cars)
+}
+
+// Close ends the watch, it is safe to call after the presence dropped it
+func (w *PresenceWatch) Close() {
+	if w.presence == nil {
+		return
+	}
+
+	w.presence.mu.Lock()
+	defer w.presence.mu.Unlock()
+
+	if w.presence.watchers[w] {
+		w.presence.drop(w)
+	}
+}
@@ -0,0 +1,46 @@
+package stream_test
+
+import (
+	"context"
+	"testing"
+
+	"carApi/infrastructure/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresence_Announce(t *testing.T) {
+	presence := stream.NewPresence(10)
+	first := presence.Watch()
+	defer first.Close()
+	second := presence.Watch()
+	defer second.Close()
+
+	presence.Announce(context.TODO(), 3)
+
+	assert.Equal(t, int64(3), <-first.Cars)
+	assert.Equal(t, int64(3), <-second.Cars)
+}
+
+func TestPresence_SlowWatcher(t *testing.T) {
+	presence := stream.NewPresence(1)
+	slow := presence.Watch()
+	defer slow.Close()
+
+	presence.Announce(context.TODO(), 1)
+	presence.Announce(context.TODO(), 2)
+
+	assert.Equal(t, int64(1), <-slow.Cars)
+	_, ok := <-slow.Cars
+	assert.False(t, ok, "a watcher lagging behind is dropped")
+}
+
+func TestPresence_Close(t *testing.T) {
+	presence := stream.NewPresence(10)
+	watch := presence.Watch()
+	watch.Close()
+	watch.Close()
+
+	presence.Close()
+	_, ok := <-presence.Watch().Cars
+	assert.False(t, ok)
+}
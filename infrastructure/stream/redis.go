@@ -0,0 +1,135 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"carApi/entity"
+	"carApi/infrastructure/events"
+	"carApi/utils/logger"
+	"github.com/go-redis/redis"
+)
+
+type redisPublisher struct {
+	client  *redis.Client
+	channel string
+}
+
+// NewRedisPublisher will create an events publisher sending the events to the pub/sub channel
+// every instance listens to
+func NewRedisPublisher(client *redis.Client, channel string) events.Publisher {
+	return &redisPublisher{
+		client:  client,
+		channel: channel,
+	}
+}
+
+func (p *redisPublisher) Publish(ctx context.Context, event entity.Event) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.client.WithContext(ctx).Publish(p.channel, message).Err()
+}
+
+// RedisListener hands the events of the pub/sub channel to the hub of the instance. Pub/sub keeps
+// no history, the events published while the connection is being restored are not streamed.
+type RedisListener struct {
+	client  *redis.Client
+	channel string
+	hub     *Hub
+	logger  logger.Logger
+}
+
+// NewRedisListener will create a listener of the channel feeding hub
+func NewRedisListener(client *redis.Client, channel string, hub *Hub, logger logger.Logger) *RedisListener {
+	return &RedisListener{
+		client:  client,
+		channel: channel,
+		hub:     hub,
+		logger:  logger,
+	}
+}
+
+// Run listens until ctx is done, the subscription is restored by the client when the connection
+// drops
+func (l *RedisListener) Run(ctx context.Context) {
+	listen(ctx, l.client, l.channel, func(payload string) {
+		var event entity.Event
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			l.logger.Warnf("decoding event of channel %s: %v", l.channel, err)
+			return
+		}
+		l.hub.Publish(ctx, event)
+	})
+}
+
+type redisAnnouncer struct {
+	client  *redis.Client
+	channel string
+}
+
+// NewRedisAnnouncer will create an announcer sending the cars to the pub/sub channel every
+// instance listens to
+func NewRedisAnnouncer(client *redis.Client, channel string) Announcer {
+	return &redisAnnouncer{
+		client:  client,
+		channel: channel,
+	}
+}
+
+func (a *redisAnnouncer) Announce(ctx context.Context, carID int64) error {
+	return a.client.WithContext(ctx).Publish(a.channel, strconv.FormatInt(carID, 10)).Err()
+}
+
+// RedisPresenceListener hands the cars announced on the pub/sub channel to the presence of the
+// instance
+type RedisPresenceListener struct {
+	client   *redis.Client
+	channel  string
+	presence *Presence
+	logger   logger.Logger
+}
+
+// NewRedisPresenceListener will create a listener of the channel feeding presence
+func NewRedisPresenceListener(client *redis.Client, channel string, presence *Presence, logger logger.Logger) *RedisPresenceListener {
+	return &RedisPresenceListener{
+		client:   client,
+		channel:  channel,
+		presence: presence,
+		logger:   logger,
+	}
+}
+
+// Run listens until ctx is done
+func (l *RedisPresenceListener) Run(ctx context.Context) {
+	listen(ctx, l.client, l.channel, func(payload string) {
+		carID, err := strconv.ParseInt(payload, 10, 64)
+		if err != nil {
+			l.logger.Warnf("decoding car of channel %s: %v", l.channel, err)
+			return
+		}
+		l.presence.Announce(ctx, carID)
+	})
+}
+
+// listen hands the messages of the channel to handle until ctx is done
+func listen(ctx context.Context, client *redis.Client, channel string, handle func(payload string)) {
+	pubsub := client.Subscribe(channel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			handle(message.Payload)
+		}
+	}
+}
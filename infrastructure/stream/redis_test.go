@@ -0,0 +1,152 @@
+package stream_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/stream"
+	"carApi/mocks"
+	"github.com/alicebob/miniredis/server"
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pubsubServer answers SUBSCRIBE and PUBLISH, miniredis has no pub/sub. A subscribed client
+// is written to by its own SUBSCRIBE call, which pushes the messages until the server closes.
+type pubsubServer struct {
+	*server.Server
+
+	mu          sync.Mutex
+	subscribers map[string][]chan string
+}
+
+func newPubsubServer(t *testing.T) *pubsubServer {
+	srv, err := server.NewServer("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(srv.Close)
+
+	s := &pubsubServer{Server: srv, subscribers: map[string][]chan string{}}
+	t.Cleanup(s.closeSubscribers)
+	require.NoError(t, srv.Register("SUBSCRIBE", func(c *server.Peer, cmd string, args []string) {
+		channel := args[0]
+		messages := make(chan string, 16)
+		s.mu.Lock()
+		s.subscribers[channel] = append(s.subscribers[channel], messages)
+		s.mu.Unlock()
+
+		c.WriteLen(3)
+		c.WriteBulk("subscribe")
+		c.WriteBulk(channel)
+		c.WriteInt(1)
+		c.Flush()
+		for message := range messages {
+			c.WriteLen(3)
+			c.WriteBulk("message")
+			c.WriteBulk(channel)
+			c.WriteBulk(message)
+			c.Flush()
+		}
+	}))
+	require.NoError(t, srv.Register("PUBLISH", func(c *server.Peer, cmd string, args []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, messages := range s.subscribers[args[0]] {
+			messages <- args[1]
+		}
+		c.WriteInt(len(s.subscribers[args[0]]))
+	}))
+	return s
+}
+
+func (s *pubsubServer) subscribed(channel string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers[channel]) > 0
+}
+
+func (s *pubsubServer) closeSubscribers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, subscribers := range s.subscribers {
+		for _, messages := range subscribers {
+			close(messages)
+		}
+	}
+	s.subscribers = map[string][]chan string{}
+}
+
+func TestRedisListener(t *testing.T) {
+	srv := newPubsubServer(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr().String()})
+	defer client.Close()
+
+	hub := stream.NewHub(10, 10)
+	subscription := hub.Subscribe(0)
+	defer subscription.Close()
+
+	mockLogger := new(mocks.Logger)
+	listener := stream.NewRedisListener(client, "carapi:cars", hub, mockLogger)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		listener.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return srv.subscribed("carapi:cars") }, time.Second, 5*time.Millisecond)
+
+	event := entity.Event{ID: 7, Type: entity.EventCarDeleted, CarID: 3, Payload: json.RawMessage(`{"car_id":3}`), OccurredAt: time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)}
+	publisher := stream.NewRedisPublisher(client, "carapi:cars")
+	require.NoError(t, publisher.Publish(context.TODO(), event))
+
+	select {
+	case received := <-subscription.Events:
+		assert.Equal(t, event, received)
+	case <-time.After(time.Second):
+		t.Fatal("the event did not reach the hub")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestRedisPresenceListener(t *testing.T) {
+	srv := newPubsubServer(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr().String()})
+	defer client.Close()
+
+	presence := stream.NewPresence(10)
+	watch := presence.Watch()
+	defer watch.Close()
+
+	mockLogger := new(mocks.Logger)
+	mockLogger.On("Warnf", "decoding car of channel %s: %v", "carapi:presence", mock.Anything).Once()
+	listener := stream.NewRedisPresenceListener(client, "carapi:presence", presence, mockLogger)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		listener.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return srv.subscribed("carapi:presence") }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, client.Publish("carapi:presence", "not-a-car").Err())
+	announcer := stream.NewRedisAnnouncer(client, "carapi:presence")
+	require.NoError(t, announcer.Announce(context.TODO(), 3))
+
+	select {
+	case carID := <-watch.Cars:
+		assert.Equal(t, int64(3), carID)
+	case <-time.After(time.Second):
+		t.Fatal("the car did not reach the presence")
+	}
+
+	cancel()
+	<-done
+	mockLogger.AssertExpectations(t)
+}
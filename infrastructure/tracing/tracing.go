@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"carApi/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracerName is the instrumentation scope of every span created by the API
+	TracerName = "carApi"
+	// ServiceName identifies the API in the tracing backend
+	ServiceName = "carapi"
+
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// NewTracerProvider will create the tracer provider selected by TRACING_EXPORTER: none keeps
+// generating trace ids for the request ids without exporting, stdout prints the spans and
+// otlp sends them over HTTP to TRACING_ENDPOINT, e.g. a local collector on localhost:4318
+func NewTracerProvider(cfg *config.Config) (*sdktrace.TracerProvider, error) {
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRatio))),
+	}
+
+	switch cfg.Tracing.Exporter {
+	case "", ExporterNone:
+	case ExporterStdout:
+		exporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	case ExporterOTLP:
+		exporterOptions := []otlptracehttp.Option{otlptracehttp.WithInsecure()}
+		if cfg.Tracing.Endpoint != "" {
+			exporterOptions = append(exporterOptions, otlptracehttp.WithEndpoint(cfg.Tracing.Endpoint))
+		}
+		exporter, err := otlptracehttp.New(context.Background(), exporterOptions...)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Tracing.Exporter)
+	}
+
+	return sdktrace.NewTracerProvider(options...), nil
+}
+
+// NewStdoutTracerProvider will create a provider that writes every span to w as soon as it ends
+func NewStdoutTracerProvider(w io.Writer) (*sdktrace.TracerProvider, error) {
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, err
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(ServiceName))),
+		sdktrace.WithSyncer(exporter),
+	), nil
+}
+
+// Setup makes the provider and the W3C trace context propagator global
+func Setup(provider trace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// Tracer gives the API tracer of the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// End records err on the span before ending it, sql.ErrNoRows is an expected outcome and not an error
+func End(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+var (
+	sqlStringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlNumericLiteral = regexp.MustCompile(`([^$\w.])\d+(?:\.\d+)?\b`)
+)
+
+// SanitizeSQL replaces the literals of a statement by ? so values never end up in a span,
+// positional parameters such as $1 are kept
+func SanitizeSQL(query string) string {
+	query = sqlStringLiteral.ReplaceAllString(query, "?")
+	return sqlNumericLiteral.ReplaceAllString(query, "${1}?")
+}
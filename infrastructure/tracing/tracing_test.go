@@ -0,0 +1,39 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"carApi/infrastructure/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeSQL(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"SELECT id FROM cars WHERE id = $1", "SELECT id FROM cars WHERE id = $1"},
+		{"SELECT id FROM cars WHERE make = 'Toyota' AND year > 2019", "SELECT id FROM cars WHERE make = ? AND year > ?"},
+		{"UPDATE cars SET color = 'O''Brien red', price_amount = 12.5 WHERE id = $2", "UPDATE cars SET color = ?, price_amount = ? WHERE id = $2"},
+		{"SELECT category, COUNT(*) FROM cars GROUP BY category", "SELECT category, COUNT(*) FROM cars GROUP BY category"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tracing.SanitizeSQL(tt.query))
+	}
+}
+
+func TestNewStdoutTracerProvider(t *testing.T) {
+	var buf bytes.Buffer
+	provider, err := tracing.NewStdoutTracerProvider(&buf)
+	assert.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Tracer(tracing.TracerName).Start(context.Background(), "carUsecase.Fetch")
+	tracing.End(span, assert.AnError)
+
+	assert.Contains(t, buf.String(), `"Name":"carUsecase.Fetch"`)
+	assert.Contains(t, buf.String(), assert.AnError.Error())
+}
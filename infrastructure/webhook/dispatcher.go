@@ -0,0 +1,244 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"carApi/config"
+	"carApi/entity"
+	"carApi/infrastructure/metrics"
+	"carApi/infrastructure/tracing"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+	"carApi/utils/logger"
+	"carApi/utils/netguard"
+)
+
+// maxErrorLength bounds the last error kept on a delivery
+const maxErrorLength = 512
+
+// Dispatcher posts the due deliveries to their subscription. A 2xx answer succeeds the delivery,
+// anything else is retried with an exponential backoff until the attempts run out and the
+// delivery is dead.
+type Dispatcher struct {
+	webhookRepo  pgsql.WebhookRepository
+	deliveryRepo pgsql.WebhookDeliveryRepository
+	txManager    transaction.TxManager
+	client       *http.Client
+	metrics      *metrics.Metrics
+	logger       logger.Logger
+
+	maxAttempts    int
+	backoffInitial time.Duration
+	backoffMax     time.Duration
+	interval       time.Duration
+	batchSize      int
+}
+
+// NewDispatcher will create a dispatcher polling for due deliveries as set by cfg
+func NewDispatcher(webhookRepo pgsql.WebhookRepository, deliveryRepo pgsql.WebhookDeliveryRepository, txManager transaction.TxManager, metrics *metrics.Metrics, logger logger.Logger, cfg config.WebhooksConfig) *Dispatcher {
+	return &Dispatcher{
+		webhookRepo:    webhookRepo,
+		deliveryRepo:   deliveryRepo,
+		txManager:      txManager,
+		client:         newClient(time.Duration(cfg.Timeout) * time.Second),
+		metrics:        metrics,
+		logger:         logger,
+		maxAttempts:    cfg.MaxAttempts,
+		backoffInitial: time.Duration(cfg.BackoffInitial) * time.Second,
+		backoffMax:     time.Duration(cfg.BackoffMax) * time.Second,
+		interval:       time.Duration(cfg.PollInterval) * time.Millisecond,
+		batchSize:      cfg.BatchSize,
+	}
+}
+
+// newClient gives the client posting the deliveries. It only connects to public addresses,
+// whatever the host of the subscription resolves to by then, goes through no proxy and does not
+// follow redirects, a redirect answer fails the attempt like any other non 2xx one.
+func newClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   netguard.Control,
+	}).DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Run dispatches the due deliveries until ctx is done, a full batch is followed by the next one
+// without waiting for the interval
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		dispatched, err := d.DispatchDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			d.logger.Errorf("dispatching webhooks: %v", err)
+		}
+
+		if err == nil && dispatched == d.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue attempts one batch of due deliveries and gives how many were attempted. The batch
+// is claimed in a transaction by pushing its next attempt past the request timeout, so another
+// dispatcher skips it meanwhile and a crash only delays it.
+func (d *Dispatcher) DispatchDue(c context.Context) (dispatched int, err error) {
+	ctx, span := tracing.Tracer().Start(c, "webhook.Dispatcher")
+	defer func() { tracing.End(span, err) }()
+
+	var deliveries []entity.WebhookDelivery
+	err = d.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		due, err := d.deliveryRepo.FetchDue(ctx, now, d.batchSize)
+		if err != nil {
+			return err
+		}
+
+		deliveries = make([]entity.WebhookDelivery, 0, len(due))
+		for _, delivery := range due {
+			delivery.NextAttemptAt = now.Add(2 * d.client.Timeout)
+			if err := d.deliveryRepo.Update(ctx, &delivery); err != nil {
+				return err
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *entity.WebhookDelivery) {
+			defer wg.Done()
+			d.dispatch(ctx, delivery)
+		}(&deliveries[i])
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, delivery *entity.WebhookDelivery) {
+	subscription, err := d.webhookRepo.GetByID(ctx, delivery.SubscriptionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// deleted meanwhile along with its deliveries
+		return
+	}
+	if err != nil {
+		d.logger.Errorf("loading webhook %d of delivery %d: %v", delivery.SubscriptionID, delivery.ID, err)
+		return
+	}
+
+	statusCode, err := d.post(ctx, subscription, *delivery)
+	if ctx.Err() != nil {
+		// shutting down, the attempt is not counted and the claim runs out
+		return
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+	delivery.UpdatedAt = now
+
+	switch {
+	case err == nil:
+		delivery.Status = entity.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		d.metrics.ObserveWebhook(metrics.WebhookResultSucceeded)
+	case delivery.Attempts >= d.maxAttempts:
+		delivery.Status = entity.WebhookDeliveryDead
+		delivery.LastError = truncate(err.Error(), maxErrorLength)
+		d.metrics.ObserveWebhook(metrics.WebhookResultDead)
+		d.logger.Warnf("webhook delivery %d to %s is dead after %d attempts: %v", delivery.ID, subscription.URL, delivery.Attempts, err)
+	default:
+		delivery.NextAttemptAt = now.Add(d.Backoff(delivery.Attempts))
+		delivery.LastError = truncate(err.Error(), maxErrorLength)
+		d.metrics.ObserveWebhook(metrics.WebhookResultRetry)
+	}
+
+	if err := d.deliveryRepo.Update(ctx, delivery); err != nil {
+		d.logger.Errorf("updating webhook delivery %d: %v", delivery.ID, err)
+	}
+}
+
+// post sends the delivery and gives the status code of the answer, zero when there is none
+func (d *Dispatcher) post(ctx context.Context, subscription entity.WebhookSubscription, delivery entity.WebhookDelivery) (int, error) {
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "carApi-webhook")
+	req.Header.Set(HeaderEventID, strconv.FormatInt(delivery.EventID, 10))
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, delivery.Body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drained so the connection is reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Backoff gives the wait after the given number of failed attempts: the initial backoff doubled
+// after every attempt past the first, up to the max backoff
+func (d *Dispatcher) Backoff(attempts int) time.Duration {
+	backoff := d.backoffInitial
+	for i := 1; i < attempts && backoff < d.backoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > d.backoffMax {
+		backoff = d.backoffMax
+	}
+	return backoff
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
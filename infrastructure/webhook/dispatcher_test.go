@@ -0,0 +1,309 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"carApi/config"
+	"carApi/entity"
+	"carApi/infrastructure/metrics"
+	"carApi/infrastructure/webhook"
+	"carApi/mocks"
+	"carApi/repository/memory"
+	"carApi/repository/pgsql"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver is a partner endpoint answering with status and keeping the requests it got
+type receiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(status int) *receiver {
+	r := &receiver{status: status}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, req)
+		r.bodies = append(r.bodies, body)
+		w.WriteHeader(r.status)
+	}))
+	return r
+}
+
+func (r *receiver) received() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+func webhooksConfig() config.WebhooksConfig {
+	cfg := config.Default().Webhooks
+	cfg.Timeout = 1
+	cfg.MaxAttempts = 2
+	return cfg
+}
+
+type dispatcherFixture struct {
+	webhookRepo  pgsql.WebhookRepository
+	deliveryRepo pgsql.WebhookDeliveryRepository
+	metrics      *metrics.Metrics
+	logger       *mocks.Logger
+	dispatcher   *webhook.Dispatcher
+}
+
+// newDispatcherFixture gives a dispatcher over an in-memory store, loopback lets it reach the
+// receivers the tests serve on the loopback interface, which it refuses otherwise
+func newDispatcherFixture(cfg config.WebhooksConfig, loopback bool) dispatcherFixture {
+	store := memory.NewStore()
+	f := dispatcherFixture{
+		webhookRepo:  memory.NewMemoryWebhookRepository(store),
+		deliveryRepo: memory.NewMemoryWebhookDeliveryRepository(store),
+		metrics:      metrics.NewMetrics(),
+		logger:       new(mocks.Logger),
+	}
+	f.dispatcher = webhook.NewDispatcher(f.webhookRepo, f.deliveryRepo, memory.NewMemoryTxManager(store), f.metrics, f.logger, cfg)
+	if loopback {
+		f.dispatcher.AllowLoopback()
+	}
+	return f
+}
+
+// deliver stores a delivery of the event to the subscription through the fanout
+func (f dispatcherFixture) deliver(t *testing.T, event entity.Event) {
+	require.NoError(t, webhook.NewFanout(f.webhookRepo, f.deliveryRepo).Publish(context.TODO(), event))
+}
+
+func (f dispatcherFixture) delivery(t *testing.T, subscriptionID int64) entity.WebhookDelivery {
+	deliveries, err := f.deliveryRepo.FetchBySubscriptionID(context.TODO(), subscriptionID, "", 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	return deliveries[0]
+}
+
+// makeDue brings the next attempt of the delivery forward instead of waiting for the backoff
+func (f dispatcherFixture) makeDue(t *testing.T, delivery entity.WebhookDelivery) {
+	delivery.NextAttemptAt = time.Now().Add(-time.Second)
+	require.NoError(t, f.deliveryRepo.Update(context.TODO(), &delivery))
+}
+
+var deletedEvent = entity.Event{ID: 7, Type: entity.EventCarDeleted, CarID: 3, Payload: json.RawMessage(`{"car_id":3}`), OccurredAt: time.Now()}
+
+func TestSign(t *testing.T) {
+	signature := webhook.Sign("0123456789abcdef", 1700000000, []byte(`{"id":1}`))
+	assert.Equal(t, "sha256=4bcaced68dfea90a68df035b89cb7fb26692d899d32a1ccb1b0616cf48e4d1ed", signature)
+}
+
+func TestDispatcher_DispatchDue(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		partner := newReceiver(http.StatusNoContent)
+		defer partner.Close()
+
+		f := newDispatcherFixture(webhooksConfig(), true)
+		subscription := createSubscription(t, f.webhookRepo, partner.URL+"/hooks", entity.EventCarDeleted)
+		f.deliver(t, deletedEvent)
+
+		dispatched, err := f.dispatcher.DispatchDue(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, 1, dispatched)
+
+		require.Equal(t, 1, partner.received())
+		req, body := partner.requests[0], partner.bodies[0]
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/hooks", req.URL.Path)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, "7", req.Header.Get(webhook.HeaderEventID))
+		assert.Equal(t, "car.deleted", req.Header.Get(webhook.HeaderEvent))
+		timestamp, err := strconv.ParseInt(req.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, webhook.Sign(subscription.Secret, timestamp, body), req.Header.Get(webhook.HeaderSignature))
+		assert.JSONEq(t, `{"car_id":3}`, string(mustData(t, body)))
+
+		delivery := f.delivery(t, subscription.ID)
+		assert.Equal(t, entity.WebhookDeliverySucceeded, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, http.StatusNoContent, delivery.LastStatusCode)
+		assert.NotNil(t, delivery.DeliveredAt)
+		assert.Equal(t, 1.0, testutil.ToFloat64(f.metrics.Webhooks.WithLabelValues(metrics.WebhookResultSucceeded)))
+
+		dispatched, err = f.dispatcher.DispatchDue(context.TODO())
+		require.NoError(t, err)
+		assert.Zero(t, dispatched, "a succeeded delivery is not sent again")
+	})
+
+	t.Run("retry-then-dead", func(t *testing.T) {
+		partner := newReceiver(http.StatusServiceUnavailable)
+		defer partner.Close()
+
+		f := newDispatcherFixture(webhooksConfig(), true)
+		subscription := createSubscription(t, f.webhookRepo, partner.URL, entity.EventCarDeleted)
+		f.deliver(t, deletedEvent)
+
+		before := time.Now()
+		_, err := f.dispatcher.DispatchDue(context.TODO())
+		require.NoError(t, err)
+
+		delivery := f.delivery(t, subscription.ID)
+		assert.Equal(t, entity.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, http.StatusServiceUnavailable, delivery.LastStatusCode)
+		assert.Equal(t, "unexpected status 503", delivery.LastError)
+		assert.WithinDuration(t, before.Add(30*time.Second), delivery.NextAttemptAt, 5*time.Second)
+		assert.Equal(t, 1.0, testutil.ToFloat64(f.metrics.Webhooks.WithLabelValues(metrics.WebhookResultRetry)))
+
+		dispatched, err := f.dispatcher.DispatchDue(context.TODO())
+		require.NoError(t, err)
+		assert.Zero(t, dispatched, "the retry waits for the backoff")
+
+		f.makeDue(t, delivery)
+		f.logger.On("Warnf", mock.Anything, delivery.ID, partner.URL, 2, mock.Anything).Once()
+
+		_, err = f.dispatcher.DispatchDue(context.TODO())
+		require.NoError(t, err)
+
+		delivery = f.delivery(t, subscription.ID)
+		assert.Equal(t, entity.WebhookDeliveryDead, delivery.Status)
+		assert.Equal(t, 2, delivery.Attempts)
+		assert.Nil(t, delivery.DeliveredAt)
+		assert.Equal(t, 2, partner.received())
+		assert.Equal(t, 1.0, testutil.ToFloat64(f.metrics.Webhooks.WithLabelValues(metrics.WebhookResultDead)))
+		f.logger.AssertExpectations(t)
+
+		f.makeDue(t, delivery)
+		dispatched, err = f.dispatcher.DispatchDue(context.TODO())
+		require.NoError(t, err)
+		assert.Zero(t, dispatched, "a dead delivery waits for a redelivery")
+	})
+
+	t.Run("error-unreachable", func(t *testing.T) {
+		partner := newReceiver(http.StatusOK)
+		partner.Close()
+
+		f := newDispatcherFixture(webhooksConfig(), true)
+		subscription := createSubscription(t, f.webhookRepo, partner.URL, entity.EventCarDeleted)
+		f.deliver(t, deletedEvent)
+
+		_, err := f.dispatcher.DispatchDue(context.TODO())
+		require.NoError(t, err)
+
+		delivery := f.delivery(t, subscription.ID)
+		assert.Equal(t, entity.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Zero(t, delivery.LastStatusCode)
+		assert.NotEmpty(t, delivery.LastError)
+	})
+
+	t.Run("error-private-address", func(t *testing.T) {
+		partner := newReceiver(http.StatusOK)
+		defer partner.Close()
+
+		f := newDispatcherFixture(webhooksConfig(), false)
+		subscription := createSubscription(t, f.webhookRepo, partner.URL, entity.EventCarDeleted)
+		f.deliver(t, deletedEvent)
+
+		_, err := f.dispatcher.DispatchDue(context.TODO())
+		require.NoError(t, err)
+
+		delivery := f.delivery(t, subscription.ID)
+		assert.Equal(t, entity.WebhookDeliveryPending, delivery.Status)
+		assert.Contains(t, delivery.LastError, "address is not public")
+		assert.Zero(t, partner.received())
+	})
+
+	t.Run("error-redirect", func(t *testing.T) {
+		internal := newReceiver(http.StatusOK)
+		defer internal.Close()
+		partner := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusTemporaryRedirect))
+		defer partner.Close()
+
+		f := newDispatcherFixture(webhooksConfig(), true)
+		subscription := createSubscription(t, f.webhookRepo, partner.URL, entity.EventCarDeleted)
+		f.deliver(t, deletedEvent)
+
+		_, err := f.dispatcher.DispatchDue(context.TODO())
+		require.NoError(t, err)
+
+		delivery := f.delivery(t, subscription.ID)
+		assert.Equal(t, entity.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, http.StatusTemporaryRedirect, delivery.LastStatusCode)
+		assert.Zero(t, internal.received(), "redirects are not followed")
+	})
+
+	t.Run("batch-size", func(t *testing.T) {
+		partner := newReceiver(http.StatusOK)
+		defer partner.Close()
+
+		cfg := webhooksConfig()
+		cfg.BatchSize = 2
+		f := newDispatcherFixture(cfg, true)
+		createSubscription(t, f.webhookRepo, partner.URL, entity.EventCarDeleted)
+		for id := int64(1); id <= 3; id++ {
+			f.deliver(t, entity.Event{ID: id, Type: entity.EventCarDeleted, CarID: id, Payload: json.RawMessage(`{}`)})
+		}
+
+		dispatched, err := f.dispatcher.DispatchDue(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, 2, dispatched)
+
+		dispatched, err = f.dispatcher.DispatchDue(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, 1, dispatched)
+		assert.Equal(t, 3, partner.received())
+	})
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	f := newDispatcherFixture(webhooksConfig(), true)
+
+	assert.Equal(t, 30*time.Second, f.dispatcher.Backoff(1))
+	assert.Equal(t, 60*time.Second, f.dispatcher.Backoff(2))
+	assert.Equal(t, 240*time.Second, f.dispatcher.Backoff(4))
+	assert.Equal(t, time.Hour, f.dispatcher.Backoff(8))
+	assert.Equal(t, time.Hour, f.dispatcher.Backoff(100))
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	partner := newReceiver(http.StatusOK)
+	defer partner.Close()
+
+	cfg := webhooksConfig()
+	cfg.PollInterval = 10
+	f := newDispatcherFixture(cfg, true)
+	createSubscription(t, f.webhookRepo, partner.URL, entity.EventCarDeleted)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		f.dispatcher.Run(ctx)
+		close(done)
+	}()
+
+	f.deliver(t, deletedEvent)
+	assert.Eventually(t, func() bool { return partner.received() == 1 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func mustData(t *testing.T, body []byte) json.RawMessage {
+	var decoded webhook.Body
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	return decoded.Data
+}
@@ -0,0 +1,14 @@
+package webhook
+
+import (
+	"net"
+	"net/http"
+)
+
+// AllowLoopback lets the dispatcher reach the receivers the tests serve on the loopback
+// interface, the rest of its client is kept
+func (d *Dispatcher) AllowLoopback() {
+	transport := d.client.Transport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: d.client.Timeout}).DialContext
+	d.client.Transport = transport
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+)
+
+// Body is the JSON document posted to the subscriptions
+type Body struct {
+	ID         int64            `json:"id"`
+	Type       entity.EventType `json:"type"`
+	CarID      int64            `json:"car_id"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Data       json.RawMessage  `json:"data"`
+}
+
+// Fanout is the events publisher turning an event into a pending delivery for every subscription
+// to its type. It runs in the relay transaction so the deliveries are stored along with the event
+// being marked as published, an event relayed twice is delivered once.
+type Fanout struct {
+	webhookRepo  pgsql.WebhookRepository
+	deliveryRepo pgsql.WebhookDeliveryRepository
+}
+
+// NewFanout will create a publisher storing the deliveries of the events
+func NewFanout(webhookRepo pgsql.WebhookRepository, deliveryRepo pgsql.WebhookDeliveryRepository) *Fanout {
+	return &Fanout{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+	}
+}
+
+func (f *Fanout) Publish(ctx context.Context, event entity.Event) error {
+	subscriptions, err := f.webhookRepo.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	now := time.Now()
+	for _, subscription := range subscriptions {
+		if !subscription.Subscribes(event.Type) {
+			continue
+		}
+
+		if body == nil {
+			body, err = json.Marshal(Body{
+				ID:         event.ID,
+				Type:       event.Type,
+				CarID:      event.CarID,
+				OccurredAt: event.OccurredAt,
+				Data:       event.Payload,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		delivery := entity.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Body:           body,
+			Status:         entity.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if err := f.deliveryRepo.Create(ctx, &delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/webhook"
+	"carApi/repository/memory"
+	"carApi/repository/pgsql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createSubscription(t *testing.T, webhookRepo pgsql.WebhookRepository, url string, eventTypes ...entity.EventType) entity.WebhookSubscription {
+	subscription := entity.WebhookSubscription{
+		URL:        url,
+		EventTypes: eventTypes,
+		Secret:     "0123456789abcdef",
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	require.NoError(t, webhookRepo.Create(context.TODO(), &subscription))
+	return subscription
+}
+
+func TestFanout_Publish(t *testing.T) {
+	store := memory.NewStore()
+	webhookRepo := memory.NewMemoryWebhookRepository(store)
+	deliveryRepo := memory.NewMemoryWebhookDeliveryRepository(store)
+	deleted := createSubscription(t, webhookRepo, "https://a.example.com/hooks", entity.EventCarDeleted)
+	created := createSubscription(t, webhookRepo, "https://b.example.com/hooks", entity.EventCarCreated)
+
+	occurredAt := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	event := entity.Event{ID: 7, Type: entity.EventCarDeleted, CarID: 3, Payload: json.RawMessage(`{"car_id":3}`), OccurredAt: occurredAt}
+
+	fanout := webhook.NewFanout(webhookRepo, deliveryRepo)
+	require.NoError(t, fanout.Publish(context.TODO(), event))
+	require.NoError(t, fanout.Publish(context.TODO(), event), "an event relayed twice is delivered once")
+
+	deliveries, err := deliveryRepo.FetchBySubscriptionID(context.TODO(), deleted.ID, "", 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, int64(7), deliveries[0].EventID)
+	assert.Equal(t, entity.WebhookDeliveryPending, deliveries[0].Status)
+	assert.JSONEq(t, `{"id":7,"type":"car.deleted","car_id":3,"occurred_at":"2026-10-18T09:30:00Z","data":{"car_id":3}}`, string(deliveries[0].Body))
+
+	deliveries, err = deliveryRepo.FetchBySubscriptionID(context.TODO(), created.ID, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries, "the subscription to other types gets nothing")
+}
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS car_images(
+    id SERIAL NOT NULL PRIMARY KEY,
+    car_id INTEGER NOT NULL REFERENCES cars(id) ON DELETE CASCADE,
+    storage_key VARCHAR NOT NULL,
+    content_type VARCHAR NOT NULL,
+    size BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS car_images_car_id_position_idx ON car_images(car_id, position);
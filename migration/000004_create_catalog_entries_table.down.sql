@@ -0,0 +1 @@
+DROP TABLE IF EXISTS catalog_entries;
\ No newline at end of file
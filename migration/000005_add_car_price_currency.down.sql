@@ -0,0 +1,5 @@
+-- amounts go back to whole units assuming two minor unit digits, the currency is lost
+ALTER TABLE cars DROP COLUMN price_currency;
+UPDATE cars SET price_amount = price_amount / 100;
+ALTER TABLE cars ALTER COLUMN price_amount TYPE INTEGER;
+ALTER TABLE cars RENAME COLUMN price_amount TO price;
@@ -0,0 +1,5 @@
+-- prices were whole units without a currency, they are moved to minor units of USD
+ALTER TABLE cars RENAME COLUMN price TO price_amount;
+ALTER TABLE cars ALTER COLUMN price_amount TYPE BIGINT;
+UPDATE cars SET price_amount = price_amount * 100;
+ALTER TABLE cars ADD COLUMN price_currency CHAR(3) NOT NULL DEFAULT 'USD';
@@ -0,0 +1 @@
+COMMENT ON COLUMN cars.mileage IS NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS outbox(
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    event_type VARCHAR NOT NULL,
+    -- no foreign key, the events of a deleted car are still published
+    car_id INTEGER NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox(id) WHERE published_at IS NULL;
@@ -0,0 +1,7 @@
+-- a car has at most one reservation, an expired one is replaced by the next rep reserving the car
+CREATE TABLE IF NOT EXISTS car_reservations(
+    car_id INTEGER NOT NULL PRIMARY KEY REFERENCES cars(id) ON DELETE CASCADE,
+    rep VARCHAR(64) NOT NULL,
+    reserved_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
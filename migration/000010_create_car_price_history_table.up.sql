@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS car_price_history(
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    car_id INTEGER NOT NULL REFERENCES cars(id) ON DELETE CASCADE,
+    price_amount BIGINT NOT NULL,
+    price_currency CHAR(3) NOT NULL,
+    changed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS car_price_history_car_id_changed_at_idx ON car_price_history(car_id, changed_at);
+
+-- the history of the existing cars starts from their current price
+INSERT INTO car_price_history (car_id, price_amount, price_currency, changed_at)
+SELECT id, price_amount, price_currency, COALESCE(updated_at, created_at, NOW()) FROM cars;
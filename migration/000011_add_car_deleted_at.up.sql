@@ -0,0 +1,4 @@
+-- deleting a car only marks it, purge-deleted removes the rows once the retention has passed
+ALTER TABLE cars ADD COLUMN deleted_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS cars_deleted_at_idx ON cars(deleted_at) WHERE deleted_at IS NOT NULL;
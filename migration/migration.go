@@ -0,0 +1,8 @@
+package migration
+
+import "embed"
+
+// Files are the SQL migrations embedded in the binary, named <version>_<title>.<up|down>.sql
+//
+//go:embed *.sql
+var Files embed.FS
@@ -0,0 +1,30 @@
+package migration_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"carApi/migration"
+	sqliteMigration "carApi/migration/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiles(t *testing.T) {
+	for name, files := range map[string]fs.FS{"postgres": migration.Files, "sqlite": sqliteMigration.Files} {
+		t.Run(name, func(t *testing.T) {
+			names, err := fs.Glob(files, "*.up.sql")
+			assert.NoError(t, err)
+			assert.NotEmpty(t, names)
+
+			for _, up := range names {
+				down := strings.TrimSuffix(up, ".up.sql") + ".down.sql"
+
+				content, err := fs.ReadFile(files, down)
+				if assert.NoError(t, err, "%s has no down migration", up) {
+					assert.NotEmpty(t, strings.TrimSpace(string(content)), "%s is empty", down)
+				}
+			}
+		})
+	}
+}
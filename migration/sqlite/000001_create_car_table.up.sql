@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS cars(
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    make TEXT NOT NULL,
+    model TEXT NOT NULL,
+    package TEXT NOT NULL,
+    color TEXT NOT NULL,
+    identification TEXT NOT NULL,
+    year INTEGER NOT NULL,
+    category TEXT NOT NULL,
+    -- kilometers
+    mileage INTEGER NOT NULL,
+    -- minor units of price_currency
+    price_amount INTEGER NOT NULL,
+    price_currency TEXT NOT NULL DEFAULT 'USD',
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
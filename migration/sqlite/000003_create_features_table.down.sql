@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS car_features;
+DROP TABLE IF EXISTS features;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS features(
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS car_features(
+    car_id INTEGER NOT NULL REFERENCES cars(id) ON DELETE CASCADE,
+    feature_id INTEGER NOT NULL REFERENCES features(id) ON DELETE CASCADE,
+    PRIMARY KEY (car_id, feature_id)
+);
+
+CREATE INDEX IF NOT EXISTS car_features_feature_id_idx ON car_features(feature_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS catalog_entries(
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    parent_id INTEGER REFERENCES catalog_entries(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS catalog_entries_kind_parent_name_idx ON catalog_entries(kind, COALESCE(parent_id, 0), LOWER(name));
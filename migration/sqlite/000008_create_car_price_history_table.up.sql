@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS car_price_history(
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    car_id INTEGER NOT NULL REFERENCES cars(id) ON DELETE CASCADE,
+    -- minor units of price_currency
+    price_amount INTEGER NOT NULL,
+    price_currency TEXT NOT NULL,
+    changed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS car_price_history_car_id_changed_at_idx ON car_price_history(car_id, changed_at);
+
+-- the history of the existing cars starts from their current price
+INSERT INTO car_price_history (car_id, price_amount, price_currency, changed_at)
+SELECT id, price_amount, price_currency, COALESCE(updated_at, created_at, CURRENT_TIMESTAMP) FROM cars;
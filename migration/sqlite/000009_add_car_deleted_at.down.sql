@@ -0,0 +1,5 @@
+-- the cars still waiting for their purge are removed for good
+DELETE FROM cars WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS cars_deleted_at_idx;
+ALTER TABLE cars DROP COLUMN deleted_at;
@@ -0,0 +1,9 @@
+package sqlite
+
+import "embed"
+
+// Files are the SQLite migrations embedded in the binary, they follow the Postgres migrations
+// but start from the current schema since no SQLite database predates them
+//
+//go:embed *.sql
+var Files embed.FS
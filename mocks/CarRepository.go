@@ -5,8 +5,11 @@ package mocks
 import (
 	context "context"
 
-	mock "github.com/stretchr/testify/mock"
 	entity "carApi/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // CarRepository is an autogenerated mock type for the CarRepository type
@@ -14,6 +17,50 @@ type CarRepository struct {
 	mock.Mock
 }
 
+// Count provides a mock function with given fields: ctx, filter
+func (_m *CarRepository) Count(ctx context.Context, filter entity.CarFilter) (int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CarFilter) int64); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, entity.CarFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountByCategory provides a mock function with given fields: ctx
+func (_m *CarRepository) CountByCategory(ctx context.Context) (map[string]int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 map[string]int64
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]int64); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, car
 func (_m *CarRepository) Create(ctx context.Context, car *entity.Car) error {
 	ret := _m.Called(ctx, car)
@@ -42,13 +89,13 @@ func (_m *CarRepository) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx
-func (_m *CarRepository) Fetch(ctx context.Context) ([]entity.Car, error) {
-	ret := _m.Called(ctx)
+// Fetch provides a mock function with given fields: ctx, filter, page
+func (_m *CarRepository) Fetch(ctx context.Context, filter entity.CarFilter, page entity.Page) ([]entity.Car, error) {
+	ret := _m.Called(ctx, filter, page)
 
 	var r0 []entity.Car
-	if rf, ok := ret.Get(0).(func(context.Context) []entity.Car); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CarFilter, entity.Page) []entity.Car); ok {
+		r0 = rf(ctx, filter, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Car)
@@ -56,8 +103,8 @@ func (_m *CarRepository) Fetch(ctx context.Context) ([]entity.Car, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, entity.CarFilter, entity.Page) error); ok {
+		r1 = rf(ctx, filter, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -86,6 +133,27 @@ func (_m *CarRepository) GetByID(ctx context.Context, id int64) (entity.Car, err
 	return r0, r1
 }
 
+// PurgeDeleted provides a mock function with given fields: ctx, before
+func (_m *CarRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, car
 func (_m *CarRepository) Update(ctx context.Context, car *entity.Car) error {
 	ret := _m.Called(ctx, car)
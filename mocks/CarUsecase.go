@@ -5,10 +5,13 @@ package mocks
 import (
 	context "context"
 
-	mock "github.com/stretchr/testify/mock"
 	entity "carApi/entity"
 
+	mock "github.com/stretchr/testify/mock"
+
 	request "carApi/transport/request"
+
+	time "time"
 )
 
 // CarUsecase is an autogenerated mock type for the CarUsecase type
@@ -44,19 +47,70 @@ func (_m *CarUsecase) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx
-func (_m *CarUsecase) Fetch(ctx context.Context) ([]entity.Car, error) {
-	ret := _m.Called(ctx)
+// Fetch provides a mock function with given fields: ctx, filter, display
+func (_m *CarUsecase) Fetch(ctx context.Context, filter entity.CarFilter, display entity.CarDisplay) ([]entity.Car, error) {
+	ret := _m.Called(ctx, filter, display)
 
 	var r0 []entity.Car
-	if rf, ok := ret.Get(0).(func(context.Context) []entity.Car); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CarFilter, entity.CarDisplay) []entity.Car); ok {
+		r0 = rf(ctx, filter, display)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Car)
 		}
 	}
 
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, entity.CarFilter, entity.CarDisplay) error); ok {
+		r1 = rf(ctx, filter, display)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchPage provides a mock function with given fields: ctx, filter, page, display
+func (_m *CarUsecase) FetchPage(ctx context.Context, filter entity.CarFilter, page entity.Page, display entity.CarDisplay) ([]entity.Car, int64, error) {
+	ret := _m.Called(ctx, filter, page, display)
+
+	var r0 []entity.Car
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CarFilter, entity.Page, entity.CarDisplay) []entity.Car); ok {
+		r0 = rf(ctx, filter, page, display)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Car)
+		}
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, entity.CarFilter, entity.Page, entity.CarDisplay) int64); ok {
+		r1 = rf(ctx, filter, page, display)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, entity.CarFilter, entity.Page, entity.CarDisplay) error); ok {
+		r2 = rf(ctx, filter, page, display)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FlushCache provides a mock function with given fields: ctx
+func (_m *CarUsecase) FlushCache(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
 		r1 = rf(ctx)
@@ -67,20 +121,62 @@ func (_m *CarUsecase) Fetch(ctx context.Context) ([]entity.Car, error) {
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: ctx, id
-func (_m *CarUsecase) GetByID(ctx context.Context, id int64) (entity.Car, error) {
-	ret := _m.Called(ctx, id)
+// GetByID provides a mock function with given fields: ctx, id, display
+func (_m *CarUsecase) GetByID(ctx context.Context, id int64, display entity.CarDisplay) (entity.Car, error) {
+	ret := _m.Called(ctx, id, display)
 
 	var r0 entity.Car
-	if rf, ok := ret.Get(0).(func(context.Context, int64) entity.Car); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, entity.CarDisplay) entity.Car); ok {
+		r0 = rf(ctx, id, display)
 	} else {
 		r0 = ret.Get(0).(entity.Car)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, entity.CarDisplay) error); ok {
+		r1 = rf(ctx, id, display)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeleted provides a mock function with given fields: ctx, before
+func (_m *CarUsecase) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RebuildCache provides a mock function with given fields: ctx
+func (_m *CarUsecase) RebuildCache(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2,13 +2,31 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	utils "carApi/utils"
+)
 
 // HttpErr is an autogenerated mock type for the HttpErr type
 type HttpErr struct {
 	mock.Mock
 }
 
+// Code provides a mock function with given fields:
+func (_m *HttpErr) Code() utils.ErrorCode {
+	ret := _m.Called()
+
+	var r0 utils.ErrorCode
+	if rf, ok := ret.Get(0).(func() utils.ErrorCode); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(utils.ErrorCode)
+	}
+
+	return r0
+}
+
 // Details provides a mock function with given fields:
 func (_m *HttpErr) Details() interface{} {
 	ret := _m.Called()
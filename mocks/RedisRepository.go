@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 
 	time "time"
@@ -13,20 +15,41 @@ type RedisRepository struct {
 	mock.Mock
 }
 
-// Get provides a mock function with given fields: key
-func (_m *RedisRepository) Get(key string) (string, error) {
-	ret := _m.Called(key)
+// Get provides a mock function with given fields: ctx, key
+func (_m *RedisRepository) Get(ctx context.Context, key string) (string, error) {
+	ret := _m.Called(ctx, key)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(key)
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, key)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(key)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Incr provides a mock function with given fields: ctx, key
+func (_m *RedisRepository) Incr(ctx context.Context, key string) (int64, error) {
+	ret := _m.Called(ctx, key)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -34,13 +57,13 @@ func (_m *RedisRepository) Get(key string) (string, error) {
 	return r0, r1
 }
 
-// Set provides a mock function with given fields: key, value, exp
-func (_m *RedisRepository) Set(key string, value interface{}, exp time.Duration) error {
-	ret := _m.Called(key, value, exp)
+// Set provides a mock function with given fields: ctx, key, value, exp
+func (_m *RedisRepository) Set(ctx context.Context, key string, value interface{}, exp time.Duration) error {
+	ret := _m.Called(ctx, key, value, exp)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, interface{}, time.Duration) error); ok {
-		r0 = rf(key, value, exp)
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}, time.Duration) error); ok {
+		r0 = rf(ctx, key, value, exp)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,1223 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: car/v1/car.proto
+
+package carv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Money is an amount in the minor units of its ISO 4217 currency, e.g. cents for USD
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_car_v1_car_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Money) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// Distance is a mileage expressed in km or mi
+type Distance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         int64                  `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Unit          string                 `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Distance) Reset() {
+	*x = Distance{}
+	mi := &file_car_v1_car_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Distance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Distance) ProtoMessage() {}
+
+func (x *Distance) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Distance.ProtoReflect.Descriptor instead.
+func (*Distance) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Distance) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Distance) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type CarImage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url   string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// thumbnails are keyed by size name
+	Thumbnails  map[string]string `protobuf:"bytes,3,rep,name=thumbnails,proto3" json:"thumbnails,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ContentType string            `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// size is in bytes
+	Size          int64                  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	Width         int32                  `protobuf:"varint,6,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32                  `protobuf:"varint,7,opt,name=height,proto3" json:"height,omitempty"`
+	Position      int32                  `protobuf:"varint,8,opt,name=position,proto3" json:"position,omitempty"`
+	IsPrimary     bool                   `protobuf:"varint,9,opt,name=is_primary,json=isPrimary,proto3" json:"is_primary,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CarImage) Reset() {
+	*x = CarImage{}
+	mi := &file_car_v1_car_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarImage) ProtoMessage() {}
+
+func (x *CarImage) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarImage.ProtoReflect.Descriptor instead.
+func (*CarImage) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CarImage) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CarImage) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CarImage) GetThumbnails() map[string]string {
+	if x != nil {
+		return x.Thumbnails
+	}
+	return nil
+}
+
+func (x *CarImage) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *CarImage) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *CarImage) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *CarImage) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *CarImage) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *CarImage) GetIsPrimary() bool {
+	if x != nil {
+		return x.IsPrimary
+	}
+	return false
+}
+
+func (x *CarImage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *CarImage) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Feature struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Feature) Reset() {
+	*x = Feature{}
+	mi := &file_car_v1_car_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Feature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Feature) ProtoMessage() {}
+
+func (x *Feature) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Feature.ProtoReflect.Descriptor instead.
+func (*Feature) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Feature) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Feature) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Feature) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type Car struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Make     string                 `protobuf:"bytes,2,opt,name=make,proto3" json:"make,omitempty"`
+	Model    string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	Package  string                 `protobuf:"bytes,4,opt,name=package,proto3" json:"package,omitempty"`
+	Color    string                 `protobuf:"bytes,5,opt,name=color,proto3" json:"color,omitempty"`
+	Year     int32                  `protobuf:"varint,6,opt,name=year,proto3" json:"year,omitempty"`
+	Category string                 `protobuf:"bytes,7,opt,name=category,proto3" json:"category,omitempty"`
+	// mileage is in kilometers
+	Mileage int64 `protobuf:"varint,8,opt,name=mileage,proto3" json:"mileage,omitempty"`
+	// display_mileage is the mileage in the requested unit
+	DisplayMileage *Distance `protobuf:"bytes,9,opt,name=display_mileage,json=displayMileage,proto3" json:"display_mileage,omitempty"`
+	Price          *Money    `protobuf:"bytes,10,opt,name=price,proto3" json:"price,omitempty"`
+	// display_price is the price converted to the requested currency
+	DisplayPrice   *Money `protobuf:"bytes,11,opt,name=display_price,json=displayPrice,proto3" json:"display_price,omitempty"`
+	Identification string `protobuf:"bytes,12,opt,name=identification,proto3" json:"identification,omitempty"`
+	// images and features are only given by GetCar
+	Images        []*CarImage            `protobuf:"bytes,13,rep,name=images,proto3" json:"images,omitempty"`
+	Features      []*Feature             `protobuf:"bytes,14,rep,name=features,proto3" json:"features,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Car) Reset() {
+	*x = Car{}
+	mi := &file_car_v1_car_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Car) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Car) ProtoMessage() {}
+
+func (x *Car) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Car.ProtoReflect.Descriptor instead.
+func (*Car) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Car) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Car) GetMake() string {
+	if x != nil {
+		return x.Make
+	}
+	return ""
+}
+
+func (x *Car) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Car) GetPackage() string {
+	if x != nil {
+		return x.Package
+	}
+	return ""
+}
+
+func (x *Car) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Car) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Car) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Car) GetMileage() int64 {
+	if x != nil {
+		return x.Mileage
+	}
+	return 0
+}
+
+func (x *Car) GetDisplayMileage() *Distance {
+	if x != nil {
+		return x.DisplayMileage
+	}
+	return nil
+}
+
+func (x *Car) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *Car) GetDisplayPrice() *Money {
+	if x != nil {
+		return x.DisplayPrice
+	}
+	return nil
+}
+
+func (x *Car) GetIdentification() string {
+	if x != nil {
+		return x.Identification
+	}
+	return ""
+}
+
+func (x *Car) GetImages() []*CarImage {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+func (x *Car) GetFeatures() []*Feature {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+func (x *Car) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Car) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// CarInput is the car to store, the mileage is read in mileage_unit, km when not given
+type CarInput struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Make           string                 `protobuf:"bytes,1,opt,name=make,proto3" json:"make,omitempty"`
+	Model          string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Package        string                 `protobuf:"bytes,3,opt,name=package,proto3" json:"package,omitempty"`
+	Color          string                 `protobuf:"bytes,4,opt,name=color,proto3" json:"color,omitempty"`
+	Year           int32                  `protobuf:"varint,5,opt,name=year,proto3" json:"year,omitempty"`
+	Category       string                 `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
+	Mileage        int64                  `protobuf:"varint,7,opt,name=mileage,proto3" json:"mileage,omitempty"`
+	MileageUnit    string                 `protobuf:"bytes,8,opt,name=mileage_unit,json=mileageUnit,proto3" json:"mileage_unit,omitempty"`
+	Price          *Money                 `protobuf:"bytes,9,opt,name=price,proto3" json:"price,omitempty"`
+	Identification string                 `protobuf:"bytes,10,opt,name=identification,proto3" json:"identification,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CarInput) Reset() {
+	*x = CarInput{}
+	mi := &file_car_v1_car_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarInput) ProtoMessage() {}
+
+func (x *CarInput) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarInput.ProtoReflect.Descriptor instead.
+func (*CarInput) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CarInput) GetMake() string {
+	if x != nil {
+		return x.Make
+	}
+	return ""
+}
+
+func (x *CarInput) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CarInput) GetPackage() string {
+	if x != nil {
+		return x.Package
+	}
+	return ""
+}
+
+func (x *CarInput) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *CarInput) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *CarInput) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CarInput) GetMileage() int64 {
+	if x != nil {
+		return x.Mileage
+	}
+	return 0
+}
+
+func (x *CarInput) GetMileageUnit() string {
+	if x != nil {
+		return x.MileageUnit
+	}
+	return ""
+}
+
+func (x *CarInput) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *CarInput) GetIdentification() string {
+	if x != nil {
+		return x.Identification
+	}
+	return ""
+}
+
+type CreateCarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Car           *CarInput              `protobuf:"bytes,1,opt,name=car,proto3" json:"car,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCarRequest) Reset() {
+	*x = CreateCarRequest{}
+	mi := &file_car_v1_car_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCarRequest) ProtoMessage() {}
+
+func (x *CreateCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCarRequest.ProtoReflect.Descriptor instead.
+func (*CreateCarRequest) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateCarRequest) GetCar() *CarInput {
+	if x != nil {
+		return x.Car
+	}
+	return nil
+}
+
+type CreateCarResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCarResponse) Reset() {
+	*x = CreateCarResponse{}
+	mi := &file_car_v1_car_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCarResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCarResponse) ProtoMessage() {}
+
+func (x *CreateCarResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCarResponse.ProtoReflect.Descriptor instead.
+func (*CreateCarResponse) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{7}
+}
+
+type GetCarRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// currency converts the price into display_price
+	Currency string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	// mileage_unit is km or mi
+	MileageUnit   string `protobuf:"bytes,3,opt,name=mileage_unit,json=mileageUnit,proto3" json:"mileage_unit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCarRequest) Reset() {
+	*x = GetCarRequest{}
+	mi := &file_car_v1_car_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCarRequest) ProtoMessage() {}
+
+func (x *GetCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCarRequest.ProtoReflect.Descriptor instead.
+func (*GetCarRequest) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetCarRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetCarRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetCarRequest) GetMileageUnit() string {
+	if x != nil {
+		return x.MileageUnit
+	}
+	return ""
+}
+
+type ListCarsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// features are the codes the cars carry, all of them unless features_match is any
+	Features      []string `protobuf:"bytes,1,rep,name=features,proto3" json:"features,omitempty"`
+	FeaturesMatch string   `protobuf:"bytes,2,opt,name=features_match,json=featuresMatch,proto3" json:"features_match,omitempty"`
+	Currency      string   `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	// mileage_min and mileage_max are read in mileage_unit
+	MileageMin  *int64 `protobuf:"varint,4,opt,name=mileage_min,json=mileageMin,proto3,oneof" json:"mileage_min,omitempty"`
+	MileageMax  *int64 `protobuf:"varint,5,opt,name=mileage_max,json=mileageMax,proto3,oneof" json:"mileage_max,omitempty"`
+	MileageUnit string `protobuf:"bytes,6,opt,name=mileage_unit,json=mileageUnit,proto3" json:"mileage_unit,omitempty"`
+	// limit is at most 100, 20 when not given
+	Limit         int32 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32 `protobuf:"varint,8,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCarsRequest) Reset() {
+	*x = ListCarsRequest{}
+	mi := &file_car_v1_car_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCarsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarsRequest) ProtoMessage() {}
+
+func (x *ListCarsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarsRequest.ProtoReflect.Descriptor instead.
+func (*ListCarsRequest) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListCarsRequest) GetFeatures() []string {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+func (x *ListCarsRequest) GetFeaturesMatch() string {
+	if x != nil {
+		return x.FeaturesMatch
+	}
+	return ""
+}
+
+func (x *ListCarsRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ListCarsRequest) GetMileageMin() int64 {
+	if x != nil && x.MileageMin != nil {
+		return *x.MileageMin
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetMileageMax() int64 {
+	if x != nil && x.MileageMax != nil {
+		return *x.MileageMax
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetMileageUnit() string {
+	if x != nil {
+		return x.MileageUnit
+	}
+	return ""
+}
+
+func (x *ListCarsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListCarsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Cars  []*Car                 `protobuf:"bytes,1,rep,name=cars,proto3" json:"cars,omitempty"`
+	// total counts the cars of every page
+	Total         int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCarsResponse) Reset() {
+	*x = ListCarsResponse{}
+	mi := &file_car_v1_car_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCarsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarsResponse) ProtoMessage() {}
+
+func (x *ListCarsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarsResponse.ProtoReflect.Descriptor instead.
+func (*ListCarsResponse) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListCarsResponse) GetCars() []*Car {
+	if x != nil {
+		return x.Cars
+	}
+	return nil
+}
+
+func (x *ListCarsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type UpdateCarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Car           *CarInput              `protobuf:"bytes,2,opt,name=car,proto3" json:"car,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCarRequest) Reset() {
+	*x = UpdateCarRequest{}
+	mi := &file_car_v1_car_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCarRequest) ProtoMessage() {}
+
+func (x *UpdateCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCarRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCarRequest) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateCarRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateCarRequest) GetCar() *CarInput {
+	if x != nil {
+		return x.Car
+	}
+	return nil
+}
+
+type DeleteCarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCarRequest) Reset() {
+	*x = DeleteCarRequest{}
+	mi := &file_car_v1_car_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCarRequest) ProtoMessage() {}
+
+func (x *DeleteCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCarRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCarRequest) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteCarRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteCarResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCarResponse) Reset() {
+	*x = DeleteCarResponse{}
+	mi := &file_car_v1_car_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCarResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCarResponse) ProtoMessage() {}
+
+func (x *DeleteCarResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCarResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCarResponse) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{13}
+}
+
+var File_car_v1_car_proto protoreflect.FileDescriptor
+
+const file_car_v1_car_proto_rawDesc = "" +
+	"\n" +
+	"\x10car/v1/car.proto\x12\rcarapi.car.v1\x1a\x1fgoogle/protobuf/timestamp.proto\";\n" +
+	"\x05Money\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"4\n" +
+	"\bDistance\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x03R\x05value\x12\x12\n" +
+	"\x04unit\x18\x02 \x01(\tR\x04unit\"\xca\x03\n" +
+	"\bCarImage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12G\n" +
+	"\n" +
+	"thumbnails\x18\x03 \x03(\v2'.carapi.car.v1.CarImage.ThumbnailsEntryR\n" +
+	"thumbnails\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\x12\x14\n" +
+	"\x05width\x18\x06 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\a \x01(\x05R\x06height\x12\x1a\n" +
+	"\bposition\x18\b \x01(\x05R\bposition\x12\x1d\n" +
+	"\n" +
+	"is_primary\x18\t \x01(\bR\tisPrimary\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x1a=\n" +
+	"\x0fThumbnailsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"A\n" +
+	"\aFeature\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"\xe5\x04\n" +
+	"\x03Car\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04make\x18\x02 \x01(\tR\x04make\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12\x18\n" +
+	"\apackage\x18\x04 \x01(\tR\apackage\x12\x14\n" +
+	"\x05color\x18\x05 \x01(\tR\x05color\x12\x12\n" +
+	"\x04year\x18\x06 \x01(\x05R\x04year\x12\x1a\n" +
+	"\bcategory\x18\a \x01(\tR\bcategory\x12\x18\n" +
+	"\amileage\x18\b \x01(\x03R\amileage\x12@\n" +
+	"\x0fdisplay_mileage\x18\t \x01(\v2\x17.carapi.car.v1.DistanceR\x0edisplayMileage\x12*\n" +
+	"\x05price\x18\n" +
+	" \x01(\v2\x14.carapi.car.v1.MoneyR\x05price\x129\n" +
+	"\rdisplay_price\x18\v \x01(\v2\x14.carapi.car.v1.MoneyR\fdisplayPrice\x12&\n" +
+	"\x0eidentification\x18\f \x01(\tR\x0eidentification\x12/\n" +
+	"\x06images\x18\r \x03(\v2\x17.carapi.car.v1.CarImageR\x06images\x122\n" +
+	"\bfeatures\x18\x0e \x03(\v2\x16.carapi.car.v1.FeatureR\bfeatures\x129\n" +
+	"\n" +
+	"created_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xa5\x02\n" +
+	"\bCarInput\x12\x12\n" +
+	"\x04make\x18\x01 \x01(\tR\x04make\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x12\x18\n" +
+	"\apackage\x18\x03 \x01(\tR\apackage\x12\x14\n" +
+	"\x05color\x18\x04 \x01(\tR\x05color\x12\x12\n" +
+	"\x04year\x18\x05 \x01(\x05R\x04year\x12\x1a\n" +
+	"\bcategory\x18\x06 \x01(\tR\bcategory\x12\x18\n" +
+	"\amileage\x18\a \x01(\x03R\amileage\x12!\n" +
+	"\fmileage_unit\x18\b \x01(\tR\vmileageUnit\x12*\n" +
+	"\x05price\x18\t \x01(\v2\x14.carapi.car.v1.MoneyR\x05price\x12&\n" +
+	"\x0eidentification\x18\n" +
+	" \x01(\tR\x0eidentification\"=\n" +
+	"\x10CreateCarRequest\x12)\n" +
+	"\x03car\x18\x01 \x01(\v2\x17.carapi.car.v1.CarInputR\x03car\"\x13\n" +
+	"\x11CreateCarResponse\"^\n" +
+	"\rGetCarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12!\n" +
+	"\fmileage_unit\x18\x03 \x01(\tR\vmileageUnit\"\xad\x02\n" +
+	"\x0fListCarsRequest\x12\x1a\n" +
+	"\bfeatures\x18\x01 \x03(\tR\bfeatures\x12%\n" +
+	"\x0efeatures_match\x18\x02 \x01(\tR\rfeaturesMatch\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12$\n" +
+	"\vmileage_min\x18\x04 \x01(\x03H\x00R\n" +
+	"mileageMin\x88\x01\x01\x12$\n" +
+	"\vmileage_max\x18\x05 \x01(\x03H\x01R\n" +
+	"mileageMax\x88\x01\x01\x12!\n" +
+	"\fmileage_unit\x18\x06 \x01(\tR\vmileageUnit\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\b \x01(\x05R\x06offsetB\x0e\n" +
+	"\f_mileage_minB\x0e\n" +
+	"\f_mileage_max\"P\n" +
+	"\x10ListCarsResponse\x12&\n" +
+	"\x04cars\x18\x01 \x03(\v2\x12.carapi.car.v1.CarR\x04cars\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"M\n" +
+	"\x10UpdateCarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12)\n" +
+	"\x03car\x18\x02 \x01(\v2\x17.carapi.car.v1.CarInputR\x03car\"\"\n" +
+	"\x10DeleteCarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x13\n" +
+	"\x11DeleteCarResponse2\xf7\x02\n" +
+	"\n" +
+	"CarService\x12N\n" +
+	"\tCreateCar\x12\x1f.carapi.car.v1.CreateCarRequest\x1a .carapi.car.v1.CreateCarResponse\x12:\n" +
+	"\x06GetCar\x12\x1c.carapi.car.v1.GetCarRequest\x1a\x12.carapi.car.v1.Car\x12K\n" +
+	"\bListCars\x12\x1e.carapi.car.v1.ListCarsRequest\x1a\x1f.carapi.car.v1.ListCarsResponse\x12@\n" +
+	"\tUpdateCar\x12\x1f.carapi.car.v1.UpdateCarRequest\x1a\x12.carapi.car.v1.Car\x12N\n" +
+	"\tDeleteCar\x12\x1f.carapi.car.v1.DeleteCarRequest\x1a .carapi.car.v1.DeleteCarResponseB\x1bZ\x19carApi/proto/car/v1;carv1b\x06proto3"
+
+var (
+	file_car_v1_car_proto_rawDescOnce sync.Once
+	file_car_v1_car_proto_rawDescData []byte
+)
+
+func file_car_v1_car_proto_rawDescGZIP() []byte {
+	file_car_v1_car_proto_rawDescOnce.Do(func() {
+		file_car_v1_car_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_car_v1_car_proto_rawDesc), len(file_car_v1_car_proto_rawDesc)))
+	})
+	return file_car_v1_car_proto_rawDescData
+}
+
+var file_car_v1_car_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_car_v1_car_proto_goTypes = []any{
+	(*Money)(nil),                 // 0: carapi.car.v1.Money
+	(*Distance)(nil),              // 1: carapi.car.v1.Distance
+	(*CarImage)(nil),              // 2: carapi.car.v1.CarImage
+	(*Feature)(nil),               // 3: carapi.car.v1.Feature
+	(*Car)(nil),                   // 4: carapi.car.v1.Car
+	(*CarInput)(nil),              // 5: carapi.car.v1.CarInput
+	(*CreateCarRequest)(nil),      // 6: carapi.car.v1.CreateCarRequest
+	(*CreateCarResponse)(nil),     // 7: carapi.car.v1.CreateCarResponse
+	(*GetCarRequest)(nil),         // 8: carapi.car.v1.GetCarRequest
+	(*ListCarsRequest)(nil),       // 9: carapi.car.v1.ListCarsRequest
+	(*ListCarsResponse)(nil),      // 10: carapi.car.v1.ListCarsResponse
+	(*UpdateCarRequest)(nil),      // 11: carapi.car.v1.UpdateCarRequest
+	(*DeleteCarRequest)(nil),      // 12: carapi.car.v1.DeleteCarRequest
+	(*DeleteCarResponse)(nil),     // 13: carapi.car.v1.DeleteCarResponse
+	nil,                           // 14: carapi.car.v1.CarImage.ThumbnailsEntry
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+}
+var file_car_v1_car_proto_depIdxs = []int32{
+	14, // 0: carapi.car.v1.CarImage.thumbnails:type_name -> carapi.car.v1.CarImage.ThumbnailsEntry
+	15, // 1: carapi.car.v1.CarImage.created_at:type_name -> google.protobuf.Timestamp
+	15, // 2: carapi.car.v1.CarImage.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: carapi.car.v1.Car.display_mileage:type_name -> carapi.car.v1.Distance
+	0,  // 4: carapi.car.v1.Car.price:type_name -> carapi.car.v1.Money
+	0,  // 5: carapi.car.v1.Car.display_price:type_name -> carapi.car.v1.Money
+	2,  // 6: carapi.car.v1.Car.images:type_name -> carapi.car.v1.CarImage
+	3,  // 7: carapi.car.v1.Car.features:type_name -> carapi.car.v1.Feature
+	15, // 8: carapi.car.v1.Car.created_at:type_name -> google.protobuf.Timestamp
+	15, // 9: carapi.car.v1.Car.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 10: carapi.car.v1.CarInput.price:type_name -> carapi.car.v1.Money
+	5,  // 11: carapi.car.v1.CreateCarRequest.car:type_name -> carapi.car.v1.CarInput
+	4,  // 12: carapi.car.v1.ListCarsResponse.cars:type_name -> carapi.car.v1.Car
+	5,  // 13: carapi.car.v1.UpdateCarRequest.car:type_name -> carapi.car.v1.CarInput
+	6,  // 14: carapi.car.v1.CarService.CreateCar:input_type -> carapi.car.v1.CreateCarRequest
+	8,  // 15: carapi.car.v1.CarService.GetCar:input_type -> carapi.car.v1.GetCarRequest
+	9,  // 16: carapi.car.v1.CarService.ListCars:input_type -> carapi.car.v1.ListCarsRequest
+	11, // 17: carapi.car.v1.CarService.UpdateCar:input_type -> carapi.car.v1.UpdateCarRequest
+	12, // 18: carapi.car.v1.CarService.DeleteCar:input_type -> carapi.car.v1.DeleteCarRequest
+	7,  // 19: carapi.car.v1.CarService.CreateCar:output_type -> carapi.car.v1.CreateCarResponse
+	4,  // 20: carapi.car.v1.CarService.GetCar:output_type -> carapi.car.v1.Car
+	10, // 21: carapi.car.v1.CarService.ListCars:output_type -> carapi.car.v1.ListCarsResponse
+	4,  // 22: carapi.car.v1.CarService.UpdateCar:output_type -> carapi.car.v1.Car
+	13, // 23: carapi.car.v1.CarService.DeleteCar:output_type -> carapi.car.v1.DeleteCarResponse
+	19, // [19:24] is the sub-list for method output_type
+	14, // [14:19] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_car_v1_car_proto_init() }
+func file_car_v1_car_proto_init() {
+	if File_car_v1_car_proto != nil {
+		return
+	}
+	file_car_v1_car_proto_msgTypes[9].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_car_v1_car_proto_rawDesc), len(file_car_v1_car_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_car_v1_car_proto_goTypes,
+		DependencyIndexes: file_car_v1_car_proto_depIdxs,
+		MessageInfos:      file_car_v1_car_proto_msgTypes,
+	}.Build()
+	File_car_v1_car_proto = out.File
+	file_car_v1_car_proto_goTypes = nil
+	file_car_v1_car_proto_depIdxs = nil
+}
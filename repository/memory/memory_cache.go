@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"carApi/infrastructure/metrics"
+	redisRepository "carApi/repository/redis"
+	"github.com/go-redis/redis"
+)
+
+type cacheItem struct {
+	value     string
+	expiresAt time.Time
+}
+
+func (i cacheItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && !now.Before(i.expiresAt)
+}
+
+type memoryCache struct {
+	mu      sync.Mutex
+	items   map[string]cacheItem
+	metrics *metrics.Metrics
+}
+
+// NewMemoryRedisRepository will create an object that represent the RedisRepository interface keeping
+// the keys in memory. A missing or expired key gives redis.Nil like the redis repository does.
+func NewMemoryRedisRepository(metrics *metrics.Metrics) redisRepository.RedisRepository {
+	return &memoryCache{
+		items:   map[string]cacheItem{},
+		metrics: metrics,
+	}
+}
+
+// Set stores the value as redis would format it, exp of zero keeps the key for good
+func (c *memoryCache) Set(ctx context.Context, key string, value interface{}, exp time.Duration) error {
+	item := cacheItem{value: format(value)}
+	if exp > 0 {
+		item.expiresAt = time.Now().Add(exp)
+	}
+
+	c.mu.Lock()
+	c.items[key] = item
+	c.mu.Unlock()
+
+	c.metrics.ObserveCache(metrics.CacheOperationSet, metrics.CacheResultOK)
+	return nil
+}
+
+// Get gives the value of the key, expired keys are removed when they are read
+func (c *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	item, ok := c.items[key]
+	if ok && item.expired(time.Now()) {
+		delete(c.items, key)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		c.metrics.ObserveCache(metrics.CacheOperationGet, metrics.CacheResultMiss)
+		return "", redis.Nil
+	}
+
+	c.metrics.ObserveCache(metrics.CacheOperationGet, metrics.CacheResultHit)
+	return item.value, nil
+}
+
+// Incr increments the counter under the key and gives its new value, a missing or expired key
+// counts from zero and a value that is not an integer is an error like in redis. The TTL is kept.
+func (c *memoryCache) Incr(ctx context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.items[key]
+	if ok && item.expired(time.Now()) {
+		item, ok = cacheItem{}, false
+	}
+
+	var value int64
+	if ok {
+		var err error
+		if value, err = strconv.ParseInt(item.value, 10, 64); err != nil {
+			return 0, errors.New("ERR value is not an integer or out of range")
+		}
+	}
+
+	value++
+	item.value = strconv.FormatInt(value, 10)
+	c.items[key] = item
+	return value, nil
+}
+
+// format gives the string redis stores for the value
+func format(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
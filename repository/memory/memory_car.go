@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+)
+
+type memoryCarRepository struct {
+	store *Store
+}
+
+// NewMemoryCarRepository will create new a carRepository object representation of CarRepository interface
+// keeping the cars in the store
+func NewMemoryCarRepository(store *Store) pgsql.CarRepository {
+	return &memoryCarRepository{
+		store: store,
+	}
+}
+
+// row keeps the columns of the cars table, what is loaded or computed around a car is left out
+func row(car entity.Car) entity.Car {
+	car.DisplayMileage = nil
+	car.DisplayPrice = nil
+	car.Images = nil
+	car.Features = nil
+	return car
+}
+
+func (r *memoryCarRepository) Create(ctx context.Context, car *entity.Car) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	car.ID = r.store.nextID("cars")
+	r.store.cars[car.ID] = row(*car)
+	return nil
+}
+
+func (r *memoryCarRepository) GetByID(ctx context.Context, id int64) (entity.Car, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	car, ok := r.store.cars[id]
+	if _, deleted := r.store.deletedCars[id]; !ok || deleted {
+		return entity.Car{}, sql.ErrNoRows
+	}
+	return car, nil
+}
+
+func (r *memoryCarRepository) Fetch(ctx context.Context, filter entity.CarFilter, page entity.Page) (cars []entity.Car, err error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, car := range r.store.cars {
+		if r.matches(car, filter) {
+			cars = append(cars, car)
+		}
+	}
+
+	sort.Slice(cars, func(i, j int) bool { return cars[i].ID < cars[j].ID })
+
+	if page.Offset >= len(cars) {
+		return nil, nil
+	}
+	cars = cars[page.Offset:]
+	if page.Limit > 0 && page.Limit < len(cars) {
+		cars = cars[:page.Limit]
+	}
+	return cars, nil
+}
+
+func (r *memoryCarRepository) Count(ctx context.Context, filter entity.CarFilter) (count int64, err error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, car := range r.store.cars {
+		if r.matches(car, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// matches applies the same criteria as the WHERE conditions of the pgsql repository
+func (r *memoryCarRepository) matches(car entity.Car, filter entity.CarFilter) bool {
+	if _, deleted := r.store.deletedCars[car.ID]; deleted {
+		return false
+	}
+	for featureID := range r.store.carFeatures[car.ID] {
+		car.Features = append(car.Features, r.store.features[featureID])
+	}
+	return filter.Matches(car)
+}
+
+func (r *memoryCarRepository) Update(ctx context.Context, car *entity.Car) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.cars[car.ID]
+	if _, deleted := r.store.deletedCars[car.ID]; !ok || deleted {
+		return affected(0)
+	}
+
+	updated := row(*car)
+	updated.CreatedAt = stored.CreatedAt
+	r.store.cars[car.ID] = updated
+	return nil
+}
+
+// Delete only marks the car as deleted like the pgsql repository, the rows stay until PurgeDeleted
+func (r *memoryCarRepository) Delete(ctx context.Context, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	_, ok := r.store.cars[id]
+	if _, deleted := r.store.deletedCars[id]; !ok || deleted {
+		return affected(0)
+	}
+
+	r.store.deletedCars[id] = time.Now().UTC()
+	return nil
+}
+
+func (r *memoryCarRepository) PurgeDeleted(ctx context.Context, before time.Time) (purged int64, err error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, deletedAt := range r.store.deletedCars {
+		if deletedAt.Before(before) {
+			r.store.deleteCar(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *memoryCarRepository) CountByCategory(ctx context.Context) (map[string]int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := map[string]int64{}
+	for _, car := range r.store.cars {
+		if _, deleted := r.store.deletedCars[car.ID]; deleted {
+			continue
+		}
+		counts[car.Category]++
+	}
+	return counts, nil
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+)
+
+type memoryCarImageRepository struct {
+	store *Store
+}
+
+// NewMemoryCarImageRepository will create new a carImageRepository object representation of CarImageRepository interface
+// keeping the images in the store
+func NewMemoryCarImageRepository(store *Store) pgsql.CarImageRepository {
+	return &memoryCarImageRepository{
+		store: store,
+	}
+}
+
+// imageRow keeps the columns of the car_images table, the URLs are resolved by the usecase
+func imageRow(image entity.CarImage) entity.CarImage {
+	image.URL = ""
+	image.Thumbnails = nil
+	return image
+}
+
+func (r *memoryCarImageRepository) Create(ctx context.Context, image *entity.CarImage) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.cars[image.CarID]; !ok {
+		return fmt.Errorf("car %d does not exist", image.CarID)
+	}
+
+	image.ID = r.store.nextID("car_images")
+	r.store.carImages[image.ID] = imageRow(*image)
+	return nil
+}
+
+func (r *memoryCarImageRepository) GetByID(ctx context.Context, id int64) (entity.CarImage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	image, ok := r.store.carImages[id]
+	if !ok {
+		return entity.CarImage{}, sql.ErrNoRows
+	}
+	return image, nil
+}
+
+func (r *memoryCarImageRepository) FetchByCarID(ctx context.Context, carID int64) ([]entity.CarImage, error) {
+	return r.FetchByCarIDs(ctx, []int64{carID})
+}
+
+// FetchByCarIDs gives the images of all the cars at once, grouped by car in the order of FetchByCarID
+func (r *memoryCarImageRepository) FetchByCarIDs(ctx context.Context, carIDs []int64) (images []entity.CarImage, err error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := map[int64]bool{}
+	for _, carID := range carIDs {
+		wanted[carID] = true
+	}
+
+	for _, image := range r.store.carImages {
+		if wanted[image.CarID] {
+			images = append(images, image)
+		}
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].CarID != images[j].CarID {
+			return images[i].CarID < images[j].CarID
+		}
+		if images[i].Position != images[j].Position {
+			return images[i].Position < images[j].Position
+		}
+		return images[i].ID < images[j].ID
+	})
+	return images, nil
+}
+
+// FetchByCarsDeletedBefore gives the images of the cars PurgeDeleted would remove at the given time
+func (r *memoryCarImageRepository) FetchByCarsDeletedBefore(ctx context.Context, before time.Time) ([]entity.CarImage, error) {
+	r.store.mu.RLock()
+	var carIDs []int64
+	for id, deletedAt := range r.store.deletedCars {
+		if deletedAt.Before(before) {
+			carIDs = append(carIDs, id)
+		}
+	}
+	r.store.mu.RUnlock()
+
+	return r.FetchByCarIDs(ctx, carIDs)
+}
+
+func (r *memoryCarImageRepository) Update(ctx context.Context, image *entity.CarImage) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.carImages[image.ID]
+	if !ok {
+		return affected(0)
+	}
+
+	stored.Position = image.Position
+	stored.IsPrimary = image.IsPrimary
+	stored.UpdatedAt = image.UpdatedAt
+	r.store.carImages[image.ID] = stored
+	return nil
+}
+
+// SetPrimary flags one image as the primary image of a car and clears the flag on the others
+func (r *memoryCarImageRepository) SetPrimary(ctx context.Context, carID int64, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for imageID, image := range r.store.carImages {
+		if image.CarID == carID {
+			image.IsPrimary = imageID == id
+			r.store.carImages[imageID] = image
+		}
+	}
+	return nil
+}
+
+func (r *memoryCarImageRepository) Delete(ctx context.Context, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.carImages[id]; !ok {
+		return affected(0)
+	}
+
+	delete(r.store.carImages, id)
+	return nil
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+)
+
+type memoryCatalogRepository struct {
+	store *Store
+}
+
+// NewMemoryCatalogRepository will create new a catalogRepository object representation of CatalogRepository interface
+// keeping the entries in the store
+func NewMemoryCatalogRepository(store *Store) pgsql.CatalogRepository {
+	return &memoryCatalogRepository{
+		store: store,
+	}
+}
+
+// find looks the entry up case-insensitively, like the unique index on the catalog
+func (r *memoryCatalogRepository) find(kind string, parentID int64, name string) (entity.CatalogEntry, bool) {
+	for _, entry := range r.store.catalog {
+		if entry.Kind == kind && entry.ParentID == parentID && strings.EqualFold(entry.Name, name) {
+			return entry, true
+		}
+	}
+	return entity.CatalogEntry{}, false
+}
+
+func (r *memoryCatalogRepository) Create(ctx context.Context, entry *entity.CatalogEntry) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.find(entry.Kind, entry.ParentID, entry.Name); ok {
+		return fmt.Errorf("%s %q already exists", entry.Kind, entry.Name)
+	}
+	if _, ok := r.store.catalog[entry.ParentID]; entry.ParentID != 0 && !ok {
+		return fmt.Errorf("catalog entry %d does not exist", entry.ParentID)
+	}
+
+	entry.ID = r.store.nextID("catalog_entries")
+	r.store.catalog[entry.ID] = *entry
+	return nil
+}
+
+// GetByName looks the entry up case-insensitively
+func (r *memoryCatalogRepository) GetByName(ctx context.Context, kind string, parentID int64, name string) (entity.CatalogEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	entry, ok := r.find(kind, parentID, name)
+	if !ok {
+		return entity.CatalogEntry{}, sql.ErrNoRows
+	}
+	return entry, nil
+}
+
+func (r *memoryCatalogRepository) Fetch(ctx context.Context, kind string, parentID int64) (entries []entity.CatalogEntry, err error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, entry := range r.store.catalog {
+		if entry.Kind == kind && entry.ParentID == parentID {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+func (r *memoryCatalogRepository) Update(ctx context.Context, entry *entity.CatalogEntry) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.catalog[entry.ID]
+	if !ok {
+		return affected(0)
+	}
+
+	if existing, ok := r.find(stored.Kind, stored.ParentID, entry.Name); ok && existing.ID != entry.ID {
+		return fmt.Errorf("%s %q already exists", stored.Kind, entry.Name)
+	}
+
+	stored.Name = entry.Name
+	stored.UpdatedAt = entry.UpdatedAt
+	r.store.catalog[entry.ID] = stored
+	return nil
+}
+
+func (r *memoryCatalogRepository) Delete(ctx context.Context, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.catalog[id]; !ok {
+		return affected(0)
+	}
+
+	r.store.deleteCatalogEntry(id)
+	return nil
+}
@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+)
+
+type memoryFeatureRepository struct {
+	store *Store
+}
+
+// NewMemoryFeatureRepository will create new a featureRepository object representation of FeatureRepository interface
+// keeping the features and their links to cars in the store
+func NewMemoryFeatureRepository(store *Store) pgsql.FeatureRepository {
+	return &memoryFeatureRepository{
+		store: store,
+	}
+}
+
+// codeTaken enforces the unique constraint on the code, except is the feature being updated
+func (r *memoryFeatureRepository) codeTaken(code string, except int64) error {
+	for id, feature := range r.store.features {
+		if feature.Code == code && id != except {
+			return fmt.Errorf("feature code %q already exists", code)
+		}
+	}
+	return nil
+}
+
+func (r *memoryFeatureRepository) Create(ctx context.Context, feature *entity.Feature) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.codeTaken(feature.Code, 0); err != nil {
+		return err
+	}
+
+	feature.ID = r.store.nextID("features")
+	r.store.features[feature.ID] = *feature
+	return nil
+}
+
+func (r *memoryFeatureRepository) GetByID(ctx context.Context, id int64) (entity.Feature, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	feature, ok := r.store.features[id]
+	if !ok {
+		return entity.Feature{}, sql.ErrNoRows
+	}
+	return feature, nil
+}
+
+func (r *memoryFeatureRepository) GetByCode(ctx context.Context, code string) (entity.Feature, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, feature := range r.store.features {
+		if feature.Code == code {
+			return feature, nil
+		}
+	}
+	return entity.Feature{}, sql.ErrNoRows
+}
+
+func (r *memoryFeatureRepository) FetchByCodes(ctx context.Context, codes []string) ([]entity.Feature, error) {
+	wanted := map[string]bool{}
+	for _, code := range codes {
+		wanted[code] = true
+	}
+
+	return r.fetch(func(feature entity.Feature) bool { return wanted[feature.Code] })
+}
+
+func (r *memoryFeatureRepository) Fetch(ctx context.Context) ([]entity.Feature, error) {
+	return r.fetch(func(entity.Feature) bool { return true })
+}
+
+func (r *memoryFeatureRepository) FetchByCarID(ctx context.Context, carID int64) ([]entity.Feature, error) {
+	r.store.mu.RLock()
+	linked := r.store.carFeatures[carID]
+	r.store.mu.RUnlock()
+
+	return r.fetch(func(feature entity.Feature) bool {
+		_, ok := linked[feature.ID]
+		return ok
+	})
+}
+
+// FetchByCarIDs gives the features of all the cars at once keyed by car, a car without
+// features has no entry
+func (r *memoryFeatureRepository) FetchByCarIDs(ctx context.Context, carIDs []int64) (map[int64][]entity.Feature, error) {
+	features := map[int64][]entity.Feature{}
+	for _, carID := range carIDs {
+		if _, ok := features[carID]; ok {
+			continue
+		}
+
+		linked, err := r.FetchByCarID(ctx, carID)
+		if err != nil {
+			return features, err
+		}
+		if len(linked) > 0 {
+			features[carID] = linked
+		}
+	}
+	return features, nil
+}
+
+// fetch lists the features kept by keep ordered by code
+func (r *memoryFeatureRepository) fetch(keep func(entity.Feature) bool) (features []entity.Feature, err error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, feature := range r.store.features {
+		if keep(feature) {
+			features = append(features, feature)
+		}
+	}
+
+	sort.Slice(features, func(i, j int) bool { return features[i].Code < features[j].Code })
+	return features, nil
+}
+
+func (r *memoryFeatureRepository) Update(ctx context.Context, feature *entity.Feature) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.features[feature.ID]
+	if !ok {
+		return affected(0)
+	}
+
+	if err := r.codeTaken(feature.Code, feature.ID); err != nil {
+		return err
+	}
+
+	stored.Code = feature.Code
+	stored.Name = feature.Name
+	stored.UpdatedAt = feature.UpdatedAt
+	r.store.features[feature.ID] = stored
+	return nil
+}
+
+func (r *memoryFeatureRepository) Delete(ctx context.Context, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.features[id]; !ok {
+		return affected(0)
+	}
+
+	delete(r.store.features, id)
+	for _, linked := range r.store.carFeatures {
+		delete(linked, id)
+	}
+	return nil
+}
+
+// AttachToCar links the features to a car, links that already exist are kept as they are
+func (r *memoryFeatureRepository) AttachToCar(ctx context.Context, carID int64, featureIDs []int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.cars[carID]; !ok {
+		return fmt.Errorf("car %d does not exist", carID)
+	}
+	for _, id := range featureIDs {
+		if _, ok := r.store.features[id]; !ok {
+			return fmt.Errorf("feature %d does not exist", id)
+		}
+	}
+
+	linked, ok := r.store.carFeatures[carID]
+	if !ok {
+		linked = map[int64]struct{}{}
+		r.store.carFeatures[carID] = linked
+	}
+	for _, id := range featureIDs {
+		linked[id] = struct{}{}
+	}
+	return nil
+}
+
+func (r *memoryFeatureRepository) DetachFromCar(ctx context.Context, carID int64, featureID int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.carFeatures[carID], featureID)
+	return nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+)
+
+type outboxEntry struct {
+	event       entity.Event
+	publishedAt *time.Time
+}
+
+type memoryOutboxRepository struct {
+	store *Store
+}
+
+// NewMemoryOutboxRepository will create new an outboxRepository object representation of OutboxRepository interface
+// keeping the events in the store
+func NewMemoryOutboxRepository(store *Store) pgsql.OutboxRepository {
+	return &memoryOutboxRepository{
+		store: store,
+	}
+}
+
+func (r *memoryOutboxRepository) Add(ctx context.Context, event *entity.Event) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	event.ID = r.store.nextID("outbox")
+	r.store.outbox[event.ID] = outboxEntry{event: *event}
+	return nil
+}
+
+func (r *memoryOutboxRepository) FetchPending(ctx context.Context, limit int) ([]entity.Event, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var events []entity.Event
+	for _, entry := range r.store.outbox {
+		if entry.publishedAt == nil {
+			events = append(events, entry.event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (r *memoryOutboxRepository) MarkPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	entry, ok := r.store.outbox[id]
+	if !ok {
+		return affected(0)
+	}
+
+	entry.publishedAt = &publishedAt
+	r.store.outbox[id] = entry
+	return nil
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	redisRepository "carApi/repository/redis"
+)
+
+type memoryPresence struct {
+	mu      sync.Mutex
+	viewers map[int64]map[string]time.Time
+}
+
+// NewMemoryPresenceRepository will create an object that represent the PresenceRepository interface
+// keeping the presences in memory, the instance is then the only one to see them
+func NewMemoryPresenceRepository() redisRepository.PresenceRepository {
+	return &memoryPresence{
+		viewers: map[int64]map[string]time.Time{},
+	}
+}
+
+func (p *memoryPresence) Join(ctx context.Context, carID int64, rep string, ttl time.Duration) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	viewers := p.prune(carID, now)
+	if viewers == nil {
+		viewers = map[string]time.Time{}
+		p.viewers[carID] = viewers
+	}
+
+	_, viewing := viewers[rep]
+	viewers[rep] = now.Add(ttl)
+	return !viewing, nil
+}
+
+func (p *memoryPresence) Leave(ctx context.Context, carID int64, rep string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	viewers := p.prune(carID, time.Now())
+	if _, viewing := viewers[rep]; !viewing {
+		return false, nil
+	}
+
+	delete(viewers, rep)
+	if len(viewers) == 0 {
+		delete(p.viewers, carID)
+	}
+	return true, nil
+}
+
+func (p *memoryPresence) Viewers(ctx context.Context, carID int64) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	viewers := []string{}
+	for rep := range p.prune(carID, time.Now()) {
+		viewers = append(viewers, rep)
+	}
+	sort.Strings(viewers)
+	return viewers, nil
+}
+
+// prune removes the lapsed presences of the car and gives the remaining ones
+func (p *memoryPresence) prune(carID int64, now time.Time) map[string]time.Time {
+	viewers := p.viewers[carID]
+	for rep, lapsesAt := range viewers {
+		if !now.Before(lapsesAt) {
+			delete(viewers, rep)
+		}
+	}
+	if viewers != nil && len(viewers) == 0 {
+		delete(p.viewers, carID)
+		return nil
+	}
+	return viewers
+}
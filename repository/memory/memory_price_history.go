@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+)
+
+type memoryPriceHistoryRepository struct {
+	store *Store
+}
+
+// NewMemoryPriceHistoryRepository will create new a priceHistoryRepository object representation of PriceHistoryRepository interface
+// keeping the price history in the store
+func NewMemoryPriceHistoryRepository(store *Store) pgsql.PriceHistoryRepository {
+	return &memoryPriceHistoryRepository{
+		store: store,
+	}
+}
+
+func (r *memoryPriceHistoryRepository) Add(ctx context.Context, point *entity.PricePoint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.cars[point.CarID]; !ok {
+		return fmt.Errorf("car %d does not exist", point.CarID)
+	}
+
+	point.ID = r.store.nextID("car_price_history")
+	r.store.prices[point.ID] = *point
+	return nil
+}
+
+// FetchByCarIDs gives the history of all the cars at once, grouped by car from the oldest price
+func (r *memoryPriceHistoryRepository) FetchByCarIDs(ctx context.Context, carIDs []int64) (points []entity.PricePoint, err error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := map[int64]bool{}
+	for _, carID := range carIDs {
+		wanted[carID] = true
+	}
+
+	for _, point := range r.store.prices {
+		if wanted[point.CarID] {
+			points = append(points, point)
+		}
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].CarID != points[j].CarID {
+			return points[i].CarID < points[j].CarID
+		}
+		if !points[i].ChangedAt.Equal(points[j].ChangedAt) {
+			return points[i].ChangedAt.Before(points[j].ChangedAt)
+		}
+		return points[i].ID < points[j].ID
+	})
+	return points, nil
+}
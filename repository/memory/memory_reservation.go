@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+)
+
+type memoryReservationRepository struct {
+	store *Store
+}
+
+// NewMemoryReservationRepository will create new a reservationRepository object representation of ReservationRepository interface
+// keeping the reservations in the store
+func NewMemoryReservationRepository(store *Store) pgsql.ReservationRepository {
+	return &memoryReservationRepository{
+		store: store,
+	}
+}
+
+func (r *memoryReservationRepository) GetByCarID(ctx context.Context, carID int64) (entity.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	reservation, ok := r.store.reservations[carID]
+	if !ok {
+		return entity.Reservation{}, sql.ErrNoRows
+	}
+	return reservation, nil
+}
+
+func (r *memoryReservationRepository) Save(ctx context.Context, reservation *entity.Reservation) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.cars[reservation.CarID]; !ok {
+		return fmt.Errorf("car %d does not exist", reservation.CarID)
+	}
+
+	r.store.reservations[reservation.CarID] = *reservation
+	return nil
+}
+
+func (r *memoryReservationRepository) Delete(ctx context.Context, carID int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.reservations[carID]; !ok {
+		return affected(0)
+	}
+
+	delete(r.store.reservations, carID)
+	return nil
+}
@@ -0,0 +1,99 @@
+package memory_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"carApi/infrastructure/metrics"
+	"carApi/repository/memory"
+	redisRepository "carApi/repository/redis"
+	"carApi/repository/repositorytest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRepositories(t *testing.T) {
+	repositorytest.TestRepositories(t, func(t *testing.T) repositorytest.Repositories {
+		store := memory.NewStore()
+		return repositorytest.Repositories{
+			Cars:         memory.NewMemoryCarRepository(store),
+			CarImages:    memory.NewMemoryCarImageRepository(store),
+			Features:     memory.NewMemoryFeatureRepository(store),
+			PriceHistory: memory.NewMemoryPriceHistoryRepository(store),
+			Reservations: memory.NewMemoryReservationRepository(store),
+			Catalog:      memory.NewMemoryCatalogRepository(store),
+			Outbox:       memory.NewMemoryOutboxRepository(store),
+
+			Webhooks:          memory.NewMemoryWebhookRepository(store),
+			WebhookDeliveries: memory.NewMemoryWebhookDeliveryRepository(store),
+		}
+	})
+}
+
+func TestMemoryCache(t *testing.T) {
+	repositorytest.TestCache(t, func(t *testing.T) repositorytest.Cache {
+		return repositorytest.Cache{
+			Repository: memory.NewMemoryRedisRepository(metrics.NewMetrics()),
+			Elapse:     time.Sleep,
+		}
+	})
+}
+
+func TestMemoryPresence(t *testing.T) {
+	repositorytest.TestPresence(t, func(t *testing.T) redisRepository.PresenceRepository {
+		return memory.NewMemoryPresenceRepository()
+	})
+}
+
+func TestMemoryCache_Metrics(t *testing.T) {
+	appMetrics := metrics.NewMetrics()
+	cache := memory.NewMemoryRedisRepository(appMetrics)
+
+	cache.Get(context.TODO(), "ping")
+	cache.Set(context.TODO(), "ping", "pong", 0)
+	cache.Get(context.TODO(), "ping")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(appMetrics.CacheRequests.WithLabelValues(metrics.CacheOperationSet, metrics.CacheResultOK)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(appMetrics.CacheRequests.WithLabelValues(metrics.CacheOperationGet, metrics.CacheResultMiss)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(appMetrics.CacheRequests.WithLabelValues(metrics.CacheOperationGet, metrics.CacheResultHit)))
+}
+
+func TestMemoryTxManager(t *testing.T) {
+	txManager := memory.NewMemoryTxManager(memory.NewStore())
+
+	t.Run("nested", func(t *testing.T) {
+		calls := 0
+		err := txManager.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			return txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+				calls++
+				return nil
+			})
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls, "a nested unit joins the outer one")
+	})
+
+	t.Run("serialized", func(t *testing.T) {
+		var running, overlaps int32
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				txManager.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+					if atomic.AddInt32(&running, 1) > 1 {
+						atomic.AddInt32(&overlaps, 1)
+					}
+					time.Sleep(time.Millisecond)
+					atomic.AddInt32(&running, -1)
+					return nil
+				})
+			}()
+		}
+		wg.Wait()
+		assert.Zero(t, overlaps)
+	})
+}
@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"context"
+
+	"carApi/repository/transaction"
+)
+
+type unitKey struct{}
+
+type memoryTxManager struct {
+	store *Store
+}
+
+// NewMemoryTxManager will create new a txManager object representation of TxManager interface. Units
+// of work on the store run one at a time so a check then act is not raced by another unit, but the
+// writes of a failed unit are not rolled back.
+func NewMemoryTxManager(store *Store) transaction.TxManager {
+	return &memoryTxManager{
+		store: store,
+	}
+}
+
+func (m *memoryTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(unitKey{}) != nil {
+		return fn(ctx)
+	}
+
+	m.store.units.Lock()
+	defer m.store.units.Unlock()
+
+	return fn(context.WithValue(ctx, unitKey{}, true))
+}
@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+)
+
+type memoryWebhookRepository struct {
+	store *Store
+}
+
+// NewMemoryWebhookRepository will create new a webhookRepository object representation of WebhookRepository interface
+// keeping the subscriptions in the store
+func NewMemoryWebhookRepository(store *Store) pgsql.WebhookRepository {
+	return &memoryWebhookRepository{
+		store: store,
+	}
+}
+
+func (r *memoryWebhookRepository) Create(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	subscription.ID = r.store.nextID("webhook_subscriptions")
+	r.store.webhooks[subscription.ID] = copyWebhook(*subscription)
+	return nil
+}
+
+func (r *memoryWebhookRepository) GetByID(ctx context.Context, id int64) (entity.WebhookSubscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subscription, ok := r.store.webhooks[id]
+	if !ok {
+		return entity.WebhookSubscription{}, sql.ErrNoRows
+	}
+	return copyWebhook(subscription), nil
+}
+
+func (r *memoryWebhookRepository) Fetch(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var subscriptions []entity.WebhookSubscription
+	for _, subscription := range r.store.webhooks {
+		subscriptions = append(subscriptions, copyWebhook(subscription))
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool { return subscriptions[i].ID < subscriptions[j].ID })
+	return subscriptions, nil
+}
+
+func (r *memoryWebhookRepository) Update(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.webhooks[subscription.ID]
+	if !ok {
+		return affected(0)
+	}
+
+	updated := copyWebhook(*subscription)
+	updated.CreatedAt = stored.CreatedAt
+	r.store.webhooks[subscription.ID] = updated
+	return nil
+}
+
+// Delete removes the subscription with its deliveries
+func (r *memoryWebhookRepository) Delete(ctx context.Context, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.webhooks[id]; !ok {
+		return affected(0)
+	}
+
+	delete(r.store.webhooks, id)
+	for deliveryID, delivery := range r.store.deliveries {
+		if delivery.SubscriptionID == id {
+			delete(r.store.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+// copyWebhook keeps the event types of the store from being shared with the caller
+func copyWebhook(subscription entity.WebhookSubscription) entity.WebhookSubscription {
+	subscription.EventTypes = append([]entity.EventType(nil), subscription.EventTypes...)
+	return subscription
+}
+
+type memoryWebhookDeliveryRepository struct {
+	store *Store
+}
+
+// NewMemoryWebhookDeliveryRepository will create new a webhookDeliveryRepository object representation of WebhookDeliveryRepository interface
+// keeping the deliveries in the store
+func NewMemoryWebhookDeliveryRepository(store *Store) pgsql.WebhookDeliveryRepository {
+	return &memoryWebhookDeliveryRepository{
+		store: store,
+	}
+}
+
+// Create adds the delivery unless the event was already delivered to the subscription, the ID is
+// then left at zero
+func (r *memoryWebhookDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.webhooks[delivery.SubscriptionID]; !ok {
+		return affected(0)
+	}
+	for _, stored := range r.store.deliveries {
+		if stored.SubscriptionID == delivery.SubscriptionID && stored.EventID == delivery.EventID {
+			return nil
+		}
+	}
+
+	delivery.ID = r.store.nextID("webhook_deliveries")
+	r.store.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *memoryWebhookDeliveryRepository) GetByID(ctx context.Context, id int64) (entity.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	delivery, ok := r.store.deliveries[id]
+	if !ok {
+		return entity.WebhookDelivery{}, sql.ErrNoRows
+	}
+	return delivery, nil
+}
+
+// FetchBySubscriptionID gives the latest deliveries first, an empty status gives them all
+func (r *memoryWebhookDeliveryRepository) FetchBySubscriptionID(ctx context.Context, subscriptionID int64, status entity.WebhookDeliveryStatus, limit int) ([]entity.WebhookDelivery, error) {
+	deliveries := r.filter(func(delivery entity.WebhookDelivery) bool {
+		return delivery.SubscriptionID == subscriptionID && (status == "" || delivery.Status == status)
+	})
+
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	return limited(deliveries, limit), nil
+}
+
+// FetchDue gives the pending deliveries whose attempt is due, oldest first
+func (r *memoryWebhookDeliveryRepository) FetchDue(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	deliveries := r.filter(func(delivery entity.WebhookDelivery) bool {
+		return delivery.Status == entity.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now)
+	})
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].NextAttemptAt.Equal(deliveries[j].NextAttemptAt) {
+			return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+		}
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	return limited(deliveries, limit), nil
+}
+
+func (r *memoryWebhookDeliveryRepository) filter(keep func(delivery entity.WebhookDelivery) bool) (deliveries []entity.WebhookDelivery) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, delivery := range r.store.deliveries {
+		if keep(delivery) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return
+}
+
+func (r *memoryWebhookDeliveryRepository) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.deliveries[delivery.ID]
+	if !ok {
+		return affected(0)
+	}
+
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.LastStatusCode = delivery.LastStatusCode
+	stored.LastError = delivery.LastError
+	stored.DeliveredAt = delivery.DeliveredAt
+	stored.UpdatedAt = delivery.UpdatedAt
+	r.store.deliveries[delivery.ID] = stored
+	return nil
+}
+
+func limited(deliveries []entity.WebhookDelivery, limit int) []entity.WebhookDelivery {
+	if len(deliveries) > limit {
+		return deliveries[:limit]
+	}
+	return deliveries
+}
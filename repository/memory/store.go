@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"carApi/entity"
+)
+
+// Store holds the tables of the in-memory repositories. Repositories sharing a store see
+// each other's rows, purging a deleted car removes its images, feature links, price history and
+// reservation the way the foreign keys of the database do.
+type Store struct {
+	mu sync.RWMutex
+	// units serializes the units of work, the repositories only take mu
+	units sync.Mutex
+
+	cars         map[int64]entity.Car
+	deletedCars  map[int64]time.Time
+	carImages    map[int64]entity.CarImage
+	features     map[int64]entity.Feature
+	carFeatures  map[int64]map[int64]struct{}
+	prices       map[int64]entity.PricePoint
+	reservations map[int64]entity.Reservation
+	catalog      map[int64]entity.CatalogEntry
+	outbox       map[int64]outboxEntry
+	webhooks     map[int64]entity.WebhookSubscription
+	deliveries   map[int64]entity.WebhookDelivery
+
+	lastID map[string]int64
+}
+
+// NewStore will create an empty store
+func NewStore() *Store {
+	return &Store{
+		cars:         map[int64]entity.Car{},
+		deletedCars:  map[int64]time.Time{},
+		carImages:    map[int64]entity.CarImage{},
+		features:     map[int64]entity.Feature{},
+		carFeatures:  map[int64]map[int64]struct{}{},
+		prices:       map[int64]entity.PricePoint{},
+		reservations: map[int64]entity.Reservation{},
+		catalog:      map[int64]entity.CatalogEntry{},
+		outbox:       map[int64]outboxEntry{},
+		webhooks:     map[int64]entity.WebhookSubscription{},
+		deliveries:   map[int64]entity.WebhookDelivery{},
+		lastID:       map[string]int64{},
+	}
+}
+
+// nextID gives the next id of the table like a SERIAL column, ids are never reused
+func (s *Store) nextID(table string) int64 {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// deleteCar removes the car and the rows referencing it
+func (s *Store) deleteCar(id int64) {
+	delete(s.cars, id)
+	delete(s.deletedCars, id)
+	delete(s.carFeatures, id)
+	delete(s.reservations, id)
+	for imageID, image := range s.carImages {
+		if image.CarID == id {
+			delete(s.carImages, imageID)
+		}
+	}
+	for pointID, point := range s.prices {
+		if point.CarID == id {
+			delete(s.prices, pointID)
+		}
+	}
+}
+
+// deleteCatalogEntry removes the entry and its descendants
+func (s *Store) deleteCatalogEntry(id int64) {
+	delete(s.catalog, id)
+	for childID, child := range s.catalog {
+		if child.ParentID == id {
+			s.deleteCatalogEntry(childID)
+		}
+	}
+}
+
+// affected mirrors the error of the pgsql repositories when an update or a delete
+// does not find its row
+func affected(n int) error {
+	if n != 1 {
+		return fmt.Errorf("weird behavior, total affected: %d", n)
+	}
+	return nil
+}
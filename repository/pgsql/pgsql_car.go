@@ -4,17 +4,25 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"carApi/entity"
+	"carApi/infrastructure/tracing"
+	"carApi/repository/transaction"
+	"github.com/lib/pq"
 )
 
 // CarRepository represent the car's repository contract
 type CarRepository interface {
 	Create(ctx context.Context, car *entity.Car) error
 	GetByID(ctx context.Context, id int64) (entity.Car, error)
-	Fetch(ctx context.Context) ([]entity.Car, error)
+	Fetch(ctx context.Context, filter entity.CarFilter, page entity.Page) ([]entity.Car, error)
+	Count(ctx context.Context, filter entity.CarFilter) (int64, error)
 	Update(ctx context.Context, car *entity.Car) error
 	Delete(ctx context.Context, id int64) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	CountByCategory(ctx context.Context) (map[string]int64, error)
 }
 
 type pgsqlCarRepository struct {
@@ -29,20 +37,47 @@ func NewPgsqlCarRepository(db *sql.DB) CarRepository {
 }
 
 func (r *pgsqlCarRepository) Create(ctx context.Context, car *entity.Car) (err error) {
-	query := "INSERT INTO cars (make, model, package, color, mileage, price, category, year, identification, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)"
-	_, err = r.db.ExecContext(ctx, query, car.Make, car.Model, car.Package, car.Color, car.Mileage, car.Price, car.Category, car.Year, car.Identification, car.CreatedAt, car.UpdatedAt)
+	query := "INSERT INTO cars (make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id"
+	ctx, span := startSpan(ctx, "INSERT", query)
+	defer func() { tracing.End(span, err) }()
+
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, car.Make, car.Model, car.Package, car.Color, car.Mileage, car.Price.Amount, car.Price.Currency, car.Category, car.Year, car.Identification, car.CreatedAt, car.UpdatedAt).Scan(&car.ID)
 	return
 }
 
+// GetByID locks the row until the end of the transaction when called within one, so the car
+// cannot change between the read and a following update or delete
 func (r *pgsqlCarRepository) GetByID(ctx context.Context, id int64) (car entity.Car, err error) {
-	query := "SELECT id, make, model, package, color, mileage, price, category, year, identification, created_at, updated_at FROM cars WHERE id = $1"
-	err = r.db.QueryRowContext(ctx, query, id).Scan(&car.ID, &car.Make, &car.Model, &car.Package, &car.Color, &car.Mileage, &car.Price, &car.Category, &car.Year, &car.Identification, &car.CreatedAt, &car.UpdatedAt)
+	query := "SELECT id, make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at FROM cars WHERE id = $1 AND deleted_at IS NULL"
+	if _, ok := transaction.FromContext(ctx); ok {
+		query += " FOR UPDATE"
+	}
+	ctx, span := startSpan(ctx, "SELECT", query)
+	defer func() { tracing.End(span, err) }()
+
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&car.ID, &car.Make, &car.Model, &car.Package, &car.Color, &car.Mileage, &car.Price.Amount, &car.Price.Currency, &car.Category, &car.Year, &car.Identification, &car.CreatedAt, &car.UpdatedAt)
 	return
 }
 
-func (r *pgsqlCarRepository) Fetch(ctx context.Context) (cars []entity.Car, err error) {
-	query := "SELECT id, make, model, package, color, mileage, price, category, year, identification, created_at, updated_at FROM cars"
-	rows, err := r.db.QueryContext(ctx, query)
+// Fetch gives the page of the cars passing the filter, in the order of their id
+func (r *pgsqlCarRepository) Fetch(ctx context.Context, filter entity.CarFilter, page entity.Page) (cars []entity.Car, err error) {
+	query := "SELECT id, make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at FROM cars"
+	where, args := buildCarFilter(filter)
+	query += " WHERE " + strings.Join(where, " AND ")
+	query += " ORDER BY id"
+	if page.Limit > 0 {
+		args = append(args, page.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if page.Offset > 0 {
+		args = append(args, page.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	ctx, span := startSpan(ctx, "SELECT", query)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return cars, err
 	}
@@ -51,7 +86,7 @@ func (r *pgsqlCarRepository) Fetch(ctx context.Context) (cars []entity.Car, err
 
 	for rows.Next() {
 		var car entity.Car
-		err := rows.Scan(&car.ID, &car.Make, &car.Model, &car.Package, &car.Color, &car.Mileage, &car.Price, &car.Category, &car.Year, &car.Identification, &car.CreatedAt, &car.UpdatedAt)
+		err := rows.Scan(&car.ID, &car.Make, &car.Model, &car.Package, &car.Color, &car.Mileage, &car.Price.Amount, &car.Price.Currency, &car.Category, &car.Year, &car.Identification, &car.CreatedAt, &car.UpdatedAt)
 		if err != nil {
 			return cars, err
 		}
@@ -62,10 +97,53 @@ func (r *pgsqlCarRepository) Fetch(ctx context.Context) (cars []entity.Car, err
 	return cars, nil
 }
 
+// Count gives the number of cars passing the filter, on every page
+func (r *pgsqlCarRepository) Count(ctx context.Context, filter entity.CarFilter) (count int64, err error) {
+	query := "SELECT COUNT(*) FROM cars"
+	where, args := buildCarFilter(filter)
+	query += " WHERE " + strings.Join(where, " AND ")
+
+	ctx, span := startSpan(ctx, "SELECT", query)
+	defer func() { tracing.End(span, err) }()
+
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&count)
+	return
+}
+
+// buildCarFilter translates the filter into WHERE conditions and their positional arguments
+func buildCarFilter(filter entity.CarFilter) (where []string, args []interface{}) {
+	where = append(where, "deleted_at IS NULL")
+
+	if len(filter.Features) > 0 {
+		args = append(args, pq.Array(filter.Features))
+		subquery := fmt.Sprintf("SELECT cf.car_id FROM car_features cf JOIN features f ON f.id = cf.feature_id WHERE f.code = ANY($%d)", len(args))
+		if filter.FeatureMatch != entity.FeatureMatchAny {
+			args = append(args, len(filter.Features))
+			subquery += fmt.Sprintf(" GROUP BY cf.car_id HAVING COUNT(DISTINCT f.id) = $%d", len(args))
+		}
+		where = append(where, "id IN ("+subquery+")")
+	}
+
+	if filter.MileageMin != nil {
+		args = append(args, *filter.MileageMin)
+		where = append(where, fmt.Sprintf("mileage >= $%d", len(args)))
+	}
+
+	if filter.MileageMax != nil {
+		args = append(args, *filter.MileageMax)
+		where = append(where, fmt.Sprintf("mileage <= $%d", len(args)))
+	}
+
+	return
+}
+
 func (r *pgsqlCarRepository) Update(ctx context.Context, car *entity.Car) (err error) {
-	//make, model, package, color, mileage, price, category, year, identification
-	query := "UPDATE cars SET make = $1, model = $2,package = $3,color = $4, mileage = $5, price = $6, category = $7, year = $8, identification = $9 , updated_at = $10 WHERE id = $11"
-	res, err := r.db.ExecContext(ctx, query, car.Make, car.Model, car.Package, car.Color, car.Mileage, car.Price, car.Category, car.Year, car.Identification, car.UpdatedAt, car.ID)
+	//make, model, package, color, mileage, price_amount, price_currency, category, year, identification
+	query := "UPDATE cars SET make = $1, model = $2, package = $3, color = $4, mileage = $5, price_amount = $6, price_currency = $7, category = $8, year = $9, identification = $10, updated_at = $11 WHERE id = $12 AND deleted_at IS NULL"
+	ctx, span := startSpan(ctx, "UPDATE", query)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, car.Make, car.Model, car.Package, car.Color, car.Mileage, car.Price.Amount, car.Price.Currency, car.Category, car.Year, car.Identification, car.UpdatedAt, car.ID)
 	if err != nil {
 		return
 	}
@@ -82,9 +160,14 @@ func (r *pgsqlCarRepository) Update(ctx context.Context, car *entity.Car) (err e
 	return
 }
 
+// Delete only marks the car as deleted, it disappears from every read but its rows stay
+// until PurgeDeleted removes them
 func (r *pgsqlCarRepository) Delete(ctx context.Context, id int64) (err error) {
-	query := "DELETE FROM cars WHERE id = $1"
-	res, err := r.db.ExecContext(ctx, query, id)
+	query := "UPDATE cars SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL"
+	ctx, span := startSpan(ctx, "UPDATE", query)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, time.Now().UTC(), id)
 	if err != nil {
 		return
 	}
@@ -100,3 +183,45 @@ func (r *pgsqlCarRepository) Delete(ctx context.Context, id int64) (err error) {
 
 	return
 }
+
+// PurgeDeleted removes for good the cars deleted before the given time, their images, features
+// and price history go with them through the foreign keys. The image files are left to the
+// usecase, which reads them first with FetchByCarsDeletedBefore.
+func (r *pgsqlCarRepository) PurgeDeleted(ctx context.Context, before time.Time) (purged int64, err error) {
+	query := "DELETE FROM cars WHERE deleted_at < $1"
+	ctx, span := startSpan(ctx, "DELETE", query)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, before.UTC())
+	if err != nil {
+		return
+	}
+
+	return res.RowsAffected()
+}
+
+func (r *pgsqlCarRepository) CountByCategory(ctx context.Context) (counts map[string]int64, err error) {
+	query := "SELECT category, COUNT(*) FROM cars WHERE deleted_at IS NULL GROUP BY category"
+	ctx, span := startSpan(ctx, "SELECT", query)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	defer rows.Close()
+
+	counts = map[string]int64{}
+	for rows.Next() {
+		var category string
+		var count int64
+		if err = rows.Scan(&category, &count); err != nil {
+			return
+		}
+
+		counts[category] = count
+	}
+
+	return counts, rows.Err()
+}
@@ -0,0 +1,130 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/transaction"
+	"github.com/lib/pq"
+)
+
+// CarImageRepository represent the car image's repository contract
+type CarImageRepository interface {
+	Create(ctx context.Context, image *entity.CarImage) error
+	GetByID(ctx context.Context, id int64) (entity.CarImage, error)
+	FetchByCarID(ctx context.Context, carID int64) ([]entity.CarImage, error)
+	FetchByCarIDs(ctx context.Context, carIDs []int64) ([]entity.CarImage, error)
+	FetchByCarsDeletedBefore(ctx context.Context, before time.Time) ([]entity.CarImage, error)
+	Update(ctx context.Context, image *entity.CarImage) error
+	SetPrimary(ctx context.Context, carID int64, id int64) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type pgsqlCarImageRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlCarImageRepository will create new a carImageRepository object representation of CarImageRepository interface
+func NewPgsqlCarImageRepository(db *sql.DB) CarImageRepository {
+	return &pgsqlCarImageRepository{
+		db: db,
+	}
+}
+
+func (r *pgsqlCarImageRepository) Create(ctx context.Context, image *entity.CarImage) (err error) {
+	query := "INSERT INTO car_images (car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, image.CarID, image.StorageKey, image.ContentType, image.Size, image.Width, image.Height, image.Position, image.IsPrimary, image.CreatedAt, image.UpdatedAt).Scan(&image.ID)
+	return
+}
+
+func (r *pgsqlCarImageRepository) GetByID(ctx context.Context, id int64) (image entity.CarImage, err error) {
+	query := "SELECT id, car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at FROM car_images WHERE id = $1"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&image.ID, &image.CarID, &image.StorageKey, &image.ContentType, &image.Size, &image.Width, &image.Height, &image.Position, &image.IsPrimary, &image.CreatedAt, &image.UpdatedAt)
+	return
+}
+
+func (r *pgsqlCarImageRepository) FetchByCarID(ctx context.Context, carID int64) ([]entity.CarImage, error) {
+	query := "SELECT id, car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at FROM car_images WHERE car_id = $1 ORDER BY position, id"
+	return r.fetch(ctx, query, carID)
+}
+
+// FetchByCarIDs gives the images of all the cars at once, grouped by car in the order of FetchByCarID
+func (r *pgsqlCarImageRepository) FetchByCarIDs(ctx context.Context, carIDs []int64) ([]entity.CarImage, error) {
+	query := "SELECT id, car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at FROM car_images WHERE car_id = ANY($1) ORDER BY car_id, position, id"
+	return r.fetch(ctx, query, pq.Array(carIDs))
+}
+
+// FetchByCarsDeletedBefore gives the images of the cars PurgeDeleted would remove at the given time
+func (r *pgsqlCarImageRepository) FetchByCarsDeletedBefore(ctx context.Context, before time.Time) ([]entity.CarImage, error) {
+	query := "SELECT id, car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at FROM car_images WHERE car_id IN (SELECT id FROM cars WHERE deleted_at < $1) ORDER BY car_id, position, id"
+	return r.fetch(ctx, query, before.UTC())
+}
+
+func (r *pgsqlCarImageRepository) fetch(ctx context.Context, query string, args ...interface{}) (images []entity.CarImage, err error) {
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return images, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var image entity.CarImage
+		err := rows.Scan(&image.ID, &image.CarID, &image.StorageKey, &image.ContentType, &image.Size, &image.Width, &image.Height, &image.Position, &image.IsPrimary, &image.CreatedAt, &image.UpdatedAt)
+		if err != nil {
+			return images, err
+		}
+
+		images = append(images, image)
+	}
+
+	return images, rows.Err()
+}
+
+func (r *pgsqlCarImageRepository) Update(ctx context.Context, image *entity.CarImage) (err error) {
+	query := "UPDATE car_images SET position = $1, is_primary = $2, updated_at = $3 WHERE id = $4"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, image.Position, image.IsPrimary, image.UpdatedAt, image.ID)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("weird behavior, total affected: %d", affect)
+	}
+
+	return
+}
+
+// SetPrimary flags one image as the primary image of a car and clears the flag on the others
+func (r *pgsqlCarImageRepository) SetPrimary(ctx context.Context, carID int64, id int64) (err error) {
+	query := "UPDATE car_images SET is_primary = (id = $2) WHERE car_id = $1"
+	_, err = transaction.Conn(ctx, r.db).ExecContext(ctx, query, carID, id)
+	return
+}
+
+func (r *pgsqlCarImageRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM car_images WHERE id = $1"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("weird behavior, total affected: %d", affect)
+	}
+
+	return
+}
@@ -0,0 +1,187 @@
+package pgsql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var carImageColumns = []string{"id", "car_id", "storage_key", "content_type", "size", "width", "height", "position", "is_primary", "created_at", "updated_at"}
+
+func TestCarImageRepo_Create(t *testing.T) {
+	image := &entity.CarImage{
+		CarID:       1,
+		StorageKey:  "cars/1/image.jpg",
+		ContentType: "image/jpeg",
+		Size:        1024,
+		Width:       640,
+		Height:      480,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "INSERT INTO car_images"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(image.CarID, image.StorageKey, image.ContentType, image.Size, image.Width, image.Height, image.Position, image.IsPrimary, image.CreatedAt, image.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	carImageRepo := pgsql.NewPgsqlCarImageRepository(db)
+	err = carImageRepo.Create(context.TODO(), image)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), image.ID)
+}
+
+func TestCarImageRepo_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows(carImageColumns).
+		AddRow(1, 1, "cars/1/image.jpg", "image/jpeg", 1024, 640, 480, 0, true, time.Now(), time.Now())
+
+	query := "SELECT id, car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at FROM car_images WHERE id = $1"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	carImageRepo := pgsql.NewPgsqlCarImageRepository(db)
+	image, err := carImageRepo.GetByID(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), image.ID)
+	assert.True(t, image.IsPrimary)
+}
+
+func TestCarImageRepo_FetchByCarID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows(carImageColumns).
+		AddRow(1, 1, "cars/1/a.jpg", "image/jpeg", 1024, 640, 480, 0, true, time.Now(), time.Now()).
+		AddRow(2, 1, "cars/1/b.png", "image/png", 2048, 800, 600, 1, false, time.Now(), time.Now())
+
+	query := "SELECT id, car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at FROM car_images WHERE car_id = $1 ORDER BY position, id"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	carImageRepo := pgsql.NewPgsqlCarImageRepository(db)
+	images, err := carImageRepo.FetchByCarID(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, images, 2)
+}
+
+func TestCarImageRepo_FetchByCarIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	carIDs := []int64{1, 2}
+	rows := sqlmock.NewRows(carImageColumns).
+		AddRow(1, 1, "cars/1/a.jpg", "image/jpeg", 1024, 640, 480, 0, true, time.Now(), time.Now()).
+		AddRow(3, 2, "cars/2/a.jpg", "image/jpeg", 1024, 640, 480, 0, true, time.Now(), time.Now())
+
+	query := "SELECT id, car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at FROM car_images WHERE car_id = ANY($1) ORDER BY car_id, position, id"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(pq.Array(carIDs)).
+		WillReturnRows(rows)
+
+	carImageRepo := pgsql.NewPgsqlCarImageRepository(db)
+	images, err := carImageRepo.FetchByCarIDs(context.TODO(), carIDs)
+	assert.NoError(t, err)
+	assert.Len(t, images, 2)
+}
+
+func TestCarImageRepo_FetchByCarsDeletedBefore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	before := time.Date(2026, 9, 19, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(carImageColumns).
+		AddRow(1, 1, "cars/1/a.jpg", "image/jpeg", 1024, 640, 480, 0, true, time.Now(), time.Now())
+
+	query := "SELECT id, car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at FROM car_images WHERE car_id IN (SELECT id FROM cars WHERE deleted_at < $1) ORDER BY car_id, position, id"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(before).
+		WillReturnRows(rows)
+
+	carImageRepo := pgsql.NewPgsqlCarImageRepository(db)
+	images, err := carImageRepo.FetchByCarsDeletedBefore(context.TODO(), before)
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+}
+
+func TestCarImageRepo_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	image := &entity.CarImage{ID: 1, Position: 2, IsPrimary: true, UpdatedAt: time.Now()}
+
+	query := "UPDATE car_images SET position = $1, is_primary = $2, updated_at = $3 WHERE id = $4"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(image.Position, image.IsPrimary, image.UpdatedAt, image.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	carImageRepo := pgsql.NewPgsqlCarImageRepository(db)
+	err = carImageRepo.Update(context.TODO(), image)
+	assert.NoError(t, err)
+}
+
+func TestCarImageRepo_SetPrimary(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "UPDATE car_images SET is_primary = (id = $2) WHERE car_id = $1"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	carImageRepo := pgsql.NewPgsqlCarImageRepository(db)
+	err = carImageRepo.SetPrimary(context.TODO(), 1, 2)
+	assert.NoError(t, err)
+}
+
+func TestCarImageRepo_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "DELETE FROM car_images WHERE id = $1"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	carImageRepo := pgsql.NewPgsqlCarImageRepository(db)
+	err = carImageRepo.Delete(context.TODO(), 1)
+	assert.NoError(t, err)
+}
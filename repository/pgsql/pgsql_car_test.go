@@ -1,13 +1,17 @@
 package pgsql_test
 
 import (
+	"bytes"
 	"context"
 	"regexp"
 	"testing"
 	"time"
 
 	"carApi/entity"
+	"carApi/infrastructure/tracing"
 	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
@@ -21,7 +25,7 @@ func TestCarRepo_Create(t *testing.T) {
 		Year:           0,
 		Category:       "Category",
 		Mileage:        0,
-		Price:          0,
+		Price:          entity.Money{Amount: 0, Currency: "USD"},
 		Identification: "Identification",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
@@ -34,13 +38,14 @@ func TestCarRepo_Create(t *testing.T) {
 	defer db.Close()
 
 	query := "INSERT INTO cars"
-	mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(car.Make, car.Model, car.Package, car.Color, car.Mileage, car.Price, car.Category, car.Year, car.Identification, car.CreatedAt, car.UpdatedAt).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(car.Make, car.Model, car.Package, car.Color, car.Mileage, car.Price.Amount, car.Price.Currency, car.Category, car.Year, car.Identification, car.CreatedAt, car.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 	carRepo := pgsql.NewPgsqlCarRepository(db)
 	err = carRepo.Create(context.TODO(), car)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(1), car.ID)
 }
 
 func TestCarRepo_GetByID(t *testing.T) {
@@ -59,16 +64,16 @@ func TestCarRepo_GetByID(t *testing.T) {
 		Year:           1,
 		Category:       "Category",
 		Mileage:        1,
-		Price:          1,
+		Price:          entity.Money{Amount: 1, Currency: "USD"},
 		Identification: "Identification",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "make", "model", "package", "color", "mileage", "price", "category", "year", "identification", "created_at", "updated_at"}).
-		AddRow(carMock.ID, carMock.Make, carMock.Model, carMock.Package, carMock.Color, carMock.Mileage, carMock.Price, carMock.Category, carMock.Year, carMock.Identification, carMock.CreatedAt, carMock.UpdatedAt)
+	rows := sqlmock.NewRows([]string{"id", "make", "model", "package", "color", "mileage", "price_amount", "price_currency", "category", "year", "identification", "created_at", "updated_at"}).
+		AddRow(carMock.ID, carMock.Make, carMock.Model, carMock.Package, carMock.Color, carMock.Mileage, carMock.Price.Amount, carMock.Price.Currency, carMock.Category, carMock.Year, carMock.Identification, carMock.CreatedAt, carMock.UpdatedAt)
 
-	query := "SELECT id, make, model, package, color, mileage, price, category, year, identification, created_at, updated_at FROM cars WHERE id = $1"
+	query := "SELECT id, make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at FROM cars WHERE id = $1 AND deleted_at IS NULL"
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
 		WithArgs(1).
 		WillReturnRows(rows)
@@ -80,6 +85,37 @@ func TestCarRepo_GetByID(t *testing.T) {
 	assert.Equal(t, carMock.ID, car.ID)
 }
 
+func TestCarRepo_GetByID_InTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "make", "model", "package", "color", "mileage", "price_amount", "price_currency", "category", "year", "identification", "created_at", "updated_at"}).
+		AddRow(1, "Make", "Model", "Package", "Color", 1, 1, "USD", "Category", 1, "Identification", time.Now(), time.Now())
+
+	query := "SELECT id, make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at FROM cars WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(1).
+		WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE cars SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL")).
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	carRepo := pgsql.NewPgsqlCarRepository(db)
+	err = transaction.NewSQLTxManager(db).WithinTransaction(context.TODO(), func(ctx context.Context) error {
+		if _, err := carRepo.GetByID(ctx, 1); err != nil {
+			return err
+		}
+		return carRepo.Delete(ctx, 1)
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCarRepo_Fetch(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -97,7 +133,7 @@ func TestCarRepo_Fetch(t *testing.T) {
 			Year:           0,
 			Category:       "Category",
 			Mileage:        0,
-			Price:          0,
+			Price:          entity.Money{Amount: 0, Currency: "USD"},
 			Identification: "Identification",
 			CreatedAt:      time.Now(),
 			UpdatedAt:      time.Now(),
@@ -111,26 +147,116 @@ func TestCarRepo_Fetch(t *testing.T) {
 			Year:           0,
 			Category:       "Category2",
 			Mileage:        0,
-			Price:          0,
+			Price:          entity.Money{Amount: 0, Currency: "USD"},
 			Identification: "Identification2",
 			CreatedAt:      time.Now(),
 			UpdatedAt:      time.Now(),
 		},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "make", "model", "package", "color", "mileage", "price", "category", "year", "identification", "created_at", "updated_at"}).
-		AddRow(mockCars[0].ID, mockCars[0].Make, mockCars[0].Model, mockCars[0].Package, mockCars[0].Color, mockCars[0].Mileage, mockCars[0].Price, mockCars[0].Category, mockCars[0].Year, mockCars[0].Identification, mockCars[0].CreatedAt, mockCars[0].UpdatedAt).
-		AddRow(mockCars[1].ID, mockCars[1].Make, mockCars[1].Model, mockCars[1].Package, mockCars[1].Color, mockCars[1].Mileage, mockCars[1].Price, mockCars[1].Category, mockCars[1].Year, mockCars[1].Identification, mockCars[1].CreatedAt, mockCars[1].UpdatedAt)
+	rows := sqlmock.NewRows([]string{"id", "make", "model", "package", "color", "mileage", "price_amount", "price_currency", "category", "year", "identification", "created_at", "updated_at"}).
+		AddRow(mockCars[0].ID, mockCars[0].Make, mockCars[0].Model, mockCars[0].Package, mockCars[0].Color, mockCars[0].Mileage, mockCars[0].Price.Amount, mockCars[0].Price.Currency, mockCars[0].Category, mockCars[0].Year, mockCars[0].Identification, mockCars[0].CreatedAt, mockCars[0].UpdatedAt).
+		AddRow(mockCars[1].ID, mockCars[1].Make, mockCars[1].Model, mockCars[1].Package, mockCars[1].Color, mockCars[1].Mileage, mockCars[1].Price.Amount, mockCars[1].Price.Currency, mockCars[1].Category, mockCars[1].Year, mockCars[1].Identification, mockCars[1].CreatedAt, mockCars[1].UpdatedAt)
 
-	query := "SELECT id, make, model, package, color, mileage, price, category, year, identification, created_at, updated_at FROM cars"
+	query := "SELECT id, make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at FROM cars"
 	mock.ExpectQuery(query).WillReturnRows(rows)
 
 	carRepo := pgsql.NewPgsqlCarRepository(db)
-	cars, err := carRepo.Fetch(context.TODO())
+	cars, err := carRepo.Fetch(context.TODO(), entity.CarFilter{}, entity.Page{})
 	assert.NoError(t, err)
 	assert.Len(t, cars, 2)
 }
 
+func TestCarRepo_FetchByFeatures(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	columns := []string{"id", "make", "model", "package", "color", "mileage", "price_amount", "price_currency", "category", "year", "identification", "created_at", "updated_at"}
+	features := []string{"heated_seats", "sunroof"}
+
+	t.Run("all", func(t *testing.T) {
+		query := "SELECT id, make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at FROM cars WHERE deleted_at IS NULL AND id IN (SELECT cf.car_id FROM car_features cf JOIN features f ON f.id = cf.feature_id WHERE f.code = ANY($1) GROUP BY cf.car_id HAVING COUNT(DISTINCT f.id) = $2) ORDER BY id"
+		mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs(pq.Array(features), 2).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Make", "Model", "Package", "Color", 0, 0, "USD", "Category", 0, "Identification", time.Now(), time.Now()))
+
+		carRepo := pgsql.NewPgsqlCarRepository(db)
+		cars, err := carRepo.Fetch(context.TODO(), entity.CarFilter{Features: features, FeatureMatch: entity.FeatureMatchAll}, entity.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, cars, 1)
+	})
+
+	t.Run("any", func(t *testing.T) {
+		query := "SELECT id, make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at FROM cars WHERE deleted_at IS NULL AND id IN (SELECT cf.car_id FROM car_features cf JOIN features f ON f.id = cf.feature_id WHERE f.code = ANY($1)) ORDER BY id"
+		mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs(pq.Array(features)).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		carRepo := pgsql.NewPgsqlCarRepository(db)
+		cars, err := carRepo.Fetch(context.TODO(), entity.CarFilter{Features: features, FeatureMatch: entity.FeatureMatchAny}, entity.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, cars, 0)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCarRepo_FetchByMileage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	columns := []string{"id", "make", "model", "package", "color", "mileage", "price_amount", "price_currency", "category", "year", "identification", "created_at", "updated_at"}
+	features := []string{"sunroof"}
+	mileageMin, mileageMax := 10000, 50000
+
+	query := "SELECT id, make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at FROM cars WHERE deleted_at IS NULL AND id IN (SELECT cf.car_id FROM car_features cf JOIN features f ON f.id = cf.feature_id WHERE f.code = ANY($1)) AND mileage >= $2 AND mileage <= $3 ORDER BY id"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(pq.Array(features), mileageMin, mileageMax).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Make", "Model", "Package", "Color", 20000, 0, "USD", "Category", 0, "Identification", time.Now(), time.Now()))
+
+	carRepo := pgsql.NewPgsqlCarRepository(db)
+	cars, err := carRepo.Fetch(context.TODO(), entity.CarFilter{Features: features, FeatureMatch: entity.FeatureMatchAny, MileageMin: &mileageMin, MileageMax: &mileageMax}, entity.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, cars, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCarRepo_FetchPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	columns := []string{"id", "make", "model", "package", "color", "mileage", "price_amount", "price_currency", "category", "year", "identification", "created_at", "updated_at"}
+	mileageMax := 50000
+
+	query := "SELECT id, make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at FROM cars WHERE deleted_at IS NULL AND mileage <= $1 ORDER BY id LIMIT $2 OFFSET $3"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(mileageMax, 20, 40).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(41, "Make", "Model", "Package", "Color", 20000, 0, "USD", "Category", 0, "Identification", time.Now(), time.Now()))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM cars WHERE deleted_at IS NULL AND mileage <= $1")).
+		WithArgs(mileageMax).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(41))
+
+	carRepo := pgsql.NewPgsqlCarRepository(db)
+	filter := entity.CarFilter{MileageMax: &mileageMax}
+	cars, err := carRepo.Fetch(context.TODO(), filter, entity.Page{Limit: 20, Offset: 40})
+	assert.NoError(t, err)
+	assert.Len(t, cars, 1)
+
+	count, err := carRepo.Count(context.TODO(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(41), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCarRepo_Update(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -147,15 +273,15 @@ func TestCarRepo_Update(t *testing.T) {
 		Year:           0,
 		Category:       "Category",
 		Mileage:        0,
-		Price:          0,
+		Price:          entity.Money{Amount: 0, Currency: "USD"},
 		Identification: "Identification",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
 
-	query := "UPDATE cars SET make = $1, model = $2,package = $3,color = $4, mileage = $5, price = $6, category = $7, year = $8, identification = $9 , updated_at = $10 WHERE id = $11"
+	query := "UPDATE cars SET make = $1, model = $2, package = $3, color = $4, mileage = $5, price_amount = $6, price_currency = $7, category = $8, year = $9, identification = $10, updated_at = $11 WHERE id = $12 AND deleted_at IS NULL"
 	mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(carMock.Make, carMock.Model, carMock.Package, carMock.Color, carMock.Mileage, carMock.Price, carMock.Category, carMock.Year, carMock.Identification, carMock.UpdatedAt, carMock.ID).
+		WithArgs(carMock.Make, carMock.Model, carMock.Package, carMock.Color, carMock.Mileage, carMock.Price.Amount, carMock.Price.Currency, carMock.Category, carMock.Year, carMock.Identification, carMock.UpdatedAt, carMock.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	carRepo := pgsql.NewPgsqlCarRepository(db)
@@ -170,12 +296,76 @@ func TestCarRepo_Delete(t *testing.T) {
 	}
 	defer db.Close()
 
-	query := "DELETE FROM cars WHERE id = $1"
+	query := "UPDATE cars SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL"
 	mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(1).
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	carRepo := pgsql.NewPgsqlCarRepository(db)
+	err = carRepo.Delete(context.TODO(), 1)
+	assert.NoError(t, err)
+}
+
+func TestCarRepo_PurgeDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	before := time.Date(2026, 9, 19, 0, 0, 0, 0, time.UTC)
+	query := "DELETE FROM cars WHERE deleted_at < $1"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	carRepo := pgsql.NewPgsqlCarRepository(db)
+	purged, err := carRepo.PurgeDeleted(context.TODO(), before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
+
+func TestCarRepo_CountByCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"category", "count"}).
+		AddRow("Sedan", 3).
+		AddRow("SUV", 1)
+
+	query := "SELECT category, COUNT(*) FROM cars WHERE deleted_at IS NULL GROUP BY category"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(rows)
+
+	carRepo := pgsql.NewPgsqlCarRepository(db)
+	counts, err := carRepo.CountByCategory(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"Sedan": 3, "SUV": 1}, counts)
+}
+
+func TestCarRepo_Span(t *testing.T) {
+	var buf bytes.Buffer
+	provider, err := tracing.NewStdoutTracerProvider(&buf)
+	assert.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+	tracing.Setup(provider)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "UPDATE cars SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(sqlmock.AnyArg(), 1).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	carRepo := pgsql.NewPgsqlCarRepository(db)
 	err = carRepo.Delete(context.TODO(), 1)
 	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `"Name":"pgsql UPDATE"`)
+	assert.Contains(t, buf.String(), `"Value":"UPDATE cars SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL"`)
 }
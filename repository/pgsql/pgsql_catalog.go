@@ -0,0 +1,104 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"carApi/entity"
+	"carApi/repository/transaction"
+)
+
+// CatalogRepository represent the reference catalog's repository contract,
+// parentID is 0 for the root kinds (makes and categories)
+type CatalogRepository interface {
+	Create(ctx context.Context, entry *entity.CatalogEntry) error
+	GetByName(ctx context.Context, kind string, parentID int64, name string) (entity.CatalogEntry, error)
+	Fetch(ctx context.Context, kind string, parentID int64) ([]entity.CatalogEntry, error)
+	Update(ctx context.Context, entry *entity.CatalogEntry) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type pgsqlCatalogRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlCatalogRepository will create new a catalogRepository object representation of CatalogRepository interface
+func NewPgsqlCatalogRepository(db *sql.DB) CatalogRepository {
+	return &pgsqlCatalogRepository{
+		db: db,
+	}
+}
+
+func (r *pgsqlCatalogRepository) Create(ctx context.Context, entry *entity.CatalogEntry) (err error) {
+	query := "INSERT INTO catalog_entries (kind, parent_id, name, created_at, updated_at) VALUES ($1, NULLIF($2, 0), $3, $4, $5) RETURNING id"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, entry.Kind, entry.ParentID, entry.Name, entry.CreatedAt, entry.UpdatedAt).Scan(&entry.ID)
+	return
+}
+
+// GetByName looks the entry up case-insensitively
+func (r *pgsqlCatalogRepository) GetByName(ctx context.Context, kind string, parentID int64, name string) (entry entity.CatalogEntry, err error) {
+	query := "SELECT id, kind, COALESCE(parent_id, 0), name, created_at, updated_at FROM catalog_entries WHERE kind = $1 AND COALESCE(parent_id, 0) = $2 AND LOWER(name) = LOWER($3)"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, kind, parentID, name).Scan(&entry.ID, &entry.Kind, &entry.ParentID, &entry.Name, &entry.CreatedAt, &entry.UpdatedAt)
+	return
+}
+
+func (r *pgsqlCatalogRepository) Fetch(ctx context.Context, kind string, parentID int64) (entries []entity.CatalogEntry, err error) {
+	query := "SELECT id, kind, COALESCE(parent_id, 0), name, created_at, updated_at FROM catalog_entries WHERE kind = $1 AND COALESCE(parent_id, 0) = $2 ORDER BY name"
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, kind, parentID)
+	if err != nil {
+		return entries, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry entity.CatalogEntry
+		err := rows.Scan(&entry.ID, &entry.Kind, &entry.ParentID, &entry.Name, &entry.CreatedAt, &entry.UpdatedAt)
+		if err != nil {
+			return entries, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *pgsqlCatalogRepository) Update(ctx context.Context, entry *entity.CatalogEntry) (err error) {
+	query := "UPDATE catalog_entries SET name = $1, updated_at = $2 WHERE id = $3"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, entry.Name, entry.UpdatedAt, entry.ID)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("weird behavior, total affected: %d", affect)
+	}
+
+	return
+}
+
+func (r *pgsqlCatalogRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM catalog_entries WHERE id = $1"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("weird behavior, total affected: %d", affect)
+	}
+
+	return
+}
@@ -0,0 +1,117 @@
+package pgsql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var catalogColumns = []string{"id", "kind", "parent_id", "name", "created_at", "updated_at"}
+
+func TestCatalogRepo_Create(t *testing.T) {
+	entry := &entity.CatalogEntry{
+		Kind:      entity.CatalogKindModel,
+		ParentID:  1,
+		Name:      "Corolla",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "INSERT INTO catalog_entries (kind, parent_id, name, created_at, updated_at) VALUES ($1, NULLIF($2, 0), $3, $4, $5) RETURNING id"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(entry.Kind, entry.ParentID, entry.Name, entry.CreatedAt, entry.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+	catalogRepo := pgsql.NewPgsqlCatalogRepository(db)
+	err = catalogRepo.Create(context.TODO(), entry)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), entry.ID)
+}
+
+func TestCatalogRepo_GetByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "SELECT id, kind, COALESCE(parent_id, 0), name, created_at, updated_at FROM catalog_entries WHERE kind = $1 AND COALESCE(parent_id, 0) = $2 AND LOWER(name) = LOWER($3)"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(entity.CatalogKindMake, 0, "TOYOTA").
+		WillReturnRows(sqlmock.NewRows(catalogColumns).AddRow(1, entity.CatalogKindMake, 0, "Toyota", time.Now(), time.Now()))
+
+	catalogRepo := pgsql.NewPgsqlCatalogRepository(db)
+	entry, err := catalogRepo.GetByName(context.TODO(), entity.CatalogKindMake, 0, "TOYOTA")
+	assert.NoError(t, err)
+	assert.Equal(t, "Toyota", entry.Name)
+}
+
+func TestCatalogRepo_Fetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows(catalogColumns).
+		AddRow(2, entity.CatalogKindModel, 1, "Camry", time.Now(), time.Now()).
+		AddRow(3, entity.CatalogKindModel, 1, "Corolla", time.Now(), time.Now())
+
+	query := "SELECT id, kind, COALESCE(parent_id, 0), name, created_at, updated_at FROM catalog_entries WHERE kind = $1 AND COALESCE(parent_id, 0) = $2 ORDER BY name"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(entity.CatalogKindModel, 1).
+		WillReturnRows(rows)
+
+	catalogRepo := pgsql.NewPgsqlCatalogRepository(db)
+	entries, err := catalogRepo.Fetch(context.TODO(), entity.CatalogKindModel, 1)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestCatalogRepo_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	entry := &entity.CatalogEntry{ID: 1, Name: "Toyota", UpdatedAt: time.Now()}
+
+	query := "UPDATE catalog_entries SET name = $1, updated_at = $2 WHERE id = $3"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(entry.Name, entry.UpdatedAt, entry.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	catalogRepo := pgsql.NewPgsqlCatalogRepository(db)
+	err = catalogRepo.Update(context.TODO(), entry)
+	assert.NoError(t, err)
+}
+
+func TestCatalogRepo_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "DELETE FROM catalog_entries WHERE id = $1"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	catalogRepo := pgsql.NewPgsqlCatalogRepository(db)
+	err = catalogRepo.Delete(context.TODO(), 1)
+	assert.NoError(t, err)
+}
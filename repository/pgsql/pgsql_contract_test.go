@@ -0,0 +1,56 @@
+package pgsql_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"carApi/config"
+	"carApi/infrastructure/migrator"
+	"carApi/migration"
+	"carApi/repository/pgsql"
+	"carApi/repository/repositorytest"
+	"carApi/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDatabaseEnv names a disposable database the contract suite runs against, every
+// table is truncated between tests so it must not hold data worth keeping
+const TestDatabaseEnv = "TEST_DATABASE_URL"
+
+func TestPgsqlRepositories_Contract(t *testing.T) {
+	url := os.Getenv(TestDatabaseEnv)
+	if url == "" {
+		t.Skipf("%s is not set", TestDatabaseEnv)
+	}
+
+	db, err := sql.Open("postgres", url)
+	require.NoError(t, err)
+	defer db.Close()
+
+	appLogger := logger.NewApiLogger(config.Default())
+	appLogger.InitLogger()
+	m, err := migrator.NewMigrator(db, migration.Files, time.Minute, appLogger)
+	require.NoError(t, err)
+	require.NoError(t, m.Up())
+	require.NoError(t, m.Close())
+
+	repositorytest.TestRepositories(t, func(t *testing.T) repositorytest.Repositories {
+		_, err := db.Exec("TRUNCATE cars, car_images, features, car_features, car_price_history, car_reservations, catalog_entries, outbox, webhook_subscriptions, webhook_deliveries RESTART IDENTITY CASCADE")
+		require.NoError(t, err)
+
+		return repositorytest.Repositories{
+			Cars:         pgsql.NewPgsqlCarRepository(db),
+			CarImages:    pgsql.NewPgsqlCarImageRepository(db),
+			Features:     pgsql.NewPgsqlFeatureRepository(db),
+			PriceHistory: pgsql.NewPgsqlPriceHistoryRepository(db),
+			Reservations: pgsql.NewPgsqlReservationRepository(db),
+			Catalog:      pgsql.NewPgsqlCatalogRepository(db),
+			Outbox:       pgsql.NewPgsqlOutboxRepository(db),
+
+			Webhooks:          pgsql.NewPgsqlWebhookRepository(db),
+			WebhookDeliveries: pgsql.NewPgsqlWebhookDeliveryRepository(db),
+		}
+	})
+}
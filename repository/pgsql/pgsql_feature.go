@@ -0,0 +1,168 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"carApi/entity"
+	"carApi/repository/transaction"
+	"github.com/lib/pq"
+)
+
+// FeatureRepository represent the feature's repository contract
+type FeatureRepository interface {
+	Create(ctx context.Context, feature *entity.Feature) error
+	GetByID(ctx context.Context, id int64) (entity.Feature, error)
+	GetByCode(ctx context.Context, code string) (entity.Feature, error)
+	FetchByCodes(ctx context.Context, codes []string) ([]entity.Feature, error)
+	Fetch(ctx context.Context) ([]entity.Feature, error)
+	Update(ctx context.Context, feature *entity.Feature) error
+	Delete(ctx context.Context, id int64) error
+	FetchByCarID(ctx context.Context, carID int64) ([]entity.Feature, error)
+	FetchByCarIDs(ctx context.Context, carIDs []int64) (map[int64][]entity.Feature, error)
+	AttachToCar(ctx context.Context, carID int64, featureIDs []int64) error
+	DetachFromCar(ctx context.Context, carID int64, featureID int64) error
+}
+
+type pgsqlFeatureRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlFeatureRepository will create new a featureRepository object representation of FeatureRepository interface
+func NewPgsqlFeatureRepository(db *sql.DB) FeatureRepository {
+	return &pgsqlFeatureRepository{
+		db: db,
+	}
+}
+
+func (r *pgsqlFeatureRepository) Create(ctx context.Context, feature *entity.Feature) (err error) {
+	query := "INSERT INTO features (code, name, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, feature.Code, feature.Name, feature.CreatedAt, feature.UpdatedAt).Scan(&feature.ID)
+	return
+}
+
+func (r *pgsqlFeatureRepository) GetByID(ctx context.Context, id int64) (feature entity.Feature, err error) {
+	query := "SELECT id, code, name, created_at, updated_at FROM features WHERE id = $1"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&feature.ID, &feature.Code, &feature.Name, &feature.CreatedAt, &feature.UpdatedAt)
+	return
+}
+
+func (r *pgsqlFeatureRepository) GetByCode(ctx context.Context, code string) (feature entity.Feature, err error) {
+	query := "SELECT id, code, name, created_at, updated_at FROM features WHERE code = $1"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, code).Scan(&feature.ID, &feature.Code, &feature.Name, &feature.CreatedAt, &feature.UpdatedAt)
+	return
+}
+
+func (r *pgsqlFeatureRepository) FetchByCodes(ctx context.Context, codes []string) ([]entity.Feature, error) {
+	query := "SELECT id, code, name, created_at, updated_at FROM features WHERE code = ANY($1) ORDER BY code"
+	return r.fetch(ctx, query, pq.Array(codes))
+}
+
+func (r *pgsqlFeatureRepository) Fetch(ctx context.Context) ([]entity.Feature, error) {
+	query := "SELECT id, code, name, created_at, updated_at FROM features ORDER BY code"
+	return r.fetch(ctx, query)
+}
+
+func (r *pgsqlFeatureRepository) FetchByCarID(ctx context.Context, carID int64) ([]entity.Feature, error) {
+	query := "SELECT f.id, f.code, f.name, f.created_at, f.updated_at FROM features f JOIN car_features cf ON cf.feature_id = f.id WHERE cf.car_id = $1 ORDER BY f.code"
+	return r.fetch(ctx, query, carID)
+}
+
+// FetchByCarIDs gives the features of all the cars at once keyed by car, a car without
+// features has no entry
+func (r *pgsqlFeatureRepository) FetchByCarIDs(ctx context.Context, carIDs []int64) (features map[int64][]entity.Feature, err error) {
+	query := "SELECT cf.car_id, f.id, f.code, f.name, f.created_at, f.updated_at FROM features f JOIN car_features cf ON cf.feature_id = f.id WHERE cf.car_id = ANY($1) ORDER BY cf.car_id, f.code"
+	features = map[int64][]entity.Feature{}
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, pq.Array(carIDs))
+	if err != nil {
+		return features, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var carID int64
+		var feature entity.Feature
+		err := rows.Scan(&carID, &feature.ID, &feature.Code, &feature.Name, &feature.CreatedAt, &feature.UpdatedAt)
+		if err != nil {
+			return features, err
+		}
+
+		features[carID] = append(features[carID], feature)
+	}
+
+	return features, rows.Err()
+}
+
+func (r *pgsqlFeatureRepository) fetch(ctx context.Context, query string, args ...interface{}) (features []entity.Feature, err error) {
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return features, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var feature entity.Feature
+		err := rows.Scan(&feature.ID, &feature.Code, &feature.Name, &feature.CreatedAt, &feature.UpdatedAt)
+		if err != nil {
+			return features, err
+		}
+
+		features = append(features, feature)
+	}
+
+	return features, rows.Err()
+}
+
+func (r *pgsqlFeatureRepository) Update(ctx context.Context, feature *entity.Feature) (err error) {
+	query := "UPDATE features SET code = $1, name = $2, updated_at = $3 WHERE id = $4"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, feature.Code, feature.Name, feature.UpdatedAt, feature.ID)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("weird behavior, total affected: %d", affect)
+	}
+
+	return
+}
+
+func (r *pgsqlFeatureRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM features WHERE id = $1"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("weird behavior, total affected: %d", affect)
+	}
+
+	return
+}
+
+// AttachToCar links the features to a car, links that already exist are kept as they are
+func (r *pgsqlFeatureRepository) AttachToCar(ctx context.Context, carID int64, featureIDs []int64) (err error) {
+	query := "INSERT INTO car_features (car_id, feature_id) SELECT $1, unnest($2::integer[]) ON CONFLICT DO NOTHING"
+	_, err = transaction.Conn(ctx, r.db).ExecContext(ctx, query, carID, pq.Array(featureIDs))
+	return
+}
+
+func (r *pgsqlFeatureRepository) DetachFromCar(ctx context.Context, carID int64, featureID int64) (err error) {
+	query := "DELETE FROM car_features WHERE car_id = $1 AND feature_id = $2"
+	_, err = transaction.Conn(ctx, r.db).ExecContext(ctx, query, carID, featureID)
+	return
+}
@@ -0,0 +1,195 @@
+package pgsql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var featureColumns = []string{"id", "code", "name", "created_at", "updated_at"}
+
+func TestFeatureRepo_Create(t *testing.T) {
+	feature := &entity.Feature{
+		Code:      "sunroof",
+		Name:      "Sunroof",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "INSERT INTO features"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(feature.Code, feature.Name, feature.CreatedAt, feature.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	featureRepo := pgsql.NewPgsqlFeatureRepository(db)
+	err = featureRepo.Create(context.TODO(), feature)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), feature.ID)
+}
+
+func TestFeatureRepo_GetByCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "SELECT id, code, name, created_at, updated_at FROM features WHERE code = $1"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs("sunroof").
+		WillReturnRows(sqlmock.NewRows(featureColumns).AddRow(1, "sunroof", "Sunroof", time.Now(), time.Now()))
+
+	featureRepo := pgsql.NewPgsqlFeatureRepository(db)
+	feature, err := featureRepo.GetByCode(context.TODO(), "sunroof")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), feature.ID)
+}
+
+func TestFeatureRepo_FetchByCodes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	codes := []string{"heated_seats", "sunroof"}
+	rows := sqlmock.NewRows(featureColumns).
+		AddRow(1, "heated_seats", "Heated seats", time.Now(), time.Now()).
+		AddRow(2, "sunroof", "Sunroof", time.Now(), time.Now())
+
+	query := "SELECT id, code, name, created_at, updated_at FROM features WHERE code = ANY($1) ORDER BY code"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(pq.Array(codes)).
+		WillReturnRows(rows)
+
+	featureRepo := pgsql.NewPgsqlFeatureRepository(db)
+	features, err := featureRepo.FetchByCodes(context.TODO(), codes)
+	assert.NoError(t, err)
+	assert.Len(t, features, 2)
+}
+
+func TestFeatureRepo_FetchByCarID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "SELECT f.id, f.code, f.name, f.created_at, f.updated_at FROM features f JOIN car_features cf ON cf.feature_id = f.id WHERE cf.car_id = $1 ORDER BY f.code"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(featureColumns).AddRow(2, "sunroof", "Sunroof", time.Now(), time.Now()))
+
+	featureRepo := pgsql.NewPgsqlFeatureRepository(db)
+	features, err := featureRepo.FetchByCarID(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, features, 1)
+}
+
+func TestFeatureRepo_FetchByCarIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	carIDs := []int64{1, 2}
+	rows := sqlmock.NewRows(append([]string{"car_id"}, featureColumns...)).
+		AddRow(1, 2, "sunroof", "Sunroof", time.Now(), time.Now()).
+		AddRow(1, 3, "towbar", "Towbar", time.Now(), time.Now()).
+		AddRow(2, 2, "sunroof", "Sunroof", time.Now(), time.Now())
+
+	query := "SELECT cf.car_id, f.id, f.code, f.name, f.created_at, f.updated_at FROM features f JOIN car_features cf ON cf.feature_id = f.id WHERE cf.car_id = ANY($1) ORDER BY cf.car_id, f.code"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(pq.Array(carIDs)).
+		WillReturnRows(rows)
+
+	featureRepo := pgsql.NewPgsqlFeatureRepository(db)
+	features, err := featureRepo.FetchByCarIDs(context.TODO(), carIDs)
+	assert.NoError(t, err)
+	assert.Len(t, features[1], 2)
+	assert.Len(t, features[2], 1)
+}
+
+func TestFeatureRepo_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	feature := &entity.Feature{ID: 1, Code: "sunroof", Name: "Panoramic sunroof", UpdatedAt: time.Now()}
+
+	query := "UPDATE features SET code = $1, name = $2, updated_at = $3 WHERE id = $4"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(feature.Code, feature.Name, feature.UpdatedAt, feature.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	featureRepo := pgsql.NewPgsqlFeatureRepository(db)
+	err = featureRepo.Update(context.TODO(), feature)
+	assert.NoError(t, err)
+}
+
+func TestFeatureRepo_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "DELETE FROM features WHERE id = $1"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	featureRepo := pgsql.NewPgsqlFeatureRepository(db)
+	err = featureRepo.Delete(context.TODO(), 1)
+	assert.NoError(t, err)
+}
+
+func TestFeatureRepo_AttachToCar(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "INSERT INTO car_features (car_id, feature_id) SELECT $1, unnest($2::integer[]) ON CONFLICT DO NOTHING"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(1, pq.Array([]int64{1, 2})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	featureRepo := pgsql.NewPgsqlFeatureRepository(db)
+	err = featureRepo.AttachToCar(context.TODO(), 1, []int64{1, 2})
+	assert.NoError(t, err)
+}
+
+func TestFeatureRepo_DetachFromCar(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "DELETE FROM car_features WHERE car_id = $1 AND feature_id = $2"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	featureRepo := pgsql.NewPgsqlFeatureRepository(db)
+	err = featureRepo.DetachFromCar(context.TODO(), 1, 2)
+	assert.NoError(t, err)
+}
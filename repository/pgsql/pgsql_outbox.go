@@ -0,0 +1,95 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/tracing"
+	"carApi/repository/transaction"
+)
+
+// OutboxRepository represent the outbox's repository contract, events are added in the
+// transaction of the write they describe and marked once published
+type OutboxRepository interface {
+	Add(ctx context.Context, event *entity.Event) error
+	FetchPending(ctx context.Context, limit int) ([]entity.Event, error)
+	MarkPublished(ctx context.Context, id int64, publishedAt time.Time) error
+}
+
+type pgsqlOutboxRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlOutboxRepository will create new an outboxRepository object representation of OutboxRepository interface
+func NewPgsqlOutboxRepository(db *sql.DB) OutboxRepository {
+	return &pgsqlOutboxRepository{
+		db: db,
+	}
+}
+
+func (r *pgsqlOutboxRepository) Add(ctx context.Context, event *entity.Event) (err error) {
+	query := "INSERT INTO outbox (event_type, car_id, payload, occurred_at) VALUES ($1, $2, $3, $4) RETURNING id"
+	ctx, span := startSpan(ctx, "INSERT", query)
+	defer func() { tracing.End(span, err) }()
+
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, event.Type, event.CarID, []byte(event.Payload), event.OccurredAt).Scan(&event.ID)
+	return
+}
+
+// FetchPending gives the oldest unpublished events in id order. Within a transaction the rows
+// stay locked until it ends so a second relay waits instead of publishing them again.
+// The writes of a car are serialized by its row lock, so its events get increasing ids in the
+// order they were committed.
+func (r *pgsqlOutboxRepository) FetchPending(ctx context.Context, limit int) (events []entity.Event, err error) {
+	query := "SELECT id, event_type, car_id, payload, occurred_at FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT $1"
+	if _, ok := transaction.FromContext(ctx); ok {
+		query += " FOR UPDATE"
+	}
+	ctx, span := startSpan(ctx, "SELECT", query)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, limit)
+	if err != nil {
+		return events, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var event entity.Event
+		var payload []byte
+		err := rows.Scan(&event.ID, &event.Type, &event.CarID, &payload, &event.OccurredAt)
+		if err != nil {
+			return events, err
+		}
+
+		event.Payload = payload
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *pgsqlOutboxRepository) MarkPublished(ctx context.Context, id int64, publishedAt time.Time) (err error) {
+	query := "UPDATE outbox SET published_at = $1 WHERE id = $2"
+	ctx, span := startSpan(ctx, "UPDATE", query)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, publishedAt, id)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("weird behavior, total affected: %d", affect)
+	}
+	return
+}
@@ -0,0 +1,109 @@
+package pgsql_test
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestOutboxRepo_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	event := &entity.Event{
+		Type:       entity.EventCarDeleted,
+		CarID:      1,
+		Payload:    json.RawMessage(`{"car_id":1}`),
+		OccurredAt: time.Now(),
+	}
+
+	query := "INSERT INTO outbox"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(event.Type, event.CarID, []byte(event.Payload), event.OccurredAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	outboxRepo := pgsql.NewPgsqlOutboxRepository(db)
+	err = outboxRepo.Add(context.TODO(), event)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), event.ID)
+}
+
+func TestOutboxRepo_FetchPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	occurredAt := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "event_type", "car_id", "payload", "occurred_at"}).
+		AddRow(1, "car.created", 1, []byte(`{"car":{"id":1}}`), occurredAt).
+		AddRow(2, "car.deleted", 1, []byte(`{"car_id":1}`), occurredAt)
+
+	t.Run("success", func(t *testing.T) {
+		query := "SELECT id, event_type, car_id, payload, occurred_at FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT $1"
+		mock.ExpectQuery("^" + regexp.QuoteMeta(query) + "$").WithArgs(10).WillReturnRows(rows)
+
+		outboxRepo := pgsql.NewPgsqlOutboxRepository(db)
+		events, err := outboxRepo.FetchPending(context.TODO(), 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Event{
+			{ID: 1, Type: entity.EventCarCreated, CarID: 1, Payload: json.RawMessage(`{"car":{"id":1}}`), OccurredAt: occurredAt},
+			{ID: 2, Type: entity.EventCarDeleted, CarID: 1, Payload: json.RawMessage(`{"car_id":1}`), OccurredAt: occurredAt},
+		}, events)
+	})
+
+	t.Run("in-transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("ORDER BY id LIMIT $1 FOR UPDATE")).WithArgs(10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "car_id", "payload", "occurred_at"}))
+		mock.ExpectCommit()
+
+		outboxRepo := pgsql.NewPgsqlOutboxRepository(db)
+		err := transaction.NewSQLTxManager(db).WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			events, err := outboxRepo.FetchPending(ctx, 10)
+			assert.Empty(t, events)
+			return err
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOutboxRepo_MarkPublished(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	publishedAt := time.Now()
+	query := "UPDATE outbox SET published_at = $1 WHERE id = $2"
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(publishedAt, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		outboxRepo := pgsql.NewPgsqlOutboxRepository(db)
+		err := outboxRepo.MarkPublished(context.TODO(), 1, publishedAt)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error-missing", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(publishedAt, 404).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		outboxRepo := pgsql.NewPgsqlOutboxRepository(db)
+		err := outboxRepo.MarkPublished(context.TODO(), 404, publishedAt)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,57 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+
+	"carApi/entity"
+	"carApi/repository/transaction"
+	"github.com/lib/pq"
+)
+
+// PriceHistoryRepository represent the price history's repository contract, the history of a
+// car is removed along with it
+type PriceHistoryRepository interface {
+	Add(ctx context.Context, point *entity.PricePoint) error
+	FetchByCarIDs(ctx context.Context, carIDs []int64) ([]entity.PricePoint, error)
+}
+
+type pgsqlPriceHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlPriceHistoryRepository will create new a priceHistoryRepository object representation of PriceHistoryRepository interface
+func NewPgsqlPriceHistoryRepository(db *sql.DB) PriceHistoryRepository {
+	return &pgsqlPriceHistoryRepository{
+		db: db,
+	}
+}
+
+func (r *pgsqlPriceHistoryRepository) Add(ctx context.Context, point *entity.PricePoint) (err error) {
+	query := "INSERT INTO car_price_history (car_id, price_amount, price_currency, changed_at) VALUES ($1, $2, $3, $4) RETURNING id"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, point.CarID, point.Price.Amount, point.Price.Currency, point.ChangedAt).Scan(&point.ID)
+	return
+}
+
+// FetchByCarIDs gives the history of all the cars at once, grouped by car from the oldest price
+func (r *pgsqlPriceHistoryRepository) FetchByCarIDs(ctx context.Context, carIDs []int64) (points []entity.PricePoint, err error) {
+	query := "SELECT id, car_id, price_amount, price_currency, changed_at FROM car_price_history WHERE car_id = ANY($1) ORDER BY car_id, changed_at, id"
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, pq.Array(carIDs))
+	if err != nil {
+		return points, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var point entity.PricePoint
+		err := rows.Scan(&point.ID, &point.CarID, &point.Price.Amount, &point.Price.Currency, &point.ChangedAt)
+		if err != nil {
+			return points, err
+		}
+
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
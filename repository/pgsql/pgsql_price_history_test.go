@@ -0,0 +1,61 @@
+package pgsql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var pricePointColumns = []string{"id", "car_id", "price_amount", "price_currency", "changed_at"}
+
+func TestPriceHistoryRepo_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	point := &entity.PricePoint{CarID: 1, Price: entity.Money{Amount: 1500000, Currency: "USD"}, ChangedAt: time.Now()}
+
+	query := "INSERT INTO car_price_history (car_id, price_amount, price_currency, changed_at) VALUES ($1, $2, $3, $4) RETURNING id"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(point.CarID, point.Price.Amount, point.Price.Currency, point.ChangedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	priceHistoryRepo := pgsql.NewPgsqlPriceHistoryRepository(db)
+	err = priceHistoryRepo.Add(context.TODO(), point)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), point.ID)
+}
+
+func TestPriceHistoryRepo_FetchByCarIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	carIDs := []int64{1, 2}
+	rows := sqlmock.NewRows(pricePointColumns).
+		AddRow(1, 1, 1500000, "USD", time.Now()).
+		AddRow(3, 1, 1400000, "USD", time.Now()).
+		AddRow(2, 2, 900000, "EUR", time.Now())
+
+	query := "SELECT id, car_id, price_amount, price_currency, changed_at FROM car_price_history WHERE car_id = ANY($1) ORDER BY car_id, changed_at, id"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(pq.Array(carIDs)).
+		WillReturnRows(rows)
+
+	priceHistoryRepo := pgsql.NewPgsqlPriceHistoryRepository(db)
+	points, err := priceHistoryRepo.FetchByCarIDs(context.TODO(), carIDs)
+	assert.NoError(t, err)
+	assert.Len(t, points, 3)
+	assert.Equal(t, entity.Money{Amount: 900000, Currency: "EUR"}, points[2].Price)
+}
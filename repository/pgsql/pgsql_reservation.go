@@ -0,0 +1,61 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"carApi/entity"
+	"carApi/repository/transaction"
+)
+
+// ReservationRepository represent the reservation's repository contract, a car has at most one
+// reservation which is removed along with the car
+type ReservationRepository interface {
+	GetByCarID(ctx context.Context, carID int64) (entity.Reservation, error)
+	Save(ctx context.Context, reservation *entity.Reservation) error
+	Delete(ctx context.Context, carID int64) error
+}
+
+type pgsqlReservationRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlReservationRepository will create new a reservationRepository object representation of ReservationRepository interface
+func NewPgsqlReservationRepository(db *sql.DB) ReservationRepository {
+	return &pgsqlReservationRepository{
+		db: db,
+	}
+}
+
+func (r *pgsqlReservationRepository) GetByCarID(ctx context.Context, carID int64) (reservation entity.Reservation, err error) {
+	query := "SELECT car_id, rep, reserved_at, expires_at FROM car_reservations WHERE car_id = $1"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, carID).Scan(&reservation.CarID, &reservation.Rep, &reservation.ReservedAt, &reservation.ExpiresAt)
+	return
+}
+
+// Save stores the reservation in place of the one the car had, if any
+func (r *pgsqlReservationRepository) Save(ctx context.Context, reservation *entity.Reservation) (err error) {
+	query := "INSERT INTO car_reservations (car_id, rep, reserved_at, expires_at) VALUES ($1, $2, $3, $4) ON CONFLICT (car_id) DO UPDATE SET rep = EXCLUDED.rep, reserved_at = EXCLUDED.reserved_at, expires_at = EXCLUDED.expires_at"
+	_, err = transaction.Conn(ctx, r.db).ExecContext(ctx, query, reservation.CarID, reservation.Rep, reservation.ReservedAt, reservation.ExpiresAt)
+	return
+}
+
+func (r *pgsqlReservationRepository) Delete(ctx context.Context, carID int64) (err error) {
+	query := "DELETE FROM car_reservations WHERE car_id = $1"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, carID)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("weird behavior, total affected: %d", affect)
+	}
+
+	return
+}
@@ -0,0 +1,69 @@
+package pgsql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestReservationRepo_GetByCarID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	reservedAt := time.Now()
+	rows := sqlmock.NewRows([]string{"car_id", "rep", "reserved_at", "expires_at"}).
+		AddRow(1, "jane", reservedAt, reservedAt.Add(time.Hour))
+
+	query := "SELECT car_id, rep, reserved_at, expires_at FROM car_reservations WHERE car_id = $1"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).WillReturnRows(rows)
+
+	reservationRepo := pgsql.NewPgsqlReservationRepository(db)
+	reservation, err := reservationRepo.GetByCarID(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Reservation{CarID: 1, Rep: "jane", ReservedAt: reservedAt, ExpiresAt: reservedAt.Add(time.Hour)}, reservation)
+}
+
+func TestReservationRepo_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	reservation := &entity.Reservation{CarID: 1, Rep: "jane", ReservedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+
+	query := "INSERT INTO car_reservations (car_id, rep, reserved_at, expires_at) VALUES ($1, $2, $3, $4) ON CONFLICT (car_id) DO UPDATE SET rep = EXCLUDED.rep, reserved_at = EXCLUDED.reserved_at, expires_at = EXCLUDED.expires_at"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(reservation.CarID, reservation.Rep, reservation.ReservedAt, reservation.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	reservationRepo := pgsql.NewPgsqlReservationRepository(db)
+	err = reservationRepo.Save(context.TODO(), reservation)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReservationRepo_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "DELETE FROM car_reservations WHERE car_id = $1"
+	mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reservationRepo := pgsql.NewPgsqlReservationRepository(db)
+	assert.NoError(t, reservationRepo.Delete(context.TODO(), 1))
+	assert.Error(t, reservationRepo.Delete(context.TODO(), 2))
+}
@@ -0,0 +1,21 @@
+package pgsql
+
+import (
+	"context"
+
+	"carApi/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan opens a client span for a statement, literals are stripped from the recorded query
+func startSpan(ctx context.Context, operation string, query string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "pgsql "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.query.text", tracing.SanitizeSQL(query)),
+		),
+	)
+}
@@ -0,0 +1,121 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"carApi/entity"
+	"carApi/repository/transaction"
+	"github.com/lib/pq"
+)
+
+// WebhookRepository represent the webhook subscription's repository contract
+type WebhookRepository interface {
+	Create(ctx context.Context, subscription *entity.WebhookSubscription) error
+	GetByID(ctx context.Context, id int64) (entity.WebhookSubscription, error)
+	Fetch(ctx context.Context) ([]entity.WebhookSubscription, error)
+	Update(ctx context.Context, subscription *entity.WebhookSubscription) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type pgsqlWebhookRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlWebhookRepository will create new a webhookRepository object representation of WebhookRepository interface
+func NewPgsqlWebhookRepository(db *sql.DB) WebhookRepository {
+	return &pgsqlWebhookRepository{
+		db: db,
+	}
+}
+
+func (r *pgsqlWebhookRepository) Create(ctx context.Context, subscription *entity.WebhookSubscription) (err error) {
+	query := "INSERT INTO webhook_subscriptions (url, event_types, secret, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, subscription.URL, pq.Array(eventTypeStrings(subscription.EventTypes)), subscription.Secret, subscription.CreatedAt, subscription.UpdatedAt).Scan(&subscription.ID)
+	return
+}
+
+func (r *pgsqlWebhookRepository) GetByID(ctx context.Context, id int64) (subscription entity.WebhookSubscription, err error) {
+	query := "SELECT id, url, event_types, secret, created_at, updated_at FROM webhook_subscriptions WHERE id = $1"
+	var eventTypes []string
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&subscription.ID, &subscription.URL, pq.Array(&eventTypes), &subscription.Secret, &subscription.CreatedAt, &subscription.UpdatedAt)
+	subscription.EventTypes = toEventTypes(eventTypes)
+	return
+}
+
+func (r *pgsqlWebhookRepository) Fetch(ctx context.Context) (subscriptions []entity.WebhookSubscription, err error) {
+	query := "SELECT id, url, event_types, secret, created_at, updated_at FROM webhook_subscriptions ORDER BY id"
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return subscriptions, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var subscription entity.WebhookSubscription
+		var eventTypes []string
+		err := rows.Scan(&subscription.ID, &subscription.URL, pq.Array(&eventTypes), &subscription.Secret, &subscription.CreatedAt, &subscription.UpdatedAt)
+		if err != nil {
+			return subscriptions, err
+		}
+
+		subscription.EventTypes = toEventTypes(eventTypes)
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *pgsqlWebhookRepository) Update(ctx context.Context, subscription *entity.WebhookSubscription) (err error) {
+	query := "UPDATE webhook_subscriptions SET url = $1, event_types = $2, secret = $3, updated_at = $4 WHERE id = $5"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, subscription.URL, pq.Array(eventTypeStrings(subscription.EventTypes)), subscription.Secret, subscription.UpdatedAt, subscription.ID)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("weird behavior, total affected: %d", affect)
+	}
+	return
+}
+
+func (r *pgsqlWebhookRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM webhook_subscriptions WHERE id = $1"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("weird behavior, total affected: %d", affect)
+	}
+	return
+}
+
+func eventTypeStrings(eventTypes []entity.EventType) []string {
+	values := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		values[i] = string(eventType)
+	}
+	return values
+}
+
+func toEventTypes(values []string) []entity.EventType {
+	eventTypes := make([]entity.EventType, len(values))
+	for i, value := range values {
+		eventTypes[i] = entity.EventType(value)
+	}
+	return eventTypes
+}
@@ -0,0 +1,123 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/transaction"
+)
+
+// WebhookDeliveryRepository represent the webhook delivery's repository contract
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *entity.WebhookDelivery) error
+	GetByID(ctx context.Context, id int64) (entity.WebhookDelivery, error)
+	FetchBySubscriptionID(ctx context.Context, subscriptionID int64, status entity.WebhookDeliveryStatus, limit int) ([]entity.WebhookDelivery, error)
+	FetchDue(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error)
+	Update(ctx context.Context, delivery *entity.WebhookDelivery) error
+}
+
+const webhookDeliveryColumns = "id, subscription_id, event_id, event_type, body, status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at, updated_at"
+
+type pgsqlWebhookDeliveryRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlWebhookDeliveryRepository will create new a webhookDeliveryRepository object representation of WebhookDeliveryRepository interface
+func NewPgsqlWebhookDeliveryRepository(db *sql.DB) WebhookDeliveryRepository {
+	return &pgsqlWebhookDeliveryRepository{
+		db: db,
+	}
+}
+
+// Create adds the delivery unless the event was already delivered to the subscription, the ID is
+// then left at zero
+func (r *pgsqlWebhookDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) (err error) {
+	query := "INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, body, status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) ON CONFLICT (subscription_id, event_id) DO NOTHING RETURNING id"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, delivery.SubscriptionID, delivery.EventID, delivery.EventType, []byte(delivery.Body), delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt, delivery.CreatedAt, delivery.UpdatedAt).Scan(&delivery.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	return
+}
+
+func (r *pgsqlWebhookDeliveryRepository) GetByID(ctx context.Context, id int64) (delivery entity.WebhookDelivery, err error) {
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE id = $1"
+	err = scanWebhookDelivery(transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, id), &delivery)
+	return
+}
+
+// FetchBySubscriptionID gives the latest deliveries first, an empty status gives them all
+func (r *pgsqlWebhookDeliveryRepository) FetchBySubscriptionID(ctx context.Context, subscriptionID int64, status entity.WebhookDeliveryStatus, limit int) ([]entity.WebhookDelivery, error) {
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE subscription_id = $1 AND ($2 = '' OR status = $2) ORDER BY id DESC LIMIT $3"
+	return r.fetch(ctx, query, subscriptionID, status, limit)
+}
+
+// FetchDue gives the pending deliveries whose attempt is due, oldest first. Within a transaction
+// the rows stay locked until it ends and the ones locked by another dispatcher are skipped.
+func (r *pgsqlWebhookDeliveryRepository) FetchDue(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE status = $1 AND next_attempt_at <= $2 ORDER BY next_attempt_at, id LIMIT $3"
+	if _, ok := transaction.FromContext(ctx); ok {
+		query += " FOR UPDATE SKIP LOCKED"
+	}
+	return r.fetch(ctx, query, entity.WebhookDeliveryPending, now, limit)
+}
+
+func (r *pgsqlWebhookDeliveryRepository) fetch(ctx context.Context, query string, args ...interface{}) (deliveries []entity.WebhookDelivery, err error) {
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return deliveries, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var delivery entity.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &delivery); err != nil {
+			return deliveries, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *pgsqlWebhookDeliveryRepository) Update(ctx context.Context, delivery *entity.WebhookDelivery) (err error) {
+	query := "UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5, delivered_at = $6, updated_at = $7 WHERE id = $8"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt, delivery.UpdatedAt, delivery.ID)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("weird behavior, total affected: %d", affect)
+	}
+	return
+}
+
+// scanWebhookDelivery reads the webhookDeliveryColumns of a row
+func scanWebhookDelivery(row interface {
+	Scan(dest ...interface{}) error
+}, delivery *entity.WebhookDelivery) error {
+	var body []byte
+	var deliveredAt sql.NullTime
+	err := row.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &body, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastStatusCode, &delivery.LastError, &deliveredAt, &delivery.CreatedAt, &delivery.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	delivery.Body = body
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return nil
+}
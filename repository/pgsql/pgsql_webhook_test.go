@@ -0,0 +1,264 @@
+package pgsql_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var webhookColumns = []string{"id", "url", "event_types", "secret", "created_at", "updated_at"}
+
+var webhookDeliveryColumns = []string{"id", "subscription_id", "event_id", "event_type", "body", "status", "attempts", "next_attempt_at", "last_status_code", "last_error", "delivered_at", "created_at", "updated_at"}
+
+func TestWebhookRepo_Create(t *testing.T) {
+	subscription := &entity.WebhookSubscription{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []entity.EventType{entity.EventCarCreated, entity.EventCarDeleted},
+		Secret:     "0123456789abcdef",
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "INSERT INTO webhook_subscriptions"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(subscription.URL, pq.Array([]string{"car.created", "car.deleted"}), subscription.Secret, subscription.CreatedAt, subscription.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
+	webhookRepo := pgsql.NewPgsqlWebhookRepository(db)
+	err = webhookRepo.Create(context.TODO(), subscription)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), subscription.ID)
+}
+
+func TestWebhookRepo_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "SELECT id, url, event_types, secret, created_at, updated_at FROM webhook_subscriptions WHERE id = $1"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows(webhookColumns).AddRow(4, "https://partner.example.com/hooks", "{car.created,car.deleted}", "0123456789abcdef", time.Now(), time.Now()))
+
+	webhookRepo := pgsql.NewPgsqlWebhookRepository(db)
+	subscription, err := webhookRepo.GetByID(context.TODO(), 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.EventType{entity.EventCarCreated, entity.EventCarDeleted}, subscription.EventTypes)
+}
+
+func TestWebhookRepo_Fetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows(webhookColumns).
+		AddRow(1, "https://a.example.com/hooks", "{car.created}", "0123456789abcdef", time.Now(), time.Now()).
+		AddRow(2, "https://b.example.com/hooks", "{car.price_changed}", "fedcba9876543210", time.Now(), time.Now())
+
+	query := "SELECT id, url, event_types, secret, created_at, updated_at FROM webhook_subscriptions ORDER BY id"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(rows)
+
+	webhookRepo := pgsql.NewPgsqlWebhookRepository(db)
+	subscriptions, err := webhookRepo.Fetch(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 2)
+}
+
+func TestWebhookRepo_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	subscription := &entity.WebhookSubscription{
+		ID:         4,
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []entity.EventType{entity.EventCarPriceChanged},
+		Secret:     "0123456789abcdef",
+		UpdatedAt:  time.Now(),
+	}
+
+	query := "UPDATE webhook_subscriptions SET url = $1, event_types = $2, secret = $3, updated_at = $4 WHERE id = $5"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(subscription.URL, pq.Array([]string{"car.price_changed"}), subscription.Secret, subscription.UpdatedAt, subscription.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	webhookRepo := pgsql.NewPgsqlWebhookRepository(db)
+	err = webhookRepo.Update(context.TODO(), subscription)
+	assert.NoError(t, err)
+}
+
+func TestWebhookRepo_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "DELETE FROM webhook_subscriptions WHERE id = $1"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	webhookRepo := pgsql.NewPgsqlWebhookRepository(db)
+	err = webhookRepo.Delete(context.TODO(), 4)
+	assert.NoError(t, err)
+}
+
+func TestWebhookDeliveryRepo_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	newDelivery := func() *entity.WebhookDelivery {
+		return &entity.WebhookDelivery{
+			SubscriptionID: 4,
+			EventID:        7,
+			EventType:      entity.EventCarDeleted,
+			Body:           json.RawMessage(`{"id":7}`),
+			Status:         entity.WebhookDeliveryPending,
+		}
+	}
+
+	query := "INSERT INTO webhook_deliveries"
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+
+		delivery := newDelivery()
+		webhookDeliveryRepo := pgsql.NewPgsqlWebhookDeliveryRepository(db)
+		err := webhookDeliveryRepo.Create(context.TODO(), delivery)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(9), delivery.ID)
+	})
+
+	t.Run("already-delivered", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("ON CONFLICT (subscription_id, event_id) DO NOTHING")).
+			WillReturnError(sql.ErrNoRows)
+
+		delivery := newDelivery()
+		webhookDeliveryRepo := pgsql.NewPgsqlWebhookDeliveryRepository(db)
+		err := webhookDeliveryRepo.Create(context.TODO(), delivery)
+		assert.NoError(t, err)
+		assert.Zero(t, delivery.ID)
+	})
+}
+
+func TestWebhookDeliveryRepo_FetchBySubscriptionID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	deliveredAt := time.Now()
+	rows := sqlmock.NewRows(webhookDeliveryColumns).
+		AddRow(2, 4, 8, "car.deleted", []byte(`{"id":8}`), "succeeded", 1, deliveredAt, 204, "", deliveredAt, deliveredAt, deliveredAt).
+		AddRow(1, 4, 7, "car.created", []byte(`{"id":7}`), "dead", 8, deliveredAt, 500, "unexpected status 500", nil, deliveredAt, deliveredAt)
+
+	query := "FROM webhook_deliveries WHERE subscription_id = $1 AND ($2 = '' OR status = $2) ORDER BY id DESC LIMIT $3"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(4, "", 50).
+		WillReturnRows(rows)
+
+	webhookDeliveryRepo := pgsql.NewPgsqlWebhookDeliveryRepository(db)
+	deliveries, err := webhookDeliveryRepo.FetchBySubscriptionID(context.TODO(), 4, "", 50)
+	assert.NoError(t, err)
+	if assert.Len(t, deliveries, 2) {
+		assert.Equal(t, &deliveredAt, deliveries[0].DeliveredAt)
+		assert.Equal(t, json.RawMessage(`{"id":8}`), deliveries[0].Body)
+		assert.Nil(t, deliveries[1].DeliveredAt)
+		assert.Equal(t, entity.WebhookDeliveryDead, deliveries[1].Status)
+	}
+}
+
+func TestWebhookDeliveryRepo_FetchDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		query := "WHERE status = $1 AND next_attempt_at <= $2 ORDER BY next_attempt_at, id LIMIT $3"
+		mock.ExpectQuery(regexp.QuoteMeta(query)+"$").
+			WithArgs(entity.WebhookDeliveryPending, now, 10).
+			WillReturnRows(sqlmock.NewRows(webhookDeliveryColumns).
+				AddRow(1, 4, 7, "car.created", []byte(`{"id":7}`), "pending", 0, now, 0, "", nil, now, now))
+
+		webhookDeliveryRepo := pgsql.NewPgsqlWebhookDeliveryRepository(db)
+		deliveries, err := webhookDeliveryRepo.FetchDue(context.TODO(), now, 10)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+	})
+
+	t.Run("in-transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("LIMIT $3 FOR UPDATE SKIP LOCKED")).
+			WithArgs(entity.WebhookDeliveryPending, now, 10).
+			WillReturnRows(sqlmock.NewRows(webhookDeliveryColumns))
+		mock.ExpectCommit()
+
+		webhookDeliveryRepo := pgsql.NewPgsqlWebhookDeliveryRepository(db)
+		err := transaction.NewSQLTxManager(db).WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			deliveries, err := webhookDeliveryRepo.FetchDue(ctx, now, 10)
+			assert.Empty(t, deliveries)
+			return err
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookDeliveryRepo_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	delivery := &entity.WebhookDelivery{
+		ID:             9,
+		Status:         entity.WebhookDeliveryPending,
+		Attempts:       2,
+		NextAttemptAt:  time.Now(),
+		LastStatusCode: 503,
+		LastError:      "unexpected status 503",
+		UpdatedAt:      time.Now(),
+	}
+
+	query := "UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5, delivered_at = $6, updated_at = $7 WHERE id = $8"
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError, nil, delivery.UpdatedAt, delivery.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	webhookDeliveryRepo := pgsql.NewPgsqlWebhookDeliveryRepository(db)
+	err = webhookDeliveryRepo.Update(context.TODO(), delivery)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"carApi/infrastructure/tracing"
+	"github.com/go-redis/redis"
+)
+
+const presenceKeyPrefix = "carapi:presence:car:"
+
+// PresenceRepository represent the sales reps viewing the cars, a presence lapses after its
+// ttl unless it is joined again
+type PresenceRepository interface {
+	Join(ctx context.Context, carID int64, rep string, ttl time.Duration) (joined bool, err error)
+	Leave(ctx context.Context, carID int64, rep string) (left bool, err error)
+	Viewers(ctx context.Context, carID int64) ([]string, error)
+}
+
+type presenceRepository struct {
+	client *redis.Client
+}
+
+// NewPresenceRepository will create an object that represent the PresenceRepository interface,
+// the viewers of a car are a sorted set scored by the time their presence lapses
+func NewPresenceRepository(client *redis.Client) PresenceRepository {
+	return &presenceRepository{
+		client: client,
+	}
+}
+
+// Join marks rep as viewing the car for ttl, joined tells rep was not viewing it already. The
+// key lives as long as its latest presence so an abandoned car leaves nothing behind.
+func (r *presenceRepository) Join(ctx context.Context, carID int64, rep string, ttl time.Duration) (joined bool, err error) {
+	key := presenceKey(carID)
+	ctx, span := startSpan(ctx, "ZADD", key)
+	defer func() { tracing.End(span, err) }()
+
+	now := time.Now()
+	var added *redis.IntCmd
+	_, err = r.client.WithContext(ctx).TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(key, "-inf", score(now))
+		added = pipe.ZAdd(key, redis.Z{Score: float64(now.Add(ttl).UnixMilli()), Member: rep})
+		pipe.PExpire(key, ttl)
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	return added.Val() == 1, nil
+}
+
+// Leave ends the presence of rep on the car, left tells rep was viewing it
+func (r *presenceRepository) Leave(ctx context.Context, carID int64, rep string) (left bool, err error) {
+	key := presenceKey(carID)
+	ctx, span := startSpan(ctx, "ZREM", key)
+	defer func() { tracing.End(span, err) }()
+
+	removed, err := r.client.WithContext(ctx).ZRem(key, rep).Result()
+	if err != nil {
+		return
+	}
+	return removed == 1, nil
+}
+
+// Viewers gives the reps viewing the car sorted by name, lapsed presences are left out
+func (r *presenceRepository) Viewers(ctx context.Context, carID int64) (viewers []string, err error) {
+	key := presenceKey(carID)
+	ctx, span := startSpan(ctx, "ZRANGEBYSCORE", key)
+	defer func() { tracing.End(span, err) }()
+
+	viewers, err = r.client.WithContext(ctx).ZRangeByScore(key, redis.ZRangeBy{Min: "(" + score(time.Now()), Max: "+inf"}).Result()
+	if err != nil {
+		return
+	}
+	sort.Strings(viewers)
+	return viewers, nil
+}
+
+func presenceKey(carID int64) string {
+	return presenceKeyPrefix + strconv.FormatInt(carID, 10)
+}
+
+func score(t time.Time) string {
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
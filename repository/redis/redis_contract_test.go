@@ -0,0 +1,45 @@
+package redis_test
+
+import (
+	"testing"
+
+	"carApi/infrastructure/metrics"
+	redisRepo "carApi/repository/redis"
+	"carApi/repository/repositorytest"
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis"
+)
+
+func TestRedisRepository_Contract(t *testing.T) {
+	repositorytest.TestCache(t, func(t *testing.T) repositorytest.Cache {
+		mr, err := miniredis.Run()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub redis connection", err)
+		}
+		t.Cleanup(mr.Close)
+
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+
+		return repositorytest.Cache{
+			Repository: redisRepo.NewRedisRepository(client, metrics.NewMetrics()),
+			// miniredis only expires keys when told time has passed
+			Elapse: mr.FastForward,
+		}
+	})
+}
+
+func TestPresenceRepository_Contract(t *testing.T) {
+	repositorytest.TestPresence(t, func(t *testing.T) redisRepo.PresenceRepository {
+		mr, err := miniredis.Run()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub redis connection", err)
+		}
+		t.Cleanup(mr.Close)
+
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+
+		return redisRepo.NewPresenceRepository(client)
+	})
+}
@@ -1,34 +1,89 @@
 package redis
 
 import (
+	"context"
 	"time"
 
+	"carApi/infrastructure/metrics"
+	"carApi/infrastructure/tracing"
 	"github.com/go-redis/redis"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // RedisRepository represent the redis repositories
 type RedisRepository interface {
-	Set(key string, value interface{}, exp time.Duration) error
-	Get(key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, exp time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
+	Incr(ctx context.Context, key string) (int64, error)
 }
 
 type redisRepository struct {
-	client *redis.Client
+	client  *redis.Client
+	metrics *metrics.Metrics
 }
 
 // NewRedisRepository will create an object that represent the RedisRepository interface
-func NewRedisRepository(client *redis.Client) RedisRepository {
+func NewRedisRepository(client *redis.Client, metrics *metrics.Metrics) RedisRepository {
 	return &redisRepository{
-		client: client,
+		client:  client,
+		metrics: metrics,
 	}
 }
 
 // Set attaches the redis repository and set the data
-func (r *redisRepository) Set(key string, value interface{}, exp time.Duration) error {
-	return r.client.Set(key, value, exp).Err()
+func (r *redisRepository) Set(ctx context.Context, key string, value interface{}, exp time.Duration) error {
+	ctx, span := startSpan(ctx, "SET", key)
+	err := r.client.WithContext(ctx).Set(key, value, exp).Err()
+	tracing.End(span, err)
+	if err != nil {
+		r.metrics.ObserveCache(metrics.CacheOperationSet, metrics.CacheResultError)
+		return err
+	}
+
+	r.metrics.ObserveCache(metrics.CacheOperationSet, metrics.CacheResultOK)
+	return nil
+}
+
+// Get attaches the redis repository and get the data, a missing key is a miss and not an error in the metrics
+func (r *redisRepository) Get(ctx context.Context, key string) (string, error) {
+	ctx, span := startSpan(ctx, "GET", key)
+	value, err := r.client.WithContext(ctx).Get(key).Result()
+	span.SetAttributes(attribute.Bool("cache.hit", err == nil))
+	if err == redis.Nil {
+		tracing.End(span, nil)
+	} else {
+		tracing.End(span, err)
+	}
+
+	switch {
+	case err == redis.Nil:
+		r.metrics.ObserveCache(metrics.CacheOperationGet, metrics.CacheResultMiss)
+	case err != nil:
+		r.metrics.ObserveCache(metrics.CacheOperationGet, metrics.CacheResultError)
+	default:
+		r.metrics.ObserveCache(metrics.CacheOperationGet, metrics.CacheResultHit)
+	}
+
+	return value, err
+}
+
+// Incr increments the counter under the key and gives its new value, a missing key counts from zero
+func (r *redisRepository) Incr(ctx context.Context, key string) (value int64, err error) {
+	ctx, span := startSpan(ctx, "INCR", key)
+	defer func() { tracing.End(span, err) }()
+
+	value, err = r.client.WithContext(ctx).Incr(key).Result()
+	return
 }
 
-// Get attaches the redis repository and get the data
-func (r *redisRepository) Get(key string) (string, error) {
-	return r.client.Get(key).Result()
+func startSpan(ctx context.Context, operation string, key string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "redis "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation.name", operation),
+			attribute.String("cache.key", key),
+		),
+	)
 }
@@ -1,17 +1,20 @@
 package redis_test
 
 import (
+	"context"
 	"log"
 	"testing"
 	"time"
 
+	"carApi/infrastructure/metrics"
 	redisRepo "carApi/repository/redis"
 	"github.com/alicebob/miniredis"
 	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
-func SetupRedis() redisRepo.RedisRepository {
+func SetupRedis() (redisRepo.RedisRepository, *metrics.Metrics) {
 	mr, err := miniredis.Run()
 	if err != nil {
 		log.Fatalf("an error '%s' was not expected when opening a stub redis connection", err)
@@ -21,28 +24,49 @@ func SetupRedis() redisRepo.RedisRepository {
 		Addr: mr.Addr(),
 	})
 
-	redisRepository := redisRepo.NewRedisRepository(client)
-	return redisRepository
+	appMetrics := metrics.NewMetrics()
+	redisRepository := redisRepo.NewRedisRepository(client, appMetrics)
+	return redisRepository, appMetrics
 }
 
 func TestSet(t *testing.T) {
-	redisRepository := SetupRedis()
-	err := redisRepository.Set("ping", "pong", time.Duration(0))
+	redisRepository, appMetrics := SetupRedis()
+	err := redisRepository.Set(context.TODO(), "ping", "pong", time.Duration(0))
 	assert.NoError(t, err)
+	assert.Equal(t, 1.0, testutil.ToFloat64(appMetrics.CacheRequests.WithLabelValues(metrics.CacheOperationSet, metrics.CacheResultOK)))
 }
 
 func TestGet(t *testing.T) {
-	redisRepository := SetupRedis()
+	redisRepository, appMetrics := SetupRedis()
 	key, val, exp := "ping", "pong", time.Duration(0)
 
-	value, err := redisRepository.Get(key)
+	value, err := redisRepository.Get(context.TODO(), key)
 	assert.NotNil(t, err)
 	assert.Equal(t, value, "")
 
-	err = redisRepository.Set(key, val, exp)
+	err = redisRepository.Set(context.TODO(), key, val, exp)
 	assert.NoError(t, err)
 
-	value, err = redisRepository.Get(key)
+	value, err = redisRepository.Get(context.TODO(), key)
 	assert.NoError(t, err)
 	assert.Equal(t, value, val)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(appMetrics.CacheRequests.WithLabelValues(metrics.CacheOperationGet, metrics.CacheResultMiss)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(appMetrics.CacheRequests.WithLabelValues(metrics.CacheOperationGet, metrics.CacheResultHit)))
+}
+
+func TestIncr(t *testing.T) {
+	redisRepository, _ := SetupRedis()
+
+	value, err := redisRepository.Incr(context.TODO(), "cars:listing:version")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), value)
+
+	value, err = redisRepository.Incr(context.TODO(), "cars:listing:version")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), value)
+
+	stored, err := redisRepository.Get(context.TODO(), "cars:listing:version")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", stored)
 }
@@ -0,0 +1,105 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	redisRepository "carApi/repository/redis"
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Cache is the cache under test, Elapse lets the given duration pass for the TTLs
+type Cache struct {
+	Repository redisRepository.RedisRepository
+	Elapse     func(d time.Duration)
+}
+
+// TestCache runs the cache suite, setup is called for every test and must give an empty cache
+func TestCache(t *testing.T, setup func(t *testing.T) Cache) {
+	tests := map[string]func(t *testing.T, cache Cache){
+		"get-missing":  testCacheGetMissing,
+		"set-get":      testCacheSetGet,
+		"ttl":          testCacheTTL,
+		"incr":         testCacheIncr,
+		"set-replaces": testCacheSetReplaces,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, setup(t))
+		})
+	}
+}
+
+func testCacheGetMissing(t *testing.T, cache Cache) {
+	_, err := cache.Repository.Get(context.Background(), "missing")
+	assert.Equal(t, redis.Nil, err)
+}
+
+func testCacheSetGet(t *testing.T, cache Cache) {
+	ctx := context.Background()
+	require.NoError(t, cache.Repository.Set(ctx, "text", "pong", 0))
+	require.NoError(t, cache.Repository.Set(ctx, "bytes", []byte(`[{"id":1}]`), 0))
+	require.NoError(t, cache.Repository.Set(ctx, "number", 42, 0))
+
+	for key, want := range map[string]string{"text": "pong", "bytes": `[{"id":1}]`, "number": "42"} {
+		value, err := cache.Repository.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, want, value)
+	}
+}
+
+func testCacheTTL(t *testing.T, cache Cache) {
+	ctx := context.Background()
+	require.NoError(t, cache.Repository.Set(ctx, "short", "value", 50*time.Millisecond))
+	require.NoError(t, cache.Repository.Set(ctx, "long", "value", time.Hour))
+	require.NoError(t, cache.Repository.Set(ctx, "forever", "value", 0))
+
+	value, err := cache.Repository.Get(ctx, "short")
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	cache.Elapse(100 * time.Millisecond)
+
+	_, err = cache.Repository.Get(ctx, "short")
+	assert.Equal(t, redis.Nil, err)
+	_, err = cache.Repository.Get(ctx, "long")
+	assert.NoError(t, err)
+	_, err = cache.Repository.Get(ctx, "forever")
+	assert.NoError(t, err)
+}
+
+func testCacheIncr(t *testing.T, cache Cache) {
+	ctx := context.Background()
+	value, err := cache.Repository.Incr(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), value, "a missing key counts from zero")
+
+	require.NoError(t, cache.Repository.Set(ctx, "counter", 41, 0))
+	value, err = cache.Repository.Incr(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), value)
+
+	stored, err := cache.Repository.Get(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, "42", stored)
+
+	require.NoError(t, cache.Repository.Set(ctx, "text", "pong", 0))
+	_, err = cache.Repository.Incr(ctx, "text")
+	assert.Error(t, err, "a value that is not an integer cannot be incremented")
+}
+
+func testCacheSetReplaces(t *testing.T, cache Cache) {
+	ctx := context.Background()
+	require.NoError(t, cache.Repository.Set(ctx, "key", "old", 50*time.Millisecond))
+	require.NoError(t, cache.Repository.Set(ctx, "key", "new", 0))
+
+	cache.Elapse(100 * time.Millisecond)
+
+	value, err := cache.Repository.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "new", value, "setting a key again replaces its TTL")
+}
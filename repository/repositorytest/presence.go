@@ -0,0 +1,94 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	redisRepository "carApi/repository/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPresence runs the presence suite, setup is called for every test and must give a
+// repository without presences. The presences lapse on the wall clock.
+func TestPresence(t *testing.T, setup func(t *testing.T) redisRepository.PresenceRepository) {
+	tests := map[string]func(t *testing.T, repo redisRepository.PresenceRepository){
+		"join-leave": testPresenceJoinLeave,
+		"ttl":        testPresenceTTL,
+		"per-car":    testPresencePerCar,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, setup(t))
+		})
+	}
+}
+
+func testPresenceJoinLeave(t *testing.T, repo redisRepository.PresenceRepository) {
+	ctx := context.Background()
+
+	viewers, err := repo.Viewers(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, viewers)
+
+	joined, err := repo.Join(ctx, 1, "rep-b", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, joined)
+	joined, err = repo.Join(ctx, 1, "rep-a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, joined)
+	joined, err = repo.Join(ctx, 1, "rep-b", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, joined, "joining again only refreshes the presence")
+
+	viewers, err = repo.Viewers(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rep-a", "rep-b"}, viewers)
+
+	left, err := repo.Leave(ctx, 1, "rep-b")
+	require.NoError(t, err)
+	assert.True(t, left)
+	left, err = repo.Leave(ctx, 1, "rep-b")
+	require.NoError(t, err)
+	assert.False(t, left)
+
+	viewers, err = repo.Viewers(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rep-a"}, viewers)
+}
+
+func testPresenceTTL(t *testing.T, repo redisRepository.PresenceRepository) {
+	ctx := context.Background()
+	_, err := repo.Join(ctx, 1, "short", 50*time.Millisecond)
+	require.NoError(t, err)
+	_, err = repo.Join(ctx, 1, "long", time.Minute)
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	viewers, err := repo.Viewers(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"long"}, viewers)
+
+	joined, err := repo.Join(ctx, 1, "short", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, joined, "a lapsed presence is joined anew")
+}
+
+func testPresencePerCar(t *testing.T, repo redisRepository.PresenceRepository) {
+	ctx := context.Background()
+	_, err := repo.Join(ctx, 1, "rep-a", time.Minute)
+	require.NoError(t, err)
+	_, err = repo.Join(ctx, 2, "rep-b", time.Minute)
+	require.NoError(t, err)
+
+	viewers, err := repo.Viewers(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rep-b"}, viewers)
+
+	left, err := repo.Leave(ctx, 2, "rep-a")
+	require.NoError(t, err)
+	assert.False(t, left)
+}
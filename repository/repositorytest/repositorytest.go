@@ -0,0 +1,907 @@
+// Package repositorytest is the behavior every repository implementation must have, the
+// pgsql and the in-memory repositories run the same suite so they can be swapped.
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Repositories are the repositories under test, they share the same datastore
+type Repositories struct {
+	Cars         pgsql.CarRepository
+	CarImages    pgsql.CarImageRepository
+	Features     pgsql.FeatureRepository
+	PriceHistory pgsql.PriceHistoryRepository
+	Reservations pgsql.ReservationRepository
+	Catalog      pgsql.CatalogRepository
+	Outbox       pgsql.OutboxRepository
+
+	Webhooks          pgsql.WebhookRepository
+	WebhookDeliveries pgsql.WebhookDeliveryRepository
+}
+
+// TestRepositories runs the suite, setup is called for every test and must give
+// repositories over an empty datastore
+func TestRepositories(t *testing.T, setup func(t *testing.T) Repositories) {
+	tests := map[string]func(t *testing.T, repos Repositories){
+		"car-create-get":              testCarCreateGet,
+		"car-get-missing":             testCarGetMissing,
+		"car-update":                  testCarUpdate,
+		"car-update-delete-missing":   testCarUpdateDeleteMissing,
+		"car-soft-delete":             testCarSoftDelete,
+		"car-purge-cascade":           testCarPurgeCascade,
+		"car-purge-retention":         testCarPurgeRetention,
+		"car-fetch-order":             testCarFetchOrder,
+		"car-fetch-mileage":           testCarFetchMileage,
+		"car-fetch-features":          testCarFetchFeatures,
+		"car-fetch-page":              testCarFetchPage,
+		"car-count":                   testCarCount,
+		"car-count-by-category":       testCarCountByCategory,
+		"car-concurrent-create":       testCarConcurrentCreate,
+		"car-image-order-and-primary": testCarImageOrderAndPrimary,
+		"car-image-update-delete":     testCarImageUpdateDelete,
+		"car-image-fetch-by-cars":     testCarImageFetchByCars,
+		"feature-lookup":              testFeatureLookup,
+		"feature-attach-detach":       testFeatureAttachDetach,
+		"feature-fetch-by-cars":       testFeatureFetchByCars,
+		"price-history-order":         testPriceHistoryOrder,
+		"price-history-cascade":       testPriceHistoryCascade,
+		"reservation-save-get":        testReservationSaveGet,
+		"reservation-delete":          testReservationDelete,
+		"reservation-cascade":         testReservationCascade,
+		"catalog-lookup":              testCatalogLookup,
+		"catalog-delete-cascade":      testCatalogDeleteCascade,
+		"outbox-pending-order":        testOutboxPendingOrder,
+		"outbox-mark-published":       testOutboxMarkPublished,
+		"webhook-crud":                testWebhookCRUD,
+		"webhook-delivery-once":       testWebhookDeliveryOnce,
+		"webhook-delivery-due":        testWebhookDeliveryDue,
+		"webhook-delivery-log":        testWebhookDeliveryLog,
+		"webhook-delete-cascade":      testWebhookDeleteCascade,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, setup(t))
+		})
+	}
+}
+
+// now is truncated to what a TIMESTAMP column keeps
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func newCar(category string, mileage int) entity.Car {
+	return entity.Car{
+		Make:           "Toyota",
+		Model:          "Corolla",
+		Package:        "Base",
+		Color:          "White",
+		Year:           2020,
+		Category:       category,
+		Mileage:        mileage,
+		Price:          entity.Money{Amount: 1500000, Currency: "USD"},
+		Identification: "JT2BF22K1W0123456",
+		CreatedAt:      now(),
+		UpdatedAt:      now(),
+	}
+}
+
+// normalize puts the timestamps in UTC, drivers give them back in their own location
+func normalize(car entity.Car) entity.Car {
+	car.CreatedAt = car.CreatedAt.UTC()
+	car.UpdatedAt = car.UpdatedAt.UTC()
+	return car
+}
+
+func normalizeReservation(reservation entity.Reservation) entity.Reservation {
+	reservation.ReservedAt = reservation.ReservedAt.UTC()
+	reservation.ExpiresAt = reservation.ExpiresAt.UTC()
+	return reservation
+}
+
+// createCar stores the car and gives it back with its id, Create is not required to set it
+func createCar(t *testing.T, repos Repositories, car entity.Car) entity.Car {
+	ctx := context.Background()
+	require.NoError(t, repos.Cars.Create(ctx, &car))
+	require.NotZero(t, car.ID, "Create sets the id of the car")
+	return car
+}
+
+func createFeature(t *testing.T, repos Repositories, code string) entity.Feature {
+	feature := entity.Feature{Code: code, Name: code, CreatedAt: now(), UpdatedAt: now()}
+	require.NoError(t, repos.Features.Create(context.Background(), &feature))
+	require.NotZero(t, feature.ID)
+	return feature
+}
+
+func ids(cars []entity.Car) []int64 {
+	list := []int64{}
+	for _, car := range cars {
+		list = append(list, car.ID)
+	}
+	return list
+}
+
+func testCarCreateGet(t *testing.T, repos Repositories) {
+	car := newCar("Sedan", 12000)
+	car.Images = []entity.CarImage{{StorageKey: "not-a-column"}}
+	car = createCar(t, repos, car)
+
+	got, err := repos.Cars.GetByID(context.Background(), car.ID)
+	require.NoError(t, err)
+
+	car.Images = nil
+	assert.Equal(t, normalize(car), normalize(got))
+}
+
+func testCarGetMissing(t *testing.T, repos Repositories) {
+	_, err := repos.Cars.GetByID(context.Background(), 404)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func testCarUpdate(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	car := createCar(t, repos, newCar("Sedan", 12000))
+
+	car.Color = "Red"
+	car.Mileage = 15000
+	car.Price = entity.Money{Amount: 1400000, Currency: "EUR"}
+	car.UpdatedAt = now().Add(time.Hour)
+	require.NoError(t, repos.Cars.Update(ctx, &car))
+
+	got, err := repos.Cars.GetByID(ctx, car.ID)
+	require.NoError(t, err)
+	assert.Equal(t, normalize(car), normalize(got))
+}
+
+func testCarUpdateDeleteMissing(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	car := newCar("Sedan", 12000)
+	car.ID = 404
+
+	assert.Error(t, repos.Cars.Update(ctx, &car))
+	assert.Error(t, repos.Cars.Delete(ctx, car.ID))
+}
+
+func testCarSoftDelete(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	car := createCar(t, repos, newCar("Sedan", 12000))
+	image := entity.CarImage{CarID: car.ID, StorageKey: "cars/1.jpg", ContentType: "image/jpeg", CreatedAt: now(), UpdatedAt: now()}
+	require.NoError(t, repos.CarImages.Create(ctx, &image))
+
+	require.NoError(t, repos.Cars.Delete(ctx, car.ID))
+
+	_, err := repos.Cars.GetByID(ctx, car.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+	cars, err := repos.Cars.Fetch(ctx, entity.CarFilter{}, entity.Page{})
+	require.NoError(t, err)
+	assert.Empty(t, cars)
+	count, err := repos.Cars.Count(ctx, entity.CarFilter{})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	counts, err := repos.Cars.CountByCategory(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+
+	assert.Error(t, repos.Cars.Update(ctx, &car), "a deleted car cannot be updated")
+	assert.Error(t, repos.Cars.Delete(ctx, car.ID), "a car is deleted once")
+
+	_, err = repos.CarImages.GetByID(ctx, image.ID)
+	assert.NoError(t, err, "the rows stay until the car is purged")
+}
+
+func testCarPurgeCascade(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	car := createCar(t, repos, newCar("Sedan", 12000))
+	feature := createFeature(t, repos, "sunroof")
+	require.NoError(t, repos.Features.AttachToCar(ctx, car.ID, []int64{feature.ID}))
+	image := entity.CarImage{CarID: car.ID, StorageKey: "cars/1.jpg", ContentType: "image/jpeg", CreatedAt: now(), UpdatedAt: now()}
+	require.NoError(t, repos.CarImages.Create(ctx, &image))
+
+	kept := createCar(t, repos, newCar("Sedan", 13000))
+	keptImage := entity.CarImage{CarID: kept.ID, StorageKey: "cars/2.jpg", ContentType: "image/jpeg", CreatedAt: now(), UpdatedAt: now()}
+	require.NoError(t, repos.CarImages.Create(ctx, &keptImage))
+
+	require.NoError(t, repos.Cars.Delete(ctx, car.ID))
+	images, err := repos.CarImages.FetchByCarsDeletedBefore(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, images, "a car deleted within the retention keeps its images")
+	images, err = repos.CarImages.FetchByCarsDeletedBefore(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, images, 1, "only the images of the deleted car are purged")
+	assert.Equal(t, "cars/1.jpg", images[0].StorageKey)
+
+	purged, err := repos.Cars.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	_, err = repos.CarImages.GetByID(ctx, image.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+	features, err := repos.Features.FetchByCarIDs(ctx, []int64{car.ID})
+	require.NoError(t, err)
+	assert.Empty(t, features)
+	_, err = repos.Features.GetByID(ctx, feature.ID)
+	assert.NoError(t, err, "features outlive the cars they were attached to")
+}
+
+func testCarPurgeRetention(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	kept := createCar(t, repos, newCar("Sedan", 1000))
+	deleted := createCar(t, repos, newCar("Sedan", 2000))
+	require.NoError(t, repos.Cars.Delete(ctx, deleted.ID))
+
+	purged, err := repos.Cars.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged, "a car deleted within the retention is kept")
+
+	purged, err = repos.Cars.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	purged, err = repos.Cars.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	_, err = repos.Cars.GetByID(ctx, kept.ID)
+	assert.NoError(t, err, "a car that is not deleted is never purged")
+}
+
+func testCarFetchOrder(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	cars, err := repos.Cars.Fetch(ctx, entity.CarFilter{}, entity.Page{})
+	require.NoError(t, err)
+	assert.Empty(t, cars)
+
+	var want []int64
+	for i := 0; i < 5; i++ {
+		want = append(want, createCar(t, repos, newCar("Sedan", i)).ID)
+	}
+
+	cars, err = repos.Cars.Fetch(ctx, entity.CarFilter{}, entity.Page{})
+	require.NoError(t, err)
+	assert.Equal(t, want, ids(cars))
+}
+
+func testCarFetchMileage(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	low := createCar(t, repos, newCar("Sedan", 1000))
+	mid := createCar(t, repos, newCar("Sedan", 50000))
+	high := createCar(t, repos, newCar("Sedan", 200000))
+
+	minimum, maximum := 1000, 50000
+	tests := map[string]struct {
+		filter entity.CarFilter
+		want   []int64
+	}{
+		"min-inclusive": {filter: entity.CarFilter{MileageMin: &maximum}, want: []int64{mid.ID, high.ID}},
+		"max-inclusive": {filter: entity.CarFilter{MileageMax: &maximum}, want: []int64{low.ID, mid.ID}},
+		"range":         {filter: entity.CarFilter{MileageMin: &minimum, MileageMax: &maximum}, want: []int64{low.ID, mid.ID}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cars, err := repos.Cars.Fetch(ctx, test.filter, entity.Page{})
+			require.NoError(t, err)
+			assert.Equal(t, test.want, ids(cars))
+		})
+	}
+}
+
+func testCarFetchFeatures(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	sunroof := createFeature(t, repos, "sunroof")
+	heatedSeats := createFeature(t, repos, "heated-seats")
+
+	both := createCar(t, repos, newCar("Sedan", 1000))
+	onlySunroof := createCar(t, repos, newCar("Sedan", 2000))
+	createCar(t, repos, newCar("Sedan", 3000))
+	require.NoError(t, repos.Features.AttachToCar(ctx, both.ID, []int64{sunroof.ID, heatedSeats.ID}))
+	require.NoError(t, repos.Features.AttachToCar(ctx, onlySunroof.ID, []int64{sunroof.ID}))
+
+	maximum := 1500
+	tests := map[string]struct {
+		filter entity.CarFilter
+		want   []int64
+	}{
+		"all":              {filter: entity.CarFilter{Features: []string{"sunroof", "heated-seats"}, FeatureMatch: entity.FeatureMatchAll}, want: []int64{both.ID}},
+		"all-by-default":   {filter: entity.CarFilter{Features: []string{"sunroof", "heated-seats"}}, want: []int64{both.ID}},
+		"any":              {filter: entity.CarFilter{Features: []string{"sunroof", "heated-seats"}, FeatureMatch: entity.FeatureMatchAny}, want: []int64{both.ID, onlySunroof.ID}},
+		"unknown-code":     {filter: entity.CarFilter{Features: []string{"sunroof", "towbar"}}, want: []int64{}},
+		"with-mileage-max": {filter: entity.CarFilter{Features: []string{"sunroof"}, MileageMax: &maximum}, want: []int64{both.ID}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cars, err := repos.Cars.Fetch(ctx, test.filter, entity.Page{})
+			require.NoError(t, err)
+			assert.Equal(t, test.want, ids(cars))
+		})
+	}
+}
+
+func testCarFetchPage(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	var all []int64
+	for i := 0; i < 5; i++ {
+		all = append(all, createCar(t, repos, newCar("Sedan", i*1000)).ID)
+	}
+
+	maximum := 3000
+	tests := map[string]struct {
+		filter entity.CarFilter
+		page   entity.Page
+		want   []int64
+	}{
+		"first":                {page: entity.Page{Limit: 2}, want: all[:2]},
+		"middle":               {page: entity.Page{Limit: 2, Offset: 2}, want: all[2:4]},
+		"last-partial":         {page: entity.Page{Limit: 2, Offset: 4}, want: all[4:]},
+		"past-the-end":         {page: entity.Page{Limit: 2, Offset: 5}, want: []int64{}},
+		"offset-without-limit": {page: entity.Page{Offset: 3}, want: all[3:]},
+		"after-filter":         {filter: entity.CarFilter{MileageMax: &maximum}, page: entity.Page{Limit: 2, Offset: 2}, want: all[2:4]},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cars, err := repos.Cars.Fetch(ctx, test.filter, test.page)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, ids(cars))
+		})
+	}
+}
+
+func testCarCount(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	sunroof := createFeature(t, repos, "sunroof")
+	withSunroof := createCar(t, repos, newCar("Sedan", 1000))
+	createCar(t, repos, newCar("Sedan", 2000))
+	createCar(t, repos, newCar("Sedan", 3000))
+	require.NoError(t, repos.Features.AttachToCar(ctx, withSunroof.ID, []int64{sunroof.ID}))
+
+	count, err := repos.Cars.Count(ctx, entity.CarFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	minimum := 2000
+	count, err = repos.Cars.Count(ctx, entity.CarFilter{MileageMin: &minimum})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = repos.Cars.Count(ctx, entity.CarFilter{Features: []string{"sunroof"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func testCarCountByCategory(t *testing.T, repos Repositories) {
+	createCar(t, repos, newCar("Sedan", 1000))
+	createCar(t, repos, newCar("Sedan", 2000))
+	createCar(t, repos, newCar("SUV", 3000))
+
+	counts, err := repos.Cars.CountByCategory(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"Sedan": 2, "SUV": 1}, counts)
+}
+
+func testCarConcurrentCreate(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			car := newCar("Sedan", i)
+			assert.NoError(t, repos.Cars.Create(ctx, &car))
+			_, err := repos.Cars.Fetch(ctx, entity.CarFilter{}, entity.Page{})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	cars, err := repos.Cars.Fetch(ctx, entity.CarFilter{}, entity.Page{})
+	require.NoError(t, err)
+	assert.Len(t, cars, 20)
+}
+
+func testCarImageOrderAndPrimary(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	car := createCar(t, repos, newCar("Sedan", 1000))
+
+	var images []entity.CarImage
+	for _, position := range []int{2, 1, 1} {
+		image := entity.CarImage{CarID: car.ID, StorageKey: "cars/image.jpg", ContentType: "image/jpeg", Size: 1024, Width: 640, Height: 480, Position: position, CreatedAt: now(), UpdatedAt: now()}
+		require.NoError(t, repos.CarImages.Create(ctx, &image))
+		require.NotZero(t, image.ID)
+		images = append(images, image)
+	}
+
+	got, err := repos.CarImages.FetchByCarID(ctx, car.ID)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, []int64{images[1].ID, images[2].ID, images[0].ID}, []int64{got[0].ID, got[1].ID, got[2].ID})
+
+	require.NoError(t, repos.CarImages.SetPrimary(ctx, car.ID, images[2].ID))
+	got, err = repos.CarImages.FetchByCarID(ctx, car.ID)
+	require.NoError(t, err)
+	for _, image := range got {
+		assert.Equal(t, image.ID == images[2].ID, image.IsPrimary)
+	}
+
+	other, err := repos.CarImages.FetchByCarID(ctx, car.ID+1)
+	require.NoError(t, err)
+	assert.Empty(t, other)
+}
+
+func testCarImageFetchByCars(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	first := createCar(t, repos, newCar("Sedan", 1000))
+	second := createCar(t, repos, newCar("Sedan", 1000))
+
+	var images []entity.CarImage
+	for _, image := range []entity.CarImage{{CarID: second.ID, Position: 0}, {CarID: first.ID, Position: 1}, {CarID: first.ID, Position: 0}} {
+		image.StorageKey = "cars/image.jpg"
+		image.ContentType = "image/jpeg"
+		image.CreatedAt = now()
+		image.UpdatedAt = now()
+		require.NoError(t, repos.CarImages.Create(ctx, &image))
+		images = append(images, image)
+	}
+
+	got, err := repos.CarImages.FetchByCarIDs(ctx, []int64{first.ID, second.ID})
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, []int64{images[2].ID, images[1].ID, images[0].ID}, []int64{got[0].ID, got[1].ID, got[2].ID})
+
+	got, err = repos.CarImages.FetchByCarIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func testCarImageUpdateDelete(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	car := createCar(t, repos, newCar("Sedan", 1000))
+	image := entity.CarImage{CarID: car.ID, StorageKey: "cars/image.jpg", ContentType: "image/jpeg", CreatedAt: now(), UpdatedAt: now()}
+	require.NoError(t, repos.CarImages.Create(ctx, &image))
+
+	image.Position = 3
+	image.IsPrimary = true
+	image.StorageKey = "ignored.jpg"
+	require.NoError(t, repos.CarImages.Update(ctx, &image))
+
+	got, err := repos.CarImages.GetByID(ctx, image.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, got.Position)
+	assert.True(t, got.IsPrimary)
+	assert.Equal(t, "cars/image.jpg", got.StorageKey, "only the position and the primary flag are updated")
+
+	require.NoError(t, repos.CarImages.Delete(ctx, image.ID))
+	assert.Error(t, repos.CarImages.Delete(ctx, image.ID))
+	assert.Error(t, repos.CarImages.Update(ctx, &image))
+}
+
+func testFeatureLookup(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	sunroof := createFeature(t, repos, "sunroof")
+	createFeature(t, repos, "heated-seats")
+	createFeature(t, repos, "towbar")
+
+	got, err := repos.Features.GetByCode(ctx, "sunroof")
+	require.NoError(t, err)
+	assert.Equal(t, sunroof.ID, got.ID)
+
+	_, err = repos.Features.GetByCode(ctx, "missing")
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	duplicate := entity.Feature{Code: "sunroof", Name: "Sunroof"}
+	assert.Error(t, repos.Features.Create(ctx, &duplicate))
+
+	features, err := repos.Features.FetchByCodes(ctx, []string{"towbar", "sunroof", "missing"})
+	require.NoError(t, err)
+	require.Len(t, features, 2)
+	assert.Equal(t, "sunroof", features[0].Code)
+	assert.Equal(t, "towbar", features[1].Code)
+
+	features, err = repos.Features.Fetch(ctx)
+	require.NoError(t, err)
+	require.Len(t, features, 3)
+	assert.Equal(t, "heated-seats", features[0].Code)
+}
+
+func testFeatureAttachDetach(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	car := createCar(t, repos, newCar("Sedan", 1000))
+	sunroof := createFeature(t, repos, "sunroof")
+	towbar := createFeature(t, repos, "towbar")
+
+	require.NoError(t, repos.Features.AttachToCar(ctx, car.ID, []int64{sunroof.ID, towbar.ID}))
+	require.NoError(t, repos.Features.AttachToCar(ctx, car.ID, []int64{sunroof.ID}), "attaching twice is not an error")
+
+	features, err := repos.Features.FetchByCarID(ctx, car.ID)
+	require.NoError(t, err)
+	assert.Len(t, features, 2)
+
+	require.NoError(t, repos.Features.DetachFromCar(ctx, car.ID, towbar.ID))
+	require.NoError(t, repos.Features.Delete(ctx, sunroof.ID))
+
+	features, err = repos.Features.FetchByCarID(ctx, car.ID)
+	require.NoError(t, err)
+	assert.Empty(t, features)
+}
+
+func testFeatureFetchByCars(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	first := createCar(t, repos, newCar("Sedan", 1000))
+	second := createCar(t, repos, newCar("Sedan", 1000))
+	bare := createCar(t, repos, newCar("Sedan", 1000))
+	sunroof := createFeature(t, repos, "sunroof")
+	towbar := createFeature(t, repos, "towbar")
+	require.NoError(t, repos.Features.AttachToCar(ctx, first.ID, []int64{towbar.ID, sunroof.ID}))
+	require.NoError(t, repos.Features.AttachToCar(ctx, second.ID, []int64{towbar.ID}))
+
+	features, err := repos.Features.FetchByCarIDs(ctx, []int64{first.ID, second.ID, bare.ID})
+	require.NoError(t, err)
+	assert.Len(t, features, 2, "a car without features has no entry")
+	if assert.Len(t, features[first.ID], 2) {
+		assert.Equal(t, "sunroof", features[first.ID][0].Code)
+		assert.Equal(t, "towbar", features[first.ID][1].Code)
+	}
+	if assert.Len(t, features[second.ID], 1) {
+		assert.Equal(t, towbar.ID, features[second.ID][0].ID)
+	}
+
+	features, err = repos.Features.FetchByCarIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, features)
+}
+
+func testPriceHistoryOrder(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	first := createCar(t, repos, newCar("Sedan", 1000))
+	second := createCar(t, repos, newCar("Sedan", 1000))
+
+	changedAt := now()
+	points := []entity.PricePoint{
+		{CarID: second.ID, Price: entity.Money{Amount: 900000, Currency: "EUR"}, ChangedAt: changedAt},
+		{CarID: first.ID, Price: entity.Money{Amount: 1400000, Currency: "USD"}, ChangedAt: changedAt.Add(time.Hour)},
+		{CarID: first.ID, Price: entity.Money{Amount: 1500000, Currency: "USD"}, ChangedAt: changedAt},
+	}
+	for i := range points {
+		require.NoError(t, repos.PriceHistory.Add(ctx, &points[i]))
+		require.NotZero(t, points[i].ID)
+	}
+
+	got, err := repos.PriceHistory.FetchByCarIDs(ctx, []int64{first.ID, second.ID})
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, []int64{points[2].ID, points[1].ID, points[0].ID}, []int64{got[0].ID, got[1].ID, got[2].ID})
+	assert.Equal(t, entity.Money{Amount: 1500000, Currency: "USD"}, got[0].Price)
+	assert.Equal(t, changedAt, got[0].ChangedAt.UTC())
+
+	got, err = repos.PriceHistory.FetchByCarIDs(ctx, []int64{second.ID + 1})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func testPriceHistoryCascade(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	car := createCar(t, repos, newCar("Sedan", 1000))
+	point := entity.PricePoint{CarID: car.ID, Price: car.Price, ChangedAt: now()}
+	require.NoError(t, repos.PriceHistory.Add(ctx, &point))
+
+	require.NoError(t, repos.Cars.Delete(ctx, car.ID))
+
+	got, err := repos.PriceHistory.FetchByCarIDs(ctx, []int64{car.ID})
+	require.NoError(t, err)
+	assert.Len(t, got, 1, "the history stays until the car is purged")
+
+	_, err = repos.Cars.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	got, err = repos.PriceHistory.FetchByCarIDs(ctx, []int64{car.ID})
+	require.NoError(t, err)
+	assert.Empty(t, got, "the history goes with the car")
+}
+
+func testReservationSaveGet(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	car := createCar(t, repos, newCar("Sedan", 1000))
+	_, err := repos.Reservations.GetByCarID(ctx, car.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	reservation := entity.Reservation{CarID: car.ID, Rep: "jane", ReservedAt: now(), ExpiresAt: now().Add(time.Hour)}
+	require.NoError(t, repos.Reservations.Save(ctx, &reservation))
+	got, err := repos.Reservations.GetByCarID(ctx, car.ID)
+	require.NoError(t, err)
+	assert.Equal(t, normalizeReservation(reservation), normalizeReservation(got))
+
+	// saving again replaces the reservation of the car
+	reservation = entity.Reservation{CarID: car.ID, Rep: "john", ReservedAt: now().Add(time.Minute), ExpiresAt: now().Add(2 * time.Hour)}
+	require.NoError(t, repos.Reservations.Save(ctx, &reservation))
+	got, err = repos.Reservations.GetByCarID(ctx, car.ID)
+	require.NoError(t, err)
+	assert.Equal(t, normalizeReservation(reservation), normalizeReservation(got))
+
+	missing := entity.Reservation{CarID: car.ID + 1, Rep: "jane", ReservedAt: now(), ExpiresAt: now().Add(time.Hour)}
+	assert.Error(t, repos.Reservations.Save(ctx, &missing), "the car must exist")
+}
+
+func testReservationDelete(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	car := createCar(t, repos, newCar("Sedan", 1000))
+	reservation := entity.Reservation{CarID: car.ID, Rep: "jane", ReservedAt: now(), ExpiresAt: now().Add(time.Hour)}
+	require.NoError(t, repos.Reservations.Save(ctx, &reservation))
+
+	require.NoError(t, repos.Reservations.Delete(ctx, car.ID))
+	_, err := repos.Reservations.GetByCarID(ctx, car.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Error(t, repos.Reservations.Delete(ctx, car.ID))
+}
+
+func testReservationCascade(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	car := createCar(t, repos, newCar("Sedan", 1000))
+	reservation := entity.Reservation{CarID: car.ID, Rep: "jane", ReservedAt: now(), ExpiresAt: now().Add(time.Hour)}
+	require.NoError(t, repos.Reservations.Save(ctx, &reservation))
+
+	require.NoError(t, repos.Cars.Delete(ctx, car.ID))
+	_, err := repos.Cars.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	_, err = repos.Reservations.GetByCarID(ctx, car.ID)
+	assert.Equal(t, sql.ErrNoRows, err, "the reservation goes with the car")
+}
+
+func testCatalogLookup(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	for _, name := range []string{"Toyota", "Honda"} {
+		entry := entity.CatalogEntry{Kind: entity.CatalogKindMake, Name: name, CreatedAt: now(), UpdatedAt: now()}
+		require.NoError(t, repos.Catalog.Create(ctx, &entry))
+		require.NotZero(t, entry.ID)
+	}
+
+	got, err := repos.Catalog.GetByName(ctx, entity.CatalogKindMake, 0, "toyota")
+	require.NoError(t, err)
+	assert.Equal(t, "Toyota", got.Name)
+
+	_, err = repos.Catalog.GetByName(ctx, entity.CatalogKindCategory, 0, "Toyota")
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	duplicate := entity.CatalogEntry{Kind: entity.CatalogKindMake, Name: "TOYOTA"}
+	assert.Error(t, repos.Catalog.Create(ctx, &duplicate))
+
+	entries, err := repos.Catalog.Fetch(ctx, entity.CatalogKindMake, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Honda", entries[0].Name)
+
+	got.Name = "Toyota Motor"
+	require.NoError(t, repos.Catalog.Update(ctx, &got))
+	_, err = repos.Catalog.GetByName(ctx, entity.CatalogKindMake, 0, "Toyota Motor")
+	assert.NoError(t, err)
+}
+
+func testCatalogDeleteCascade(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	carMake := entity.CatalogEntry{Kind: entity.CatalogKindMake, Name: "Toyota"}
+	require.NoError(t, repos.Catalog.Create(ctx, &carMake))
+	model := entity.CatalogEntry{Kind: entity.CatalogKindModel, ParentID: carMake.ID, Name: "Corolla"}
+	require.NoError(t, repos.Catalog.Create(ctx, &model))
+	trim := entity.CatalogEntry{Kind: entity.CatalogKindTrim, ParentID: model.ID, Name: "Base"}
+	require.NoError(t, repos.Catalog.Create(ctx, &trim))
+
+	require.NoError(t, repos.Catalog.Delete(ctx, carMake.ID))
+
+	_, err := repos.Catalog.GetByName(ctx, entity.CatalogKindTrim, model.ID, "Base")
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Error(t, repos.Catalog.Delete(ctx, model.ID), "the model went with its make")
+}
+
+func newEvent(t *testing.T, carID int64, payload entity.EventPayload) *entity.Event {
+	event, err := entity.NewCarEvent(carID, payload, now())
+	require.NoError(t, err)
+	return &event
+}
+
+func testOutboxPendingOrder(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	created := newEvent(t, 1, entity.CarCreated{Car: newCar("Sedan", 0)})
+	require.NoError(t, repos.Outbox.Add(ctx, created))
+	require.NotZero(t, created.ID)
+	deleted := newEvent(t, 1, entity.CarDeleted{CarID: 1})
+	require.NoError(t, repos.Outbox.Add(ctx, deleted))
+	other := newEvent(t, 2, entity.CarDeleted{CarID: 2})
+	require.NoError(t, repos.Outbox.Add(ctx, other))
+	assert.Greater(t, deleted.ID, created.ID)
+
+	events, err := repos.Outbox.FetchPending(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, created.ID, events[0].ID)
+	assert.Equal(t, entity.EventCarCreated, events[0].Type)
+	assert.Equal(t, int64(1), events[0].CarID)
+	assert.JSONEq(t, string(created.Payload), string(events[0].Payload))
+	assert.Equal(t, created.OccurredAt, events[0].OccurredAt.UTC())
+	assert.Equal(t, deleted.ID, events[1].ID)
+}
+
+func testOutboxMarkPublished(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	first := newEvent(t, 1, entity.CarDeleted{CarID: 1})
+	require.NoError(t, repos.Outbox.Add(ctx, first))
+	second := newEvent(t, 2, entity.CarDeleted{CarID: 2})
+	require.NoError(t, repos.Outbox.Add(ctx, second))
+
+	require.NoError(t, repos.Outbox.MarkPublished(ctx, first.ID, now()))
+	assert.Error(t, repos.Outbox.MarkPublished(ctx, 404, now()))
+
+	events, err := repos.Outbox.FetchPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, second.ID, events[0].ID)
+}
+
+func createWebhook(t *testing.T, repos Repositories, eventTypes ...entity.EventType) entity.WebhookSubscription {
+	subscription := entity.WebhookSubscription{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: eventTypes,
+		Secret:     "0123456789abcdef",
+		CreatedAt:  now(),
+		UpdatedAt:  now(),
+	}
+	require.NoError(t, repos.Webhooks.Create(context.Background(), &subscription))
+	require.NotZero(t, subscription.ID)
+	return subscription
+}
+
+func newDelivery(subscriptionID int64, eventID int64, nextAttemptAt time.Time) entity.WebhookDelivery {
+	return entity.WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      entity.EventCarDeleted,
+		Body:           []byte(`{"id":1,"type":"car.deleted"}`),
+		Status:         entity.WebhookDeliveryPending,
+		NextAttemptAt:  nextAttemptAt,
+		CreatedAt:      now(),
+		UpdatedAt:      now(),
+	}
+}
+
+func testWebhookCRUD(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	subscription := createWebhook(t, repos, entity.EventCarCreated, entity.EventCarDeleted)
+
+	got, err := repos.Webhooks.GetByID(ctx, subscription.ID)
+	require.NoError(t, err)
+	assert.Equal(t, subscription.URL, got.URL)
+	assert.Equal(t, subscription.EventTypes, got.EventTypes)
+	assert.Equal(t, subscription.Secret, got.Secret)
+
+	subscription.URL = "https://partner.example.com/v2/hooks"
+	subscription.EventTypes = []entity.EventType{entity.EventCarPriceChanged}
+	require.NoError(t, repos.Webhooks.Update(ctx, &subscription))
+
+	subscriptions, err := repos.Webhooks.Fetch(ctx)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, "https://partner.example.com/v2/hooks", subscriptions[0].URL)
+	assert.Equal(t, []entity.EventType{entity.EventCarPriceChanged}, subscriptions[0].EventTypes)
+
+	require.NoError(t, repos.Webhooks.Delete(ctx, subscription.ID))
+	_, err = repos.Webhooks.GetByID(ctx, subscription.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Error(t, repos.Webhooks.Delete(ctx, subscription.ID))
+	assert.Error(t, repos.Webhooks.Update(ctx, &subscription))
+}
+
+func testWebhookDeliveryOnce(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	subscription := createWebhook(t, repos, entity.EventCarDeleted)
+
+	delivery := newDelivery(subscription.ID, 1, now())
+	require.NoError(t, repos.WebhookDeliveries.Create(ctx, &delivery))
+	require.NotZero(t, delivery.ID)
+
+	again := newDelivery(subscription.ID, 1, now())
+	require.NoError(t, repos.WebhookDeliveries.Create(ctx, &again), "an event relayed twice is not an error")
+	assert.Zero(t, again.ID)
+
+	deliveries, err := repos.WebhookDeliveries.FetchBySubscriptionID(ctx, subscription.ID, "", 10)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+}
+
+func testWebhookDeliveryDue(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	subscription := createWebhook(t, repos, entity.EventCarDeleted)
+	current := now()
+
+	late := newDelivery(subscription.ID, 1, current.Add(-time.Minute))
+	require.NoError(t, repos.WebhookDeliveries.Create(ctx, &late))
+	due := newDelivery(subscription.ID, 2, current)
+	require.NoError(t, repos.WebhookDeliveries.Create(ctx, &due))
+	later := newDelivery(subscription.ID, 3, current.Add(time.Minute))
+	require.NoError(t, repos.WebhookDeliveries.Create(ctx, &later))
+	dead := newDelivery(subscription.ID, 4, current.Add(-time.Hour))
+	dead.Status = entity.WebhookDeliveryDead
+	require.NoError(t, repos.WebhookDeliveries.Create(ctx, &dead))
+
+	deliveries, err := repos.WebhookDeliveries.FetchDue(ctx, current, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, late.ID, deliveries[0].ID)
+	assert.Equal(t, due.ID, deliveries[1].ID)
+
+	deliveries, err = repos.WebhookDeliveries.FetchDue(ctx, current, 1)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+}
+
+func testWebhookDeliveryLog(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	subscription := createWebhook(t, repos, entity.EventCarDeleted)
+	other := createWebhook(t, repos, entity.EventCarDeleted)
+
+	first := newDelivery(subscription.ID, 1, now())
+	require.NoError(t, repos.WebhookDeliveries.Create(ctx, &first))
+	second := newDelivery(subscription.ID, 2, now())
+	require.NoError(t, repos.WebhookDeliveries.Create(ctx, &second))
+	otherDelivery := newDelivery(other.ID, 1, now())
+	require.NoError(t, repos.WebhookDeliveries.Create(ctx, &otherDelivery))
+
+	deliveredAt := now()
+	first.Status = entity.WebhookDeliveryDead
+	first.Attempts = 8
+	first.LastStatusCode = 500
+	first.LastError = "unexpected status 500"
+	first.UpdatedAt = deliveredAt
+	require.NoError(t, repos.WebhookDeliveries.Update(ctx, &first))
+	second.Status = entity.WebhookDeliverySucceeded
+	second.Attempts = 1
+	second.LastStatusCode = 204
+	second.DeliveredAt = &deliveredAt
+	require.NoError(t, repos.WebhookDeliveries.Update(ctx, &second))
+
+	deliveries, err := repos.WebhookDeliveries.FetchBySubscriptionID(ctx, subscription.ID, "", 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, second.ID, deliveries[0].ID, "latest first")
+	assert.Equal(t, entity.WebhookDeliverySucceeded, deliveries[0].Status)
+	require.NotNil(t, deliveries[0].DeliveredAt)
+	assert.Equal(t, deliveredAt, deliveries[0].DeliveredAt.UTC())
+	assert.JSONEq(t, string(second.Body), string(deliveries[0].Body))
+
+	deliveries, err = repos.WebhookDeliveries.FetchBySubscriptionID(ctx, subscription.ID, entity.WebhookDeliveryDead, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, 8, deliveries[0].Attempts)
+	assert.Equal(t, 500, deliveries[0].LastStatusCode)
+	assert.Equal(t, "unexpected status 500", deliveries[0].LastError)
+	assert.Nil(t, deliveries[0].DeliveredAt)
+
+	got, err := repos.WebhookDeliveries.GetByID(ctx, otherDelivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, got.SubscriptionID)
+	_, err = repos.WebhookDeliveries.GetByID(ctx, 404)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func testWebhookDeleteCascade(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	subscription := createWebhook(t, repos, entity.EventCarDeleted)
+	delivery := newDelivery(subscription.ID, 1, now())
+	require.NoError(t, repos.WebhookDeliveries.Create(ctx, &delivery))
+
+	require.NoError(t, repos.Webhooks.Delete(ctx, subscription.ID))
+
+	_, err := repos.WebhookDeliveries.GetByID(ctx, delivery.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
@@ -0,0 +1,208 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/tracing"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+)
+
+type sqliteCarRepository struct {
+	db *sql.DB
+}
+
+// NewSqliteCarRepository will create new a carRepository object representation of CarRepository interface
+func NewSqliteCarRepository(db *sql.DB) pgsql.CarRepository {
+	return &sqliteCarRepository{
+		db: db,
+	}
+}
+
+func (r *sqliteCarRepository) Create(ctx context.Context, car *entity.Car) (err error) {
+	query := "INSERT INTO cars (make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	ctx, span := startSpan(ctx, "INSERT", query)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, car.Make, car.Model, car.Package, car.Color, car.Mileage, car.Price.Amount, car.Price.Currency, car.Category, car.Year, car.Identification, car.CreatedAt, car.UpdatedAt)
+	if err != nil {
+		return
+	}
+
+	car.ID, err = res.LastInsertId()
+	return
+}
+
+func (r *sqliteCarRepository) GetByID(ctx context.Context, id int64) (car entity.Car, err error) {
+	query := "SELECT id, make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at FROM cars WHERE id = ? AND deleted_at IS NULL"
+	ctx, span := startSpan(ctx, "SELECT", query)
+	defer func() { tracing.End(span, err) }()
+
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&car.ID, &car.Make, &car.Model, &car.Package, &car.Color, &car.Mileage, &car.Price.Amount, &car.Price.Currency, &car.Category, &car.Year, &car.Identification, &car.CreatedAt, &car.UpdatedAt)
+	return
+}
+
+// Fetch gives the page of the cars passing the filter, in the order of their id. SQLite only
+// takes an OFFSET after a LIMIT, -1 stands for none.
+func (r *sqliteCarRepository) Fetch(ctx context.Context, filter entity.CarFilter, page entity.Page) (cars []entity.Car, err error) {
+	query := "SELECT id, make, model, package, color, mileage, price_amount, price_currency, category, year, identification, created_at, updated_at FROM cars"
+	where, args := buildCarFilter(filter)
+	query += " WHERE " + strings.Join(where, " AND ")
+	query += " ORDER BY id"
+	if page.Limit > 0 || page.Offset > 0 {
+		limit := page.Limit
+		if limit == 0 {
+			limit = -1
+		}
+		args = append(args, limit, page.Offset)
+		query += " LIMIT ? OFFSET ?"
+	}
+
+	ctx, span := startSpan(ctx, "SELECT", query)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return cars, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var car entity.Car
+		err := rows.Scan(&car.ID, &car.Make, &car.Model, &car.Package, &car.Color, &car.Mileage, &car.Price.Amount, &car.Price.Currency, &car.Category, &car.Year, &car.Identification, &car.CreatedAt, &car.UpdatedAt)
+		if err != nil {
+			return cars, err
+		}
+
+		cars = append(cars, car)
+	}
+
+	return cars, rows.Err()
+}
+
+// Count gives the number of cars passing the filter, on every page
+func (r *sqliteCarRepository) Count(ctx context.Context, filter entity.CarFilter) (count int64, err error) {
+	query := "SELECT COUNT(*) FROM cars"
+	where, args := buildCarFilter(filter)
+	query += " WHERE " + strings.Join(where, " AND ")
+
+	ctx, span := startSpan(ctx, "SELECT", query)
+	defer func() { tracing.End(span, err) }()
+
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&count)
+	return
+}
+
+// buildCarFilter translates the filter into WHERE conditions and their arguments,
+// the same conditions as the pgsql repository with the feature codes bound one by one
+func buildCarFilter(filter entity.CarFilter) (where []string, args []interface{}) {
+	where = append(where, "deleted_at IS NULL")
+
+	if len(filter.Features) > 0 {
+		for _, code := range filter.Features {
+			args = append(args, code)
+		}
+		subquery := "SELECT cf.car_id FROM car_features cf JOIN features f ON f.id = cf.feature_id WHERE f.code IN (" + placeholders(len(filter.Features)) + ")"
+		if filter.FeatureMatch != entity.FeatureMatchAny {
+			args = append(args, len(filter.Features))
+			subquery += " GROUP BY cf.car_id HAVING COUNT(DISTINCT f.id) = ?"
+		}
+		where = append(where, "id IN ("+subquery+")")
+	}
+
+	if filter.MileageMin != nil {
+		args = append(args, *filter.MileageMin)
+		where = append(where, "mileage >= ?")
+	}
+
+	if filter.MileageMax != nil {
+		args = append(args, *filter.MileageMax)
+		where = append(where, "mileage <= ?")
+	}
+
+	return
+}
+
+func (r *sqliteCarRepository) Update(ctx context.Context, car *entity.Car) (err error) {
+	query := "UPDATE cars SET make = ?, model = ?, package = ?, color = ?, mileage = ?, price_amount = ?, price_currency = ?, category = ?, year = ?, identification = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL"
+	ctx, span := startSpan(ctx, "UPDATE", query)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, car.Make, car.Model, car.Package, car.Color, car.Mileage, car.Price.Amount, car.Price.Currency, car.Category, car.Year, car.Identification, car.UpdatedAt, car.ID)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func (r *sqliteCarRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := "UPDATE cars SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL"
+	ctx, span := startSpan(ctx, "UPDATE", query)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, time.Now().UTC(), id)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func (r *sqliteCarRepository) PurgeDeleted(ctx context.Context, before time.Time) (purged int64, err error) {
+	query := "DELETE FROM cars WHERE deleted_at < ?"
+	ctx, span := startSpan(ctx, "DELETE", query)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, before.UTC())
+	if err != nil {
+		return
+	}
+
+	return res.RowsAffected()
+}
+
+func (r *sqliteCarRepository) CountByCategory(ctx context.Context) (counts map[string]int64, err error) {
+	query := "SELECT category, COUNT(*) FROM cars WHERE deleted_at IS NULL GROUP BY category"
+	ctx, span := startSpan(ctx, "SELECT", query)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	defer rows.Close()
+
+	counts = map[string]int64{}
+	for rows.Next() {
+		var category string
+		var count int64
+		if err = rows.Scan(&category, &count); err != nil {
+			return
+		}
+
+		counts[category] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// checkAffected fails an update or a delete that did not touch exactly one row
+func checkAffected(res sql.Result) error {
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		return fmt.Errorf("weird behavior, total affected: %d", affect)
+	}
+	return nil
+}
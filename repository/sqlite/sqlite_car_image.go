@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+)
+
+type sqliteCarImageRepository struct {
+	db *sql.DB
+}
+
+// NewSqliteCarImageRepository will create new a carImageRepository object representation of CarImageRepository interface
+func NewSqliteCarImageRepository(db *sql.DB) pgsql.CarImageRepository {
+	return &sqliteCarImageRepository{
+		db: db,
+	}
+}
+
+func (r *sqliteCarImageRepository) Create(ctx context.Context, image *entity.CarImage) (err error) {
+	query := "INSERT INTO car_images (car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, image.CarID, image.StorageKey, image.ContentType, image.Size, image.Width, image.Height, image.Position, image.IsPrimary, image.CreatedAt, image.UpdatedAt)
+	if err != nil {
+		return
+	}
+
+	image.ID, err = res.LastInsertId()
+	return
+}
+
+func (r *sqliteCarImageRepository) GetByID(ctx context.Context, id int64) (image entity.CarImage, err error) {
+	query := "SELECT id, car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at FROM car_images WHERE id = ?"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&image.ID, &image.CarID, &image.StorageKey, &image.ContentType, &image.Size, &image.Width, &image.Height, &image.Position, &image.IsPrimary, &image.CreatedAt, &image.UpdatedAt)
+	return
+}
+
+func (r *sqliteCarImageRepository) FetchByCarID(ctx context.Context, carID int64) ([]entity.CarImage, error) {
+	query := "SELECT id, car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at FROM car_images WHERE car_id = ? ORDER BY position, id"
+	return r.fetch(ctx, query, carID)
+}
+
+// FetchByCarIDs gives the images of all the cars at once, grouped by car in the order of FetchByCarID
+func (r *sqliteCarImageRepository) FetchByCarIDs(ctx context.Context, carIDs []int64) ([]entity.CarImage, error) {
+	if len(carIDs) == 0 {
+		return nil, nil
+	}
+
+	query := "SELECT id, car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at FROM car_images WHERE car_id IN (" + placeholders(len(carIDs)) + ") ORDER BY car_id, position, id"
+	return r.fetch(ctx, query, int64Args(carIDs)...)
+}
+
+// FetchByCarsDeletedBefore gives the images of the cars PurgeDeleted would remove at the given time
+func (r *sqliteCarImageRepository) FetchByCarsDeletedBefore(ctx context.Context, before time.Time) ([]entity.CarImage, error) {
+	query := "SELECT id, car_id, storage_key, content_type, size, width, height, position, is_primary, created_at, updated_at FROM car_images WHERE car_id IN (SELECT id FROM cars WHERE deleted_at < ?) ORDER BY car_id, position, id"
+	return r.fetch(ctx, query, before.UTC())
+}
+
+func (r *sqliteCarImageRepository) fetch(ctx context.Context, query string, args ...interface{}) (images []entity.CarImage, err error) {
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return images, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var image entity.CarImage
+		err := rows.Scan(&image.ID, &image.CarID, &image.StorageKey, &image.ContentType, &image.Size, &image.Width, &image.Height, &image.Position, &image.IsPrimary, &image.CreatedAt, &image.UpdatedAt)
+		if err != nil {
+			return images, err
+		}
+
+		images = append(images, image)
+	}
+
+	return images, rows.Err()
+}
+
+func (r *sqliteCarImageRepository) Update(ctx context.Context, image *entity.CarImage) (err error) {
+	query := "UPDATE car_images SET position = ?, is_primary = ?, updated_at = ? WHERE id = ?"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, image.Position, image.IsPrimary, image.UpdatedAt, image.ID)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+// SetPrimary flags one image as the primary image of a car and clears the flag on the others
+func (r *sqliteCarImageRepository) SetPrimary(ctx context.Context, carID int64, id int64) (err error) {
+	query := "UPDATE car_images SET is_primary = (id = ?) WHERE car_id = ?"
+	_, err = transaction.Conn(ctx, r.db).ExecContext(ctx, query, id, carID)
+	return
+}
+
+func (r *sqliteCarImageRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM car_images WHERE id = ?"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
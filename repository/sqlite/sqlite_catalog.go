@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+)
+
+type sqliteCatalogRepository struct {
+	db *sql.DB
+}
+
+// NewSqliteCatalogRepository will create new a catalogRepository object representation of CatalogRepository interface
+func NewSqliteCatalogRepository(db *sql.DB) pgsql.CatalogRepository {
+	return &sqliteCatalogRepository{
+		db: db,
+	}
+}
+
+func (r *sqliteCatalogRepository) Create(ctx context.Context, entry *entity.CatalogEntry) (err error) {
+	query := "INSERT INTO catalog_entries (kind, parent_id, name, created_at, updated_at) VALUES (?, NULLIF(?, 0), ?, ?, ?)"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, entry.Kind, entry.ParentID, entry.Name, entry.CreatedAt, entry.UpdatedAt)
+	if err != nil {
+		return
+	}
+
+	entry.ID, err = res.LastInsertId()
+	return
+}
+
+// GetByName looks the entry up case-insensitively
+func (r *sqliteCatalogRepository) GetByName(ctx context.Context, kind string, parentID int64, name string) (entry entity.CatalogEntry, err error) {
+	query := "SELECT id, kind, COALESCE(parent_id, 0), name, created_at, updated_at FROM catalog_entries WHERE kind = ? AND COALESCE(parent_id, 0) = ? AND LOWER(name) = LOWER(?)"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, kind, parentID, name).Scan(&entry.ID, &entry.Kind, &entry.ParentID, &entry.Name, &entry.CreatedAt, &entry.UpdatedAt)
+	return
+}
+
+func (r *sqliteCatalogRepository) Fetch(ctx context.Context, kind string, parentID int64) (entries []entity.CatalogEntry, err error) {
+	query := "SELECT id, kind, COALESCE(parent_id, 0), name, created_at, updated_at FROM catalog_entries WHERE kind = ? AND COALESCE(parent_id, 0) = ? ORDER BY name"
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, kind, parentID)
+	if err != nil {
+		return entries, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry entity.CatalogEntry
+		err := rows.Scan(&entry.ID, &entry.Kind, &entry.ParentID, &entry.Name, &entry.CreatedAt, &entry.UpdatedAt)
+		if err != nil {
+			return entries, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *sqliteCatalogRepository) Update(ctx context.Context, entry *entity.CatalogEntry) (err error) {
+	query := "UPDATE catalog_entries SET name = ?, updated_at = ? WHERE id = ?"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, entry.Name, entry.UpdatedAt, entry.ID)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func (r *sqliteCatalogRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM catalog_entries WHERE id = ?"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
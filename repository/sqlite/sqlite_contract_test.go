@@ -0,0 +1,109 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"carApi/config"
+	"carApi/entity"
+	"carApi/infrastructure/datastore"
+	"carApi/infrastructure/migrator"
+	sqliteMigration "carApi/migration/sqlite"
+	"carApi/repository/repositorytest"
+	"carApi/repository/sqlite"
+	"carApi/repository/transaction"
+	"carApi/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupDatabase opens a migrated database in a temporary file
+func setupDatabase(t *testing.T) *sql.DB {
+	appLogger := logger.NewApiLogger(config.Default())
+	appLogger.InitLogger()
+
+	cfg := config.Default().Database
+	cfg.URL = "sqlite://" + filepath.Join(t.TempDir(), "carapi.db")
+
+	db, err := datastore.NewDatabase(cfg, appLogger)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	m, err := migrator.NewMigrator(db, sqliteMigration.Files, time.Minute, appLogger)
+	require.NoError(t, err)
+	require.NoError(t, m.Up())
+	require.NoError(t, m.Close())
+	return db
+}
+
+func TestSqliteRepositories_Contract(t *testing.T) {
+	repositorytest.TestRepositories(t, func(t *testing.T) repositorytest.Repositories {
+		db := setupDatabase(t)
+		return repositorytest.Repositories{
+			Cars:         sqlite.NewSqliteCarRepository(db),
+			CarImages:    sqlite.NewSqliteCarImageRepository(db),
+			Features:     sqlite.NewSqliteFeatureRepository(db),
+			PriceHistory: sqlite.NewSqlitePriceHistoryRepository(db),
+			Reservations: sqlite.NewSqliteReservationRepository(db),
+			Catalog:      sqlite.NewSqliteCatalogRepository(db),
+			Outbox:       sqlite.NewSqliteOutboxRepository(db),
+
+			Webhooks:          sqlite.NewSqliteWebhookRepository(db),
+			WebhookDeliveries: sqlite.NewSqliteWebhookDeliveryRepository(db),
+		}
+	})
+}
+
+func TestSqliteTransaction(t *testing.T) {
+	db := setupDatabase(t)
+	carRepo := sqlite.NewSqliteCarRepository(db)
+	featureRepo := sqlite.NewSqliteFeatureRepository(db)
+	txManager := transaction.NewSQLTxManager(db)
+
+	t.Run("rollback", func(t *testing.T) {
+		err := txManager.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			car := entity.Car{Make: "Toyota", Price: entity.Money{Currency: "USD"}}
+			if err := carRepo.Create(ctx, &car); err != nil {
+				return err
+			}
+			return featureRepo.AttachToCar(ctx, car.ID, []int64{404})
+		})
+		assert.Error(t, err)
+
+		cars, err := carRepo.Fetch(context.TODO(), entity.CarFilter{}, entity.Page{})
+		assert.NoError(t, err)
+		assert.Empty(t, cars, "the car created before the failure is rolled back")
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		err := txManager.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			car := entity.Car{Make: "Toyota", Price: entity.Money{Currency: "USD"}}
+			return carRepo.Create(ctx, &car)
+		})
+		assert.NoError(t, err)
+
+		cars, err := carRepo.Fetch(context.TODO(), entity.CarFilter{}, entity.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, cars, 1)
+	})
+
+	t.Run("rollback-on-panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			txManager.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+				car := entity.Car{Make: "Honda", Price: entity.Money{Currency: "USD"}}
+				if err := carRepo.Create(ctx, &car); err != nil {
+					return err
+				}
+				panic(errors.New("boom"))
+			})
+		})
+
+		cars, err := carRepo.Fetch(context.TODO(), entity.CarFilter{}, entity.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, cars, 1)
+	})
+}
@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+)
+
+type sqliteFeatureRepository struct {
+	db *sql.DB
+}
+
+// NewSqliteFeatureRepository will create new a featureRepository object representation of FeatureRepository interface
+func NewSqliteFeatureRepository(db *sql.DB) pgsql.FeatureRepository {
+	return &sqliteFeatureRepository{
+		db: db,
+	}
+}
+
+func (r *sqliteFeatureRepository) Create(ctx context.Context, feature *entity.Feature) (err error) {
+	query := "INSERT INTO features (code, name, created_at, updated_at) VALUES (?, ?, ?, ?)"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, feature.Code, feature.Name, feature.CreatedAt, feature.UpdatedAt)
+	if err != nil {
+		return
+	}
+
+	feature.ID, err = res.LastInsertId()
+	return
+}
+
+func (r *sqliteFeatureRepository) GetByID(ctx context.Context, id int64) (feature entity.Feature, err error) {
+	query := "SELECT id, code, name, created_at, updated_at FROM features WHERE id = ?"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&feature.ID, &feature.Code, &feature.Name, &feature.CreatedAt, &feature.UpdatedAt)
+	return
+}
+
+func (r *sqliteFeatureRepository) GetByCode(ctx context.Context, code string) (feature entity.Feature, err error) {
+	query := "SELECT id, code, name, created_at, updated_at FROM features WHERE code = ?"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, code).Scan(&feature.ID, &feature.Code, &feature.Name, &feature.CreatedAt, &feature.UpdatedAt)
+	return
+}
+
+func (r *sqliteFeatureRepository) FetchByCodes(ctx context.Context, codes []string) ([]entity.Feature, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, len(codes))
+	for i, code := range codes {
+		args[i] = code
+	}
+
+	query := "SELECT id, code, name, created_at, updated_at FROM features WHERE code IN (" + placeholders(len(codes)) + ") ORDER BY code"
+	return r.fetch(ctx, query, args...)
+}
+
+func (r *sqliteFeatureRepository) Fetch(ctx context.Context) ([]entity.Feature, error) {
+	query := "SELECT id, code, name, created_at, updated_at FROM features ORDER BY code"
+	return r.fetch(ctx, query)
+}
+
+func (r *sqliteFeatureRepository) FetchByCarID(ctx context.Context, carID int64) ([]entity.Feature, error) {
+	query := "SELECT f.id, f.code, f.name, f.created_at, f.updated_at FROM features f JOIN car_features cf ON cf.feature_id = f.id WHERE cf.car_id = ? ORDER BY f.code"
+	return r.fetch(ctx, query, carID)
+}
+
+// FetchByCarIDs gives the features of all the cars at once keyed by car, a car without
+// features has no entry
+func (r *sqliteFeatureRepository) FetchByCarIDs(ctx context.Context, carIDs []int64) (features map[int64][]entity.Feature, err error) {
+	features = map[int64][]entity.Feature{}
+	if len(carIDs) == 0 {
+		return features, nil
+	}
+
+	query := "SELECT cf.car_id, f.id, f.code, f.name, f.created_at, f.updated_at FROM features f JOIN car_features cf ON cf.feature_id = f.id WHERE cf.car_id IN (" + placeholders(len(carIDs)) + ") ORDER BY cf.car_id, f.code"
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, int64Args(carIDs)...)
+	if err != nil {
+		return features, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var carID int64
+		var feature entity.Feature
+		err := rows.Scan(&carID, &feature.ID, &feature.Code, &feature.Name, &feature.CreatedAt, &feature.UpdatedAt)
+		if err != nil {
+			return features, err
+		}
+
+		features[carID] = append(features[carID], feature)
+	}
+
+	return features, rows.Err()
+}
+
+func (r *sqliteFeatureRepository) fetch(ctx context.Context, query string, args ...interface{}) (features []entity.Feature, err error) {
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return features, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var feature entity.Feature
+		err := rows.Scan(&feature.ID, &feature.Code, &feature.Name, &feature.CreatedAt, &feature.UpdatedAt)
+		if err != nil {
+			return features, err
+		}
+
+		features = append(features, feature)
+	}
+
+	return features, rows.Err()
+}
+
+func (r *sqliteFeatureRepository) Update(ctx context.Context, feature *entity.Feature) (err error) {
+	query := "UPDATE features SET code = ?, name = ?, updated_at = ? WHERE id = ?"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, feature.Code, feature.Name, feature.UpdatedAt, feature.ID)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func (r *sqliteFeatureRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM features WHERE id = ?"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+// AttachToCar links the features to a car, links that already exist are kept as they are
+func (r *sqliteFeatureRepository) AttachToCar(ctx context.Context, carID int64, featureIDs []int64) (err error) {
+	if len(featureIDs) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(featureIDs))
+	args := make([]interface{}, 0, 2*len(featureIDs))
+	for i, featureID := range featureIDs {
+		values[i] = "(?, ?)"
+		args = append(args, carID, featureID)
+	}
+
+	query := "INSERT OR IGNORE INTO car_features (car_id, feature_id) VALUES " + strings.Join(values, ", ")
+	_, err = transaction.Conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return
+}
+
+func (r *sqliteFeatureRepository) DetachFromCar(ctx context.Context, carID int64, featureID int64) (err error) {
+	query := "DELETE FROM car_features WHERE car_id = ? AND feature_id = ?"
+	_, err = transaction.Conn(ctx, r.db).ExecContext(ctx, query, carID, featureID)
+	return
+}
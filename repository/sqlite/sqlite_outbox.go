@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/tracing"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+)
+
+type sqliteOutboxRepository struct {
+	db *sql.DB
+}
+
+// NewSqliteOutboxRepository will create new an outboxRepository object representation of OutboxRepository interface
+func NewSqliteOutboxRepository(db *sql.DB) pgsql.OutboxRepository {
+	return &sqliteOutboxRepository{
+		db: db,
+	}
+}
+
+func (r *sqliteOutboxRepository) Add(ctx context.Context, event *entity.Event) (err error) {
+	query := "INSERT INTO outbox (event_type, car_id, payload, occurred_at) VALUES (?, ?, ?, ?)"
+	ctx, span := startSpan(ctx, "INSERT", query)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, event.Type, event.CarID, string(event.Payload), event.OccurredAt)
+	if err != nil {
+		return
+	}
+
+	event.ID, err = res.LastInsertId()
+	return
+}
+
+// FetchPending gives the oldest unpublished events in id order, a transaction holds the write
+// lock of the database so a second relay waits for it
+func (r *sqliteOutboxRepository) FetchPending(ctx context.Context, limit int) (events []entity.Event, err error) {
+	query := "SELECT id, event_type, car_id, payload, occurred_at FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT ?"
+	ctx, span := startSpan(ctx, "SELECT", query)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, limit)
+	if err != nil {
+		return events, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var event entity.Event
+		var payload string
+		err := rows.Scan(&event.ID, &event.Type, &event.CarID, &payload, &event.OccurredAt)
+		if err != nil {
+			return events, err
+		}
+
+		event.Payload = []byte(payload)
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *sqliteOutboxRepository) MarkPublished(ctx context.Context, id int64, publishedAt time.Time) (err error) {
+	query := "UPDATE outbox SET published_at = ? WHERE id = ?"
+	ctx, span := startSpan(ctx, "UPDATE", query)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, publishedAt, id)
+	if err != nil {
+		return
+	}
+
+	err = checkAffected(res)
+	return
+}
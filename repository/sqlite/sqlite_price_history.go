@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+)
+
+type sqlitePriceHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewSqlitePriceHistoryRepository will create new a priceHistoryRepository object representation of PriceHistoryRepository interface
+func NewSqlitePriceHistoryRepository(db *sql.DB) pgsql.PriceHistoryRepository {
+	return &sqlitePriceHistoryRepository{
+		db: db,
+	}
+}
+
+func (r *sqlitePriceHistoryRepository) Add(ctx context.Context, point *entity.PricePoint) (err error) {
+	query := "INSERT INTO car_price_history (car_id, price_amount, price_currency, changed_at) VALUES (?, ?, ?, ?)"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, point.CarID, point.Price.Amount, point.Price.Currency, point.ChangedAt)
+	if err != nil {
+		return
+	}
+
+	point.ID, err = res.LastInsertId()
+	return
+}
+
+// FetchByCarIDs gives the history of all the cars at once, grouped by car from the oldest price
+func (r *sqlitePriceHistoryRepository) FetchByCarIDs(ctx context.Context, carIDs []int64) (points []entity.PricePoint, err error) {
+	if len(carIDs) == 0 {
+		return nil, nil
+	}
+
+	query := "SELECT id, car_id, price_amount, price_currency, changed_at FROM car_price_history WHERE car_id IN (" + placeholders(len(carIDs)) + ") ORDER BY car_id, changed_at, id"
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, int64Args(carIDs)...)
+	if err != nil {
+		return points, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var point entity.PricePoint
+		err := rows.Scan(&point.ID, &point.CarID, &point.Price.Amount, &point.Price.Currency, &point.ChangedAt)
+		if err != nil {
+			return points, err
+		}
+
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
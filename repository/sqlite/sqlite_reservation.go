@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+)
+
+type sqliteReservationRepository struct {
+	db *sql.DB
+}
+
+// NewSqliteReservationRepository will create new a reservationRepository object representation of ReservationRepository interface
+func NewSqliteReservationRepository(db *sql.DB) pgsql.ReservationRepository {
+	return &sqliteReservationRepository{
+		db: db,
+	}
+}
+
+func (r *sqliteReservationRepository) GetByCarID(ctx context.Context, carID int64) (reservation entity.Reservation, err error) {
+	query := "SELECT car_id, rep, reserved_at, expires_at FROM car_reservations WHERE car_id = ?"
+	err = transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, carID).Scan(&reservation.CarID, &reservation.Rep, &reservation.ReservedAt, &reservation.ExpiresAt)
+	return
+}
+
+func (r *sqliteReservationRepository) Save(ctx context.Context, reservation *entity.Reservation) (err error) {
+	query := "INSERT INTO car_reservations (car_id, rep, reserved_at, expires_at) VALUES (?, ?, ?, ?) ON CONFLICT (car_id) DO UPDATE SET rep = excluded.rep, reserved_at = excluded.reserved_at, expires_at = excluded.expires_at"
+	_, err = transaction.Conn(ctx, r.db).ExecContext(ctx, query, reservation.CarID, reservation.Rep, reservation.ReservedAt, reservation.ExpiresAt)
+	return
+}
+
+func (r *sqliteReservationRepository) Delete(ctx context.Context, carID int64) (err error) {
+	query := "DELETE FROM car_reservations WHERE car_id = ?"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, carID)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
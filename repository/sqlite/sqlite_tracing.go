@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+
+	"carApi/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan opens a client span for a statement, literals are stripped from the recorded query
+func startSpan(ctx context.Context, operation string, query string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "sqlite "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "sqlite"),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.query.text", tracing.SanitizeSQL(query)),
+		),
+	)
+}
+
+// placeholders gives n comma separated parameters, SQLite has no arrays to bind a list to
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// int64Args gives the ids as the arguments of a placeholders list
+func int64Args(ids []int64) []interface{} {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return args
+}
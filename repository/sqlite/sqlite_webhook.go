@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+)
+
+type sqliteWebhookRepository struct {
+	db *sql.DB
+}
+
+// NewSqliteWebhookRepository will create new a webhookRepository object representation of WebhookRepository interface,
+// the event types are kept as a JSON array
+func NewSqliteWebhookRepository(db *sql.DB) pgsql.WebhookRepository {
+	return &sqliteWebhookRepository{
+		db: db,
+	}
+}
+
+func (r *sqliteWebhookRepository) Create(ctx context.Context, subscription *entity.WebhookSubscription) (err error) {
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return
+	}
+
+	query := "INSERT INTO webhook_subscriptions (url, event_types, secret, created_at, updated_at) VALUES (?, ?, ?, ?, ?)"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, subscription.URL, string(eventTypes), subscription.Secret, subscription.CreatedAt, subscription.UpdatedAt)
+	if err != nil {
+		return
+	}
+
+	subscription.ID, err = res.LastInsertId()
+	return
+}
+
+func (r *sqliteWebhookRepository) GetByID(ctx context.Context, id int64) (subscription entity.WebhookSubscription, err error) {
+	query := "SELECT id, url, event_types, secret, created_at, updated_at FROM webhook_subscriptions WHERE id = ?"
+	err = scanWebhook(transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, id), &subscription)
+	return
+}
+
+func (r *sqliteWebhookRepository) Fetch(ctx context.Context) (subscriptions []entity.WebhookSubscription, err error) {
+	query := "SELECT id, url, event_types, secret, created_at, updated_at FROM webhook_subscriptions ORDER BY id"
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return subscriptions, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var subscription entity.WebhookSubscription
+		if err := scanWebhook(rows, &subscription); err != nil {
+			return subscriptions, err
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *sqliteWebhookRepository) Update(ctx context.Context, subscription *entity.WebhookSubscription) (err error) {
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return
+	}
+
+	query := "UPDATE webhook_subscriptions SET url = ?, event_types = ?, secret = ?, updated_at = ? WHERE id = ?"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, subscription.URL, string(eventTypes), subscription.Secret, subscription.UpdatedAt, subscription.ID)
+	if err != nil {
+		return
+	}
+
+	err = checkAffected(res)
+	return
+}
+
+func (r *sqliteWebhookRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM webhook_subscriptions WHERE id = ?"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+
+	err = checkAffected(res)
+	return
+}
+
+func scanWebhook(row interface {
+	Scan(dest ...interface{}) error
+}, subscription *entity.WebhookSubscription) error {
+	var eventTypes string
+	err := row.Scan(&subscription.ID, &subscription.URL, &eventTypes, &subscription.Secret, &subscription.CreatedAt, &subscription.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(eventTypes), &subscription.EventTypes)
+}
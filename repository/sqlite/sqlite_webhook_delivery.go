@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+)
+
+const webhookDeliveryColumns = "id, subscription_id, event_id, event_type, body, status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at, updated_at"
+
+type sqliteWebhookDeliveryRepository struct {
+	db *sql.DB
+}
+
+// NewSqliteWebhookDeliveryRepository will create new a webhookDeliveryRepository object representation of WebhookDeliveryRepository interface
+func NewSqliteWebhookDeliveryRepository(db *sql.DB) pgsql.WebhookDeliveryRepository {
+	return &sqliteWebhookDeliveryRepository{
+		db: db,
+	}
+}
+
+// Create adds the delivery unless the event was already delivered to the subscription, the ID is
+// then left at zero
+func (r *sqliteWebhookDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) (err error) {
+	query := "INSERT OR IGNORE INTO webhook_deliveries (subscription_id, event_id, event_type, body, status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, delivery.SubscriptionID, delivery.EventID, delivery.EventType, string(delivery.Body), delivery.Status, delivery.Attempts, delivery.NextAttemptAt.UTC(), delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt, delivery.CreatedAt, delivery.UpdatedAt)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil || affect == 0 {
+		return
+	}
+
+	delivery.ID, err = res.LastInsertId()
+	return
+}
+
+func (r *sqliteWebhookDeliveryRepository) GetByID(ctx context.Context, id int64) (delivery entity.WebhookDelivery, err error) {
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE id = ?"
+	err = scanWebhookDelivery(transaction.Conn(ctx, r.db).QueryRowContext(ctx, query, id), &delivery)
+	return
+}
+
+// FetchBySubscriptionID gives the latest deliveries first, an empty status gives them all
+func (r *sqliteWebhookDeliveryRepository) FetchBySubscriptionID(ctx context.Context, subscriptionID int64, status entity.WebhookDeliveryStatus, limit int) ([]entity.WebhookDelivery, error) {
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE subscription_id = ? AND (? = '' OR status = ?) ORDER BY id DESC LIMIT ?"
+	return r.fetch(ctx, query, subscriptionID, status, status, limit)
+}
+
+// FetchDue gives the pending deliveries whose attempt is due, oldest first. Timestamps are stored
+// as text, next_attempt_at is always written in UTC so they compare in time order.
+func (r *sqliteWebhookDeliveryRepository) FetchDue(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?"
+	return r.fetch(ctx, query, entity.WebhookDeliveryPending, now.UTC(), limit)
+}
+
+func (r *sqliteWebhookDeliveryRepository) fetch(ctx context.Context, query string, args ...interface{}) (deliveries []entity.WebhookDelivery, err error) {
+	rows, err := transaction.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return deliveries, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var delivery entity.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &delivery); err != nil {
+			return deliveries, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *sqliteWebhookDeliveryRepository) Update(ctx context.Context, delivery *entity.WebhookDelivery) (err error) {
+	query := "UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, delivered_at = ?, updated_at = ? WHERE id = ?"
+	res, err := transaction.Conn(ctx, r.db).ExecContext(ctx, query, delivery.Status, delivery.Attempts, delivery.NextAttemptAt.UTC(), delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt, delivery.UpdatedAt, delivery.ID)
+	if err != nil {
+		return
+	}
+
+	err = checkAffected(res)
+	return
+}
+
+func scanWebhookDelivery(row interface {
+	Scan(dest ...interface{}) error
+}, delivery *entity.WebhookDelivery) error {
+	var body string
+	var deliveredAt sql.NullTime
+	err := row.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &body, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastStatusCode, &delivery.LastError, &deliveredAt, &delivery.CreatedAt, &delivery.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	delivery.Body = []byte(body)
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return nil
+}
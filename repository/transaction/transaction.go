@@ -0,0 +1,81 @@
+// Package transaction lets a usecase run several repository calls as one unit of work, the
+// transaction travels in the context and the SQL repositories run their statements on it.
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// TxManager represent the unit of work contract
+type TxManager interface {
+	// WithinTransaction runs fn in a transaction, it is committed when fn returns nil and rolled
+	// back when fn fails or panics. A call within fn joins the transaction already running.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Executor is what the repositories need to run statements, both *sql.DB and *sql.Tx are one
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// FromContext gives the transaction running for the context, if any
+func FromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok
+}
+
+// Conn gives the transaction running for the context or db outside of a transaction
+func Conn(ctx context.Context, db *sql.DB) Executor {
+	if tx, ok := FromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
+
+type sqlTxManager struct {
+	db *sql.DB
+}
+
+// NewSQLTxManager will create new a txManager object representation of TxManager interface
+// running the transactions on db
+func NewSQLTxManager(db *sql.DB) TxManager {
+	return &sqlTxManager{
+		db: db,
+	}
+}
+
+func (m *sqlTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := FromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	// the error of fn is returned as it is since callers check its type, a failed rollback
+	// only means the connection is broken and database/sql discards it
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		tx.Rollback()
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
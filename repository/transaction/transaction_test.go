@@ -0,0 +1,148 @@
+package transaction_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"carApi/repository/transaction"
+	"carApi/utils"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestSQLTxManager_WithinTransaction(t *testing.T) {
+	t.Run("commit", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM cars").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = transaction.NewSQLTxManager(db).WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			_, ok := transaction.FromContext(ctx)
+			assert.True(t, ok)
+			_, err := transaction.Conn(ctx, db).ExecContext(ctx, "DELETE FROM cars WHERE id = $1", 1)
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback-on-error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		notFound := utils.NewNotFoundError("car not found")
+		err = transaction.NewSQLTxManager(db).WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			return notFound
+		})
+
+		assert.Equal(t, notFound, err, "the error is returned as it is")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback-on-panic", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		assert.PanicsWithValue(t, "boom", func() {
+			transaction.NewSQLTxManager(db).WithinTransaction(context.TODO(), func(ctx context.Context) error {
+				panic("boom")
+			})
+		})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nested-joins", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		txManager := transaction.NewSQLTxManager(db)
+		err = txManager.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			outer, _ := transaction.FromContext(ctx)
+			return txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+				inner, _ := transaction.FromContext(ctx)
+				assert.Same(t, outer, inner)
+				return errors.New("Unexpected Error")
+			})
+		})
+
+		assert.EqualError(t, err, "Unexpected Error")
+		assert.NoError(t, mock.ExpectationsWereMet(), "the inner failure rolls the whole unit back")
+	})
+
+	t.Run("error-begin", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin().WillReturnError(errors.New("Unexpected Error"))
+
+		called := false
+		err = transaction.NewSQLTxManager(db).WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+
+		assert.EqualError(t, err, "beginning transaction: Unexpected Error")
+		assert.False(t, called)
+	})
+
+	t.Run("error-commit", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(errors.New("Unexpected Error"))
+
+		err = transaction.NewSQLTxManager(db).WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			return nil
+		})
+
+		assert.EqualError(t, err, "committing transaction: Unexpected Error")
+	})
+}
+
+func TestConn(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	assert.Equal(t, transaction.Executor(db), transaction.Conn(context.TODO(), db))
+
+	_, ok := transaction.FromContext(context.TODO())
+	assert.False(t, ok)
+	var _ transaction.Executor = (*sql.Tx)(nil)
+}
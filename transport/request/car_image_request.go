@@ -0,0 +1,35 @@
+package request
+
+import (
+	"io"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// UploadCarImageReq represent upload car image multipart form
+type UploadCarImageReq struct {
+	File      io.Reader
+	Position  *int
+	IsPrimary bool
+}
+
+func (request UploadCarImageReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.File, validation.Required),
+		validation.Field(&request.Position, validation.Min(0)),
+	)
+}
+
+// UpdateCarImageReq represent update car image request body
+type UpdateCarImageReq struct {
+	Position  *int  `json:"position"`
+	IsPrimary *bool `json:"is_primary"`
+}
+
+func (request UpdateCarImageReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Position, validation.Min(0)),
+	)
+}
@@ -1,18 +1,27 @@
 package request
 
-import validation "github.com/go-ozzo/ozzo-validation"
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"carApi/entity"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
 
 // CreateCarReq represent create car request body
 type CreateCarReq struct {
-	Make           string `json:"make"`
-	Model          string `json:"model"`
-	Package        string `json:"package"`
-	Color          string `json:"color"`
-	Year           int    `json:"year"`
-	Category       string `json:"category"`
-	Mileage        int    `json:"mileage"`
-	Price          int    `json:"price"`
-	Identification string `json:"identification"`
+	Make           string   `json:"make" xml:"make"`
+	Model          string   `json:"model" xml:"model"`
+	Package        string   `json:"package" xml:"package"`
+	Color          string   `json:"color" xml:"color"`
+	Year           int      `json:"year" xml:"year"`
+	Category       string   `json:"category" xml:"category"`
+	Mileage        int      `json:"mileage" xml:"mileage"`
+	MileageUnit    string   `json:"mileage_unit" xml:"mileage_unit"`
+	Price          MoneyReq `json:"price" xml:"price"`
+	Identification string   `json:"identification" xml:"identification"`
 }
 
 func (request CreateCarReq) Validate() error {
@@ -25,11 +34,17 @@ func (request CreateCarReq) Validate() error {
 		validation.Field(&request.Year, validation.Required),
 		validation.Field(&request.Category, validation.Required),
 		validation.Field(&request.Mileage, validation.Required),
+		validation.Field(&request.MileageUnit, validation.In(entity.MileageUnitKilometers, entity.MileageUnitMiles)),
 		validation.Field(&request.Price, validation.Required),
 		validation.Field(&request.Identification, validation.Required),
 	)
 }
 
+// MileageKilometers gives the mileage in the canonical unit, kilometers unless mileage_unit says otherwise
+func (request CreateCarReq) MileageKilometers() int {
+	return mileageKilometers(request.Mileage, request.MileageUnit)
+}
+
 // UpdateCarReq represent update car request body
 type UpdateCarReq CreateCarReq
 
@@ -43,6 +58,154 @@ func (request UpdateCarReq) Validate() error {
 		validation.Field(&request.Year, validation.Required),
 		validation.Field(&request.Category, validation.Required),
 		validation.Field(&request.Mileage, validation.Required),
+		validation.Field(&request.MileageUnit, validation.In(entity.MileageUnitKilometers, entity.MileageUnitMiles)),
 		validation.Field(&request.Price, validation.Required),
 		validation.Field(&request.Identification, validation.Required))
 }
+
+// MileageKilometers gives the mileage in the canonical unit, kilometers unless mileage_unit says otherwise
+func (request UpdateCarReq) MileageKilometers() int {
+	return mileageKilometers(request.Mileage, request.MileageUnit)
+}
+
+// GetCarReq represent get car query parameters, the Accept-Language header
+// decides the mileage unit when no mileage_unit is given
+type GetCarReq struct {
+	Currency       string `query:"currency"`
+	MileageUnit    string `query:"mileage_unit"`
+	AcceptLanguage string `header:"Accept-Language"`
+}
+
+func (request GetCarReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Currency, currencyRule),
+		validation.Field(&request.MileageUnit, validation.In(entity.MileageUnitKilometers, entity.MileageUnitMiles)),
+	)
+}
+
+// Display converts the query parameters into the car display options
+func (request GetCarReq) Display() entity.CarDisplay {
+	return entity.CarDisplay{
+		Currency:    strings.ToUpper(request.Currency),
+		MileageUnit: resolveMileageUnit(request.MileageUnit, request.AcceptLanguage),
+	}
+}
+
+// FetchCarReq represent fetch car query parameters, mileage bounds are given in
+// the requested mileage unit which falls back to the Accept-Language header
+type FetchCarReq struct {
+	Features       string `query:"features"`
+	FeaturesMatch  string `query:"features_match"`
+	Currency       string `query:"currency"`
+	MileageMin     *int   `query:"mileage_min"`
+	MileageMax     *int   `query:"mileage_max"`
+	MileageUnit    string `query:"mileage_unit"`
+	AcceptLanguage string `header:"Accept-Language"`
+}
+
+func (request FetchCarReq) Validate() error {
+	errs := validation.Errors{
+		"features_match": validation.Validate(request.FeaturesMatch, validation.In(entity.FeatureMatchAll, entity.FeatureMatchAny)),
+		"currency":       validation.Validate(request.Currency, currencyRule),
+		"mileage_min":    validation.Validate(request.MileageMin, validation.Min(0)),
+		"mileage_max":    validation.Validate(request.MileageMax, validation.Min(0)),
+		"mileage_unit":   validation.Validate(request.MileageUnit, validation.In(entity.MileageUnitKilometers, entity.MileageUnitMiles)),
+	}
+	if errs["mileage_max"] == nil && request.MileageMin != nil && request.MileageMax != nil && *request.MileageMax < *request.MileageMin {
+		errs["mileage_max"] = errors.New("must be no less than mileage_min")
+	}
+	return errs.Filter()
+}
+
+// FetchCarPageReq represent a page of the car listing, the listing filters apply and the page
+// starts Offset cars in
+type FetchCarPageReq struct {
+	FetchCarReq
+	Limit  int
+	Offset int
+}
+
+func (request FetchCarPageReq) Validate() error {
+	if err := request.FetchCarReq.Validate(); err != nil {
+		return err
+	}
+	return validation.Errors{
+		"limit":  validation.Validate(request.Limit, validation.Required, validation.Min(1), validation.Max(100)),
+		"offset": validation.Validate(request.Offset, validation.Min(0)),
+	}.Filter()
+}
+
+// Page gives the window of the listing the repository reads
+func (request FetchCarPageReq) Page() entity.Page {
+	return entity.Page{Limit: request.Limit, Offset: request.Offset}
+}
+
+// StreamCarReq represent stream car query parameters, the listing filters apply and the
+// Last-Event-ID header, or last_event_id for clients that cannot set it, resumes the stream
+type StreamCarReq struct {
+	FetchCarReq
+	LastEventID      int64 `header:"Last-Event-ID"`
+	LastEventIDQuery int64 `query:"last_event_id"`
+}
+
+func (request StreamCarReq) Validate() error {
+	if err := request.FetchCarReq.Validate(); err != nil {
+		return err
+	}
+	return validation.Errors{
+		"Last-Event-ID": validation.Validate(request.LastEventID, validation.Min(int64(0))),
+		"last_event_id": validation.Validate(request.LastEventIDQuery, validation.Min(int64(0))),
+	}.Filter()
+}
+
+// ResumeFrom gives the id of the last event the client got, zero when it starts afresh
+func (request StreamCarReq) ResumeFrom() int64 {
+	if request.LastEventID != 0 {
+		return request.LastEventID
+	}
+	return request.LastEventIDQuery
+}
+
+// Display converts the query parameters into the car display options
+func (request FetchCarReq) Display() entity.CarDisplay {
+	return entity.CarDisplay{
+		Currency:    strings.ToUpper(request.Currency),
+		MileageUnit: resolveMileageUnit(request.MileageUnit, request.AcceptLanguage),
+	}
+}
+
+// Filter converts the query parameters into a car filter, feature codes are
+// normalized and deduplicated so "all" semantics count each feature once
+func (request FetchCarReq) Filter() entity.CarFilter {
+	filter := entity.CarFilter{
+		FeatureMatch: entity.FeatureMatchAll,
+	}
+
+	// bounds are widened to whole kilometers so no car inside the requested range is left out
+	unit := resolveMileageUnit(request.MileageUnit, request.AcceptLanguage)
+	if request.MileageMin != nil {
+		mileageMin := int(math.Floor(entity.ToKilometers(float64(*request.MileageMin), unit)))
+		filter.MileageMin = &mileageMin
+	}
+	if request.MileageMax != nil {
+		mileageMax := int(math.Ceil(entity.ToKilometers(float64(*request.MileageMax), unit)))
+		filter.MileageMax = &mileageMax
+	}
+	if request.FeaturesMatch != "" {
+		filter.FeatureMatch = request.FeaturesMatch
+	}
+
+	seen := map[string]bool{}
+	for _, code := range strings.Split(request.Features, ",") {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		filter.Features = append(filter.Features, code)
+	}
+	sort.Strings(filter.Features)
+
+	return filter
+}
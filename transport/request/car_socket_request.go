@@ -0,0 +1,43 @@
+package request
+
+import (
+	"carApi/entity"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// ConnectCarSocketReq represent car socket handshake parameters, the get car parameters apply to
+// the cars pushed to it. The sales rep behind the socket is the one its rep key names, rep may
+// only repeat that name.
+type ConnectCarSocketReq struct {
+	GetCarReq
+	Rep string `query:"rep"`
+}
+
+func (request ConnectCarSocketReq) Validate() error {
+	if err := request.GetCarReq.Validate(); err != nil {
+		return err
+	}
+	return validation.Errors{
+		"rep": validation.Validate(request.Rep, validation.Length(1, 64)),
+	}.Filter()
+}
+
+// CarSocketMessageReq represent a message sent by a client over the car socket
+type CarSocketMessageReq struct {
+	Type   entity.CarSocketMessageType `json:"type"`
+	CarIDs []int64                     `json:"car_ids"`
+	CarID  int64                       `json:"car_id"`
+}
+
+func (request CarSocketMessageReq) Validate() error {
+	errs := validation.Errors{
+		"type": validation.Validate(request.Type, validation.Required, validation.In(entity.CarSocketSubscribe, entity.CarSocketUnsubscribe, entity.CarSocketView, entity.CarSocketLeave, entity.CarSocketReserve, entity.CarSocketRelease)),
+	}
+	switch request.Type {
+	case entity.CarSocketSubscribe, entity.CarSocketUnsubscribe:
+		errs["car_ids"] = validation.Validate(request.CarIDs, validation.Required, validation.Each(validation.Min(int64(1))))
+	case entity.CarSocketView, entity.CarSocketLeave, entity.CarSocketReserve, entity.CarSocketRelease:
+		errs["car_id"] = validation.Validate(request.CarID, validation.Required, validation.Min(int64(1)))
+	}
+	return errs.Filter()
+}
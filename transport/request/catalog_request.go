@@ -0,0 +1,15 @@
+package request
+
+import validation "github.com/go-ozzo/ozzo-validation"
+
+// CatalogEntryReq represent create and update catalog entry request body
+type CatalogEntryReq struct {
+	Name string `json:"name"`
+}
+
+func (request CatalogEntryReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 64)),
+	)
+}
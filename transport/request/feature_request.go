@@ -0,0 +1,46 @@
+package request
+
+import (
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// featureCodePattern restricts feature codes to the snake_case identifiers used in list filters
+var featureCodePattern = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// CreateFeatureReq represent create feature request body
+type CreateFeatureReq struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+func (request CreateFeatureReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Code, validation.Required, validation.Length(1, 64), validation.Match(featureCodePattern)),
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 128)),
+	)
+}
+
+// UpdateFeatureReq represent update feature request body
+type UpdateFeatureReq CreateFeatureReq
+
+func (request UpdateFeatureReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Code, validation.Required, validation.Length(1, 64), validation.Match(featureCodePattern)),
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 128)))
+}
+
+// AttachCarFeaturesReq represent attach features to a car request body
+type AttachCarFeaturesReq struct {
+	Features []string `json:"features"`
+}
+
+func (request AttachCarFeaturesReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Features, validation.Required, validation.Each(validation.Match(featureCodePattern))),
+	)
+}
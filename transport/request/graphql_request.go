@@ -0,0 +1,21 @@
+package request
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// GraphQLReq represent a GraphQL request body, the Accept-Language header decides the mileage
+// unit of the cars when a query gives no mileageUnit
+type GraphQLReq struct {
+	Query          string                 `json:"query"`
+	OperationName  string                 `json:"operationName"`
+	Variables      map[string]interface{} `json:"variables"`
+	AcceptLanguage string                 `header:"Accept-Language"`
+}
+
+func (request GraphQLReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Query, validation.Required),
+	)
+}
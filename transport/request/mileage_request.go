@@ -0,0 +1,47 @@
+package request
+
+import (
+	"math"
+
+	"carApi/entity"
+	"golang.org/x/text/language"
+)
+
+// milesRegions are the regions where road distances are given in miles
+var milesRegions = map[string]bool{
+	"GB": true,
+	"LR": true,
+	"MM": true,
+	"US": true,
+}
+
+// MileageUnitFromLanguage derives the mileage unit from the preferred region of an
+// Accept-Language header, an empty unit is returned when no region is given
+func MileageUnitFromLanguage(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return ""
+	}
+
+	region, confidence := tags[0].Region()
+	if confidence != language.Exact {
+		return ""
+	}
+	if milesRegions[region.String()] {
+		return entity.MileageUnitMiles
+	}
+	return entity.MileageUnitKilometers
+}
+
+// resolveMileageUnit prefers the explicit unit over the one derived from the language
+func resolveMileageUnit(unit string, acceptLanguage string) string {
+	if unit != "" {
+		return unit
+	}
+	return MileageUnitFromLanguage(acceptLanguage)
+}
+
+// mileageKilometers converts a mileage given in unit to whole kilometers
+func mileageKilometers(value int, unit string) int {
+	return int(math.Round(entity.ToKilometers(float64(value), unit)))
+}
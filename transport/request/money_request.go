@@ -0,0 +1,43 @@
+package request
+
+import (
+	"errors"
+	"strings"
+
+	"carApi/entity"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// currencyRule accepts the ISO 4217 codes listed in entity.Currencies, in any casing
+var currencyRule = validation.By(func(value interface{}) error {
+	currency, _ := value.(string)
+	if currency == "" {
+		return nil
+	}
+	if _, ok := entity.Currencies[strings.ToUpper(currency)]; !ok {
+		return errors.New("must be a supported ISO 4217 currency code")
+	}
+	return nil
+})
+
+// MoneyReq represent an amount in minor units, e.g. cents, and its currency
+type MoneyReq struct {
+	Amount   int64  `json:"amount" xml:"amount"`
+	Currency string `json:"currency" xml:"currency"`
+}
+
+func (request MoneyReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Amount, validation.Required, validation.Min(int64(0))),
+		validation.Field(&request.Currency, validation.Required, currencyRule),
+	)
+}
+
+// Money converts the request into the money value object
+func (request MoneyReq) Money() entity.Money {
+	return entity.Money{
+		Amount:   request.Amount,
+		Currency: strings.ToUpper(request.Currency),
+	}
+}
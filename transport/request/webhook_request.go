@@ -0,0 +1,77 @@
+package request
+
+import (
+	"errors"
+	"net/url"
+
+	"carApi/entity"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// webhookURLRule accepts absolute http and https URLs, the webhook usecase then checks that their
+// host only resolves to public addresses
+var webhookURLRule = validation.By(func(value interface{}) error {
+	raw, _ := value.(string)
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an absolute http or https URL")
+	}
+	return nil
+})
+
+// eventTypeRule accepts the types listed in entity.EventTypes
+var eventTypeRule = validation.By(func(value interface{}) error {
+	eventType, _ := value.(entity.EventType)
+	for _, known := range entity.EventTypes {
+		if eventType == known {
+			return nil
+		}
+	}
+	return errors.New("must be a known event type")
+})
+
+// CreateWebhookReq represent create webhook subscription request body
+type CreateWebhookReq struct {
+	URL        string             `json:"url"`
+	EventTypes []entity.EventType `json:"event_types"`
+	Secret     string             `json:"secret"`
+}
+
+func (request CreateWebhookReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.URL, validation.Required, validation.Length(1, 2048), webhookURLRule),
+		validation.Field(&request.EventTypes, validation.Required, validation.Each(eventTypeRule)),
+		validation.Field(&request.Secret, validation.Required, validation.Length(16, 256)),
+	)
+}
+
+// UpdateWebhookReq represent update webhook subscription request body, an empty secret keeps the current one
+type UpdateWebhookReq CreateWebhookReq
+
+func (request UpdateWebhookReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.URL, validation.Required, validation.Length(1, 2048), webhookURLRule),
+		validation.Field(&request.EventTypes, validation.Required, validation.Each(eventTypeRule)),
+		validation.Field(&request.Secret, validation.Length(16, 256)),
+	)
+}
+
+// FetchWebhookDeliveriesReq represent fetch webhook deliveries query parameters, the latest
+// deliveries come first
+type FetchWebhookDeliveriesReq struct {
+	Status string `query:"status"`
+	Limit  int    `query:"limit"`
+}
+
+func (request FetchWebhookDeliveriesReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Status, validation.In(string(entity.WebhookDeliveryPending), string(entity.WebhookDeliverySucceeded), string(entity.WebhookDeliveryDead))),
+		validation.Field(&request.Limit, validation.Min(0), validation.Max(100)),
+	)
+}
@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/storage"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+	"carApi/transport/request"
+	"carApi/utils"
+	"carApi/utils/media"
+	uuid "github.com/satori/go.uuid"
+)
+
+// CarImageUsecase represent the car image's usecase contract
+type CarImageUsecase interface {
+	Upload(ctx context.Context, carID int64, request *request.UploadCarImageReq) (entity.CarImage, error)
+	Fetch(ctx context.Context, carID int64) ([]entity.CarImage, error)
+	Update(ctx context.Context, carID int64, id int64, request *request.UpdateCarImageReq) error
+	Delete(ctx context.Context, carID int64, id int64) error
+}
+
+type carImageUsecase struct {
+	carRepo       pgsql.CarRepository
+	carImageRepo  pgsql.CarImageRepository
+	txManager     transaction.TxManager
+	storage       storage.Storage
+	maxUploadSize int64
+	maxPixels     int64
+	ctxTimeout    time.Duration
+}
+
+// NewCarImageUsecase will create new a carImageUsecase object representation of CarImageUsecase interface
+func NewCarImageUsecase(carRepo pgsql.CarRepository, carImageRepo pgsql.CarImageRepository, txManager transaction.TxManager, storage storage.Storage, maxUploadSize int64, maxPixels int64, ctxTimeout time.Duration) CarImageUsecase {
+	return &carImageUsecase{
+		carRepo:       carRepo,
+		carImageRepo:  carImageRepo,
+		txManager:     txManager,
+		storage:       storage,
+		maxUploadSize: maxUploadSize,
+		maxPixels:     maxPixels,
+		ctxTimeout:    ctxTimeout,
+	}
+}
+
+func (u *carImageUsecase) Upload(c context.Context, carID int64, request *request.UploadCarImageReq) (carImage entity.CarImage, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	if _, err = u.carRepo.GetByID(ctx, carID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = utils.NewError(utils.CodeCarNotFound, "car not found")
+		}
+		return
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(request.File, u.maxUploadSize+1))
+	if err != nil {
+		return
+	}
+
+	if int64(len(data)) > u.maxUploadSize {
+		err = utils.NewError(utils.CodeImageTooLarge, fmt.Sprintf("image must not exceed %d bytes", u.maxUploadSize))
+		return
+	}
+
+	contentType := media.DetectContentType(data)
+	ext, ok := media.Extension(contentType)
+	if !ok {
+		err = utils.NewError(utils.CodeImageTypeUnsupported, fmt.Sprintf("%s is not a supported image type", contentType))
+		return
+	}
+
+	// the dimensions are read from the header first, a small file may decode to a huge image
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		err = utils.NewError(utils.CodeImageUnreadable, "image could not be decoded")
+		return
+	}
+
+	if int64(config.Width)*int64(config.Height) > u.maxPixels {
+		err = utils.NewError(utils.CodeImageTooLarge, fmt.Sprintf("image must not exceed %d pixels", u.maxPixels))
+		return
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		err = utils.NewError(utils.CodeImageUnreadable, "image could not be decoded")
+		return
+	}
+
+	key := fmt.Sprintf("cars/%d/%s%s", carID, uuid.NewV4().String(), ext)
+	uploaded, err := u.store(ctx, key, img, data, contentType)
+	if err != nil {
+		return
+	}
+
+	// the position and the primary image are taken from the images read in the same transaction,
+	// the row is rolled back as a whole and the stored files go with it
+	bounds := img.Bounds()
+	err = u.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		images, err := u.carImageRepo.FetchByCarID(ctx, carID)
+		if err != nil {
+			return err
+		}
+
+		position := len(images)
+		if request.Position != nil {
+			position = *request.Position
+		}
+
+		carImage = entity.CarImage{
+			CarID:       carID,
+			StorageKey:  key,
+			ContentType: contentType,
+			Size:        int64(len(data)),
+			Width:       bounds.Dx(),
+			Height:      bounds.Dy(),
+			Position:    position,
+			IsPrimary:   request.IsPrimary || len(images) == 0,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+
+		if err = u.carImageRepo.Create(ctx, &carImage); err != nil {
+			return err
+		}
+
+		if carImage.IsPrimary && len(images) > 0 {
+			return u.carImageRepo.SetPrimary(ctx, carID, carImage.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		cleanup(ctx, u.storage, uploaded)
+		carImage = entity.CarImage{}
+		return
+	}
+
+	resolveImageURLs(u.storage, &carImage)
+	return
+}
+
+// store uploads the original image and all of its thumbnails, it returns the
+// keys that were written so a failed upload can be cleaned up
+func (u *carImageUsecase) store(ctx context.Context, key string, img image.Image, data []byte, contentType string) (uploaded []string, err error) {
+	if err = u.storage.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return
+	}
+	uploaded = append(uploaded, key)
+
+	for size, maxSide := range entity.ThumbnailSizes {
+		var buf bytes.Buffer
+		if err = media.Encode(&buf, media.Thumbnail(img, maxSide), contentType); err != nil {
+			cleanup(ctx, u.storage, uploaded)
+			return nil, err
+		}
+
+		thumbnailKey := media.ThumbnailKey(key, size)
+		if err = u.storage.Put(ctx, thumbnailKey, &buf, int64(buf.Len()), contentType); err != nil {
+			cleanup(ctx, u.storage, uploaded)
+			return nil, err
+		}
+		uploaded = append(uploaded, thumbnailKey)
+	}
+
+	return
+}
+
+// cleanup removes stored objects on a best-effort basis, an orphaned object
+// is harmless while a failed request must still report its original error
+func cleanup(ctx context.Context, storage storage.Storage, keys []string) {
+	for _, key := range keys {
+		_ = storage.Delete(ctx, key)
+	}
+}
+
+// imageKeys gives the keys of the original image and all of its thumbnails
+func imageKeys(carImage entity.CarImage) []string {
+	keys := []string{carImage.StorageKey}
+	for size := range entity.ThumbnailSizes {
+		keys = append(keys, media.ThumbnailKey(carImage.StorageKey, size))
+	}
+	return keys
+}
+
+func (u *carImageUsecase) Fetch(c context.Context, carID int64) (images []entity.CarImage, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	if _, err = u.carRepo.GetByID(ctx, carID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = utils.NewError(utils.CodeCarNotFound, "car not found")
+		}
+		return
+	}
+
+	images, err = u.carImageRepo.FetchByCarID(ctx, carID)
+	if err != nil {
+		return
+	}
+
+	for i := range images {
+		resolveImageURLs(u.storage, &images[i])
+	}
+	return
+}
+
+func (u *carImageUsecase) Update(c context.Context, carID int64, id int64, request *request.UpdateCarImageReq) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	carImage, err := u.getCarImage(ctx, carID, id)
+	if err != nil {
+		return
+	}
+
+	if request.Position != nil {
+		carImage.Position = *request.Position
+	}
+	if request.IsPrimary != nil {
+		carImage.IsPrimary = *request.IsPrimary
+	}
+	carImage.UpdatedAt = time.Now()
+
+	if err = u.carImageRepo.Update(ctx, &carImage); err != nil {
+		return
+	}
+
+	if carImage.IsPrimary {
+		err = u.carImageRepo.SetPrimary(ctx, carID, carImage.ID)
+	}
+	return
+}
+
+func (u *carImageUsecase) Delete(c context.Context, carID int64, id int64) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	carImage, err := u.getCarImage(ctx, carID, id)
+	if err != nil {
+		return
+	}
+
+	if err = u.carImageRepo.Delete(ctx, id); err != nil {
+		return
+	}
+
+	cleanup(ctx, u.storage, imageKeys(carImage))
+
+	if !carImage.IsPrimary {
+		return
+	}
+
+	// promote the next image in order so the car keeps a primary image
+	images, err := u.carImageRepo.FetchByCarID(ctx, carID)
+	if err != nil || len(images) == 0 {
+		return
+	}
+
+	err = u.carImageRepo.SetPrimary(ctx, carID, images[0].ID)
+	return
+}
+
+func (u *carImageUsecase) getCarImage(ctx context.Context, carID int64, id int64) (carImage entity.CarImage, err error) {
+	carImage, err = u.carImageRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = utils.NewError(utils.CodeCarImageNotFound, "car image not found")
+		}
+		return
+	}
+
+	if carImage.CarID != carID {
+		err = utils.NewError(utils.CodeCarImageNotFound, "car image not found")
+	}
+	return
+}
+
+// resolveImageURLs fills the public URLs of an image and its thumbnails
+func resolveImageURLs(storage storage.Storage, carImage *entity.CarImage) {
+	carImage.URL = storage.URL(carImage.StorageKey)
+	carImage.Thumbnails = make(map[string]string, len(entity.ThumbnailSizes))
+	for size := range entity.ThumbnailSizes {
+		carImage.Thumbnails[size] = storage.URL(media.ThumbnailKey(carImage.StorageKey, size))
+	}
+}
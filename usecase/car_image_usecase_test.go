@@ -0,0 +1,276 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var maxPixels = int64(4_000_000)
+
+func newPNG(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func TestCarImageUC_Upload(t *testing.T) {
+	mockCarRepo := new(mocks.CarRepository)
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockTxManager := new(mocks.TxManager)
+	mockStorage := new(mocks.Storage)
+	mockCar := entity.Car{ID: 1}
+	maxUploadSize := int64(1 << 20)
+
+	t.Run("success", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
+		mockStorage.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), "image/png").
+			Return(nil).Times(1 + len(entity.ThumbnailSizes))
+		expectTransaction(mockTxManager)
+		mockCarImageRepo.On("FetchByCarID", withinTransaction, mock.AnythingOfType("int64")).Return([]entity.CarImage{}, nil).Once()
+		mockCarImageRepo.On("Create", withinTransaction, mock.AnythingOfType("*entity.CarImage")).Return(nil).Once()
+		mockStorage.On("URL", mock.AnythingOfType("string")).Return("http://localhost/media/image.png")
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, maxUploadSize, maxPixels, ctxTimeout)
+		carImage, err := carImageUsecase.Upload(context.TODO(), mockCar.ID, &request.UploadCarImageReq{
+			File: bytes.NewReader(newPNG(t, 2000, 1000)),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", carImage.ContentType)
+		assert.Equal(t, 2000, carImage.Width)
+		assert.True(t, carImage.IsPrimary)
+		assert.Len(t, carImage.Thumbnails, len(entity.ThumbnailSizes))
+		assert.True(t, strings.HasPrefix(carImage.StorageKey, "cars/1/"))
+		mockCarRepo.AssertExpectations(t)
+		mockCarImageRepo.AssertExpectations(t)
+		mockTxManager.AssertExpectations(t)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("primary-replaces-existing", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
+		mockStorage.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), "image/png").
+			Return(nil).Times(1 + len(entity.ThumbnailSizes))
+		expectTransaction(mockTxManager)
+		mockCarImageRepo.On("FetchByCarID", withinTransaction, mock.AnythingOfType("int64")).Return([]entity.CarImage{{ID: 1, IsPrimary: true}}, nil).Once()
+		mockCarImageRepo.On("Create", withinTransaction, mock.AnythingOfType("*entity.CarImage")).Return(nil).Once()
+		mockCarImageRepo.On("SetPrimary", withinTransaction, mockCar.ID, mock.AnythingOfType("int64")).Return(nil).Once()
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, maxUploadSize, maxPixels, ctxTimeout)
+		carImage, err := carImageUsecase.Upload(context.TODO(), mockCar.ID, &request.UploadCarImageReq{
+			File:      bytes.NewReader(newPNG(t, 10, 10)),
+			IsPrimary: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, carImage.Position)
+		mockCarImageRepo.AssertExpectations(t)
+		mockTxManager.AssertExpectations(t)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("car-not-exist", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(entity.Car{}, sql.ErrNoRows).Once()
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, maxUploadSize, maxPixels, ctxTimeout)
+		_, err := carImageUsecase.Upload(context.TODO(), mockCar.ID, &request.UploadCarImageReq{
+			File: bytes.NewReader(newPNG(t, 10, 10)),
+		})
+
+		require.Error(t, err)
+		assert.Equal(t, 404, err.(utils.HttpErr).Status())
+		mockCarRepo.AssertExpectations(t)
+	})
+
+	t.Run("too-large", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, 16, maxPixels, ctxTimeout)
+		_, err := carImageUsecase.Upload(context.TODO(), mockCar.ID, &request.UploadCarImageReq{
+			File: bytes.NewReader(newPNG(t, 10, 10)),
+		})
+
+		require.Error(t, err)
+		assert.Equal(t, 413, err.(utils.HttpErr).Status())
+		mockCarRepo.AssertExpectations(t)
+	})
+
+	t.Run("too-many-pixels", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
+
+		mockStorage := new(mocks.Storage)
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, maxUploadSize, 100*100, ctxTimeout)
+		_, err := carImageUsecase.Upload(context.TODO(), mockCar.ID, &request.UploadCarImageReq{
+			File: bytes.NewReader(newPNG(t, 101, 100)),
+		})
+
+		require.Error(t, err)
+		assert.Equal(t, 413, err.(utils.HttpErr).Status())
+		assert.Equal(t, utils.CodeImageTooLarge, err.(utils.HttpErr).Code())
+		mockCarRepo.AssertExpectations(t)
+		mockStorage.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unsupported-type", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, maxUploadSize, maxPixels, ctxTimeout)
+		_, err := carImageUsecase.Upload(context.TODO(), mockCar.ID, &request.UploadCarImageReq{
+			File: strings.NewReader("%PDF-1.4 not an image"),
+		})
+
+		require.Error(t, err)
+		assert.Equal(t, 415, err.(utils.HttpErr).Status())
+		mockCarRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-db-cleans-up-storage", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
+		mockStorage.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), "image/png").
+			Return(nil).Times(1 + len(entity.ThumbnailSizes))
+		expectTransaction(mockTxManager)
+		mockCarImageRepo.On("FetchByCarID", withinTransaction, mock.AnythingOfType("int64")).Return([]entity.CarImage{}, nil).Once()
+		mockCarImageRepo.On("Create", withinTransaction, mock.AnythingOfType("*entity.CarImage")).Return(errors.New("Unexpected Error")).Once()
+		mockStorage.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Times(1 + len(entity.ThumbnailSizes))
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, maxUploadSize, maxPixels, ctxTimeout)
+		_, err := carImageUsecase.Upload(context.TODO(), mockCar.ID, &request.UploadCarImageReq{
+			File: bytes.NewReader(newPNG(t, 10, 10)),
+		})
+
+		assert.Error(t, err)
+		mockCarImageRepo.AssertExpectations(t)
+		mockTxManager.AssertExpectations(t)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("error-set-primary-cleans-up-storage", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
+		mockStorage.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), "image/png").
+			Return(nil).Times(1 + len(entity.ThumbnailSizes))
+		expectTransaction(mockTxManager)
+		mockCarImageRepo.On("FetchByCarID", withinTransaction, mock.AnythingOfType("int64")).Return([]entity.CarImage{{ID: 1, IsPrimary: true}}, nil).Once()
+		mockCarImageRepo.On("Create", withinTransaction, mock.AnythingOfType("*entity.CarImage")).Return(nil).Once()
+		mockCarImageRepo.On("SetPrimary", withinTransaction, mockCar.ID, mock.AnythingOfType("int64")).Return(errors.New("Unexpected Error")).Once()
+		mockStorage.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Times(1 + len(entity.ThumbnailSizes))
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, maxUploadSize, maxPixels, ctxTimeout)
+		_, err := carImageUsecase.Upload(context.TODO(), mockCar.ID, &request.UploadCarImageReq{
+			File:      bytes.NewReader(newPNG(t, 10, 10)),
+			IsPrimary: true,
+		})
+
+		assert.Error(t, err)
+		mockCarImageRepo.AssertExpectations(t)
+		mockTxManager.AssertExpectations(t)
+		mockStorage.AssertExpectations(t)
+	})
+}
+
+func TestCarImageUC_Fetch(t *testing.T) {
+	mockCarRepo := new(mocks.CarRepository)
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockTxManager := new(mocks.TxManager)
+	mockStorage := new(mocks.Storage)
+	mockCarImages := []entity.CarImage{
+		{ID: 1, CarID: 1, StorageKey: "cars/1/a.jpg", Position: 0, IsPrimary: true},
+		{ID: 2, CarID: 1, StorageKey: "cars/1/b.jpg", Position: 1},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(entity.Car{ID: 1}, nil).Once()
+		mockCarImageRepo.On("FetchByCarID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCarImages, nil).Once()
+		mockStorage.On("URL", mock.AnythingOfType("string")).Return("http://localhost/media/image.jpg")
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, 1<<20, maxPixels, ctxTimeout)
+		images, err := carImageUsecase.Fetch(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, images, 2)
+		assert.NotEmpty(t, images[1].URL)
+		mockCarImageRepo.AssertExpectations(t)
+	})
+}
+
+func TestCarImageUC_Update(t *testing.T) {
+	mockCarRepo := new(mocks.CarRepository)
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockTxManager := new(mocks.TxManager)
+	mockStorage := new(mocks.Storage)
+	mockCarImage := entity.CarImage{ID: 2, CarID: 1, StorageKey: "cars/1/b.jpg", Position: 1, UpdatedAt: time.Now()}
+
+	t.Run("success", func(t *testing.T) {
+		position, primary := 0, true
+		mockCarImageRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCarImage, nil).Once()
+		mockCarImageRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.CarImage")).Return(nil).Once()
+		mockCarImageRepo.On("SetPrimary", mock.Anything, int64(1), int64(2)).Return(nil).Once()
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, 1<<20, maxPixels, ctxTimeout)
+		err := carImageUsecase.Update(context.TODO(), 1, 2, &request.UpdateCarImageReq{Position: &position, IsPrimary: &primary})
+
+		assert.NoError(t, err)
+		mockCarImageRepo.AssertExpectations(t)
+	})
+
+	t.Run("image-of-other-car", func(t *testing.T) {
+		mockCarImageRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCarImage, nil).Once()
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, 1<<20, maxPixels, ctxTimeout)
+		err := carImageUsecase.Update(context.TODO(), 9, 2, &request.UpdateCarImageReq{})
+
+		require.Error(t, err)
+		assert.Equal(t, 404, err.(utils.HttpErr).Status())
+		mockCarImageRepo.AssertExpectations(t)
+	})
+}
+
+func TestCarImageUC_Delete(t *testing.T) {
+	mockCarRepo := new(mocks.CarRepository)
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockTxManager := new(mocks.TxManager)
+	mockStorage := new(mocks.Storage)
+	mockCarImage := entity.CarImage{ID: 1, CarID: 1, StorageKey: "cars/1/a.jpg", IsPrimary: true}
+
+	t.Run("success-promotes-next-image", func(t *testing.T) {
+		mockCarImageRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCarImage, nil).Once()
+		mockCarImageRepo.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+		mockStorage.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Times(1 + len(entity.ThumbnailSizes))
+		mockCarImageRepo.On("FetchByCarID", mock.Anything, int64(1)).Return([]entity.CarImage{{ID: 2, CarID: 1}}, nil).Once()
+		mockCarImageRepo.On("SetPrimary", mock.Anything, int64(1), int64(2)).Return(nil).Once()
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, 1<<20, maxPixels, ctxTimeout)
+		err := carImageUsecase.Delete(context.TODO(), 1, 1)
+
+		assert.NoError(t, err)
+		mockCarImageRepo.AssertExpectations(t)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("image-not-exist", func(t *testing.T) {
+		mockCarImageRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(entity.CarImage{}, sql.ErrNoRows).Once()
+
+		carImageUsecase := usecase.NewCarImageUsecase(mockCarRepo, mockCarImageRepo, mockTxManager, mockStorage, 1<<20, maxPixels, ctxTimeout)
+		err := carImageUsecase.Delete(context.TODO(), 1, 1)
+
+		require.Error(t, err)
+		assert.Equal(t, 404, err.(utils.HttpErr).Status())
+		mockCarImageRepo.AssertExpectations(t)
+	})
+}
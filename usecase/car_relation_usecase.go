@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/storage"
+	"carApi/infrastructure/tracing"
+	"carApi/repository/pgsql"
+)
+
+// CarRelationUsecase loads the relations of many cars in one query each, so a listing resolving
+// them car by car does not query once per car. Every requested car has an entry, empty when it
+// has nothing related.
+type CarRelationUsecase interface {
+	Images(ctx context.Context, carIDs []int64) (map[int64][]entity.CarImage, error)
+	Features(ctx context.Context, carIDs []int64) (map[int64][]entity.Feature, error)
+	PriceHistory(ctx context.Context, carIDs []int64) (map[int64][]entity.PricePoint, error)
+}
+
+type carRelationUsecase struct {
+	carImageRepo pgsql.CarImageRepository
+	featureRepo  pgsql.FeatureRepository
+	priceRepo    pgsql.PriceHistoryRepository
+	storage      storage.Storage
+	ctxTimeout   time.Duration
+}
+
+// NewCarRelationUsecase will create new a carRelationUsecase object representation of CarRelationUsecase interface
+func NewCarRelationUsecase(carImageRepo pgsql.CarImageRepository, featureRepo pgsql.FeatureRepository, priceRepo pgsql.PriceHistoryRepository, storage storage.Storage, ctxTimeout time.Duration) CarRelationUsecase {
+	return &carRelationUsecase{
+		carImageRepo: carImageRepo,
+		featureRepo:  featureRepo,
+		priceRepo:    priceRepo,
+		storage:      storage,
+		ctxTimeout:   ctxTimeout,
+	}
+}
+
+// Images gives the images of the cars in display order with their public URLs
+func (u *carRelationUsecase) Images(c context.Context, carIDs []int64) (images map[int64][]entity.CarImage, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "carRelationUsecase.Images")
+	defer func() { tracing.End(span, err) }()
+
+	list, err := u.carImageRepo.FetchByCarIDs(ctx, carIDs)
+	if err != nil {
+		return
+	}
+
+	images = make(map[int64][]entity.CarImage, len(carIDs))
+	for _, carID := range carIDs {
+		images[carID] = []entity.CarImage{}
+	}
+	for _, image := range list {
+		resolveImageURLs(u.storage, &image)
+		images[image.CarID] = append(images[image.CarID], image)
+	}
+	return
+}
+
+// Features gives the features of the cars ordered by code
+func (u *carRelationUsecase) Features(c context.Context, carIDs []int64) (features map[int64][]entity.Feature, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "carRelationUsecase.Features")
+	defer func() { tracing.End(span, err) }()
+
+	features, err = u.featureRepo.FetchByCarIDs(ctx, carIDs)
+	if err != nil {
+		return
+	}
+
+	for _, carID := range carIDs {
+		if features[carID] == nil {
+			features[carID] = []entity.Feature{}
+		}
+	}
+	return
+}
+
+// PriceHistory gives the prices the cars were listed at, from the oldest
+func (u *carRelationUsecase) PriceHistory(c context.Context, carIDs []int64) (history map[int64][]entity.PricePoint, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "carRelationUsecase.PriceHistory")
+	defer func() { tracing.End(span, err) }()
+
+	points, err := u.priceRepo.FetchByCarIDs(ctx, carIDs)
+	if err != nil {
+		return
+	}
+
+	history = make(map[int64][]entity.PricePoint, len(carIDs))
+	for _, carID := range carIDs {
+		history[carID] = []entity.PricePoint{}
+	}
+	for _, point := range points {
+		history[point.CarID] = append(history[point.CarID], point)
+	}
+	return
+}
@@ -0,0 +1,89 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCarRelationUC_Images(t *testing.T) {
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockPriceRepo := new(mocks.PriceHistoryRepository)
+	mockStorage := new(mocks.Storage)
+
+	t.Run("success", func(t *testing.T) {
+		mockCarImageRepo.On("FetchByCarIDs", mock.Anything, []int64{1, 2}).
+			Return([]entity.CarImage{{ID: 1, CarID: 1, StorageKey: "cars/1/a.jpg"}, {ID: 2, CarID: 1, StorageKey: "cars/1/b.jpg"}}, nil).Once()
+		mockStorage.On("URL", mock.AnythingOfType("string")).Return("http://localhost/media/image.jpg")
+
+		carRelationUsecase := usecase.NewCarRelationUsecase(mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockStorage, ctxTimeout)
+		images, err := carRelationUsecase.Images(context.TODO(), []int64{1, 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, images[1], 2)
+		assert.Equal(t, "http://localhost/media/image.jpg", images[1][0].URL)
+		assert.Len(t, images[1][0].Thumbnails, len(entity.ThumbnailSizes))
+		assert.NotNil(t, images[2], "a car without images has an empty entry")
+		assert.Empty(t, images[2])
+		mockCarImageRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-db", func(t *testing.T) {
+		mockCarImageRepo.On("FetchByCarIDs", mock.Anything, []int64{1}).Return(nil, errors.New("unexpected error")).Once()
+
+		carRelationUsecase := usecase.NewCarRelationUsecase(mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockStorage, ctxTimeout)
+		_, err := carRelationUsecase.Images(context.TODO(), []int64{1})
+
+		assert.Error(t, err)
+		mockCarImageRepo.AssertExpectations(t)
+	})
+}
+
+func TestCarRelationUC_Features(t *testing.T) {
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockPriceRepo := new(mocks.PriceHistoryRepository)
+	mockStorage := new(mocks.Storage)
+
+	mockFeatureRepo.On("FetchByCarIDs", mock.Anything, []int64{1, 2}).
+		Return(map[int64][]entity.Feature{1: {{ID: 3, Code: "sunroof"}}}, nil).Once()
+
+	carRelationUsecase := usecase.NewCarRelationUsecase(mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockStorage, ctxTimeout)
+	features, err := carRelationUsecase.Features(context.TODO(), []int64{1, 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Feature{{ID: 3, Code: "sunroof"}}, features[1])
+	assert.Equal(t, []entity.Feature{}, features[2])
+	mockFeatureRepo.AssertExpectations(t)
+}
+
+func TestCarRelationUC_PriceHistory(t *testing.T) {
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockPriceRepo := new(mocks.PriceHistoryRepository)
+	mockStorage := new(mocks.Storage)
+
+	mockPriceRepo.On("FetchByCarIDs", mock.Anything, []int64{1, 2}).Return([]entity.PricePoint{
+		{ID: 1, CarID: 1, Price: entity.Money{Amount: 1500000, Currency: "USD"}},
+		{ID: 3, CarID: 1, Price: entity.Money{Amount: 1400000, Currency: "USD"}},
+		{ID: 2, CarID: 2, Price: entity.Money{Amount: 900000, Currency: "EUR"}},
+	}, nil).Once()
+
+	carRelationUsecase := usecase.NewCarRelationUsecase(mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockStorage, ctxTimeout)
+	history, err := carRelationUsecase.PriceHistory(context.TODO(), []int64{1, 2})
+
+	assert.NoError(t, err)
+	if assert.Len(t, history[1], 2) {
+		assert.Equal(t, int64(1), history[1][0].ID)
+		assert.Equal(t, int64(3), history[1][1].ID)
+	}
+	assert.Len(t, history[2], 1)
+	mockPriceRepo.AssertExpectations(t)
+}
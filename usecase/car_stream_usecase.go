@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/stream"
+	"carApi/repository/pgsql"
+)
+
+// CarStreamUsecase represent the car stream's usecase contract, a stream subscribes to the car
+// events and turns each of them into the change seen through its filter and display
+type CarStreamUsecase interface {
+	Subscribe(ctx context.Context, lastEventID int64) *stream.Subscription
+	Change(ctx context.Context, event entity.Event, filter entity.CarFilter, display entity.CarDisplay) (entity.CarChange, bool, error)
+}
+
+type carStreamUsecase struct {
+	hub         *stream.Hub
+	featureRepo pgsql.FeatureRepository
+	rates       entity.ExchangeRates
+	ctxTimeout  time.Duration
+}
+
+// NewCarStreamUsecase will create new a carStreamUsecase object representation of CarStreamUsecase interface
+func NewCarStreamUsecase(hub *stream.Hub, featureRepo pgsql.FeatureRepository, rates entity.ExchangeRates, ctxTimeout time.Duration) CarStreamUsecase {
+	return &carStreamUsecase{
+		hub:         hub,
+		featureRepo: featureRepo,
+		rates:       rates,
+		ctxTimeout:  ctxTimeout,
+	}
+}
+
+func (u *carStreamUsecase) Subscribe(ctx context.Context, lastEventID int64) *stream.Subscription {
+	return u.hub.Subscribe(lastEventID)
+}
+
+// Change gives the change of the event as seen by the stream, false when the stream has nothing
+// to show for it. A created car outside the filter is left out and an updated one is removed,
+// the client drops it if it was listed. Deletes are always given as the car is gone.
+func (u *carStreamUsecase) Change(c context.Context, event entity.Event, filter entity.CarFilter, display entity.CarDisplay) (change entity.CarChange, ok bool, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	change = entity.CarChange{EventID: event.ID, CarID: event.CarID}
+
+	var car entity.Car
+	switch event.Type {
+	case entity.EventCarCreated:
+		var payload entity.CarCreated
+		if err = json.Unmarshal(event.Payload, &payload); err != nil {
+			return
+		}
+		change.Type, car = entity.CarChangeCreated, payload.Car
+	case entity.EventCarUpdated:
+		var payload entity.CarUpdated
+		if err = json.Unmarshal(event.Payload, &payload); err != nil {
+			return
+		}
+		change.Type, car = entity.CarChangeUpdated, payload.Car
+	case entity.EventCarDeleted:
+		change.Type = entity.CarChangeDeleted
+		return change, true, nil
+	default:
+		// the price change comes along with the update
+		return change, false, nil
+	}
+
+	if len(filter.Features) > 0 {
+		if car.Features, err = u.featureRepo.FetchByCarID(ctx, car.ID); err != nil {
+			return
+		}
+	}
+
+	if !filter.Matches(car) {
+		if change.Type == entity.CarChangeCreated {
+			return change, false, nil
+		}
+		change.Type = entity.CarChangeRemoved
+		return change, true, nil
+	}
+
+	if err = applyDisplay(u.rates, &car, display); err != nil {
+		return
+	}
+
+	change.Car = &car
+	return change, true, nil
+}
@@ -0,0 +1,115 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/stream"
+	"carApi/mocks"
+	"carApi/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func carEvent(t *testing.T, id int64, carID int64, payload entity.EventPayload) entity.Event {
+	event, err := entity.NewCarEvent(carID, payload, time.Now())
+	require.NoError(t, err)
+	event.ID = id
+	return event
+}
+
+func TestCarStreamUC_Subscribe(t *testing.T) {
+	hub := stream.NewHub(10, 10)
+	require.NoError(t, hub.Publish(context.TODO(), entity.Event{ID: 1}))
+	require.NoError(t, hub.Publish(context.TODO(), entity.Event{ID: 2}))
+
+	carStreamUsecase := usecase.NewCarStreamUsecase(hub, new(mocks.FeatureRepository), exchangeRates, ctxTimeout)
+	subscription := carStreamUsecase.Subscribe(context.TODO(), 1)
+	defer subscription.Close()
+
+	require.Len(t, subscription.Replay, 1)
+	assert.Equal(t, int64(2), subscription.Replay[0].ID)
+}
+
+func TestCarStreamUC_Change(t *testing.T) {
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	car := entity.Car{ID: 1, Make: "Volkswagen", Mileage: 50000, Price: entity.Money{Amount: 1000000, Currency: "USD"}}
+	maxMileage := 60000
+	sunroof := entity.CarFilter{Features: []string{"sunroof"}, FeatureMatch: entity.FeatureMatchAll}
+
+	t.Run("success-created", func(t *testing.T) {
+		carStreamUsecase := usecase.NewCarStreamUsecase(stream.NewHub(0, 1), mockFeatureRepo, exchangeRates, ctxTimeout)
+		change, ok, err := carStreamUsecase.Change(context.TODO(), carEvent(t, 5, 1, entity.CarCreated{Car: car}), entity.CarFilter{MileageMax: &maxMileage}, entity.CarDisplay{Currency: "EUR"})
+
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, int64(5), change.EventID)
+		assert.Equal(t, entity.CarChangeCreated, change.Type)
+		require.NotNil(t, change.Car)
+		assert.Equal(t, "Volkswagen", change.Car.Make)
+		assert.Equal(t, &entity.Money{Amount: 900000, Currency: "EUR"}, change.Car.DisplayPrice)
+	})
+
+	t.Run("success-updated-with-features", func(t *testing.T) {
+		mockFeatureRepo.On("FetchByCarID", mock.Anything, int64(1)).Return([]entity.Feature{{Code: "sunroof"}}, nil).Once()
+
+		carStreamUsecase := usecase.NewCarStreamUsecase(stream.NewHub(0, 1), mockFeatureRepo, exchangeRates, ctxTimeout)
+		change, ok, err := carStreamUsecase.Change(context.TODO(), carEvent(t, 6, 1, entity.CarUpdated{Car: car}), sunroof, entity.CarDisplay{})
+
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, entity.CarChangeUpdated, change.Type)
+		assert.Len(t, change.Car.Features, 1)
+		mockFeatureRepo.AssertExpectations(t)
+	})
+
+	t.Run("created-outside-filter", func(t *testing.T) {
+		mockFeatureRepo.On("FetchByCarID", mock.Anything, int64(1)).Return([]entity.Feature{}, nil).Once()
+
+		carStreamUsecase := usecase.NewCarStreamUsecase(stream.NewHub(0, 1), mockFeatureRepo, exchangeRates, ctxTimeout)
+		_, ok, err := carStreamUsecase.Change(context.TODO(), carEvent(t, 7, 1, entity.CarCreated{Car: car}), sunroof, entity.CarDisplay{})
+
+		require.NoError(t, err)
+		assert.False(t, ok)
+		mockFeatureRepo.AssertExpectations(t)
+	})
+
+	t.Run("updated-outside-filter", func(t *testing.T) {
+		lowMileage := 1000
+		carStreamUsecase := usecase.NewCarStreamUsecase(stream.NewHub(0, 1), mockFeatureRepo, exchangeRates, ctxTimeout)
+		change, ok, err := carStreamUsecase.Change(context.TODO(), carEvent(t, 8, 1, entity.CarUpdated{Car: car}), entity.CarFilter{MileageMax: &lowMileage}, entity.CarDisplay{})
+
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, entity.CarChangeRemoved, change.Type)
+		assert.Equal(t, int64(1), change.CarID)
+		assert.Nil(t, change.Car)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		carStreamUsecase := usecase.NewCarStreamUsecase(stream.NewHub(0, 1), mockFeatureRepo, exchangeRates, ctxTimeout)
+		change, ok, err := carStreamUsecase.Change(context.TODO(), carEvent(t, 9, 1, entity.CarDeleted{CarID: 1}), sunroof, entity.CarDisplay{})
+
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, entity.CarChangeDeleted, change.Type)
+	})
+
+	t.Run("price-changed", func(t *testing.T) {
+		carStreamUsecase := usecase.NewCarStreamUsecase(stream.NewHub(0, 1), mockFeatureRepo, exchangeRates, ctxTimeout)
+		_, ok, err := carStreamUsecase.Change(context.TODO(), carEvent(t, 10, 1, entity.CarPriceChanged{CarID: 1}), entity.CarFilter{}, entity.CarDisplay{})
+
+		require.NoError(t, err)
+		assert.False(t, ok, "the update carries the new price")
+	})
+
+	t.Run("error-no-rate", func(t *testing.T) {
+		carStreamUsecase := usecase.NewCarStreamUsecase(stream.NewHub(0, 1), mockFeatureRepo, exchangeRates, ctxTimeout)
+		_, _, err := carStreamUsecase.Change(context.TODO(), carEvent(t, 11, 1, entity.CarCreated{Car: car}), entity.CarFilter{}, entity.CarDisplay{Currency: "JPY"})
+
+		assert.Error(t, err)
+	})
+}
@@ -4,131 +4,431 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"carApi/entity"
+	"carApi/infrastructure/storage"
+	"carApi/infrastructure/tracing"
 	"carApi/repository/pgsql"
 	"carApi/repository/redis"
+	"carApi/repository/transaction"
 	"carApi/transport/request"
 	"carApi/utils"
+	goredis "github.com/go-redis/redis"
 )
 
 // CarUsecase represent the car's usecase contract
 type CarUsecase interface {
 	Create(ctx context.Context, request *request.CreateCarReq) error
-	GetByID(ctx context.Context, id int64) (entity.Car, error)
-	Fetch(ctx context.Context) ([]entity.Car, error)
+	GetByID(ctx context.Context, id int64, display entity.CarDisplay) (entity.Car, error)
+	Fetch(ctx context.Context, filter entity.CarFilter, display entity.CarDisplay) ([]entity.Car, error)
+	FetchPage(ctx context.Context, filter entity.CarFilter, page entity.Page, display entity.CarDisplay) ([]entity.Car, int64, error)
 	Update(ctx context.Context, id int64, request *request.UpdateCarReq) error
 	Delete(ctx context.Context, id int64) error
+	FlushCache(ctx context.Context) (int64, error)
+	RebuildCache(ctx context.Context) (int, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
 
 type carUsecase struct {
-	carRepo    pgsql.CarRepository
-	redisRepo  redis.RedisRepository
-	ctxTimeout time.Duration
+	carRepo      pgsql.CarRepository
+	carImageRepo pgsql.CarImageRepository
+	featureRepo  pgsql.FeatureRepository
+	priceRepo    pgsql.PriceHistoryRepository
+	catalogRepo  pgsql.CatalogRepository
+	outboxRepo   pgsql.OutboxRepository
+	redisRepo    redis.RedisRepository
+	txManager    transaction.TxManager
+	storage      storage.Storage
+	rates        entity.ExchangeRates
+	cacheTTL     time.Duration
+	ctxTimeout   time.Duration
 }
 
 // NewCarUsecase will create new an carUsecase object representation of CarUsecase interface
-func NewCarUsecase(carRepo pgsql.CarRepository, redisRepo redis.RedisRepository, ctxTimeout time.Duration) CarUsecase {
+func NewCarUsecase(carRepo pgsql.CarRepository, carImageRepo pgsql.CarImageRepository, featureRepo pgsql.FeatureRepository, priceRepo pgsql.PriceHistoryRepository, catalogRepo pgsql.CatalogRepository, outboxRepo pgsql.OutboxRepository, redisRepo redis.RedisRepository, txManager transaction.TxManager, storage storage.Storage, rates entity.ExchangeRates, cacheTTL time.Duration, ctxTimeout time.Duration) CarUsecase {
 	return &carUsecase{
-		carRepo:    carRepo,
-		redisRepo:  redisRepo,
-		ctxTimeout: ctxTimeout,
+		carRepo:      carRepo,
+		carImageRepo: carImageRepo,
+		featureRepo:  featureRepo,
+		priceRepo:    priceRepo,
+		catalogRepo:  catalogRepo,
+		outboxRepo:   outboxRepo,
+		redisRepo:    redisRepo,
+		txManager:    txManager,
+		storage:      storage,
+		rates:        rates,
+		cacheTTL:     cacheTTL,
+		ctxTimeout:   ctxTimeout,
 	}
 }
 
+// Create stores the car, the first point of its price history and its CarCreated event in one transaction
 func (u *carUsecase) Create(c context.Context, request *request.CreateCarReq) (err error) {
 	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
 	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "carUsecase.Create")
+	defer func() { tracing.End(span, err) }()
 
-	err = u.carRepo.Create(ctx, &entity.Car{
+	car := entity.Car{
 		Make:           request.Make,
 		Model:          request.Model,
 		Package:        request.Package,
 		Color:          request.Color,
 		Year:           request.Year,
 		Category:       request.Category,
-		Mileage:        request.Mileage,
-		Price:          request.Price,
+		Mileage:        request.MileageKilometers(),
+		Price:          request.Price.Money(),
 		Identification: request.Identification,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
+	}
+
+	err = u.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := normalizeCatalog(ctx, u.catalogRepo, &car); err != nil {
+			return err
+		}
+
+		if err := u.carRepo.Create(ctx, &car); err != nil {
+			return err
+		}
+
+		if err := u.priceRepo.Add(ctx, &entity.PricePoint{CarID: car.ID, Price: car.Price, ChangedAt: car.CreatedAt}); err != nil {
+			return err
+		}
+
+		return u.emit(ctx, car.ID, entity.CarCreated{Car: car})
 	})
+	if err == nil {
+		invalidateListings(ctx, u.redisRepo)
+	}
 	return
 }
 
-func (u *carUsecase) GetByID(c context.Context, id int64) (car entity.Car, err error) {
+func (u *carUsecase) GetByID(c context.Context, id int64, display entity.CarDisplay) (car entity.Car, err error) {
 	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
 	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "carUsecase.GetByID")
+	defer func() { tracing.End(span, err) }()
 
 	car, err = u.carRepo.GetByID(ctx, id)
-	if err != nil && err == sql.ErrNoRows {
-		err = utils.NewNotFoundError("car not found")
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		err = utils.NewError(utils.CodeCarNotFound, "car not found")
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	car.Images, err = u.carImageRepo.FetchByCarID(ctx, id)
+	if err != nil {
 		return
 	}
+
+	for i := range car.Images {
+		resolveImageURLs(u.storage, &car.Images[i])
+	}
+
+	car.Features, err = u.featureRepo.FetchByCarID(ctx, id)
+	if err != nil {
+		return
+	}
+
+	err = applyDisplay(u.rates, &car, display)
+	return
+}
+
+func (u *carUsecase) Fetch(c context.Context, filter entity.CarFilter, display entity.CarDisplay) (cars []entity.Car, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "carUsecase.Fetch")
+	defer func() { tracing.End(span, err) }()
+
+	version, cacheable := listingsVersion(ctx, u.redisRepo)
+	cacheKey := fetchCacheKey(version, filter)
+	var carsCached string
+	if cacheable {
+		carsCached, _ = u.redisRepo.Get(ctx, cacheKey)
+	}
+	if err = json.Unmarshal([]byte(carsCached), &cars); err != nil {
+		cars, err = u.carRepo.Fetch(ctx, filter, entity.Page{})
+		if err != nil {
+			return
+		}
+
+		if cacheable {
+			carsString, _ := json.Marshal(&cars)
+			u.redisRepo.Set(ctx, cacheKey, carsString, u.cacheTTL)
+		}
+	}
+
+	for i := range cars {
+		if err = applyDisplay(u.rates, &cars[i], display); err != nil {
+			return
+		}
+	}
 	return
 }
 
-func (u *carUsecase) Fetch(c context.Context) (cars []entity.Car, err error) {
+// cachedPage is a page of the listing as cached, along with the number of cars of every page
+type cachedPage struct {
+	Cars  []entity.Car `json:"cars"`
+	Total int64        `json:"total"`
+}
+
+// FetchPage gives a page of the listing and the number of cars of every page, only the cars of
+// the page are read
+func (u *carUsecase) FetchPage(c context.Context, filter entity.CarFilter, page entity.Page, display entity.CarDisplay) (cars []entity.Car, total int64, err error) {
 	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
 	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "carUsecase.FetchPage")
+	defer func() { tracing.End(span, err) }()
+
+	version, cacheable := listingsVersion(ctx, u.redisRepo)
+	cacheKey := fetchPageCacheKey(version, filter, page)
+	var cached cachedPage
+	var pageCached string
+	if cacheable {
+		pageCached, _ = u.redisRepo.Get(ctx, cacheKey)
+	}
+	if err = json.Unmarshal([]byte(pageCached), &cached); err != nil {
+		if cached.Cars, err = u.carRepo.Fetch(ctx, filter, page); err != nil {
+			return
+		}
+		if cached.Total, err = u.carRepo.Count(ctx, filter); err != nil {
+			return
+		}
 
-	carsCached, _ := u.redisRepo.Get("cars")
-	if err = json.Unmarshal([]byte(carsCached), &cars); err == nil {
+		if cacheable {
+			pageString, _ := json.Marshal(&cached)
+			u.redisRepo.Set(ctx, cacheKey, pageString, u.cacheTTL)
+		}
+	}
+
+	cars, total = cached.Cars, cached.Total
+	for i := range cars {
+		if err = applyDisplay(u.rates, &cars[i], display); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// applyDisplay fills the display fields of a car, the cache always holds the
+// stored values so any display can be served from it
+func applyDisplay(rates entity.ExchangeRates, car *entity.Car, display entity.CarDisplay) error {
+	if display.MileageUnit != "" {
+		mileage := entity.FromKilometers(car.Mileage, display.MileageUnit)
+		car.DisplayMileage = &mileage
+	}
+
+	if display.Currency == "" {
+		return nil
+	}
+
+	price, err := rates.Convert(car.Price, display.Currency)
+	if err != nil {
+		return utils.NewError(utils.CodeExchangeRateUnavailable, fmt.Sprintf("no exchange rate from %s to %s", car.Price.Currency, display.Currency))
+	}
+	car.DisplayPrice = &price
+	return nil
+}
+
+// FlushCache moves the car listings to a new version so none of the cached ones is read again, the
+// old entries are left to their TTL. It gives the new version.
+func (u *carUsecase) FlushCache(c context.Context) (version int64, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "carUsecase.FlushCache")
+	defer func() { tracing.End(span, err) }()
+
+	version, err = u.redisRepo.Incr(ctx, listingsVersionKey)
+	return
+}
+
+// RebuildCache moves the car listings to a new version then caches the unfiltered listing again,
+// it gives the number of cars listed
+func (u *carUsecase) RebuildCache(c context.Context) (listed int, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "carUsecase.RebuildCache")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err = u.redisRepo.Incr(ctx, listingsVersionKey); err != nil {
 		return
 	}
 
-	cars, err = u.carRepo.Fetch(ctx)
+	cars, err := u.Fetch(ctx, entity.CarFilter{}, entity.CarDisplay{})
+	return len(cars), err
+}
+
+// PurgeDeleted removes for good the cars deleted before the given time along with their image
+// files, the files are removed once the rows are. The cars left the listings when they were
+// deleted so the cache is kept.
+func (u *carUsecase) PurgeDeleted(c context.Context, before time.Time) (purged int64, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "carUsecase.PurgeDeleted")
+	defer func() { tracing.End(span, err) }()
+
+	var images []entity.CarImage
+	err = u.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if images, err = u.carImageRepo.FetchByCarsDeletedBefore(ctx, before); err != nil {
+			return err
+		}
+
+		purged, err = u.carRepo.PurgeDeleted(ctx, before)
+		return err
+	})
 	if err != nil {
 		return
 	}
 
-	carsString, _ := json.Marshal(&cars)
-	u.redisRepo.Set("cars", carsString, 30*time.Second)
+	for _, carImage := range images {
+		cleanup(ctx, u.storage, imageKeys(carImage))
+	}
 	return
 }
 
+// listingsVersionKey holds the version of the cached listings, every listing key carries it so
+// bumping it invalidates them all at once without looking them up
+const listingsVersionKey = "cars:listing:version"
+
+// listingsVersion gives the current version of the cached listings, a missing version is 0. When
+// it cannot be read the listing is neither read from nor written to the cache, an entry of an old
+// version could be served otherwise.
+func listingsVersion(ctx context.Context, redisRepo redis.RedisRepository) (version string, ok bool) {
+	version, err := redisRepo.Get(ctx, listingsVersionKey)
+	if errors.Is(err, goredis.Nil) {
+		return "0", true
+	}
+	return version, err == nil
+}
+
+// invalidateListings moves the cached listings to a new version after a write that may change
+// them. The write is committed by then, a failure only leaves the listings stale until their TTL.
+func invalidateListings(ctx context.Context, redisRepo redis.RedisRepository) {
+	_, _ = redisRepo.Incr(ctx, listingsVersionKey)
+}
+
+// fetchCacheKey keeps the unfiltered listing of a version under "cars:v<version>" and gives every
+// filter combination its own cache entry
+func fetchCacheKey(version string, filter entity.CarFilter) string {
+	key := "cars:v" + version
+	if len(filter.Features) > 0 {
+		key += fmt.Sprintf(":features=%s:match=%s", strings.Join(filter.Features, ","), filter.FeatureMatch)
+	}
+	if filter.MileageMin != nil {
+		key += fmt.Sprintf(":mileage_min=%d", *filter.MileageMin)
+	}
+	if filter.MileageMax != nil {
+		key += fmt.Sprintf(":mileage_max=%d", *filter.MileageMax)
+	}
+	return key
+}
+
+// fetchPageCacheKey gives every page of a listing its own entry next to the whole listing
+func fetchPageCacheKey(version string, filter entity.CarFilter, page entity.Page) string {
+	return fetchCacheKey(version, filter) + fmt.Sprintf(":limit=%d:offset=%d", page.Limit, page.Offset)
+}
+
+// Update reads and writes the car in one transaction so a concurrent update or delete cannot
+// slip in between, CarUpdated is emitted along with CarPriceChanged when the price changes and the
+// new price is added to the history
 func (u *carUsecase) Update(c context.Context, id int64, request *request.UpdateCarReq) (err error) {
 	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
 	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "carUsecase.Update")
+	defer func() { tracing.End(span, err) }()
 
-	car, err := u.carRepo.GetByID(ctx, id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			err = utils.NewNotFoundError("car not found")
-			return
+	err = u.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		car, err := u.carRepo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return utils.NewError(utils.CodeCarNotFound, "car not found")
+			}
+			return err
 		}
-		return
-	}
 
-	car.Identification = request.Identification
-	car.Price = request.Price
-	car.Mileage = request.Mileage
-	car.Year = request.Year
-	car.Color = request.Color
-	car.Make = request.Make
-	car.Model = request.Category
-	car.Package = request.Package
-	car.Category = request.Category
-	car.UpdatedAt = time.Now()
+		oldPrice := car.Price
+		car.Identification = request.Identification
+		car.Price = request.Price.Money()
+		car.Mileage = request.MileageKilometers()
+		car.Year = request.Year
+		car.Color = request.Color
+		car.Make = request.Make
+		car.Model = request.Model
+		car.Package = request.Package
+		car.Category = request.Category
+		car.UpdatedAt = time.Now()
+
+		if err = normalizeCatalog(ctx, u.catalogRepo, &car); err != nil {
+			return err
+		}
 
-	err = u.carRepo.Update(ctx, &car)
+		if err = u.carRepo.Update(ctx, &car); err != nil {
+			return err
+		}
+
+		if err = u.emit(ctx, car.ID, entity.CarUpdated{Car: car}); err != nil {
+			return err
+		}
+
+		if car.Price != oldPrice {
+			if err = u.priceRepo.Add(ctx, &entity.PricePoint{CarID: car.ID, Price: car.Price, ChangedAt: car.UpdatedAt}); err != nil {
+				return err
+			}
+			return u.emit(ctx, car.ID, entity.CarPriceChanged{CarID: car.ID, OldPrice: oldPrice, NewPrice: car.Price})
+		}
+		return nil
+	})
+	if err == nil {
+		invalidateListings(ctx, u.redisRepo)
+	}
 	return
 }
 
+// Delete checks the car exists, deletes it and emits CarDeleted in one transaction
 func (u *carUsecase) Delete(c context.Context, id int64) (err error) {
 	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
 	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "carUsecase.Delete")
+	defer func() { tracing.End(span, err) }()
 
-	_, err = u.carRepo.GetByID(ctx, id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			err = utils.NewNotFoundError("car not found")
-			return
+	err = u.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := u.carRepo.GetByID(ctx, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return utils.NewError(utils.CodeCarNotFound, "car not found")
+			}
+			return err
 		}
-		return
-	}
 
-	err = u.carRepo.Delete(ctx, id)
+		if err := u.carRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return u.emit(ctx, id, entity.CarDeleted{CarID: id})
+	})
+	if err == nil {
+		invalidateListings(ctx, u.redisRepo)
+	}
 	return
 }
+
+func (u *carUsecase) emit(ctx context.Context, carID int64, payload entity.EventPayload) error {
+	return emit(ctx, u.outboxRepo, carID, payload)
+}
+
+// emit adds the event of the car to the outbox, within a transaction it is only kept if the
+// write it describes is committed
+func emit(ctx context.Context, outboxRepo pgsql.OutboxRepository, carID int64, payload entity.EventPayload) error {
+	event, err := entity.NewCarEvent(carID, payload, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return outboxRepo.Add(ctx, &event)
+}
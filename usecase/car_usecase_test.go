@@ -12,15 +12,63 @@ import (
 	"carApi/mocks"
 	"carApi/transport/request"
 	"carApi/usecase"
+	"carApi/utils"
+	goredis "github.com/go-redis/redis"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 var ctxTimeout = 60 * time.Second
 
+var cacheTTL = 30 * time.Second
+
+var exchangeRates = entity.ExchangeRates{Base: "USD", Rates: map[string]float64{"EUR": 0.9}}
+
+// expectCatalog makes the catalog recognize the make, model, package and category of the test cars
+func expectCatalog(mockCatalogRepo *mocks.CatalogRepository) {
+	mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindMake, int64(0), "make").
+		Return(entity.CatalogEntry{ID: 1, Kind: entity.CatalogKindMake, Name: "Make"}, nil).Once()
+	mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindModel, int64(1), "model").
+		Return(entity.CatalogEntry{ID: 2, Kind: entity.CatalogKindModel, ParentID: 1, Name: "Model"}, nil).Once()
+	mockCatalogRepo.On("Fetch", mock.Anything, entity.CatalogKindTrim, int64(2)).
+		Return([]entity.CatalogEntry{{ID: 3, Kind: entity.CatalogKindTrim, ParentID: 2, Name: "Package"}}, nil).Once()
+	mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindCategory, int64(0), "category").
+		Return(entity.CatalogEntry{ID: 4, Kind: entity.CatalogKindCategory, Name: "Category"}, nil).Once()
+}
+
+type inTransaction struct{}
+
+// expectTransaction makes the transaction manager run the unit of work with a context telling
+// it is in a transaction, so the repository calls can be checked to happen within it
+func expectTransaction(mockTxManager *mocks.TxManager) {
+	mockTxManager.On("WithinTransaction", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(context.WithValue(ctx, inTransaction{}, true))
+		}).Once()
+}
+
+var withinTransaction = mock.MatchedBy(func(ctx context.Context) bool {
+	return ctx.Value(inTransaction{}) != nil
+})
+
+// eventOf matches the outbox event of the given type for the car
+func eventOf(eventType entity.EventType, carID int64) interface{} {
+	return mock.MatchedBy(func(event *entity.Event) bool {
+		return event.Type == eventType && event.CarID == carID
+	})
+}
+
 func TestCarUC_Create(t *testing.T) {
 	mockRedisRepo := new(mocks.RedisRepository)
 	mockCarRepo := new(mocks.CarRepository)
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockPriceRepo := new(mocks.PriceHistoryRepository)
+	mockCatalogRepo := new(mocks.CatalogRepository)
+	mockOutboxRepo := new(mocks.OutboxRepository)
+	mockStorage := new(mocks.Storage)
+	mockTxManager := new(mocks.TxManager)
 	createCarReq := request.CreateCarReq{
 		Make:           "make",
 		Model:          "model",
@@ -29,36 +77,119 @@ func TestCarUC_Create(t *testing.T) {
 		Year:           0,
 		Category:       "category",
 		Mileage:        0,
-		Price:          0,
+		Price:          request.MoneyReq{Amount: 1500000, Currency: "usd"},
 		Identification: "identification",
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockCarRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil).Once()
-
-		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
+		expectTransaction(mockTxManager)
+		expectCatalog(mockCatalogRepo)
+		mockCarRepo.On("Create", withinTransaction, mock.MatchedBy(func(car *entity.Car) bool {
+			return car.Make == "Make" && car.Model == "Model" && car.Package == "Package" && car.Category == "Category" &&
+				car.Price == entity.Money{Amount: 1500000, Currency: "USD"}
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.Car).ID = 1
+		}).Return(nil).Once()
+		mockPriceRepo.On("Add", withinTransaction, mock.MatchedBy(func(point *entity.PricePoint) bool {
+			return point.CarID == 1 && point.Price == entity.Money{Amount: 1500000, Currency: "USD"} && !point.ChangedAt.IsZero()
+		})).Return(nil).Once()
+		mockOutboxRepo.On("Add", withinTransaction, mock.MatchedBy(func(event *entity.Event) bool {
+			var payload entity.CarCreated
+			return event.Type == entity.EventCarCreated && event.CarID == 1 &&
+				json.Unmarshal(event.Payload, &payload) == nil && payload.Car.ID == 1 && payload.Car.Make == "Make"
+		})).Return(nil).Once()
+		mockRedisRepo.On("Incr", mock.Anything, "cars:listing:version").Return(int64(2), nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
 		err := carUsecase.Create(context.TODO(), &createCarReq)
 
 		assert.NoError(t, err)
 		mockRedisRepo.AssertExpectations(t)
 		mockCarRepo.AssertExpectations(t)
+		mockCatalogRepo.AssertExpectations(t)
+		mockPriceRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-mileage-in-miles", func(t *testing.T) {
+		milesCarReq := createCarReq
+		milesCarReq.Mileage = 10000
+		milesCarReq.MileageUnit = entity.MileageUnitMiles
+		expectTransaction(mockTxManager)
+		expectCatalog(mockCatalogRepo)
+		mockCarRepo.On("Create", mock.Anything, mock.MatchedBy(func(car *entity.Car) bool {
+			return car.Mileage == 16093
+		})).Return(nil).Once()
+		mockPriceRepo.On("Add", mock.Anything, mock.AnythingOfType("*entity.PricePoint")).Return(nil).Once()
+		mockOutboxRepo.On("Add", mock.Anything, eventOf(entity.EventCarCreated, 0)).Return(nil).Once()
+		mockRedisRepo.On("Incr", mock.Anything, "cars:listing:version").Return(int64(2), nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		err := carUsecase.Create(context.TODO(), &milesCarReq)
+
+		assert.NoError(t, err)
+		mockRedisRepo.AssertExpectations(t)
+		mockCarRepo.AssertExpectations(t)
+		mockCatalogRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-unknown-catalog", func(t *testing.T) {
+		expectTransaction(mockTxManager)
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindMake, int64(0), "make").
+			Return(entity.CatalogEntry{}, sql.ErrNoRows).Once()
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindCategory, int64(0), "category").
+			Return(entity.CatalogEntry{ID: 4, Kind: entity.CatalogKindCategory, Name: "Category"}, nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		err := carUsecase.Create(context.TODO(), &createCarReq)
+
+		require.Error(t, err)
+		assert.Equal(t, 400, err.(utils.HttpErr).Status())
+		mockCarRepo.AssertExpectations(t)
+		mockCatalogRepo.AssertExpectations(t)
 	})
 
 	t.Run("error-db", func(t *testing.T) {
+		expectTransaction(mockTxManager)
+		expectCatalog(mockCatalogRepo)
 		mockCarRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(errors.New("Unexpected Error")).Once()
 
-		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
 		err := carUsecase.Create(context.TODO(), &createCarReq)
 
 		assert.NotNil(t, err)
 		mockRedisRepo.AssertExpectations(t)
 		mockCarRepo.AssertExpectations(t)
 	})
+
+	t.Run("error-outbox", func(t *testing.T) {
+		expectTransaction(mockTxManager)
+		expectCatalog(mockCatalogRepo)
+		mockCarRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil).Once()
+		mockPriceRepo.On("Add", mock.Anything, mock.AnythingOfType("*entity.PricePoint")).Return(nil).Once()
+		mockOutboxRepo.On("Add", mock.Anything, mock.AnythingOfType("*entity.Event")).Return(errors.New("Unexpected Error")).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		err := carUsecase.Create(context.TODO(), &createCarReq)
+
+		assert.EqualError(t, err, "Unexpected Error", "the car is rolled back with its event")
+		mockCarRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+	})
+
+	mockTxManager.AssertExpectations(t)
 }
 
 func TestCarUC_GetByID(t *testing.T) {
 	mockRedisRepo := new(mocks.RedisRepository)
 	mockCarRepo := new(mocks.CarRepository)
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockPriceRepo := new(mocks.PriceHistoryRepository)
+	mockCatalogRepo := new(mocks.CatalogRepository)
+	mockOutboxRepo := new(mocks.OutboxRepository)
+	mockStorage := new(mocks.Storage)
+	mockTxManager := new(mocks.TxManager)
 	mockCar := entity.Car{
 		ID:             1,
 		Make:           "make",
@@ -68,30 +199,45 @@ func TestCarUC_GetByID(t *testing.T) {
 		Year:           0,
 		Category:       "category",
 		Mileage:        0,
-		Price:          0,
+		Price:          entity.Money{Amount: 1500000, Currency: "USD"},
 		Identification: "identification",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
+	mockCarImages := []entity.CarImage{
+		{ID: 1, CarID: 1, StorageKey: "cars/1/image.jpg", IsPrimary: true},
+	}
+	mockFeatures := []entity.Feature{
+		{ID: 1, Code: "sunroof", Name: "Sunroof"},
+	}
 
 	t.Run("success", func(t *testing.T) {
 		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
+		mockCarImageRepo.On("FetchByCarID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCarImages, nil).Once()
+		mockStorage.On("URL", mock.AnythingOfType("string")).Return("http://localhost/media/image.jpg")
+		mockFeatureRepo.On("FetchByCarID", mock.Anything, mock.AnythingOfType("int64")).Return(mockFeatures, nil).Once()
 
-		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
-		car, err := carUsecase.GetByID(context.TODO(), mockCar.ID)
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		car, err := carUsecase.GetByID(context.TODO(), mockCar.ID, entity.CarDisplay{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, car)
 		assert.Equal(t, car.ID, mockCar.ID)
+		assert.Len(t, car.Images, 1)
+		assert.Equal(t, "http://localhost/media/image.jpg", car.Images[0].URL)
+		assert.Len(t, car.Images[0].Thumbnails, len(entity.ThumbnailSizes))
+		assert.Equal(t, mockFeatures, car.Features)
 		mockRedisRepo.AssertExpectations(t)
 		mockCarRepo.AssertExpectations(t)
+		mockCarImageRepo.AssertExpectations(t)
+		mockFeatureRepo.AssertExpectations(t)
 	})
 
 	t.Run("car-not-exist", func(t *testing.T) {
 		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(entity.Car{}, sql.ErrNoRows).Once()
 
-		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
-		car, err := carUsecase.GetByID(context.TODO(), mockCar.ID)
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		car, err := carUsecase.GetByID(context.TODO(), mockCar.ID, entity.CarDisplay{})
 
 		assert.NotNil(t, err)
 		assert.Equal(t, car, entity.Car{})
@@ -102,8 +248,8 @@ func TestCarUC_GetByID(t *testing.T) {
 	t.Run("error-db", func(t *testing.T) {
 		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(entity.Car{}, errors.New("Unexpected Error")).Once()
 
-		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
-		car, err := carUsecase.GetByID(context.TODO(), mockCar.ID)
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		car, err := carUsecase.GetByID(context.TODO(), mockCar.ID, entity.CarDisplay{})
 
 		assert.NotNil(t, err)
 		assert.Equal(t, car, entity.Car{})
@@ -115,6 +261,13 @@ func TestCarUC_GetByID(t *testing.T) {
 func TestCarUC_Fetch(t *testing.T) {
 	mockRedisRepo := new(mocks.RedisRepository)
 	mockCarRepo := new(mocks.CarRepository)
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockPriceRepo := new(mocks.PriceHistoryRepository)
+	mockCatalogRepo := new(mocks.CatalogRepository)
+	mockOutboxRepo := new(mocks.OutboxRepository)
+	mockStorage := new(mocks.Storage)
+	mockTxManager := new(mocks.TxManager)
 	mockCar := entity.Car{
 		ID:             1,
 		Make:           "make",
@@ -124,7 +277,7 @@ func TestCarUC_Fetch(t *testing.T) {
 		Year:           0,
 		Category:       "category",
 		Mileage:        0,
-		Price:          0,
+		Price:          entity.Money{Amount: 1500000, Currency: "USD"},
 		Identification: "identification",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
@@ -134,12 +287,13 @@ func TestCarUC_Fetch(t *testing.T) {
 	mockListCar = append(mockListCar, mockCar)
 
 	t.Run("success", func(t *testing.T) {
-		mockRedisRepo.On("Get", mock.AnythingOfType("string")).Return("", errors.New("Unexpected Error")).Once()
-		mockCarRepo.On("Fetch", mock.Anything).Return(mockListCar, nil).Once()
-		mockRedisRepo.On("Set", mock.AnythingOfType("string"), mock.AnythingOfType("[]uint8"), mock.AnythingOfType("time.Duration")).Return(nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("7", nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, mock.AnythingOfType("string")).Return("", errors.New("Unexpected Error")).Once()
+		mockCarRepo.On("Fetch", mock.Anything, mock.AnythingOfType("entity.CarFilter"), entity.Page{}).Return(mockListCar, nil).Once()
+		mockRedisRepo.On("Set", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]uint8"), cacheTTL).Return(nil).Once()
 
-		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
-		cars, err := carUsecase.Fetch(context.TODO())
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		cars, err := carUsecase.Fetch(context.TODO(), entity.CarFilter{}, entity.CarDisplay{})
 
 		assert.NoError(t, err)
 		assert.Len(t, cars, len(mockListCar))
@@ -149,10 +303,11 @@ func TestCarUC_Fetch(t *testing.T) {
 
 	t.Run("success-get-from-cache", func(t *testing.T) {
 		mockListCarByte, _ := json.Marshal(mockListCar)
-		mockRedisRepo.On("Get", mock.AnythingOfType("string")).Return(string(mockListCarByte), nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("7", nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, mock.AnythingOfType("string")).Return(string(mockListCarByte), nil).Once()
 
-		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
-		cars, err := carUsecase.Fetch(context.TODO())
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		cars, err := carUsecase.Fetch(context.TODO(), entity.CarFilter{}, entity.CarDisplay{})
 
 		assert.NoError(t, err)
 		assert.Len(t, cars, len(mockListCar))
@@ -161,12 +316,108 @@ func TestCarUC_Fetch(t *testing.T) {
 
 	})
 
+	t.Run("success-filtered-by-features", func(t *testing.T) {
+		filter := entity.CarFilter{Features: []string{"heated_seats", "sunroof"}, FeatureMatch: entity.FeatureMatchAny}
+		mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("7", nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, "cars:v7:features=heated_seats,sunroof:match=any").Return("", errors.New("Unexpected Error")).Once()
+		mockCarRepo.On("Fetch", mock.Anything, filter, entity.Page{}).Return(mockListCar, nil).Once()
+		mockRedisRepo.On("Set", mock.Anything, "cars:v7:features=heated_seats,sunroof:match=any", mock.AnythingOfType("[]uint8"), cacheTTL).Return(nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		cars, err := carUsecase.Fetch(context.TODO(), filter, entity.CarDisplay{})
+
+		assert.NoError(t, err)
+		assert.Len(t, cars, len(mockListCar))
+		mockRedisRepo.AssertExpectations(t)
+		mockCarRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-display-currency", func(t *testing.T) {
+		mockListCarByte, _ := json.Marshal(mockListCar)
+		mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("7", nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, "cars:v7").Return(string(mockListCarByte), nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		cars, err := carUsecase.Fetch(context.TODO(), entity.CarFilter{}, entity.CarDisplay{Currency: "EUR"})
+
+		require.NoError(t, err)
+		require.Len(t, cars, 1)
+		assert.Equal(t, entity.Money{Amount: 1500000, Currency: "USD"}, cars[0].Price)
+		assert.Equal(t, &entity.Money{Amount: 1350000, Currency: "EUR"}, cars[0].DisplayPrice)
+		mockRedisRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-filtered-by-mileage-display-miles", func(t *testing.T) {
+		mileageMin, mileageMax := 1609, 16094
+		filter := entity.CarFilter{MileageMin: &mileageMin, MileageMax: &mileageMax}
+		mileageCar := mockCar
+		mileageCar.Mileage = 16093
+		mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("7", nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, "cars:v7:mileage_min=1609:mileage_max=16094").Return("", errors.New("Unexpected Error")).Once()
+		mockCarRepo.On("Fetch", mock.Anything, filter, entity.Page{}).Return([]entity.Car{mileageCar}, nil).Once()
+		mockRedisRepo.On("Set", mock.Anything, "cars:v7:mileage_min=1609:mileage_max=16094", mock.AnythingOfType("[]uint8"), cacheTTL).Return(nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		cars, err := carUsecase.Fetch(context.TODO(), filter, entity.CarDisplay{MileageUnit: entity.MileageUnitMiles})
+
+		require.NoError(t, err)
+		require.Len(t, cars, 1)
+		assert.Equal(t, 16093, cars[0].Mileage)
+		assert.Equal(t, &entity.Distance{Value: 10000, Unit: entity.MileageUnitMiles}, cars[0].DisplayMileage)
+		mockRedisRepo.AssertExpectations(t)
+		mockCarRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-no-exchange-rate", func(t *testing.T) {
+		mockListCarByte, _ := json.Marshal(mockListCar)
+		mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("7", nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, "cars:v7").Return(string(mockListCarByte), nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		_, err := carUsecase.Fetch(context.TODO(), entity.CarFilter{}, entity.CarDisplay{Currency: "GBP"})
+
+		require.Error(t, err)
+		assert.Equal(t, 400, err.(utils.HttpErr).Status())
+		mockRedisRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-version-missing", func(t *testing.T) {
+		mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("", goredis.Nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, "cars:v0").Return("", goredis.Nil).Once()
+		mockCarRepo.On("Fetch", mock.Anything, entity.CarFilter{}, entity.Page{}).Return(mockListCar, nil).Once()
+		mockRedisRepo.On("Set", mock.Anything, "cars:v0", mock.AnythingOfType("[]uint8"), cacheTTL).Return(nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		cars, err := carUsecase.Fetch(context.TODO(), entity.CarFilter{}, entity.CarDisplay{})
+
+		assert.NoError(t, err)
+		assert.Len(t, cars, len(mockListCar))
+		mockRedisRepo.AssertExpectations(t)
+		mockCarRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-version-unreadable-skips-cache", func(t *testing.T) {
+		mockRedisRepo := new(mocks.RedisRepository)
+		mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("", errors.New("Unexpected Error")).Once()
+		mockCarRepo.On("Fetch", mock.Anything, entity.CarFilter{}, entity.Page{}).Return(mockListCar, nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		cars, err := carUsecase.Fetch(context.TODO(), entity.CarFilter{}, entity.CarDisplay{})
+
+		assert.NoError(t, err)
+		assert.Len(t, cars, len(mockListCar))
+		mockRedisRepo.AssertExpectations(t)
+		mockRedisRepo.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockCarRepo.AssertExpectations(t)
+	})
+
 	t.Run("error-db", func(t *testing.T) {
-		mockRedisRepo.On("Get", mock.AnythingOfType("string")).Return("", errors.New("Unexpected Error")).Once()
-		mockCarRepo.On("Fetch", mock.Anything).Return([]entity.Car{}, errors.New("Unexpected Error")).Once()
+		mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("7", nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, mock.AnythingOfType("string")).Return("", errors.New("Unexpected Error")).Once()
+		mockCarRepo.On("Fetch", mock.Anything, mock.AnythingOfType("entity.CarFilter"), entity.Page{}).Return([]entity.Car{}, errors.New("Unexpected Error")).Once()
 
-		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
-		cars, err := carUsecase.Fetch(context.TODO())
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		cars, err := carUsecase.Fetch(context.TODO(), entity.CarFilter{}, entity.CarDisplay{})
 
 		assert.NotNil(t, err)
 		assert.Len(t, cars, 0)
@@ -175,9 +426,71 @@ func TestCarUC_Fetch(t *testing.T) {
 	})
 }
 
+func TestCarUC_FetchPage(t *testing.T) {
+	mockRedisRepo := new(mocks.RedisRepository)
+	mockCarRepo := new(mocks.CarRepository)
+	mockCar := entity.Car{ID: 3, Make: "make", Price: entity.Money{Amount: 1500000, Currency: "USD"}}
+	page := entity.Page{Limit: 2, Offset: 2}
+
+	t.Run("success", func(t *testing.T) {
+		mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("7", nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, "cars:v7:limit=2:offset=2").Return("", errors.New("Unexpected Error")).Once()
+		mockCarRepo.On("Fetch", mock.Anything, entity.CarFilter{}, page).Return([]entity.Car{mockCar}, nil).Once()
+		mockCarRepo.On("Count", mock.Anything, entity.CarFilter{}).Return(int64(3), nil).Once()
+		mockRedisRepo.On("Set", mock.Anything, "cars:v7:limit=2:offset=2", mock.AnythingOfType("[]uint8"), cacheTTL).Return(nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, nil, nil, nil, nil, nil, mockRedisRepo, nil, nil, exchangeRates, cacheTTL, ctxTimeout)
+		cars, total, err := carUsecase.FetchPage(context.TODO(), entity.CarFilter{}, page, entity.CarDisplay{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []entity.Car{mockCar}, cars)
+		assert.Equal(t, int64(3), total)
+		mockRedisRepo.AssertExpectations(t)
+		mockCarRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-get-from-cache", func(t *testing.T) {
+		mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("7", nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, "cars:v7:limit=2:offset=2").Return(`{"cars":[{"id":3,"make":"make","price":{"amount":1500000,"currency":"USD"}}],"total":3}`, nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, nil, nil, nil, nil, nil, mockRedisRepo, nil, nil, exchangeRates, cacheTTL, ctxTimeout)
+		cars, total, err := carUsecase.FetchPage(context.TODO(), entity.CarFilter{}, page, entity.CarDisplay{Currency: "EUR"})
+
+		require.NoError(t, err)
+		require.Len(t, cars, 1)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, &entity.Money{Amount: 1350000, Currency: "EUR"}, cars[0].DisplayPrice)
+		mockRedisRepo.AssertExpectations(t)
+		mockCarRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-db", func(t *testing.T) {
+		mockRedisRepo := new(mocks.RedisRepository)
+		mockCarRepo := new(mocks.CarRepository)
+		mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("7", nil).Once()
+		mockRedisRepo.On("Get", mock.Anything, "cars:v7:limit=2:offset=2").Return("", errors.New("Unexpected Error")).Once()
+		mockCarRepo.On("Fetch", mock.Anything, entity.CarFilter{}, page).Return([]entity.Car{mockCar}, nil).Once()
+		mockCarRepo.On("Count", mock.Anything, entity.CarFilter{}).Return(int64(0), errors.New("Unexpected Error")).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, nil, nil, nil, nil, nil, mockRedisRepo, nil, nil, exchangeRates, cacheTTL, ctxTimeout)
+		_, _, err := carUsecase.FetchPage(context.TODO(), entity.CarFilter{}, page, entity.CarDisplay{})
+
+		assert.Error(t, err)
+		mockRedisRepo.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockCarRepo.AssertExpectations(t)
+	})
+}
+
 func TestCarUC_Update(t *testing.T) {
 	mockRedisRepo := new(mocks.RedisRepository)
 	mockCarRepo := new(mocks.CarRepository)
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockPriceRepo := new(mocks.PriceHistoryRepository)
+	mockCatalogRepo := new(mocks.CatalogRepository)
+	mockOutboxRepo := new(mocks.OutboxRepository)
+	mockStorage := new(mocks.Storage)
+	mockTxManager := new(mocks.TxManager)
 	mockCar := entity.Car{
 		ID:             1,
 		Make:           "make",
@@ -187,7 +500,7 @@ func TestCarUC_Update(t *testing.T) {
 		Year:           0,
 		Category:       "category",
 		Mileage:        0,
-		Price:          0,
+		Price:          entity.Money{Amount: 1500000, Currency: "USD"},
 		Identification: "identification",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
@@ -200,49 +513,105 @@ func TestCarUC_Update(t *testing.T) {
 		Year:           0,
 		Category:       "category",
 		Mileage:        0,
-		Price:          0,
+		Price:          request.MoneyReq{Amount: 1500000, Currency: "usd"},
 		Identification: "identification",
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
-		mockCarRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil).Once()
-
-		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
+		expectTransaction(mockTxManager)
+		mockCarRepo.On("GetByID", withinTransaction, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
+		expectCatalog(mockCatalogRepo)
+		mockCarRepo.On("Update", withinTransaction, mock.MatchedBy(func(car *entity.Car) bool {
+			return car.Model == "Model" && car.Category == "Category"
+		})).Return(nil).Once()
+		mockOutboxRepo.On("Add", withinTransaction, eventOf(entity.EventCarUpdated, mockCar.ID)).Return(nil).Once()
+		mockRedisRepo.On("Incr", mock.Anything, "cars:listing:version").Return(int64(2), nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
 		err := carUsecase.Update(context.TODO(), mockCar.ID, &updateCarReq)
 
 		assert.NoError(t, err)
 		mockRedisRepo.AssertExpectations(t)
 		mockCarRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-price-changed", func(t *testing.T) {
+		repricedCarReq := updateCarReq
+		repricedCarReq.Price = request.MoneyReq{Amount: 1400000, Currency: "eur"}
+		expectTransaction(mockTxManager)
+		mockCarRepo.On("GetByID", withinTransaction, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
+		expectCatalog(mockCatalogRepo)
+		mockCarRepo.On("Update", withinTransaction, mock.AnythingOfType("*entity.Car")).Return(nil).Once()
+		mockPriceRepo.On("Add", withinTransaction, mock.MatchedBy(func(point *entity.PricePoint) bool {
+			return point.CarID == 1 && point.Price == entity.Money{Amount: 1400000, Currency: "EUR"}
+		})).Return(nil).Once()
+		updated := mockOutboxRepo.On("Add", withinTransaction, eventOf(entity.EventCarUpdated, mockCar.ID)).Return(nil).Once()
+		mockOutboxRepo.On("Add", withinTransaction, mock.MatchedBy(func(event *entity.Event) bool {
+			var payload entity.CarPriceChanged
+			return event.Type == entity.EventCarPriceChanged && json.Unmarshal(event.Payload, &payload) == nil &&
+				payload == entity.CarPriceChanged{CarID: 1, OldPrice: entity.Money{Amount: 1500000, Currency: "USD"}, NewPrice: entity.Money{Amount: 1400000, Currency: "EUR"}}
+		})).Return(nil).Once().NotBefore(updated)
+		mockRedisRepo.On("Incr", mock.Anything, "cars:listing:version").Return(int64(2), nil).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		err := carUsecase.Update(context.TODO(), mockCar.ID, &repricedCarReq)
+
+		assert.NoError(t, err)
+		mockRedisRepo.AssertExpectations(t)
+		mockCarRepo.AssertExpectations(t)
+		mockPriceRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
 	})
 
 	t.Run("car-not-exist", func(t *testing.T) {
+		expectTransaction(mockTxManager)
 		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(entity.Car{}, sql.ErrNoRows).Once()
 
-		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
 		err := carUsecase.Update(context.TODO(), mockCar.ID, &updateCarReq)
 
-		assert.NotNil(t, err)
+		assert.IsType(t, utils.HttpError{}, err)
 		mockRedisRepo.AssertExpectations(t)
 		mockCarRepo.AssertExpectations(t)
 	})
 
 	t.Run("error-db", func(t *testing.T) {
+		expectTransaction(mockTxManager)
 		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
+		expectCatalog(mockCatalogRepo)
 		mockCarRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(errors.New("Unexpected Error")).Once()
 
-		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
 		err := carUsecase.Update(context.TODO(), mockCar.ID, &updateCarReq)
 
 		assert.NotNil(t, err)
 		mockRedisRepo.AssertExpectations(t)
 		mockCarRepo.AssertExpectations(t)
 	})
+
+	t.Run("error-begin-transaction", func(t *testing.T) {
+		mockTxManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(errors.New("Unexpected Error")).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		err := carUsecase.Update(context.TODO(), mockCar.ID, &updateCarReq)
+
+		assert.EqualError(t, err, "Unexpected Error")
+		mockCarRepo.AssertExpectations(t)
+		mockTxManager.AssertExpectations(t)
+	})
 }
 
 func TestCarUC_Delete(t *testing.T) {
 	mockRedisRepo := new(mocks.RedisRepository)
 	mockCarRepo := new(mocks.CarRepository)
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockPriceRepo := new(mocks.PriceHistoryRepository)
+	mockCatalogRepo := new(mocks.CatalogRepository)
+	mockOutboxRepo := new(mocks.OutboxRepository)
+	mockStorage := new(mocks.Storage)
+	mockTxManager := new(mocks.TxManager)
 	mockCar := entity.Car{
 		ID:             1,
 		Make:           "make",
@@ -252,44 +621,150 @@ func TestCarUC_Delete(t *testing.T) {
 		Year:           0,
 		Category:       "category",
 		Mileage:        0,
-		Price:          0,
+		Price:          entity.Money{Amount: 1500000, Currency: "USD"},
 		Identification: "identification",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
-		mockCarRepo.On("Delete", mock.Anything, mock.AnythingOfType("int64")).Return(nil).Once()
+		expectTransaction(mockTxManager)
+		mockCarRepo.On("GetByID", withinTransaction, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
+		mockCarRepo.On("Delete", withinTransaction, mock.AnythingOfType("int64")).Return(nil).Once()
+		mockOutboxRepo.On("Add", withinTransaction, eventOf(entity.EventCarDeleted, mockCar.ID)).Return(nil).Once()
+		mockRedisRepo.On("Incr", mock.Anything, "cars:listing:version").Return(int64(2), nil).Once()
 
-		carRepository := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
+		carRepository := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
 		err := carRepository.Delete(context.TODO(), mockCar.ID)
 
 		assert.NoError(t, err)
 		mockRedisRepo.AssertExpectations(t)
 		mockCarRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+		mockStorage.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
 	})
 
 	t.Run("car-not-exist", func(t *testing.T) {
+		expectTransaction(mockTxManager)
 		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(entity.Car{}, sql.ErrNoRows).Once()
 
-		carRepository := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
+		carRepository := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
 		err := carRepository.Delete(context.TODO(), mockCar.ID)
 
-		assert.NotNil(t, err)
+		assert.IsType(t, utils.HttpError{}, err)
 		mockRedisRepo.AssertExpectations(t)
 		mockCarRepo.AssertExpectations(t)
 	})
 
 	t.Run("error-db", func(t *testing.T) {
+		expectTransaction(mockTxManager)
 		mockCarRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockCar, nil).Once()
 		mockCarRepo.On("Delete", mock.Anything, mock.AnythingOfType("int64")).Return(errors.New("Unexpected Error")).Once()
 
-		carRepository := usecase.NewCarUsecase(mockCarRepo, mockRedisRepo, ctxTimeout)
+		carRepository := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
 		err := carRepository.Delete(context.TODO(), mockCar.ID)
 
 		assert.NotNil(t, err)
 		mockRedisRepo.AssertExpectations(t)
 		mockCarRepo.AssertExpectations(t)
 	})
+
+	mockTxManager.AssertExpectations(t)
+}
+
+func TestCarUC_FlushCache(t *testing.T) {
+	mockRedisRepo := new(mocks.RedisRepository)
+	mockCarRepo := new(mocks.CarRepository)
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockPriceRepo := new(mocks.PriceHistoryRepository)
+	mockCatalogRepo := new(mocks.CatalogRepository)
+	mockOutboxRepo := new(mocks.OutboxRepository)
+	mockStorage := new(mocks.Storage)
+	mockTxManager := new(mocks.TxManager)
+
+	mockRedisRepo.On("Incr", mock.Anything, "cars:listing:version").Return(int64(8), nil).Once()
+
+	carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+	version, err := carUsecase.FlushCache(context.TODO())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), version)
+	mockRedisRepo.AssertExpectations(t)
+}
+
+func TestCarUC_RebuildCache(t *testing.T) {
+	mockRedisRepo := new(mocks.RedisRepository)
+	mockCarRepo := new(mocks.CarRepository)
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockPriceRepo := new(mocks.PriceHistoryRepository)
+	mockCatalogRepo := new(mocks.CatalogRepository)
+	mockOutboxRepo := new(mocks.OutboxRepository)
+	mockStorage := new(mocks.Storage)
+	mockTxManager := new(mocks.TxManager)
+
+	mockRedisRepo.On("Incr", mock.Anything, "cars:listing:version").Return(int64(8), nil).Once()
+	mockRedisRepo.On("Get", mock.Anything, "cars:listing:version").Return("8", nil).Once()
+	mockRedisRepo.On("Get", mock.Anything, "cars:v8").Return("", errors.New("Unexpected Error")).Once()
+	mockCarRepo.On("Fetch", mock.Anything, entity.CarFilter{}, entity.Page{}).Return([]entity.Car{{ID: 1}, {ID: 2}}, nil).Once()
+	mockRedisRepo.On("Set", mock.Anything, "cars:v8", mock.Anything, cacheTTL).Return(nil).Once()
+
+	carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+	listed, err := carUsecase.RebuildCache(context.TODO())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, listed)
+	mockRedisRepo.AssertExpectations(t)
+	mockCarRepo.AssertExpectations(t)
+}
+
+func TestCarUC_PurgeDeleted(t *testing.T) {
+	mockRedisRepo := new(mocks.RedisRepository)
+	mockCarRepo := new(mocks.CarRepository)
+	mockCarImageRepo := new(mocks.CarImageRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockPriceRepo := new(mocks.PriceHistoryRepository)
+	mockCatalogRepo := new(mocks.CatalogRepository)
+	mockOutboxRepo := new(mocks.OutboxRepository)
+	mockStorage := new(mocks.Storage)
+	mockTxManager := new(mocks.TxManager)
+
+	before := time.Date(2026, 9, 19, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		expectTransaction(mockTxManager)
+		mockCarImageRepo.On("FetchByCarsDeletedBefore", withinTransaction, before).Return([]entity.CarImage{{ID: 1, CarID: 1, StorageKey: "cars/1/a.png"}}, nil).Once()
+		mockCarRepo.On("PurgeDeleted", withinTransaction, before).Return(int64(2), nil).Once()
+		mockStorage.On("Delete", mock.Anything, "cars/1/a.png").Return(nil).Once()
+		for size := range entity.ThumbnailSizes {
+			mockStorage.On("Delete", mock.Anything, "cars/1/a_"+size+".png").Return(nil).Once()
+		}
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		purged, err := carUsecase.PurgeDeleted(context.TODO(), before)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+		mockCarRepo.AssertExpectations(t)
+		mockCarImageRepo.AssertExpectations(t)
+		mockStorage.AssertExpectations(t)
+		mockRedisRepo.AssertNotCalled(t, "Incr", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-db-keeps-files", func(t *testing.T) {
+		mockStorage := new(mocks.Storage)
+		expectTransaction(mockTxManager)
+		mockCarImageRepo.On("FetchByCarsDeletedBefore", withinTransaction, before).Return([]entity.CarImage{{ID: 1, CarID: 1, StorageKey: "cars/1/a.png"}}, nil).Once()
+		mockCarRepo.On("PurgeDeleted", withinTransaction, before).Return(int64(0), errors.New("Unexpected Error")).Once()
+
+		carUsecase := usecase.NewCarUsecase(mockCarRepo, mockCarImageRepo, mockFeatureRepo, mockPriceRepo, mockCatalogRepo, mockOutboxRepo, mockRedisRepo, mockTxManager, mockStorage, exchangeRates, cacheTTL, ctxTimeout)
+		_, err := carUsecase.PurgeDeleted(context.TODO(), before)
+
+		assert.Error(t, err)
+		mockCarRepo.AssertExpectations(t)
+		mockStorage.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	mockTxManager.AssertExpectations(t)
 }
@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"carApi/transport/request"
+	"carApi/utils"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// CatalogUsecase represent the reference catalog's usecase contract
+type CatalogUsecase interface {
+	Fetch(ctx context.Context, kind string, path entity.CatalogPath) ([]entity.CatalogEntry, error)
+	Create(ctx context.Context, kind string, path entity.CatalogPath, request *request.CatalogEntryReq) (entity.CatalogEntry, error)
+	Update(ctx context.Context, kind string, path entity.CatalogPath, name string, request *request.CatalogEntryReq) error
+	Delete(ctx context.Context, kind string, path entity.CatalogPath, name string) error
+	Seed(ctx context.Context, seed entity.CatalogSeed) error
+}
+
+type catalogUsecase struct {
+	catalogRepo pgsql.CatalogRepository
+	ctxTimeout  time.Duration
+}
+
+// NewCatalogUsecase will create new a catalogUsecase object representation of CatalogUsecase interface
+func NewCatalogUsecase(catalogRepo pgsql.CatalogRepository, ctxTimeout time.Duration) CatalogUsecase {
+	return &catalogUsecase{
+		catalogRepo: catalogRepo,
+		ctxTimeout:  ctxTimeout,
+	}
+}
+
+func (u *catalogUsecase) Fetch(c context.Context, kind string, path entity.CatalogPath) (entries []entity.CatalogEntry, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	parentID, err := u.resolveParent(ctx, kind, path)
+	if err != nil {
+		return
+	}
+
+	entries, err = u.catalogRepo.Fetch(ctx, kind, parentID)
+	return
+}
+
+func (u *catalogUsecase) Create(c context.Context, kind string, path entity.CatalogPath, request *request.CatalogEntryReq) (entry entity.CatalogEntry, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	parentID, err := u.resolveParent(ctx, kind, path)
+	if err != nil {
+		return
+	}
+
+	name := strings.TrimSpace(request.Name)
+	_, err = u.catalogRepo.GetByName(ctx, kind, parentID, name)
+	if err == nil {
+		err = utils.NewError(utils.CodeCatalogEntryAlreadyExists, fmt.Sprintf("%s %s already exists", kind, name))
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+
+	entry = entity.CatalogEntry{
+		Kind:      kind,
+		ParentID:  parentID,
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	err = u.catalogRepo.Create(ctx, &entry)
+	return
+}
+
+func (u *catalogUsecase) Update(c context.Context, kind string, path entity.CatalogPath, name string, request *request.CatalogEntryReq) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	entry, err := u.get(ctx, kind, path, name)
+	if err != nil {
+		return
+	}
+
+	newName := strings.TrimSpace(request.Name)
+	existing, err := u.catalogRepo.GetByName(ctx, kind, entry.ParentID, newName)
+	if err == nil && existing.ID != entry.ID {
+		err = utils.NewError(utils.CodeCatalogEntryAlreadyExists, fmt.Sprintf("%s %s already exists", kind, newName))
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+
+	entry.Name = newName
+	entry.UpdatedAt = time.Now()
+
+	err = u.catalogRepo.Update(ctx, &entry)
+	return
+}
+
+func (u *catalogUsecase) Delete(c context.Context, kind string, path entity.CatalogPath, name string) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	entry, err := u.get(ctx, kind, path, name)
+	if err != nil {
+		return
+	}
+
+	err = u.catalogRepo.Delete(ctx, entry.ID)
+	return
+}
+
+// Seed loads the catalog entries that are missing, entries that already
+// exist are left untouched so seeding can run on every start
+func (u *catalogUsecase) Seed(c context.Context, seed entity.CatalogSeed) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	for _, seedMake := range seed.Makes {
+		carMake, err := u.ensure(ctx, entity.CatalogKindMake, 0, seedMake.Name)
+		if err != nil {
+			return err
+		}
+
+		for _, seedModel := range seedMake.Models {
+			carModel, err := u.ensure(ctx, entity.CatalogKindModel, carMake.ID, seedModel.Name)
+			if err != nil {
+				return err
+			}
+
+			for _, trim := range seedModel.Trims {
+				if _, err := u.ensure(ctx, entity.CatalogKindTrim, carModel.ID, trim); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, category := range seed.Categories {
+		if _, err = u.ensure(ctx, entity.CatalogKindCategory, 0, category); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (u *catalogUsecase) ensure(ctx context.Context, kind string, parentID int64, name string) (entry entity.CatalogEntry, err error) {
+	entry, err = u.catalogRepo.GetByName(ctx, kind, parentID, name)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+
+	entry = entity.CatalogEntry{
+		Kind:      kind,
+		ParentID:  parentID,
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	err = u.catalogRepo.Create(ctx, &entry)
+	return
+}
+
+func (u *catalogUsecase) get(ctx context.Context, kind string, path entity.CatalogPath, name string) (entry entity.CatalogEntry, err error) {
+	parentID, err := u.resolveParent(ctx, kind, path)
+	if err != nil {
+		return
+	}
+
+	entry, err = u.catalogRepo.GetByName(ctx, kind, parentID, name)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		err = utils.NewError(utils.CodeCatalogEntryNotFound, kind+" not found")
+	}
+	return
+}
+
+// resolveParent walks the path down to the parent of kind and returns its ID
+func (u *catalogUsecase) resolveParent(ctx context.Context, kind string, path entity.CatalogPath) (parentID int64, err error) {
+	parentKind, nested := entity.CatalogParentKind[kind]
+	if !nested {
+		return
+	}
+
+	if parentKind == entity.CatalogKindModel {
+		parentID, err = u.resolveParent(ctx, parentKind, path)
+		if err != nil {
+			return
+		}
+	}
+
+	name := path.Make
+	if parentKind == entity.CatalogKindModel {
+		name = path.Model
+	}
+
+	parent, err := u.catalogRepo.GetByName(ctx, parentKind, parentID, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = utils.NewError(utils.CodeCatalogEntryNotFound, parentKind+" not found")
+		}
+		return
+	}
+
+	return parent.ID, nil
+}
+
+// normalizeCatalog checks the make, model, package and category of a car
+// against the reference catalog and rewrites them with the catalog spelling.
+// The package is only checked when trims are defined for the model.
+func normalizeCatalog(ctx context.Context, catalogRepo pgsql.CatalogRepository, car *entity.Car) error {
+	errs := validation.Errors{}
+
+	carMake, err := catalogRepo.GetByName(ctx, entity.CatalogKindMake, 0, car.Make)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		errs["make"] = errors.New("unknown make")
+	case err != nil:
+		return err
+	default:
+		car.Make = carMake.Name
+
+		carModel, err := catalogRepo.GetByName(ctx, entity.CatalogKindModel, carMake.ID, car.Model)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			errs["model"] = fmt.Errorf("unknown model for make %s", carMake.Name)
+		case err != nil:
+			return err
+		default:
+			car.Model = carModel.Name
+
+			trims, err := catalogRepo.Fetch(ctx, entity.CatalogKindTrim, carModel.ID)
+			if err != nil {
+				return err
+			}
+
+			if len(trims) > 0 {
+				errs["package"] = fmt.Errorf("unknown trim for model %s", carModel.Name)
+				for _, trim := range trims {
+					if strings.EqualFold(trim.Name, car.Package) {
+						car.Package = trim.Name
+						delete(errs, "package")
+						break
+					}
+				}
+			}
+		}
+	}
+
+	category, err := catalogRepo.GetByName(ctx, entity.CatalogKindCategory, 0, car.Category)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		errs["category"] = errors.New("unknown category")
+	case err != nil:
+		return err
+	default:
+		car.Category = category.Name
+	}
+
+	if len(errs) > 0 {
+		return utils.NewInvalidInputError(errs)
+	}
+	return nil
+}
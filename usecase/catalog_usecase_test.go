@@ -0,0 +1,155 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogUC_Fetch(t *testing.T) {
+	mockCatalogRepo := new(mocks.CatalogRepository)
+	toyota := entity.CatalogEntry{ID: 1, Kind: entity.CatalogKindMake, Name: "Toyota"}
+
+	t.Run("success-models-of-make", func(t *testing.T) {
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindMake, int64(0), "toyota").Return(toyota, nil).Once()
+		mockCatalogRepo.On("Fetch", mock.Anything, entity.CatalogKindModel, int64(1)).
+			Return([]entity.CatalogEntry{{ID: 2, Kind: entity.CatalogKindModel, ParentID: 1, Name: "Corolla"}}, nil).Once()
+
+		catalogUsecase := usecase.NewCatalogUsecase(mockCatalogRepo, ctxTimeout)
+		entries, err := catalogUsecase.Fetch(context.TODO(), entity.CatalogKindModel, entity.CatalogPath{Make: "toyota"})
+
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		mockCatalogRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-trims-of-model", func(t *testing.T) {
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindMake, int64(0), "toyota").Return(toyota, nil).Once()
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindModel, int64(1), "corolla").
+			Return(entity.CatalogEntry{ID: 2, Kind: entity.CatalogKindModel, ParentID: 1, Name: "Corolla"}, nil).Once()
+		mockCatalogRepo.On("Fetch", mock.Anything, entity.CatalogKindTrim, int64(2)).Return([]entity.CatalogEntry{}, nil).Once()
+
+		catalogUsecase := usecase.NewCatalogUsecase(mockCatalogRepo, ctxTimeout)
+		_, err := catalogUsecase.Fetch(context.TODO(), entity.CatalogKindTrim, entity.CatalogPath{Make: "toyota", Model: "corolla"})
+
+		assert.NoError(t, err)
+		mockCatalogRepo.AssertExpectations(t)
+	})
+
+	t.Run("make-not-exist", func(t *testing.T) {
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindMake, int64(0), "tesla").Return(entity.CatalogEntry{}, sql.ErrNoRows).Once()
+
+		catalogUsecase := usecase.NewCatalogUsecase(mockCatalogRepo, ctxTimeout)
+		_, err := catalogUsecase.Fetch(context.TODO(), entity.CatalogKindModel, entity.CatalogPath{Make: "tesla"})
+
+		require.Error(t, err)
+		assert.Equal(t, 404, err.(utils.HttpErr).Status())
+		mockCatalogRepo.AssertExpectations(t)
+	})
+}
+
+func TestCatalogUC_Create(t *testing.T) {
+	mockCatalogRepo := new(mocks.CatalogRepository)
+
+	t.Run("success", func(t *testing.T) {
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindCategory, int64(0), "SUV").Return(entity.CatalogEntry{}, sql.ErrNoRows).Once()
+		mockCatalogRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.CatalogEntry")).Return(nil).Once()
+
+		catalogUsecase := usecase.NewCatalogUsecase(mockCatalogRepo, ctxTimeout)
+		entry, err := catalogUsecase.Create(context.TODO(), entity.CatalogKindCategory, entity.CatalogPath{}, &request.CatalogEntryReq{Name: " SUV "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SUV", entry.Name)
+		mockCatalogRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-duplicate", func(t *testing.T) {
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindMake, int64(0), "toyota").
+			Return(entity.CatalogEntry{ID: 1, Kind: entity.CatalogKindMake, Name: "Toyota"}, nil).Once()
+
+		catalogUsecase := usecase.NewCatalogUsecase(mockCatalogRepo, ctxTimeout)
+		_, err := catalogUsecase.Create(context.TODO(), entity.CatalogKindMake, entity.CatalogPath{}, &request.CatalogEntryReq{Name: "toyota"})
+
+		require.Error(t, err)
+		assert.Equal(t, 409, err.(utils.HttpErr).Status())
+		mockCatalogRepo.AssertExpectations(t)
+	})
+}
+
+func TestCatalogUC_Update(t *testing.T) {
+	mockCatalogRepo := new(mocks.CatalogRepository)
+
+	t.Run("success-fix-casing", func(t *testing.T) {
+		toyota := entity.CatalogEntry{ID: 1, Kind: entity.CatalogKindMake, Name: "TOYOTA"}
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindMake, int64(0), "toyota").Return(toyota, nil).Once()
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindMake, int64(0), "Toyota").Return(toyota, nil).Once()
+		mockCatalogRepo.On("Update", mock.Anything, mock.MatchedBy(func(entry *entity.CatalogEntry) bool {
+			return entry.ID == 1 && entry.Name == "Toyota"
+		})).Return(nil).Once()
+
+		catalogUsecase := usecase.NewCatalogUsecase(mockCatalogRepo, ctxTimeout)
+		err := catalogUsecase.Update(context.TODO(), entity.CatalogKindMake, entity.CatalogPath{}, "toyota", &request.CatalogEntryReq{Name: "Toyota"})
+
+		assert.NoError(t, err)
+		mockCatalogRepo.AssertExpectations(t)
+	})
+}
+
+func TestCatalogUC_Delete(t *testing.T) {
+	mockCatalogRepo := new(mocks.CatalogRepository)
+
+	t.Run("success", func(t *testing.T) {
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindCategory, int64(0), "suv").
+			Return(entity.CatalogEntry{ID: 4, Kind: entity.CatalogKindCategory, Name: "SUV"}, nil).Once()
+		mockCatalogRepo.On("Delete", mock.Anything, int64(4)).Return(nil).Once()
+
+		catalogUsecase := usecase.NewCatalogUsecase(mockCatalogRepo, ctxTimeout)
+		err := catalogUsecase.Delete(context.TODO(), entity.CatalogKindCategory, entity.CatalogPath{}, "suv")
+
+		assert.NoError(t, err)
+		mockCatalogRepo.AssertExpectations(t)
+	})
+}
+
+func TestCatalogUC_Seed(t *testing.T) {
+	mockCatalogRepo := new(mocks.CatalogRepository)
+	seed := entity.CatalogSeed{
+		Makes: []entity.CatalogSeedMake{
+			{Name: "Toyota", Models: []entity.CatalogSeedModel{{Name: "Corolla", Trims: []string{"LE"}}}},
+		},
+		Categories: []string{"Sedan"},
+	}
+
+	t.Run("success-only-missing-entries", func(t *testing.T) {
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindMake, int64(0), "Toyota").
+			Return(entity.CatalogEntry{ID: 1, Kind: entity.CatalogKindMake, Name: "Toyota"}, nil).Once()
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindModel, int64(1), "Corolla").
+			Return(entity.CatalogEntry{}, sql.ErrNoRows).Once()
+		mockCatalogRepo.On("Create", mock.Anything, mock.MatchedBy(func(entry *entity.CatalogEntry) bool {
+			entry.ID = 2
+			return entry.Kind == entity.CatalogKindModel && entry.ParentID == 1
+		})).Return(nil).Once()
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindTrim, int64(2), "LE").
+			Return(entity.CatalogEntry{}, sql.ErrNoRows).Once()
+		mockCatalogRepo.On("Create", mock.Anything, mock.MatchedBy(func(entry *entity.CatalogEntry) bool {
+			return entry.Kind == entity.CatalogKindTrim && entry.ParentID == 2
+		})).Return(nil).Once()
+		mockCatalogRepo.On("GetByName", mock.Anything, entity.CatalogKindCategory, int64(0), "Sedan").
+			Return(entity.CatalogEntry{ID: 4, Kind: entity.CatalogKindCategory, Name: "Sedan"}, nil).Once()
+
+		catalogUsecase := usecase.NewCatalogUsecase(mockCatalogRepo, ctxTimeout)
+		err := catalogUsecase.Seed(context.TODO(), seed)
+
+		assert.NoError(t, err)
+		mockCatalogRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"carApi/repository/redis"
+	"carApi/transport/request"
+	"carApi/utils"
+)
+
+// FeatureUsecase represent the feature's usecase contract
+type FeatureUsecase interface {
+	Create(ctx context.Context, request *request.CreateFeatureReq) (entity.Feature, error)
+	GetByID(ctx context.Context, id int64) (entity.Feature, error)
+	Fetch(ctx context.Context) ([]entity.Feature, error)
+	Update(ctx context.Context, id int64, request *request.UpdateFeatureReq) error
+	Delete(ctx context.Context, id int64) error
+	FetchByCar(ctx context.Context, carID int64) ([]entity.Feature, error)
+	AttachToCar(ctx context.Context, carID int64, request *request.AttachCarFeaturesReq) error
+	DetachFromCar(ctx context.Context, carID int64, code string) error
+}
+
+type featureUsecase struct {
+	carRepo     pgsql.CarRepository
+	featureRepo pgsql.FeatureRepository
+	redisRepo   redis.RedisRepository
+	ctxTimeout  time.Duration
+}
+
+// NewFeatureUsecase will create new a featureUsecase object representation of FeatureUsecase interface
+func NewFeatureUsecase(carRepo pgsql.CarRepository, featureRepo pgsql.FeatureRepository, redisRepo redis.RedisRepository, ctxTimeout time.Duration) FeatureUsecase {
+	return &featureUsecase{
+		carRepo:     carRepo,
+		featureRepo: featureRepo,
+		redisRepo:   redisRepo,
+		ctxTimeout:  ctxTimeout,
+	}
+}
+
+func (u *featureUsecase) Create(c context.Context, request *request.CreateFeatureReq) (feature entity.Feature, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	if err = u.ensureCodeAvailable(ctx, request.Code, 0); err != nil {
+		return
+	}
+
+	feature = entity.Feature{
+		Code:      request.Code,
+		Name:      request.Name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	err = u.featureRepo.Create(ctx, &feature)
+	return
+}
+
+func (u *featureUsecase) GetByID(c context.Context, id int64) (feature entity.Feature, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	feature, err = u.featureRepo.GetByID(ctx, id)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		err = utils.NewError(utils.CodeFeatureNotFound, "feature not found")
+		return
+	}
+	return
+}
+
+func (u *featureUsecase) Fetch(c context.Context) (features []entity.Feature, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	features, err = u.featureRepo.Fetch(ctx)
+	return
+}
+
+func (u *featureUsecase) Update(c context.Context, id int64, request *request.UpdateFeatureReq) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	feature, err := u.featureRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = utils.NewError(utils.CodeFeatureNotFound, "feature not found")
+		}
+		return
+	}
+
+	if err = u.ensureCodeAvailable(ctx, request.Code, id); err != nil {
+		return
+	}
+
+	feature.Code = request.Code
+	feature.Name = request.Name
+	feature.UpdatedAt = time.Now()
+
+	if err = u.featureRepo.Update(ctx, &feature); err == nil {
+		invalidateListings(ctx, u.redisRepo)
+	}
+	return
+}
+
+func (u *featureUsecase) Delete(c context.Context, id int64) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	_, err = u.featureRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = utils.NewError(utils.CodeFeatureNotFound, "feature not found")
+		}
+		return
+	}
+
+	if err = u.featureRepo.Delete(ctx, id); err == nil {
+		invalidateListings(ctx, u.redisRepo)
+	}
+	return
+}
+
+func (u *featureUsecase) FetchByCar(c context.Context, carID int64) (features []entity.Feature, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	if err = u.ensureCarExists(ctx, carID); err != nil {
+		return
+	}
+
+	features, err = u.featureRepo.FetchByCarID(ctx, carID)
+	return
+}
+
+func (u *featureUsecase) AttachToCar(c context.Context, carID int64, request *request.AttachCarFeaturesReq) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	if err = u.ensureCarExists(ctx, carID); err != nil {
+		return
+	}
+
+	features, err := u.featureRepo.FetchByCodes(ctx, request.Features)
+	if err != nil {
+		return
+	}
+
+	known := make(map[string]bool, len(features))
+	featureIDs := make([]int64, 0, len(features))
+	for _, feature := range features {
+		known[feature.Code] = true
+		featureIDs = append(featureIDs, feature.ID)
+	}
+
+	var unknown []string
+	for _, code := range request.Features {
+		if !known[code] {
+			unknown = append(unknown, code)
+		}
+	}
+	if len(unknown) > 0 {
+		err = utils.NewError(utils.CodeUnknownFeatures, fmt.Sprintf("unknown features: %s", strings.Join(unknown, ", ")))
+		return
+	}
+
+	if err = u.featureRepo.AttachToCar(ctx, carID, featureIDs); err == nil {
+		invalidateListings(ctx, u.redisRepo)
+	}
+	return
+}
+
+func (u *featureUsecase) DetachFromCar(c context.Context, carID int64, code string) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	if err = u.ensureCarExists(ctx, carID); err != nil {
+		return
+	}
+
+	feature, err := u.featureRepo.GetByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = utils.NewError(utils.CodeFeatureNotFound, "feature not found")
+		}
+		return
+	}
+
+	if err = u.featureRepo.DetachFromCar(ctx, carID, feature.ID); err == nil {
+		invalidateListings(ctx, u.redisRepo)
+	}
+	return
+}
+
+func (u *featureUsecase) ensureCarExists(ctx context.Context, carID int64) (err error) {
+	if _, err = u.carRepo.GetByID(ctx, carID); err != nil && errors.Is(err, sql.ErrNoRows) {
+		err = utils.NewError(utils.CodeCarNotFound, "car not found")
+	}
+	return
+}
+
+// ensureCodeAvailable rejects a code that already belongs to another feature
+func (u *featureUsecase) ensureCodeAvailable(ctx context.Context, code string, id int64) error {
+	existing, err := u.featureRepo.GetByCode(ctx, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.ID != id {
+		return utils.NewError(utils.CodeFeatureAlreadyExists, fmt.Sprintf("feature %s already exists", code))
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureUC_Create(t *testing.T) {
+	mockCarRepo := new(mocks.CarRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockRedisRepo := new(mocks.RedisRepository)
+	createFeatureReq := request.CreateFeatureReq{Code: "sunroof", Name: "Sunroof"}
+
+	t.Run("success", func(t *testing.T) {
+		mockFeatureRepo.On("GetByCode", mock.Anything, "sunroof").Return(entity.Feature{}, sql.ErrNoRows).Once()
+		mockFeatureRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Feature")).Return(nil).Once()
+
+		featureUsecase := usecase.NewFeatureUsecase(mockCarRepo, mockFeatureRepo, mockRedisRepo, ctxTimeout)
+		feature, err := featureUsecase.Create(context.TODO(), &createFeatureReq)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "sunroof", feature.Code)
+		mockFeatureRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-duplicate-code", func(t *testing.T) {
+		mockFeatureRepo.On("GetByCode", mock.Anything, "sunroof").Return(entity.Feature{ID: 1, Code: "sunroof"}, nil).Once()
+
+		featureUsecase := usecase.NewFeatureUsecase(mockCarRepo, mockFeatureRepo, mockRedisRepo, ctxTimeout)
+		_, err := featureUsecase.Create(context.TODO(), &createFeatureReq)
+
+		require.Error(t, err)
+		assert.Equal(t, 409, err.(utils.HttpErr).Status())
+		mockFeatureRepo.AssertExpectations(t)
+	})
+}
+
+func TestFeatureUC_Update(t *testing.T) {
+	mockCarRepo := new(mocks.CarRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockRedisRepo := new(mocks.RedisRepository)
+	updateFeatureReq := request.UpdateFeatureReq{Code: "sunroof", Name: "Panoramic sunroof"}
+
+	t.Run("success-same-code", func(t *testing.T) {
+		mockFeatureRepo.On("GetByID", mock.Anything, int64(1)).Return(entity.Feature{ID: 1, Code: "sunroof"}, nil).Once()
+		mockFeatureRepo.On("GetByCode", mock.Anything, "sunroof").Return(entity.Feature{ID: 1, Code: "sunroof"}, nil).Once()
+		mockFeatureRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Feature")).Return(nil).Once()
+		mockRedisRepo.On("Incr", mock.Anything, "cars:listing:version").Return(int64(2), nil).Once()
+
+		featureUsecase := usecase.NewFeatureUsecase(mockCarRepo, mockFeatureRepo, mockRedisRepo, ctxTimeout)
+		err := featureUsecase.Update(context.TODO(), 1, &updateFeatureReq)
+
+		assert.NoError(t, err)
+		mockRedisRepo.AssertExpectations(t)
+		mockFeatureRepo.AssertExpectations(t)
+	})
+
+	t.Run("feature-not-exist", func(t *testing.T) {
+		mockFeatureRepo.On("GetByID", mock.Anything, int64(1)).Return(entity.Feature{}, sql.ErrNoRows).Once()
+
+		featureUsecase := usecase.NewFeatureUsecase(mockCarRepo, mockFeatureRepo, mockRedisRepo, ctxTimeout)
+		err := featureUsecase.Update(context.TODO(), 1, &updateFeatureReq)
+
+		require.Error(t, err)
+		assert.Equal(t, 404, err.(utils.HttpErr).Status())
+		mockFeatureRepo.AssertExpectations(t)
+	})
+}
+
+func TestFeatureUC_Delete(t *testing.T) {
+	mockCarRepo := new(mocks.CarRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockRedisRepo := new(mocks.RedisRepository)
+
+	t.Run("success", func(t *testing.T) {
+		mockFeatureRepo.On("GetByID", mock.Anything, int64(1)).Return(entity.Feature{ID: 1}, nil).Once()
+		mockFeatureRepo.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+		mockRedisRepo.On("Incr", mock.Anything, "cars:listing:version").Return(int64(2), nil).Once()
+
+		featureUsecase := usecase.NewFeatureUsecase(mockCarRepo, mockFeatureRepo, mockRedisRepo, ctxTimeout)
+		err := featureUsecase.Delete(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		mockRedisRepo.AssertExpectations(t)
+		mockFeatureRepo.AssertExpectations(t)
+	})
+}
+
+func TestFeatureUC_AttachToCar(t *testing.T) {
+	mockCarRepo := new(mocks.CarRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockRedisRepo := new(mocks.RedisRepository)
+	mockFeatures := []entity.Feature{
+		{ID: 1, Code: "heated_seats"},
+		{ID: 2, Code: "sunroof"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, int64(1)).Return(entity.Car{ID: 1}, nil).Once()
+		mockFeatureRepo.On("FetchByCodes", mock.Anything, []string{"sunroof", "heated_seats"}).Return(mockFeatures, nil).Once()
+		mockFeatureRepo.On("AttachToCar", mock.Anything, int64(1), []int64{1, 2}).Return(nil).Once()
+		mockRedisRepo.On("Incr", mock.Anything, "cars:listing:version").Return(int64(2), nil).Once()
+
+		featureUsecase := usecase.NewFeatureUsecase(mockCarRepo, mockFeatureRepo, mockRedisRepo, ctxTimeout)
+		err := featureUsecase.AttachToCar(context.TODO(), 1, &request.AttachCarFeaturesReq{Features: []string{"sunroof", "heated_seats"}})
+
+		assert.NoError(t, err)
+		mockRedisRepo.AssertExpectations(t)
+		mockCarRepo.AssertExpectations(t)
+		mockFeatureRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-unknown-feature", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, int64(1)).Return(entity.Car{ID: 1}, nil).Once()
+		mockFeatureRepo.On("FetchByCodes", mock.Anything, []string{"sunroof", "jetpack"}).Return(mockFeatures[1:], nil).Once()
+
+		featureUsecase := usecase.NewFeatureUsecase(mockCarRepo, mockFeatureRepo, mockRedisRepo, ctxTimeout)
+		err := featureUsecase.AttachToCar(context.TODO(), 1, &request.AttachCarFeaturesReq{Features: []string{"sunroof", "jetpack"}})
+
+		require.Error(t, err)
+		assert.Equal(t, 400, err.(utils.HttpErr).Status())
+		assert.Contains(t, err.(utils.HttpErr).Details(), "jetpack")
+		mockFeatureRepo.AssertExpectations(t)
+	})
+
+	t.Run("car-not-exist", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, int64(1)).Return(entity.Car{}, sql.ErrNoRows).Once()
+
+		featureUsecase := usecase.NewFeatureUsecase(mockCarRepo, mockFeatureRepo, mockRedisRepo, ctxTimeout)
+		err := featureUsecase.AttachToCar(context.TODO(), 1, &request.AttachCarFeaturesReq{Features: []string{"sunroof"}})
+
+		require.Error(t, err)
+		assert.Equal(t, 404, err.(utils.HttpErr).Status())
+		mockCarRepo.AssertExpectations(t)
+	})
+}
+
+func TestFeatureUC_DetachFromCar(t *testing.T) {
+	mockCarRepo := new(mocks.CarRepository)
+	mockFeatureRepo := new(mocks.FeatureRepository)
+	mockRedisRepo := new(mocks.RedisRepository)
+
+	t.Run("success", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, int64(1)).Return(entity.Car{ID: 1}, nil).Once()
+		mockFeatureRepo.On("GetByCode", mock.Anything, "sunroof").Return(entity.Feature{ID: 2, Code: "sunroof"}, nil).Once()
+		mockFeatureRepo.On("DetachFromCar", mock.Anything, int64(1), int64(2)).Return(nil).Once()
+		mockRedisRepo.On("Incr", mock.Anything, "cars:listing:version").Return(int64(2), nil).Once()
+
+		featureUsecase := usecase.NewFeatureUsecase(mockCarRepo, mockFeatureRepo, mockRedisRepo, ctxTimeout)
+		err := featureUsecase.DetachFromCar(context.TODO(), 1, "sunroof")
+
+		assert.NoError(t, err)
+		mockRedisRepo.AssertExpectations(t)
+		mockFeatureRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-db", func(t *testing.T) {
+		mockCarRepo.On("GetByID", mock.Anything, int64(1)).Return(entity.Car{ID: 1}, nil).Once()
+		mockFeatureRepo.On("GetByCode", mock.Anything, "sunroof").Return(entity.Feature{}, errors.New("Unexpected Error")).Once()
+
+		featureUsecase := usecase.NewFeatureUsecase(mockCarRepo, mockFeatureRepo, mockRedisRepo, ctxTimeout)
+		err := featureUsecase.DetachFromCar(context.TODO(), 1, "sunroof")
+
+		assert.Error(t, err)
+		mockFeatureRepo.AssertExpectations(t)
+	})
+}
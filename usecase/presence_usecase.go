@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/stream"
+	"carApi/repository/pgsql"
+	redisRepository "carApi/repository/redis"
+	"carApi/utils"
+)
+
+// PresenceUsecase represent the presence's usecase contract, the sales reps tell the cars they
+// view and every instance is told when the viewers of a car change
+type PresenceUsecase interface {
+	Watch(ctx context.Context) *stream.PresenceWatch
+	View(ctx context.Context, carID int64, rep string) error
+	Refresh(ctx context.Context, carID int64, rep string) error
+	Leave(ctx context.Context, carID int64, rep string) error
+	Viewers(ctx context.Context, carID int64) (entity.CarPresence, error)
+}
+
+type presenceUsecase struct {
+	carRepo      pgsql.CarRepository
+	presenceRepo redisRepository.PresenceRepository
+	presence     *stream.Presence
+	announcer    stream.Announcer
+	ttl          time.Duration
+	ctxTimeout   time.Duration
+}
+
+// NewPresenceUsecase will create new a presenceUsecase object representation of PresenceUsecase interface,
+// a presence lasts ttl unless it is refreshed
+func NewPresenceUsecase(carRepo pgsql.CarRepository, presenceRepo redisRepository.PresenceRepository, presence *stream.Presence, announcer stream.Announcer, ttl time.Duration, ctxTimeout time.Duration) PresenceUsecase {
+	return &presenceUsecase{
+		carRepo:      carRepo,
+		presenceRepo: presenceRepo,
+		presence:     presence,
+		announcer:    announcer,
+		ttl:          ttl,
+		ctxTimeout:   ctxTimeout,
+	}
+}
+
+func (u *presenceUsecase) Watch(ctx context.Context) *stream.PresenceWatch {
+	return u.presence.Watch()
+}
+
+// View marks rep as viewing the car, the other reps are told when rep was not viewing it already
+func (u *presenceUsecase) View(c context.Context, carID int64, rep string) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	_, err = u.carRepo.GetByID(ctx, carID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return utils.NewError(utils.CodeCarNotFound, "car not found")
+	}
+	if err != nil {
+		return
+	}
+
+	return u.join(ctx, carID, rep)
+}
+
+// Refresh keeps the presence of rep on a car it views from lapsing, the car is not looked up again
+func (u *presenceUsecase) Refresh(c context.Context, carID int64, rep string) error {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	return u.join(ctx, carID, rep)
+}
+
+func (u *presenceUsecase) Leave(c context.Context, carID int64, rep string) error {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	left, err := u.presenceRepo.Leave(ctx, carID, rep)
+	if err != nil || !left {
+		return err
+	}
+	return u.announcer.Announce(ctx, carID)
+}
+
+func (u *presenceUsecase) Viewers(c context.Context, carID int64) (presence entity.CarPresence, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	presence.CarID = carID
+	presence.Viewers, err = u.presenceRepo.Viewers(ctx, carID)
+	return
+}
+
+func (u *presenceUsecase) join(ctx context.Context, carID int64, rep string) error {
+	joined, err := u.presenceRepo.Join(ctx, carID, rep, u.ttl)
+	if err != nil || !joined {
+		return err
+	}
+	return u.announcer.Announce(ctx, carID)
+}
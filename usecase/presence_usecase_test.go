@@ -0,0 +1,130 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/stream"
+	"carApi/mocks"
+	"carApi/usecase"
+	"carApi/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresenceUC_View(t *testing.T) {
+	mockCarRepo := new(mocks.CarRepository)
+	mockPresenceRepo := new(mocks.PresenceRepository)
+
+	t.Run("success", func(t *testing.T) {
+		presence := stream.NewPresence(10)
+		watch := presence.Watch()
+		defer watch.Close()
+
+		mockCarRepo.On("GetByID", mock.Anything, int64(1)).Return(entity.Car{ID: 1}, nil).Once()
+		mockPresenceRepo.On("Join", mock.Anything, int64(1), "rep-a", 45*time.Second).Return(true, nil).Once()
+
+		presenceUsecase := usecase.NewPresenceUsecase(mockCarRepo, mockPresenceRepo, presence, presence, 45*time.Second, ctxTimeout)
+		err := presenceUsecase.View(context.TODO(), 1, "rep-a")
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), <-watch.Cars)
+		mockCarRepo.AssertExpectations(t)
+		mockPresenceRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-already-viewing", func(t *testing.T) {
+		presence := stream.NewPresence(10)
+		watch := presence.Watch()
+		defer watch.Close()
+
+		mockCarRepo.On("GetByID", mock.Anything, int64(1)).Return(entity.Car{ID: 1}, nil).Once()
+		mockPresenceRepo.On("Join", mock.Anything, int64(1), "rep-a", 45*time.Second).Return(false, nil).Once()
+
+		presenceUsecase := usecase.NewPresenceUsecase(mockCarRepo, mockPresenceRepo, presence, presence, 45*time.Second, ctxTimeout)
+		err := presenceUsecase.View(context.TODO(), 1, "rep-a")
+
+		require.NoError(t, err)
+		assert.Empty(t, watch.Cars, "nothing changed for the other reps")
+	})
+
+	t.Run("error-not-found", func(t *testing.T) {
+		presence := stream.NewPresence(10)
+		mockCarRepo.On("GetByID", mock.Anything, int64(9)).Return(entity.Car{}, sql.ErrNoRows).Once()
+
+		presenceUsecase := usecase.NewPresenceUsecase(mockCarRepo, mockPresenceRepo, presence, presence, 45*time.Second, ctxTimeout)
+		err := presenceUsecase.View(context.TODO(), 9, "rep-a")
+
+		require.Error(t, err)
+		code, _ := utils.ParseHttpError(err)
+		assert.Equal(t, http.StatusNotFound, code)
+		mockPresenceRepo.AssertNotCalled(t, "Join", mock.Anything, int64(9), "rep-a", mock.Anything)
+	})
+}
+
+func TestPresenceUC_Refresh(t *testing.T) {
+	mockPresenceRepo := new(mocks.PresenceRepository)
+	presence := stream.NewPresence(10)
+	watch := presence.Watch()
+	defer watch.Close()
+
+	mockPresenceRepo.On("Join", mock.Anything, int64(1), "rep-a", 45*time.Second).Return(true, nil).Once()
+
+	presenceUsecase := usecase.NewPresenceUsecase(new(mocks.CarRepository), mockPresenceRepo, presence, presence, 45*time.Second, ctxTimeout)
+	err := presenceUsecase.Refresh(context.TODO(), 1, "rep-a")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), <-watch.Cars, "a lapsed presence is announced again")
+	mockPresenceRepo.AssertExpectations(t)
+}
+
+func TestPresenceUC_Leave(t *testing.T) {
+	mockPresenceRepo := new(mocks.PresenceRepository)
+
+	t.Run("success", func(t *testing.T) {
+		presence := stream.NewPresence(10)
+		watch := presence.Watch()
+		defer watch.Close()
+
+		mockPresenceRepo.On("Leave", mock.Anything, int64(1), "rep-a").Return(true, nil).Once()
+
+		presenceUsecase := usecase.NewPresenceUsecase(new(mocks.CarRepository), mockPresenceRepo, presence, presence, 45*time.Second, ctxTimeout)
+		err := presenceUsecase.Leave(context.TODO(), 1, "rep-a")
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), <-watch.Cars)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		presence := stream.NewPresence(10)
+		watch := presence.Watch()
+		defer watch.Close()
+
+		mockPresenceRepo.On("Leave", mock.Anything, int64(1), "rep-a").Return(false, errors.New("unexpected error")).Once()
+
+		presenceUsecase := usecase.NewPresenceUsecase(new(mocks.CarRepository), mockPresenceRepo, presence, presence, 45*time.Second, ctxTimeout)
+		err := presenceUsecase.Leave(context.TODO(), 1, "rep-a")
+
+		assert.Error(t, err)
+		assert.Empty(t, watch.Cars)
+	})
+}
+
+func TestPresenceUC_Viewers(t *testing.T) {
+	mockPresenceRepo := new(mocks.PresenceRepository)
+	mockPresenceRepo.On("Viewers", mock.Anything, int64(1)).Return([]string{"rep-a", "rep-b"}, nil).Once()
+
+	presence := stream.NewPresence(10)
+	presenceUsecase := usecase.NewPresenceUsecase(new(mocks.CarRepository), mockPresenceRepo, presence, presence, 45*time.Second, ctxTimeout)
+	carPresence, err := presenceUsecase.Viewers(context.TODO(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.CarPresence{CarID: 1, Viewers: []string{"rep-a", "rep-b"}}, carPresence)
+	mockPresenceRepo.AssertExpectations(t)
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"carApi/entity"
+	"carApi/infrastructure/tracing"
+	"carApi/repository/pgsql"
+	"carApi/repository/transaction"
+	"carApi/utils"
+)
+
+// ReservationUsecase represent the reservation's usecase contract, a sales rep holds a car until
+// it releases it or the reservation expires and every change is emitted as an event of the car
+type ReservationUsecase interface {
+	Reserve(ctx context.Context, carID int64, rep string) (entity.Reservation, error)
+	Release(ctx context.Context, carID int64, rep string) error
+	Get(ctx context.Context, carID int64) (entity.CarReservation, error)
+	Change(ctx context.Context, event entity.Event) (entity.CarReservation, bool, error)
+}
+
+type reservationUsecase struct {
+	carRepo         pgsql.CarRepository
+	reservationRepo pgsql.ReservationRepository
+	outboxRepo      pgsql.OutboxRepository
+	txManager       transaction.TxManager
+	ttl             time.Duration
+	ctxTimeout      time.Duration
+}
+
+// NewReservationUsecase will create new a reservationUsecase object representation of ReservationUsecase interface,
+// a reservation holds the car for ttl unless its rep reserves it again
+func NewReservationUsecase(carRepo pgsql.CarRepository, reservationRepo pgsql.ReservationRepository, outboxRepo pgsql.OutboxRepository, txManager transaction.TxManager, ttl time.Duration, ctxTimeout time.Duration) ReservationUsecase {
+	return &reservationUsecase{
+		carRepo:         carRepo,
+		reservationRepo: reservationRepo,
+		outboxRepo:      outboxRepo,
+		txManager:       txManager,
+		ttl:             ttl,
+		ctxTimeout:      ctxTimeout,
+	}
+}
+
+// Reserve holds the car for rep, a rep reserving the car it holds renews the reservation. The car
+// row is locked by the read so two reps cannot take it at once.
+func (u *reservationUsecase) Reserve(c context.Context, carID int64, rep string) (reservation entity.Reservation, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "reservationUsecase.Reserve")
+	defer func() { tracing.End(span, err) }()
+
+	err = u.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := u.carRepo.GetByID(ctx, carID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return utils.NewError(utils.CodeCarNotFound, "car not found")
+			}
+			return err
+		}
+
+		now := time.Now()
+		reservation = entity.Reservation{CarID: carID, Rep: rep, ReservedAt: now, ExpiresAt: now.Add(u.ttl)}
+		current, held, err := u.held(ctx, carID, now)
+		if err != nil {
+			return err
+		}
+		if held && current.Rep != rep {
+			return reservedError(current)
+		}
+		if held {
+			reservation.ReservedAt = current.ReservedAt
+		}
+
+		if err := u.reservationRepo.Save(ctx, &reservation); err != nil {
+			return err
+		}
+		return emit(ctx, u.outboxRepo, carID, entity.CarReserved{Reservation: reservation})
+	})
+	return
+}
+
+// Release frees the car rep holds, the reservation of another rep cannot be released
+func (u *reservationUsecase) Release(c context.Context, carID int64, rep string) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "reservationUsecase.Release")
+	defer func() { tracing.End(span, err) }()
+
+	return u.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, held, err := u.held(ctx, carID, time.Now())
+		if err != nil {
+			return err
+		}
+		if !held {
+			return utils.NewError(utils.CodeReservationNotFound, "car is not reserved")
+		}
+		if current.Rep != rep {
+			return reservedError(current)
+		}
+
+		if err := u.reservationRepo.Delete(ctx, carID); err != nil {
+			return err
+		}
+		return emit(ctx, u.outboxRepo, carID, entity.CarReleased{CarID: carID, Rep: rep})
+	})
+}
+
+// Get gives the reservation holding the car, none once it expired
+func (u *reservationUsecase) Get(c context.Context, carID int64) (entity.CarReservation, error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	state := entity.CarReservation{CarID: carID}
+	current, held, err := u.held(ctx, carID, time.Now())
+	if err != nil {
+		return state, err
+	}
+	if held {
+		state.Reservation = &current
+	}
+	return state, nil
+}
+
+// Change gives the reservation state the event leaves the car in, false for the events that do
+// not change it
+func (u *reservationUsecase) Change(ctx context.Context, event entity.Event) (state entity.CarReservation, ok bool, err error) {
+	state = entity.CarReservation{CarID: event.CarID}
+	switch event.Type {
+	case entity.EventCarReserved:
+		var payload entity.CarReserved
+		if err = json.Unmarshal(event.Payload, &payload); err != nil {
+			return
+		}
+		state.Reservation = &payload.Reservation
+		return state, true, nil
+	case entity.EventCarReleased:
+		return state, true, nil
+	}
+	return state, false, nil
+}
+
+// held gives the reservation of the car and whether it still holds the car at now
+func (u *reservationUsecase) held(ctx context.Context, carID int64, now time.Time) (entity.Reservation, bool, error) {
+	reservation, err := u.reservationRepo.GetByCarID(ctx, carID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return reservation, false, nil
+	}
+	if err != nil {
+		return reservation, false, err
+	}
+	return reservation, reservation.Held(now), nil
+}
+
+func reservedError(reservation entity.Reservation) error {
+	return utils.NewError(utils.CodeCarReserved, fmt.Sprintf("car is reserved by %s until %s", reservation.Rep, reservation.ExpiresAt.UTC().Format(time.RFC3339)))
+}
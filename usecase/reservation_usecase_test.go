@@ -0,0 +1,194 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/usecase"
+	"carApi/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservationUC_Reserve(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCarRepo := new(mocks.CarRepository)
+		mockReservationRepo := new(mocks.ReservationRepository)
+		mockOutboxRepo := new(mocks.OutboxRepository)
+		mockTxManager := new(mocks.TxManager)
+
+		expectTransaction(mockTxManager)
+		mockCarRepo.On("GetByID", withinTransaction, int64(1)).Return(entity.Car{ID: 1}, nil).Once()
+		// an expired reservation of another rep no longer holds the car
+		mockReservationRepo.On("GetByCarID", withinTransaction, int64(1)).Return(entity.Reservation{CarID: 1, Rep: "rep-b", ExpiresAt: time.Now().Add(-time.Minute)}, nil).Once()
+		mockReservationRepo.On("Save", withinTransaction, mock.MatchedBy(func(reservation *entity.Reservation) bool {
+			return reservation.Rep == "rep-a" && reservation.ExpiresAt.Sub(reservation.ReservedAt) == time.Hour
+		})).Return(nil).Once()
+		mockOutboxRepo.On("Add", withinTransaction, eventOf(entity.EventCarReserved, 1)).Return(nil).Once()
+
+		reservationUsecase := usecase.NewReservationUsecase(mockCarRepo, mockReservationRepo, mockOutboxRepo, mockTxManager, time.Hour, ctxTimeout)
+		reservation, err := reservationUsecase.Reserve(context.TODO(), 1, "rep-a")
+
+		require.NoError(t, err)
+		assert.Equal(t, "rep-a", reservation.Rep)
+		mockCarRepo.AssertExpectations(t)
+		mockReservationRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-renew", func(t *testing.T) {
+		mockCarRepo := new(mocks.CarRepository)
+		mockReservationRepo := new(mocks.ReservationRepository)
+		mockOutboxRepo := new(mocks.OutboxRepository)
+		mockTxManager := new(mocks.TxManager)
+		reservedAt := time.Now().Add(-30 * time.Minute)
+
+		expectTransaction(mockTxManager)
+		mockCarRepo.On("GetByID", withinTransaction, int64(1)).Return(entity.Car{ID: 1}, nil).Once()
+		mockReservationRepo.On("GetByCarID", withinTransaction, int64(1)).Return(entity.Reservation{CarID: 1, Rep: "rep-a", ReservedAt: reservedAt, ExpiresAt: reservedAt.Add(time.Hour)}, nil).Once()
+		mockReservationRepo.On("Save", withinTransaction, mock.AnythingOfType("*entity.Reservation")).Return(nil).Once()
+		mockOutboxRepo.On("Add", withinTransaction, eventOf(entity.EventCarReserved, 1)).Return(nil).Once()
+
+		reservationUsecase := usecase.NewReservationUsecase(mockCarRepo, mockReservationRepo, mockOutboxRepo, mockTxManager, time.Hour, ctxTimeout)
+		reservation, err := reservationUsecase.Reserve(context.TODO(), 1, "rep-a")
+
+		require.NoError(t, err)
+		assert.Equal(t, reservedAt, reservation.ReservedAt, "the reservation is renewed, not taken again")
+		assert.True(t, reservation.ExpiresAt.After(reservedAt.Add(time.Hour)))
+	})
+
+	t.Run("error-reserved", func(t *testing.T) {
+		mockCarRepo := new(mocks.CarRepository)
+		mockReservationRepo := new(mocks.ReservationRepository)
+		mockOutboxRepo := new(mocks.OutboxRepository)
+		mockTxManager := new(mocks.TxManager)
+
+		expectTransaction(mockTxManager)
+		mockCarRepo.On("GetByID", withinTransaction, int64(1)).Return(entity.Car{ID: 1}, nil).Once()
+		mockReservationRepo.On("GetByCarID", withinTransaction, int64(1)).Return(entity.Reservation{CarID: 1, Rep: "rep-b", ExpiresAt: time.Now().Add(time.Minute)}, nil).Once()
+
+		reservationUsecase := usecase.NewReservationUsecase(mockCarRepo, mockReservationRepo, mockOutboxRepo, mockTxManager, time.Hour, ctxTimeout)
+		_, err := reservationUsecase.Reserve(context.TODO(), 1, "rep-a")
+
+		require.Error(t, err)
+		assert.Equal(t, utils.CodeCarReserved, err.(utils.HttpErr).Code())
+		assert.Equal(t, http.StatusConflict, err.(utils.HttpErr).Status())
+		mockReservationRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-not-found", func(t *testing.T) {
+		mockCarRepo := new(mocks.CarRepository)
+		mockReservationRepo := new(mocks.ReservationRepository)
+		mockOutboxRepo := new(mocks.OutboxRepository)
+		mockTxManager := new(mocks.TxManager)
+
+		expectTransaction(mockTxManager)
+		mockCarRepo.On("GetByID", withinTransaction, int64(9)).Return(entity.Car{}, sql.ErrNoRows).Once()
+
+		reservationUsecase := usecase.NewReservationUsecase(mockCarRepo, mockReservationRepo, mockOutboxRepo, mockTxManager, time.Hour, ctxTimeout)
+		_, err := reservationUsecase.Reserve(context.TODO(), 9, "rep-a")
+
+		require.Error(t, err)
+		assert.Equal(t, utils.CodeCarNotFound, err.(utils.HttpErr).Code())
+	})
+}
+
+func TestReservationUC_Release(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockReservationRepo := new(mocks.ReservationRepository)
+		mockOutboxRepo := new(mocks.OutboxRepository)
+		mockTxManager := new(mocks.TxManager)
+
+		expectTransaction(mockTxManager)
+		mockReservationRepo.On("GetByCarID", withinTransaction, int64(1)).Return(entity.Reservation{CarID: 1, Rep: "rep-a", ExpiresAt: time.Now().Add(time.Minute)}, nil).Once()
+		mockReservationRepo.On("Delete", withinTransaction, int64(1)).Return(nil).Once()
+		mockOutboxRepo.On("Add", withinTransaction, eventOf(entity.EventCarReleased, 1)).Return(nil).Once()
+
+		reservationUsecase := usecase.NewReservationUsecase(new(mocks.CarRepository), mockReservationRepo, mockOutboxRepo, mockTxManager, time.Hour, ctxTimeout)
+		err := reservationUsecase.Release(context.TODO(), 1, "rep-a")
+
+		require.NoError(t, err)
+		mockReservationRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-other-rep", func(t *testing.T) {
+		mockReservationRepo := new(mocks.ReservationRepository)
+		mockTxManager := new(mocks.TxManager)
+
+		expectTransaction(mockTxManager)
+		mockReservationRepo.On("GetByCarID", withinTransaction, int64(1)).Return(entity.Reservation{CarID: 1, Rep: "rep-b", ExpiresAt: time.Now().Add(time.Minute)}, nil).Once()
+
+		reservationUsecase := usecase.NewReservationUsecase(new(mocks.CarRepository), mockReservationRepo, new(mocks.OutboxRepository), mockTxManager, time.Hour, ctxTimeout)
+		err := reservationUsecase.Release(context.TODO(), 1, "rep-a")
+
+		require.Error(t, err)
+		assert.Equal(t, utils.CodeCarReserved, err.(utils.HttpErr).Code())
+		mockReservationRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-not-reserved", func(t *testing.T) {
+		mockReservationRepo := new(mocks.ReservationRepository)
+		mockTxManager := new(mocks.TxManager)
+
+		expectTransaction(mockTxManager)
+		mockReservationRepo.On("GetByCarID", withinTransaction, int64(1)).Return(entity.Reservation{}, sql.ErrNoRows).Once()
+
+		reservationUsecase := usecase.NewReservationUsecase(new(mocks.CarRepository), mockReservationRepo, new(mocks.OutboxRepository), mockTxManager, time.Hour, ctxTimeout)
+		err := reservationUsecase.Release(context.TODO(), 1, "rep-a")
+
+		require.Error(t, err)
+		assert.Equal(t, utils.CodeReservationNotFound, err.(utils.HttpErr).Code())
+	})
+}
+
+func TestReservationUC_Get(t *testing.T) {
+	mockReservationRepo := new(mocks.ReservationRepository)
+	held := entity.Reservation{CarID: 1, Rep: "rep-a", ExpiresAt: time.Now().Add(time.Minute)}
+	mockReservationRepo.On("GetByCarID", mock.Anything, int64(1)).Return(held, nil).Once()
+	mockReservationRepo.On("GetByCarID", mock.Anything, int64(2)).Return(entity.Reservation{CarID: 2, Rep: "rep-a", ExpiresAt: time.Now().Add(-time.Minute)}, nil).Once()
+	mockReservationRepo.On("GetByCarID", mock.Anything, int64(3)).Return(entity.Reservation{}, sql.ErrNoRows).Once()
+
+	reservationUsecase := usecase.NewReservationUsecase(new(mocks.CarRepository), mockReservationRepo, new(mocks.OutboxRepository), new(mocks.TxManager), time.Hour, ctxTimeout)
+
+	state, err := reservationUsecase.Get(context.TODO(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CarReservation{CarID: 1, Reservation: &held}, state)
+
+	state, err = reservationUsecase.Get(context.TODO(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CarReservation{CarID: 2}, state, "an expired reservation leaves the car available")
+
+	state, err = reservationUsecase.Get(context.TODO(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CarReservation{CarID: 3}, state)
+}
+
+func TestReservationUC_Change(t *testing.T) {
+	reservationUsecase := usecase.NewReservationUsecase(new(mocks.CarRepository), new(mocks.ReservationRepository), new(mocks.OutboxRepository), new(mocks.TxManager), time.Hour, ctxTimeout)
+	reservation := entity.Reservation{CarID: 1, Rep: "rep-a", ReservedAt: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), ExpiresAt: time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)}
+	payload, err := json.Marshal(entity.CarReserved{Reservation: reservation})
+	require.NoError(t, err)
+
+	state, ok, err := reservationUsecase.Change(context.TODO(), entity.Event{ID: 5, Type: entity.EventCarReserved, CarID: 1, Payload: payload})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, entity.CarReservation{CarID: 1, Reservation: &reservation}, state)
+
+	state, ok, err = reservationUsecase.Change(context.TODO(), entity.Event{ID: 6, Type: entity.EventCarReleased, CarID: 1, Payload: []byte(`{"car_id":1,"rep":"rep-a"}`)})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, entity.CarReservation{CarID: 1}, state)
+
+	_, ok, err = reservationUsecase.Change(context.TODO(), entity.Event{ID: 7, Type: entity.EventCarUpdated, CarID: 1})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"net/url"
+	"time"
+
+	"carApi/entity"
+	"carApi/repository/pgsql"
+	"carApi/transport/request"
+	"carApi/utils"
+	"carApi/utils/netguard"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// defaultDeliveryLimit is the number of deliveries given when the request sets no limit
+const defaultDeliveryLimit = 50
+
+// webhookResolveTimeout bounds the lookup of the host of a webhook URL
+const webhookResolveTimeout = 5 * time.Second
+
+// WebhookUsecase represent the webhook subscription's usecase contract
+type WebhookUsecase interface {
+	Create(ctx context.Context, request *request.CreateWebhookReq) (entity.WebhookSubscription, error)
+	GetByID(ctx context.Context, id int64) (entity.WebhookSubscription, error)
+	Fetch(ctx context.Context) ([]entity.WebhookSubscription, error)
+	Update(ctx context.Context, id int64, request *request.UpdateWebhookReq) error
+	Delete(ctx context.Context, id int64) error
+	FetchDeliveries(ctx context.Context, id int64, request *request.FetchWebhookDeliveriesReq) ([]entity.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id int64, deliveryID int64) (entity.WebhookDelivery, error)
+}
+
+type webhookUsecase struct {
+	webhookRepo  pgsql.WebhookRepository
+	deliveryRepo pgsql.WebhookDeliveryRepository
+	resolver     netguard.Resolver
+	ctxTimeout   time.Duration
+}
+
+// NewWebhookUsecase will create new a webhookUsecase object representation of WebhookUsecase interface,
+// the resolver looks up the hosts of the subscribed URLs
+func NewWebhookUsecase(webhookRepo pgsql.WebhookRepository, deliveryRepo pgsql.WebhookDeliveryRepository, resolver netguard.Resolver, ctxTimeout time.Duration) WebhookUsecase {
+	return &webhookUsecase{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		resolver:     resolver,
+		ctxTimeout:   ctxTimeout,
+	}
+}
+
+func (u *webhookUsecase) Create(c context.Context, request *request.CreateWebhookReq) (subscription entity.WebhookSubscription, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	if err = u.checkURL(ctx, request.URL); err != nil {
+		return
+	}
+
+	subscription = entity.WebhookSubscription{
+		URL:        request.URL,
+		EventTypes: request.EventTypes,
+		Secret:     request.Secret,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	err = u.webhookRepo.Create(ctx, &subscription)
+	return
+}
+
+func (u *webhookUsecase) GetByID(c context.Context, id int64) (subscription entity.WebhookSubscription, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	subscription, err = u.webhookRepo.GetByID(ctx, id)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		err = utils.NewError(utils.CodeWebhookNotFound, "webhook not found")
+		return
+	}
+	return
+}
+
+func (u *webhookUsecase) Fetch(c context.Context) (subscriptions []entity.WebhookSubscription, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	subscriptions, err = u.webhookRepo.Fetch(ctx)
+	return
+}
+
+func (u *webhookUsecase) Update(c context.Context, id int64, request *request.UpdateWebhookReq) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	subscription, err := u.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = utils.NewError(utils.CodeWebhookNotFound, "webhook not found")
+		}
+		return
+	}
+
+	if err = u.checkURL(ctx, request.URL); err != nil {
+		return
+	}
+
+	subscription.URL = request.URL
+	subscription.EventTypes = request.EventTypes
+	if request.Secret != "" {
+		subscription.Secret = request.Secret
+	}
+	subscription.UpdatedAt = time.Now()
+
+	err = u.webhookRepo.Update(ctx, &subscription)
+	return
+}
+
+func (u *webhookUsecase) Delete(c context.Context, id int64) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	_, err = u.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = utils.NewError(utils.CodeWebhookNotFound, "webhook not found")
+		}
+		return
+	}
+
+	err = u.webhookRepo.Delete(ctx, id)
+	return
+}
+
+func (u *webhookUsecase) FetchDeliveries(c context.Context, id int64, request *request.FetchWebhookDeliveriesReq) (deliveries []entity.WebhookDelivery, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	if _, err = u.webhookRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = utils.NewError(utils.CodeWebhookNotFound, "webhook not found")
+		}
+		return
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultDeliveryLimit
+	}
+
+	deliveries, err = u.deliveryRepo.FetchBySubscriptionID(ctx, id, entity.WebhookDeliveryStatus(request.Status), limit)
+	return
+}
+
+// Redeliver sends a delivery again from a fresh count of attempts, the dispatcher picks it up
+// on its next poll. A delivery still pending is already going to be attempted.
+func (u *webhookUsecase) Redeliver(c context.Context, id int64, deliveryID int64) (delivery entity.WebhookDelivery, err error) {
+	ctx, cancel := context.WithTimeout(c, u.ctxTimeout)
+	defer cancel()
+
+	delivery, err = u.deliveryRepo.GetByID(ctx, deliveryID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && delivery.SubscriptionID != id) {
+		err = utils.NewError(utils.CodeWebhookDeliveryNotFound, "webhook delivery not found")
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	if delivery.Status == entity.WebhookDeliveryPending {
+		err = utils.NewError(utils.CodeWebhookDeliveryPending, "webhook delivery is already pending")
+		return
+	}
+
+	now := time.Now()
+	delivery.Status = entity.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	delivery.UpdatedAt = now
+
+	err = u.deliveryRepo.Update(ctx, &delivery)
+	return
+}
+
+// checkURL refuses a URL whose host resolves to an address that is not public, the API must not
+// be made to post to its own network. A host that does not exist is a validation error of the url,
+// a lookup failing otherwise is told apart. The dispatcher checks the address again when it
+// connects as the host may be rebound meanwhile.
+func (u *webhookUsecase) checkURL(c context.Context, raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return utils.NewInvalidInputError(validation.Errors{"url": errors.New("must be an absolute http or https URL")})
+	}
+
+	ctx, cancel := context.WithTimeout(c, webhookResolveTimeout)
+	defer cancel()
+
+	err = netguard.CheckHost(ctx, u.resolver, parsed.Hostname())
+	var dnsErr *net.DNSError
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, netguard.ErrNotPublic):
+		return utils.NewInvalidInputError(validation.Errors{"url": errors.New("must resolve to public addresses only")})
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return utils.NewInvalidInputError(validation.Errors{"url": errors.New("must have a host that resolves")})
+	default:
+		return utils.NewError(utils.CodeWebhookHostLookupFailed, "the host of the url could not be looked up, try again later")
+	}
+}
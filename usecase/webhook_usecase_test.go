@@ -0,0 +1,205 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"net/netip"
+	"testing"
+
+	"carApi/entity"
+	"carApi/mocks"
+	"carApi/transport/request"
+	"carApi/usecase"
+	"carApi/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookUC_Create(t *testing.T) {
+	mockWebhookRepo := new(mocks.WebhookRepository)
+	mockDeliveryRepo := new(mocks.WebhookDeliveryRepository)
+	createWebhookReq := request.CreateWebhookReq{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []entity.EventType{entity.EventCarCreated},
+		Secret:     "0123456789abcdef",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockResolver := new(mocks.Resolver)
+		mockResolver.On("LookupNetIP", mock.Anything, "ip", "partner.example.com").Return([]netip.Addr{netip.MustParseAddr("203.0.113.10")}, nil).Once()
+		mockWebhookRepo.On("Create", mock.Anything, mock.MatchedBy(func(subscription *entity.WebhookSubscription) bool {
+			return subscription.Secret == "0123456789abcdef" && subscription.Subscribes(entity.EventCarCreated)
+		})).Return(nil).Once()
+
+		webhookUsecase := usecase.NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, mockResolver, ctxTimeout)
+		subscription, err := webhookUsecase.Create(context.TODO(), &createWebhookReq)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://partner.example.com/hooks", subscription.URL)
+		mockResolver.AssertExpectations(t)
+		mockWebhookRepo.AssertExpectations(t)
+	})
+
+	tests := map[string]struct {
+		addrs []netip.Addr
+		err   error
+		code  utils.ErrorCode
+	}{
+		"error-private-address": {addrs: []netip.Addr{netip.MustParseAddr("203.0.113.10"), netip.MustParseAddr("10.0.0.5")}, code: utils.CodeValidationFailed},
+		"error-unknown-host":    {err: &net.DNSError{Err: "no such host", Name: "partner.example.com", IsNotFound: true}, code: utils.CodeValidationFailed},
+		"error-lookup":          {err: &net.DNSError{Err: "i/o timeout", Name: "partner.example.com", IsTimeout: true}, code: utils.CodeWebhookHostLookupFailed},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockResolver := new(mocks.Resolver)
+			mockResolver.On("LookupNetIP", mock.Anything, "ip", "partner.example.com").Return(tt.addrs, tt.err).Once()
+
+			webhookUsecase := usecase.NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, mockResolver, ctxTimeout)
+			_, err := webhookUsecase.Create(context.TODO(), &createWebhookReq)
+
+			require.Error(t, err)
+			assert.Equal(t, tt.code, err.(utils.HttpErr).Code())
+			mockWebhookRepo.AssertNumberOfCalls(t, "Create", 1)
+		})
+	}
+}
+
+func TestWebhookUC_Update(t *testing.T) {
+	mockWebhookRepo := new(mocks.WebhookRepository)
+	mockDeliveryRepo := new(mocks.WebhookDeliveryRepository)
+	existing := entity.WebhookSubscription{ID: 1, URL: "https://partner.example.com/hooks", Secret: "0123456789abcdef"}
+	mockResolver := new(mocks.Resolver)
+	mockResolver.On("LookupNetIP", mock.Anything, "ip", "partner.example.com").Return([]netip.Addr{netip.MustParseAddr("203.0.113.10")}, nil)
+
+	t.Run("success-keep-secret", func(t *testing.T) {
+		mockWebhookRepo.On("GetByID", mock.Anything, int64(1)).Return(existing, nil).Once()
+		mockWebhookRepo.On("Update", mock.Anything, mock.MatchedBy(func(subscription *entity.WebhookSubscription) bool {
+			return subscription.URL == "https://partner.example.com/v2" && subscription.Secret == "0123456789abcdef"
+		})).Return(nil).Once()
+
+		webhookUsecase := usecase.NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, mockResolver, ctxTimeout)
+		err := webhookUsecase.Update(context.TODO(), 1, &request.UpdateWebhookReq{
+			URL:        "https://partner.example.com/v2",
+			EventTypes: []entity.EventType{entity.EventCarDeleted},
+		})
+
+		assert.NoError(t, err)
+		mockWebhookRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-rotate-secret", func(t *testing.T) {
+		mockWebhookRepo.On("GetByID", mock.Anything, int64(1)).Return(existing, nil).Once()
+		mockWebhookRepo.On("Update", mock.Anything, mock.MatchedBy(func(subscription *entity.WebhookSubscription) bool {
+			return subscription.Secret == "fedcba9876543210"
+		})).Return(nil).Once()
+
+		webhookUsecase := usecase.NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, mockResolver, ctxTimeout)
+		err := webhookUsecase.Update(context.TODO(), 1, &request.UpdateWebhookReq{
+			URL:        "https://partner.example.com/hooks",
+			EventTypes: []entity.EventType{entity.EventCarDeleted},
+			Secret:     "fedcba9876543210",
+		})
+
+		assert.NoError(t, err)
+		mockWebhookRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-private-address", func(t *testing.T) {
+		mockWebhookRepo.On("GetByID", mock.Anything, int64(1)).Return(existing, nil).Once()
+		mockResolver.On("LookupNetIP", mock.Anything, "ip", "169.254.169.254").Return([]netip.Addr{netip.MustParseAddr("169.254.169.254")}, nil).Once()
+
+		webhookUsecase := usecase.NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, mockResolver, ctxTimeout)
+		err := webhookUsecase.Update(context.TODO(), 1, &request.UpdateWebhookReq{
+			URL:        "http://169.254.169.254/latest/meta-data",
+			EventTypes: []entity.EventType{entity.EventCarDeleted},
+		})
+
+		require.Error(t, err)
+		assert.Equal(t, utils.CodeValidationFailed, err.(utils.HttpErr).Code())
+		mockWebhookRepo.AssertNumberOfCalls(t, "Update", 2)
+	})
+
+	t.Run("error-not-found", func(t *testing.T) {
+		mockWebhookRepo.On("GetByID", mock.Anything, int64(2)).Return(entity.WebhookSubscription{}, sql.ErrNoRows).Once()
+
+		webhookUsecase := usecase.NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, mockResolver, ctxTimeout)
+		err := webhookUsecase.Update(context.TODO(), 2, &request.UpdateWebhookReq{})
+
+		require.Error(t, err)
+		assert.Equal(t, 404, err.(utils.HttpErr).Status())
+		mockWebhookRepo.AssertExpectations(t)
+	})
+}
+
+func TestWebhookUC_FetchDeliveries(t *testing.T) {
+	mockWebhookRepo := new(mocks.WebhookRepository)
+	mockDeliveryRepo := new(mocks.WebhookDeliveryRepository)
+	mockResolver := new(mocks.Resolver)
+
+	t.Run("success-default-limit", func(t *testing.T) {
+		mockWebhookRepo.On("GetByID", mock.Anything, int64(1)).Return(entity.WebhookSubscription{ID: 1}, nil).Once()
+		mockDeliveryRepo.On("FetchBySubscriptionID", mock.Anything, int64(1), entity.WebhookDeliveryDead, 50).
+			Return([]entity.WebhookDelivery{{ID: 3, Status: entity.WebhookDeliveryDead}}, nil).Once()
+
+		webhookUsecase := usecase.NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, mockResolver, ctxTimeout)
+		deliveries, err := webhookUsecase.FetchDeliveries(context.TODO(), 1, &request.FetchWebhookDeliveriesReq{Status: "dead"})
+
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+		mockDeliveryRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-not-found", func(t *testing.T) {
+		mockWebhookRepo.On("GetByID", mock.Anything, int64(2)).Return(entity.WebhookSubscription{}, sql.ErrNoRows).Once()
+
+		webhookUsecase := usecase.NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, mockResolver, ctxTimeout)
+		_, err := webhookUsecase.FetchDeliveries(context.TODO(), 2, &request.FetchWebhookDeliveriesReq{})
+
+		require.Error(t, err)
+		assert.Equal(t, 404, err.(utils.HttpErr).Status())
+	})
+}
+
+func TestWebhookUC_Redeliver(t *testing.T) {
+	mockWebhookRepo := new(mocks.WebhookRepository)
+	mockDeliveryRepo := new(mocks.WebhookDeliveryRepository)
+	mockResolver := new(mocks.Resolver)
+	dead := entity.WebhookDelivery{ID: 3, SubscriptionID: 1, Status: entity.WebhookDeliveryDead, Attempts: 8, LastStatusCode: 500}
+
+	t.Run("success", func(t *testing.T) {
+		mockDeliveryRepo.On("GetByID", mock.Anything, int64(3)).Return(dead, nil).Once()
+		mockDeliveryRepo.On("Update", mock.Anything, mock.MatchedBy(func(delivery *entity.WebhookDelivery) bool {
+			return delivery.Status == entity.WebhookDeliveryPending && delivery.Attempts == 0 && !delivery.NextAttemptAt.IsZero()
+		})).Return(nil).Once()
+
+		webhookUsecase := usecase.NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, mockResolver, ctxTimeout)
+		delivery, err := webhookUsecase.Redeliver(context.TODO(), 1, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.WebhookDeliveryPending, delivery.Status)
+		mockDeliveryRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-other-subscription", func(t *testing.T) {
+		mockDeliveryRepo.On("GetByID", mock.Anything, int64(3)).Return(dead, nil).Once()
+
+		webhookUsecase := usecase.NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, mockResolver, ctxTimeout)
+		_, err := webhookUsecase.Redeliver(context.TODO(), 2, 3)
+
+		require.Error(t, err)
+		assert.Equal(t, 404, err.(utils.HttpErr).Status())
+		mockDeliveryRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-pending", func(t *testing.T) {
+		mockDeliveryRepo.On("GetByID", mock.Anything, int64(4)).Return(entity.WebhookDelivery{ID: 4, SubscriptionID: 1, Status: entity.WebhookDeliveryPending}, nil).Once()
+
+		webhookUsecase := usecase.NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, mockResolver, ctxTimeout)
+		_, err := webhookUsecase.Redeliver(context.TODO(), 1, 4)
+
+		require.Error(t, err)
+		assert.Equal(t, 409, err.(utils.HttpErr).Status())
+	})
+}
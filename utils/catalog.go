@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ErrorCode is the stable identifier of a kind of error, clients match on it rather than on the
+// details which are meant for people and may change
+type ErrorCode string
+
+// The generic codes go with the constructors of their status, the others name the error precisely
+const (
+	CodeBadRequest           ErrorCode = "BAD_REQUEST"
+	CodeValidationFailed     ErrorCode = "VALIDATION_FAILED"
+	CodeAuthenticationFailed ErrorCode = "AUTHENTICATION_FAILED"
+	CodeUnauthorized         ErrorCode = "UNAUTHORIZED"
+	CodeForbidden            ErrorCode = "FORBIDDEN"
+	CodeNotFound             ErrorCode = "NOT_FOUND"
+	CodeMethodNotAllowed     ErrorCode = "METHOD_NOT_ALLOWED"
+	CodeNotAcceptable        ErrorCode = "NOT_ACCEPTABLE"
+	CodeConflict             ErrorCode = "CONFLICT"
+	CodePayloadTooLarge      ErrorCode = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMediaType ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	CodeUnprocessableEntity  ErrorCode = "UNPROCESSABLE_ENTITY"
+	CodeInternal             ErrorCode = "INTERNAL_ERROR"
+
+	CodeCarNotFound               ErrorCode = "CAR_NOT_FOUND"
+	CodeCarImageNotFound          ErrorCode = "CAR_IMAGE_NOT_FOUND"
+	CodeImageRequired             ErrorCode = "IMAGE_REQUIRED"
+	CodeImageUnreadable           ErrorCode = "IMAGE_UNREADABLE"
+	CodeImageTooLarge             ErrorCode = "IMAGE_TOO_LARGE"
+	CodeImageTypeUnsupported      ErrorCode = "IMAGE_TYPE_UNSUPPORTED"
+	CodeFeatureNotFound           ErrorCode = "FEATURE_NOT_FOUND"
+	CodeFeatureAlreadyExists      ErrorCode = "FEATURE_ALREADY_EXISTS"
+	CodeUnknownFeatures           ErrorCode = "UNKNOWN_FEATURES"
+	CodeCatalogEntryNotFound      ErrorCode = "CATALOG_ENTRY_NOT_FOUND"
+	CodeCatalogEntryAlreadyExists ErrorCode = "CATALOG_ENTRY_ALREADY_EXISTS"
+	CodeExchangeRateUnavailable   ErrorCode = "EXCHANGE_RATE_UNAVAILABLE"
+	CodeWebhookNotFound           ErrorCode = "WEBHOOK_NOT_FOUND"
+	CodeWebhookDeliveryNotFound   ErrorCode = "WEBHOOK_DELIVERY_NOT_FOUND"
+	CodeWebhookDeliveryPending    ErrorCode = "WEBHOOK_DELIVERY_PENDING"
+	CodeWebhookHostLookupFailed   ErrorCode = "WEBHOOK_HOST_LOOKUP_FAILED"
+	CodeSubscriptionLimitExceeded ErrorCode = "SUBSCRIPTION_LIMIT_EXCEEDED"
+	CodeCarReserved               ErrorCode = "CAR_RESERVED"
+	CodeReservationNotFound       ErrorCode = "RESERVATION_NOT_FOUND"
+	CodeQueryTooDeep              ErrorCode = "QUERY_TOO_DEEP"
+	CodeQueryTooComplex           ErrorCode = "QUERY_TOO_COMPLEX"
+)
+
+// problemType is what the catalog knows of a code, err is the error of the legacy body
+type problemType struct {
+	status int
+	err    error
+	title  string
+}
+
+var catalog = map[ErrorCode]problemType{
+	CodeBadRequest:           {http.StatusBadRequest, ErrBadRequest, "Bad request"},
+	CodeValidationFailed:     {http.StatusBadRequest, ErrBadRequest, "Validation failed"},
+	CodeAuthenticationFailed: {http.StatusUnauthorized, ErrAuthenticationFailed, "Authentication failed"},
+	CodeUnauthorized:         {http.StatusUnauthorized, ErrUnauthorized, "Unauthorized"},
+	CodeForbidden:            {http.StatusForbidden, ErrForbidden, "Forbidden"},
+	CodeNotFound:             {http.StatusNotFound, ErrNotFound, "Not found"},
+	CodeMethodNotAllowed:     {http.StatusMethodNotAllowed, ErrMethodNotAllowed, "Method not allowed"},
+	CodeNotAcceptable:        {http.StatusNotAcceptable, ErrNotAcceptable, "Not acceptable"},
+	CodeConflict:             {http.StatusConflict, ErrConflict, "Conflict"},
+	CodePayloadTooLarge:      {http.StatusRequestEntityTooLarge, ErrRequestEntityTooLarge, "Request entity too large"},
+	CodeUnsupportedMediaType: {http.StatusUnsupportedMediaType, ErrUnsupportedMediaType, "Unsupported media type"},
+	CodeUnprocessableEntity:  {http.StatusUnprocessableEntity, ErrUnprocessableEntity, "Unprocessable entity"},
+	CodeInternal:             {http.StatusInternalServerError, ErrInternalServerError, "Internal server error"},
+
+	CodeCarNotFound:               {http.StatusNotFound, ErrNotFound, "Car not found"},
+	CodeCarImageNotFound:          {http.StatusNotFound, ErrNotFound, "Car image not found"},
+	CodeImageRequired:             {http.StatusBadRequest, ErrBadRequest, "Image required"},
+	CodeImageUnreadable:           {http.StatusBadRequest, ErrBadRequest, "Image unreadable"},
+	CodeImageTooLarge:             {http.StatusRequestEntityTooLarge, ErrRequestEntityTooLarge, "Image too large"},
+	CodeImageTypeUnsupported:      {http.StatusUnsupportedMediaType, ErrUnsupportedMediaType, "Image type unsupported"},
+	CodeFeatureNotFound:           {http.StatusNotFound, ErrNotFound, "Feature not found"},
+	CodeFeatureAlreadyExists:      {http.StatusConflict, ErrConflict, "Feature already exists"},
+	CodeUnknownFeatures:           {http.StatusBadRequest, ErrBadRequest, "Unknown features"},
+	CodeCatalogEntryNotFound:      {http.StatusNotFound, ErrNotFound, "Catalog entry not found"},
+	CodeCatalogEntryAlreadyExists: {http.StatusConflict, ErrConflict, "Catalog entry already exists"},
+	CodeExchangeRateUnavailable:   {http.StatusBadRequest, ErrBadRequest, "Exchange rate unavailable"},
+	CodeWebhookNotFound:           {http.StatusNotFound, ErrNotFound, "Webhook not found"},
+	CodeWebhookDeliveryNotFound:   {http.StatusNotFound, ErrNotFound, "Webhook delivery not found"},
+	CodeWebhookDeliveryPending:    {http.StatusConflict, ErrConflict, "Webhook delivery pending"},
+	CodeWebhookHostLookupFailed:   {http.StatusServiceUnavailable, ErrServiceUnavailable, "Webhook host lookup failed"},
+	CodeSubscriptionLimitExceeded: {http.StatusBadRequest, ErrBadRequest, "Subscription limit exceeded"},
+	CodeCarReserved:               {http.StatusConflict, ErrConflict, "Car reserved"},
+	CodeReservationNotFound:       {http.StatusNotFound, ErrNotFound, "Reservation not found"},
+	CodeQueryTooDeep:              {http.StatusBadRequest, ErrBadRequest, "Query too deep"},
+	CodeQueryTooComplex:           {http.StatusBadRequest, ErrBadRequest, "Query too complex"},
+}
+
+// NewError will create the error of a code of the catalog, its status and legacy error come
+// from the catalog. A code missing from it is an internal error.
+func NewError(code ErrorCode, details interface{}) HttpErr {
+	problem, ok := catalog[code]
+	if !ok {
+		problem = catalog[CodeInternal]
+	}
+
+	return HttpError{
+		ErrStatus:  problem.status,
+		ErrError:   problem.err.Error(),
+		ErrDetails: details,
+		ErrCode:    code,
+	}
+}
+
+// ProblemTypePrefix starts the type of every problem, the code follows in lower kebab case
+const ProblemTypePrefix = "urn:carapi:problem:"
+
+// Problem is the body of an error as RFC 7807 problem details, with the code of the catalog
+// and the field errors as extension members
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     ErrorCode    `json:"code,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem will describe the error as problem details, instance is the id of the request that
+// failed. The detail is the details when those are a sentence. An error without a code of the
+// catalog is typed about:blank and titled by its status.
+func NewProblem(err HttpErr, instance string) Problem {
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(err.Status()),
+		Status:   err.Status(),
+		Instance: instance,
+	}
+
+	if entry, ok := catalog[err.Code()]; ok {
+		problem.Type = ProblemTypePrefix + strings.ReplaceAll(strings.ToLower(string(err.Code())), "_", "-")
+		problem.Title = entry.title
+		problem.Code = err.Code()
+	}
+
+	switch details := err.Details().(type) {
+	case string:
+		problem.Detail = details
+	case []FieldError:
+		problem.Errors = details
+	}
+	return problem
+}
@@ -0,0 +1,57 @@
+package utils_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"carApi/utils"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewError(t *testing.T) {
+	err := utils.NewError(utils.CodeCarNotFound, "car not found")
+
+	assert.Equal(t, http.StatusNotFound, err.Status())
+	assert.Equal(t, utils.CodeCarNotFound, err.Code())
+	assert.Equal(t, utils.ErrNotFound.Error(), err.(utils.HttpError).ErrError)
+}
+
+func TestNewProblem(t *testing.T) {
+	t.Run("catalog", func(t *testing.T) {
+		problem := utils.NewProblem(utils.NewError(utils.CodeCarNotFound, "car not found"), "req-1")
+
+		assert.Equal(t, utils.Problem{
+			Type:     "urn:carapi:problem:car-not-found",
+			Title:    "Car not found",
+			Status:   http.StatusNotFound,
+			Detail:   "car not found",
+			Instance: "req-1",
+			Code:     utils.CodeCarNotFound,
+		}, problem)
+	})
+
+	t.Run("field-errors", func(t *testing.T) {
+		problem := utils.NewProblem(utils.NewInvalidInputError(validation.Errors{
+			"year": errors.New("cannot be blank"),
+			"make": errors.New("cannot be blank"),
+		}), "req-1")
+
+		assert.Equal(t, utils.CodeValidationFailed, problem.Code)
+		assert.Equal(t, http.StatusBadRequest, problem.Status)
+		assert.Empty(t, problem.Detail)
+		assert.Equal(t, []utils.FieldError{
+			{Field: "make", Error: "cannot be blank"},
+			{Field: "year", Error: "cannot be blank"},
+		}, problem.Errors)
+	})
+
+	t.Run("without-code", func(t *testing.T) {
+		problem := utils.NewProblem(utils.NewHttpError(http.StatusTeapot, "teapot", nil), "req-1")
+
+		assert.Equal(t, "about:blank", problem.Type)
+		assert.Equal(t, http.StatusText(http.StatusTeapot), problem.Title)
+		assert.Empty(t, problem.Code)
+	})
+}
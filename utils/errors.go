@@ -7,36 +7,62 @@ import (
 	"sort"
 
 	validation "github.com/go-ozzo/ozzo-validation"
-	"github.com/labstack/gommon/log"
 )
 
 var (
-	ErrBadRequest           = errors.New("bad request")
-	ErrNotFound             = errors.New("not found")
-	ErrUnauthorized         = errors.New("unauthorized")
-	ErrForbidden            = errors.New("forbidden")
-	ErrInternalServerError  = errors.New("internal server error")
-	ErrUnprocessableEntity  = errors.New("unprocessable entity")
-	ErrAuthenticationFailed = errors.New("authentication vailed")
+	ErrBadRequest            = errors.New("bad request")
+	ErrNotFound              = errors.New("not found")
+	ErrUnauthorized          = errors.New("unauthorized")
+	ErrForbidden             = errors.New("forbidden")
+	ErrInternalServerError   = errors.New("internal server error")
+	ErrUnprocessableEntity   = errors.New("unprocessable entity")
+	ErrAuthenticationFailed  = errors.New("authentication vailed")
+	ErrConflict              = errors.New("conflict")
+	ErrRequestEntityTooLarge = errors.New("request entity too large")
+	ErrUnsupportedMediaType  = errors.New("unsupported media type")
+	ErrNotAcceptable         = errors.New("not acceptable")
+	ErrMethodNotAllowed      = errors.New("method not allowed")
+	ErrServiceUnavailable    = errors.New("service unavailable")
 )
 
 type HttpErr interface {
 	Status() int
 	Error() string
 	Details() interface{}
+	Code() ErrorCode
 }
 
+// HttpError marshals to the legacy {status, error, details} body, the code only shows in the problem details.
+// It wraps the sentinel of its status, e.g. ErrNotFound, and the cause of an internal error, which
+// is never shown to the client.
 type HttpError struct {
 	ErrStatus  int         `json:"status"`
 	ErrError   string      `json:"error"`
 	ErrDetails interface{} `json:"details"`
+	ErrCode    ErrorCode   `json:"-"`
+	cause      error
 }
 
 // Error  Error() interface method
 func (e HttpError) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("status: %d - errors: %s - details: %v - cause: %v", e.ErrStatus, e.ErrError, e.ErrDetails, e.cause)
+	}
 	return fmt.Sprintf("status: %d - errors: %s - details: %v", e.ErrStatus, e.ErrError, e.ErrDetails)
 }
 
+// Unwrap gives the sentinel of the code and the cause, so errors.Is matches either of them
+func (e HttpError) Unwrap() []error {
+	var errs []error
+	if problem, ok := catalog[e.ErrCode]; ok {
+		errs = append(errs, problem.err)
+	}
+	if e.cause != nil {
+		errs = append(errs, e.cause)
+	}
+	return errs
+}
+
 // Error status
 func (e HttpError) Status() int {
 	return e.ErrStatus
@@ -47,6 +73,11 @@ func (e HttpError) Details() interface{} {
 	return e.ErrDetails
 }
 
+// Code of the catalog, empty for an error built from a bare status
+func (e HttpError) Code() ErrorCode {
+	return e.ErrCode
+}
+
 // New Http Error
 func NewHttpError(status int, err string, details interface{}) HttpErr {
 	return HttpError{
@@ -58,77 +89,81 @@ func NewHttpError(status int, err string, details interface{}) HttpErr {
 
 // New Authentication Failed Error
 func NewAuthenticationFailedError(details interface{}) HttpErr {
-	return HttpError{
-		ErrStatus:  401,
-		ErrError:   ErrAuthenticationFailed.Error(),
-		ErrDetails: details,
-	}
+	return NewError(CodeAuthenticationFailed, details)
 }
 
 // New Bad Request Error
 func NewBadRequestError(details interface{}) HttpErr {
-	return HttpError{
-		ErrStatus:  http.StatusBadRequest,
-		ErrError:   ErrBadRequest.Error(),
-		ErrDetails: details,
-	}
+	return NewError(CodeBadRequest, details)
 }
 
 // New Not Found Error
 func NewNotFoundError(details interface{}) HttpErr {
-	return HttpError{
-		ErrStatus:  http.StatusNotFound,
-		ErrError:   ErrNotFound.Error(),
-		ErrDetails: details,
-	}
+	return NewError(CodeNotFound, details)
 }
 
 // New Unauthorized Error
 func NewUnauthorizedError(details interface{}) HttpErr {
-	return HttpError{
-		ErrStatus:  http.StatusUnauthorized,
-		ErrError:   ErrUnauthorized.Error(),
-		ErrDetails: details,
-	}
+	return NewError(CodeUnauthorized, details)
 }
 
 // New Forbidden Error
 func NewForbiddenError(details interface{}) HttpErr {
-	return HttpError{
-		ErrStatus:  http.StatusForbidden,
-		ErrError:   ErrForbidden.Error(),
-		ErrDetails: details,
-	}
+	return NewError(CodeForbidden, details)
 }
 
-// New Internal Server Error
+// New Internal Server Error, the details are kept as its cause and never shown to the client
 func NewInternalServerError(details interface{}) HttpErr {
-	log.Error(details.(error).Error())
-
-	return HttpError{
-		ErrStatus:  http.StatusInternalServerError,
-		ErrError:   ErrInternalServerError.Error(),
-		ErrDetails: nil,
+	err := NewError(CodeInternal, nil).(HttpError)
+	switch details := details.(type) {
+	case nil:
+	case error:
+		err.cause = details
+	default:
+		err.cause = fmt.Errorf("%v", details)
 	}
+	return err
+}
+
+// New Method Not Allowed Error
+func NewMethodNotAllowedError(details interface{}) HttpErr {
+	return NewError(CodeMethodNotAllowed, details)
 }
 
 // New Unprocessable Entity Error
 func NewUnprocessableEntityError(details interface{}) HttpErr {
-	return HttpError{
-		ErrStatus:  http.StatusUnprocessableEntity,
-		ErrError:   ErrUnprocessableEntity.Error(),
-		ErrDetails: details,
-	}
+	return NewError(CodeUnprocessableEntity, details)
+}
+
+// New Conflict Error
+func NewConflictError(details interface{}) HttpErr {
+	return NewError(CodeConflict, details)
+}
+
+// New Request Entity Too Large Error
+func NewRequestEntityTooLargeError(details interface{}) HttpErr {
+	return NewError(CodePayloadTooLarge, details)
+}
+
+// New Unsupported Media Type Error
+func NewUnsupportedMediaTypeError(details interface{}) HttpErr {
+	return NewError(CodeUnsupportedMediaType, details)
+}
+
+// New Not Acceptable Error
+func NewNotAcceptableError(details interface{}) HttpErr {
+	return NewError(CodeNotAcceptable, details)
+}
+
+// FieldError is the error of a field of an invalid input
+type FieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
 }
 
 // New Invalid Input Error - Validation
 func NewInvalidInputError(errs validation.Errors) HttpErr {
-	type invalidField struct {
-		Field string `json:"field"`
-		Error string `json:"error"`
-	}
-
-	var details []invalidField
+	var details []FieldError
 	var fields []string
 	for field := range errs {
 		fields = append(fields, field)
@@ -136,22 +171,20 @@ func NewInvalidInputError(errs validation.Errors) HttpErr {
 
 	sort.Strings(fields)
 	for _, field := range fields {
-		details = append(details, invalidField{
+		details = append(details, FieldError{
 			Field: field,
 			Error: errs[field].Error(),
 		})
 	}
 
-	return HttpError{
-		ErrStatus:  http.StatusBadRequest,
-		ErrError:   ErrBadRequest.Error(),
-		ErrDetails: details,
-	}
+	return NewError(CodeValidationFailed, details)
 }
 
-// Parse Http Error
-func ParseHttpError(err error) (int, interface{}) {
-	if httpErr, ok := err.(HttpErr); ok {
+// ParseHttpError finds the error carrying a status in the chain of err, any other error is
+// an internal one caused by err
+func ParseHttpError(err error) (int, HttpErr) {
+	var httpErr HttpErr
+	if errors.As(err, &httpErr) {
 		return httpErr.Status(), httpErr
 	}
 	return http.StatusInternalServerError, NewInternalServerError(err)
@@ -64,7 +64,7 @@ var loggerLevelMap = map[string]zapcore.Level{
 }
 
 func (l *apiLogger) getLoggerLevel(cfg *config.Config) zapcore.Level {
-	level, exist := loggerLevelMap[cfg.LoggerLevel]
+	level, exist := loggerLevelMap[cfg.Logger.Level]
 	if !exist {
 		return zapcore.DebugLevel
 	}
@@ -0,0 +1,73 @@
+package media
+
+import (
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// extensions maps the accepted image content types to their file extension
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// DetectContentType sniffs the content type from the leading bytes of data,
+// the client supplied header is never trusted
+func DetectContentType(data []byte) string {
+	return http.DetectContentType(data)
+}
+
+// Extension returns the file extension for an accepted image content type
+func Extension(contentType string) (string, bool) {
+	ext, ok := extensions[contentType]
+	return ext, ok
+}
+
+// ThumbnailKey derives the storage key of a thumbnail from its original key
+func ThumbnailKey(key, size string) string {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + "_" + size + ext
+}
+
+// Thumbnail scales img down so its longest side is at most maxSide pixels,
+// images that are already small enough are returned unchanged
+func Thumbnail(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*maxSide/width)
+		width = maxSide
+	} else {
+		width = max(1, width*maxSide/height)
+		height = maxSide
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// Encode writes img to w in the format of contentType
+func Encode(w io.Writer, img image.Image, contentType string) error {
+	switch contentType {
+	case "image/png":
+		return png.Encode(w, img)
+	case "image/gif":
+		return gif.Encode(w, img, nil)
+	default:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+	}
+}
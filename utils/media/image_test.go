@@ -0,0 +1,67 @@
+package media_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"carApi/utils/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+	return img
+}
+
+func TestDetectContentType(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, newImage(4, 4)))
+
+	contentType := media.DetectContentType(buf.Bytes())
+	assert.Equal(t, "image/png", contentType)
+
+	ext, ok := media.Extension(contentType)
+	assert.True(t, ok)
+	assert.Equal(t, ".png", ext)
+
+	_, ok = media.Extension(media.DetectContentType([]byte("%PDF-1.4")))
+	assert.False(t, ok)
+}
+
+func TestThumbnailKey(t *testing.T) {
+	assert.Equal(t, "cars/1/abc_small.jpg", media.ThumbnailKey("cars/1/abc.jpg", "small"))
+}
+
+func TestThumbnail(t *testing.T) {
+	t.Run("landscape", func(t *testing.T) {
+		thumb := media.Thumbnail(newImage(800, 400), 160)
+		assert.Equal(t, 160, thumb.Bounds().Dx())
+		assert.Equal(t, 80, thumb.Bounds().Dy())
+	})
+
+	t.Run("portrait", func(t *testing.T) {
+		thumb := media.Thumbnail(newImage(300, 600), 160)
+		assert.Equal(t, 80, thumb.Bounds().Dx())
+		assert.Equal(t, 160, thumb.Bounds().Dy())
+	})
+
+	t.Run("small-enough", func(t *testing.T) {
+		img := newImage(100, 50)
+		assert.Equal(t, img, media.Thumbnail(img, 160))
+	})
+
+	t.Run("encode", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, media.Encode(&buf, media.Thumbnail(newImage(400, 400), 160), "image/jpeg"))
+		assert.Equal(t, "image/jpeg", media.DetectContentType(buf.Bytes()))
+	})
+}
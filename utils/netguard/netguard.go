@@ -0,0 +1,58 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"syscall"
+)
+
+// ErrNotPublic is given for an address the API must not reach on behalf of a client
+var ErrNotPublic = errors.New("address is not public")
+
+// sharedAddressSpace is the carrier grade NAT range of RFC 6598, netip does not count it as private
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Public reports whether addr is a public unicast address, loopback, private, link-local (the
+// 169.254.169.254 metadata endpoint among them), unspecified and multicast addresses are not
+func Public(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// Resolver looks up the addresses of a host, net.DefaultResolver is one
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// CheckHost resolves host and fails with ErrNotPublic unless every address it resolves to is
+// public, a failed lookup is given as is
+func CheckHost(ctx context.Context, resolver Resolver, host string) error {
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		if !Public(addr) {
+			return fmt.Errorf("%s resolves to %s: %w", host, addr, ErrNotPublic)
+		}
+	}
+	return nil
+}
+
+// Control refuses to connect to an address that is not public. Set on a net.Dialer it checks the
+// address actually dialed, after resolution, so a host cannot be rebound to an internal address
+// between its registration and the request.
+func Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !Public(addrPort.Addr()) {
+		return fmt.Errorf("dialing %s: %w", address, ErrNotPublic)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package netguard_test
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"carApi/utils/netguard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublic(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"203.0.113.10", true},
+		{"2001:db8::1", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.public, netguard.Public(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	assert.NoError(t, netguard.CheckHost(context.TODO(), net.DefaultResolver, "203.0.113.10"))
+	assert.ErrorIs(t, netguard.CheckHost(context.TODO(), net.DefaultResolver, "169.254.169.254"), netguard.ErrNotPublic)
+	assert.ErrorIs(t, netguard.CheckHost(context.TODO(), net.DefaultResolver, "localhost"), netguard.ErrNotPublic)
+}
+
+func TestControl(t *testing.T) {
+	assert.NoError(t, netguard.Control("tcp", "203.0.113.10:443", nil))
+	assert.ErrorIs(t, netguard.Control("tcp", "127.0.0.1:8080", nil), netguard.ErrNotPublic)
+	assert.ErrorIs(t, netguard.Control("tcp6", "[::1]:8080", nil), netguard.ErrNotPublic)
+}